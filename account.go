@@ -18,19 +18,50 @@ type Account struct {
 	AuthToken  string
 	CT0        string
 	TOTPSecret string
-	Proxy      string
-	UserAgent  string
-	Profile    stealth.BrowserProfile
+	Proxy      string                 // may contain {session}/{rand} and {account} placeholders
+	UserAgent  string                 // "" = from Profile, the saved session, or picked per username
+	Profile    stealth.BrowserProfile // zero = matched to UserAgent, or as above
+	Mode       ClientMode             // official client to imitate; default ModeWeb
+	OAuth1     *OAuth1Credentials     // optional: signs v1.1 REST requests instead of the cookies
+
+	// ActiveHours limits the time of day the pool hands the account out,
+	// e.g. 07:00-23:00 in its timezone. Zero: always.
+	ActiveHours ActivityWindow
+
+	// Labels tag the account for WithAccountLabels, e.g. {"region": "eu",
+	// "purpose": "scrape"}.
+	Labels map[string]string
+
+	// ProxySessionRequests starts a new {session} in Proxy every N requests.
+	// Default: 0 (one session per login).
+	ProxySessionRequests int
 
 	active       bool
 	reactivateAt time.Time
 	client       *stealth.BrowserClient
+	clientOpts   []stealth.ClientOption // extra options for per-account clients (the tuned backend)
+	pooledProxy  bool                   // Proxy was assigned from ClientConfig.ProxyPool
 
 	mu               sync.Mutex
 	ct0RefreshedAt   time.Time
 	proxyBackoff     time.Time
 	proxyConsecFails int
-	rateLimiter      *ratelimit.Limiter
+	rateLimiter      *ratelimit.Limiter            // fallback for endpoints without their own limit
+	endpointLimiters map[string]*ratelimit.Limiter // per-operation limits, see ClientConfig.EndpointLimits
+	lastError        string
+	lastErrorAt      time.Time
+	lastSuccessAt    time.Time
+	relogins         int
+	captchaSolves    int
+	uses             int                  // requests selected in the current usage window
+	usesSince        time.Time            // start of the usage window
+	proxySession     string               // current {session} value for a templated Proxy
+	proxySessionUses int                  // requests sent on proxySession
+	rateLimitedUntil map[string]time.Time // endpoint 429 windows, kept for health persistence
+	actions          map[ActionClass]int  // write actions on actionsDay, see ClientConfig.ActionQuotas
+	actionsDay       string
+	writeLeased      bool        // held by AcquireWriteAccount
+	web              webIdentity // cookies and client UUID persisted with the session
 
 	pool.HealthTracker
 }
@@ -50,6 +81,17 @@ func (a *Account) ReactivateAt() time.Time { return a.reactivateAt }
 // SetReactivateAt implements pool.Identity.
 func (a *Account) SetReactivateAt(t time.Time) { a.reactivateAt = t }
 
+// inService reports whether the account can serve requests at now: it is
+// active, or its soft-deactivation has ended and the pool reactivates it the
+// next time it is picked.
+func (a *Account) inService(now time.Time) bool {
+	if a.IsActive() {
+		return true
+	}
+	re := a.ReactivateAt()
+	return !re.IsZero() && !re.After(now)
+}
+
 // CT0Age returns the time since the ct0 token was last refreshed.
 func (a *Account) CT0Age() time.Duration {
 	a.mu.Lock()
@@ -83,6 +125,14 @@ func (a *Account) Credentials() (authToken, ct0, userAgent string) {
 	return a.AuthToken, a.CT0, a.UserAgent
 }
 
+// currentProxy returns the account's proxy URL. A ProxyPool swaps it while
+// requests are in flight, so read it through here rather than directly.
+func (a *Account) currentProxy() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.Proxy
+}
+
 // SetCredentials atomically updates auth_token and ct0.
 func (a *Account) SetCredentials(authToken, ct0 string) {
 	a.mu.Lock()
@@ -94,53 +144,78 @@ func (a *Account) SetCredentials(authToken, ct0 string) {
 
 // AllowRequest checks if this account can make a request to the given endpoint.
 func (a *Account) AllowRequest(endpoint string) bool {
-	a.mu.Lock()
-	if a.rateLimiter == nil {
-		a.mu.Unlock()
+	rl := a.limiter(endpoint)
+	if rl == nil {
 		return true
 	}
-	rl := a.rateLimiter
-	a.mu.Unlock()
 	return rl.Allow(endpoint)
 }
 
 // MarkEndpointRateLimited marks an endpoint as rate-limited for this account.
 func (a *Account) MarkEndpointRateLimited(endpoint string, until time.Time) {
+	if rl := a.limiter(endpoint); rl != nil {
+		rl.MarkRateLimited(endpoint, until)
+	}
 	a.mu.Lock()
-	if a.rateLimiter == nil {
-		a.mu.Unlock()
-		return
+	if a.rateLimitedUntil == nil {
+		a.rateLimitedUntil = make(map[string]time.Time)
 	}
-	rl := a.rateLimiter
+	a.rateLimitedUntil[endpoint] = until
 	a.mu.Unlock()
-	rl.MarkRateLimited(endpoint, until)
 }
 
 // IsEndpointRateLimited returns true if the endpoint is currently blocked.
 func (a *Account) IsEndpointRateLimited(endpoint string) bool {
-	a.mu.Lock()
-	if a.rateLimiter == nil {
-		a.mu.Unlock()
+	rl := a.limiter(endpoint)
+	if rl == nil {
 		return false
 	}
-	rl := a.rateLimiter
-	a.mu.Unlock()
 	return rl.IsRateLimited(endpoint)
 }
 
 // EndpointAvailableAt returns when this account will be available for the given endpoint.
 func (a *Account) EndpointAvailableAt(endpoint string) time.Time {
-	a.mu.Lock()
-	if a.rateLimiter == nil {
-		a.mu.Unlock()
+	rl := a.limiter(endpoint)
+	if rl == nil {
 		return time.Time{}
 	}
-	rl := a.rateLimiter
-	a.mu.Unlock()
 	return rl.AvailableAt(endpoint)
 }
 
-// AssignBrowserProfile sets a browser profile based on index.
+// limiter returns the endpoint's own limiter if it has a per-operation limit,
+// otherwise the account-wide one. Nil if limiting is not set up.
+func (a *Account) limiter(endpoint string) *ratelimit.Limiter {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if rl, ok := a.endpointLimiters[endpoint]; ok {
+		return rl
+	}
+	return a.rateLimiter
+}
+
+// setupLimiters creates the account's rate limiters from cfg: one per
+// endpoint with a per-operation limit, plus the RateLimit fallback.
+func (a *Account) setupLimiters(cfg *ClientConfig) {
+	limiters := make(map[string]*ratelimit.Limiter)
+	for _, name := range endpointNames() {
+		if lc, ok := cfg.endpointLimit(name); ok {
+			limiters[name] = ratelimit.NewLimiter(lc)
+		}
+	}
+	for name, lc := range cfg.EndpointLimits {
+		if _, ok := limiters[name]; !ok {
+			limiters[name] = ratelimit.NewLimiter(lc)
+		}
+	}
+	a.mu.Lock()
+	a.rateLimiter = ratelimit.NewLimiter(cfg.RateLimit)
+	a.endpointLimiters = limiters
+	a.mu.Unlock()
+}
+
+// AssignBrowserProfile sets a browser profile based on index. The profile
+// then follows the account's position in the list; leave Profile unset to
+// have NewClient pick one per username and keep it in the session file.
 func AssignBrowserProfile(acc *Account, idx int) {
 	p := stealth.BuiltinProfiles[idx%len(stealth.BuiltinProfiles)]
 	acc.Profile = p
@@ -150,6 +225,7 @@ func AssignBrowserProfile(acc *Account, idx int) {
 // ParseAccounts parses a comma-separated list of accounts.
 // Format: "user1:pass1,user2:pass2" or "user1:pass1:auth_token:ct0,..."
 // or "user1:pass1:auth_token:ct0:totp_secret,...".
+// Browser profiles are left to NewClient.
 func ParseAccounts(raw string) []*Account {
 	var accounts []*Account
 	for _, entry := range strings.Split(raw, ",") {
@@ -175,7 +251,6 @@ func ParseAccounts(raw string) []*Account {
 		if len(parts) >= 5 && parts[4] != "" {
 			acc.TOTPSecret = parts[4]
 		}
-		AssignBrowserProfile(acc, len(accounts))
 		accounts = append(accounts, acc)
 	}
 	return accounts
@@ -0,0 +1,129 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AccountStatus classifies the outcome of an account probe.
+type AccountStatus string
+
+const (
+	AccountOK             AccountStatus = "ok"
+	AccountLocked         AccountStatus = "locked"          // needs a CAPTCHA or other unlock (326)
+	AccountSuspended      AccountStatus = "suspended"       // suspended by Twitter (64)
+	AccountBadCredentials AccountStatus = "bad_credentials" // auth_token/ct0 rejected (401, 32)
+	AccountRateLimited    AccountStatus = "rate_limited"    // the probe itself was throttled
+	AccountUnreachable    AccountStatus = "unreachable"     // network or proxy failure, or an unexpected response
+)
+
+// AccountCheck is the result of probing one pool account.
+type AccountCheck struct {
+	Username   string
+	Status     AccountStatus
+	ScreenName string // screen name the tokens belong to, when OK
+	Err        error  // why the account is not OK
+}
+
+// CheckAccounts probes every pool account with one account/settings call,
+// classifies it and updates the pool: suspended accounts are deactivated,
+// locked ones and ones with rejected credentials soft-deactivated for
+// BanCooldown and AuthCooldown, and soft-deactivated accounts that pass are
+// reactivated. Run it at startup or on a schedule to stop spending retries on
+// dead accounts. Results are sorted by username.
+func (c *Client) CheckAccounts(ctx context.Context) []AccountCheck {
+	accounts := c.pool.Items()
+	checks := make([]AccountCheck, len(accounts))
+	runBounded(ctx, len(accounts), defaultBulkConcurrency, func(i int) {
+		checks[i] = c.checkAccount(ctx, accounts[i])
+		c.applyAccountCheck(accounts[i], checks[i])
+	})
+	slices.SortFunc(checks, func(a, b AccountCheck) int { return strings.Compare(a.Username, b.Username) })
+	return checks
+}
+
+// checkAccount probes acc once.
+func (c *Client) checkAccount(ctx context.Context, acc *Account) AccountCheck {
+	check := AccountCheck{Username: acc.Username}
+	if err := ctx.Err(); err != nil {
+		check.Status, check.Err = AccountUnreachable, err
+		return check
+	}
+	body, respHdrs, status, err := c.accountRequest(ctx, acc, accountSettingsURL)
+	if err != nil {
+		if acc.currentProxy() != "" && isProxyError(err) {
+			c.markProxyDown(acc)
+		}
+		check.Status, check.Err = AccountUnreachable, err
+		return check
+	}
+	c.recordAPICall("AccountSettings", status, status == 200, status == 429)
+	check.Status = classifyAccountProbe(status, body)
+	switch check.Status {
+	case AccountOK:
+		var settings struct {
+			ScreenName string `json:"screen_name"`
+		}
+		_ = json.Unmarshal(body, &settings)
+		check.ScreenName = settings.ScreenName
+	case AccountRateLimited:
+		acc.MarkEndpointRateLimited("AccountSettings", parseRateLimitReset(respHdrs["x-rate-limit-reset"]))
+		check.Err = fmt.Errorf("429 rate limited")
+	default:
+		check.Err = fmt.Errorf("HTTP %d: %s", status, truncateBytes(body, 200))
+	}
+	return check
+}
+
+// classifyAccountProbe maps an account/settings response to a status.
+func classifyAccountProbe(status int, body []byte) AccountStatus {
+	switch classifyError(body, nil) {
+	case errSuspended:
+		return AccountSuspended
+	case errLocked:
+		return AccountLocked
+	case errAuthExpired:
+		return AccountBadCredentials
+	case errBanned:
+		return AccountRateLimited
+	}
+	switch status {
+	case 200:
+		return AccountOK
+	case 401:
+		return AccountBadCredentials
+	case 429:
+		return AccountRateLimited
+	}
+	return AccountUnreachable
+}
+
+// applyAccountCheck updates pool state from a probe result.
+func (c *Client) applyAccountCheck(acc *Account, check AccountCheck) {
+	switch check.Status {
+	case AccountOK:
+		acc.recordSuccess()
+		if !acc.IsActive() && !acc.ReactivateAt().IsZero() {
+			slog.Info("account check passed, reactivating", slog.String("user", acc.Username))
+			acc.SetReactivateAt(time.Time{})
+			acc.SetActive(true)
+		}
+	case AccountSuspended:
+		slog.Warn("account check: suspended, deactivating", slog.String("user", acc.Username))
+		acc.recordError(check.Err)
+		c.deactivate(acc, AccountEventSuspended, check.Err)
+	case AccountLocked:
+		slog.Warn("account check: locked", slog.String("user", acc.Username))
+		acc.recordError(check.Err)
+		c.softDeactivate(acc, c.cfg.BanCooldown, AccountEventLocked, check.Err)
+	case AccountBadCredentials:
+		slog.Warn("account check: credentials rejected", slog.String("user", acc.Username))
+		acc.recordError(check.Err)
+		c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, check.Err)
+	}
+}
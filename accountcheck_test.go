@@ -0,0 +1,64 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyAccountProbe(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   AccountStatus
+	}{
+		{200, `{"screen_name":"jack"}`, AccountOK},
+		{401, `{"errors":[{"code":32,"message":"Could not authenticate you."}]}`, AccountBadCredentials},
+		{401, ``, AccountBadCredentials},
+		{403, `{"errors":[{"code":64,"message":"Your account is suspended"}]}`, AccountSuspended},
+		{403, `{"errors":[{"code":326,"message":"To protect our users from spam"}]}`, AccountLocked},
+		{429, ``, AccountRateLimited},
+		{503, `upstream error`, AccountUnreachable},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, classifyAccountProbe(tt.status, []byte(tt.body)), "%d %s", tt.status, tt.body)
+	}
+}
+
+func TestApplyAccountCheck(t *testing.T) {
+	ok, locked, suspended := &Account{Username: "ok"}, &Account{Username: "locked", active: true}, &Account{Username: "gone", active: true}
+	ok.SetReactivateAt(time.Now().Add(time.Hour)) // soft-deactivated earlier
+	c := &Client{
+		pool: pool.New([]*Account{ok, locked, suspended}, pool.Config{}),
+		cfg:  ClientConfig{BanCooldown: time.Hour},
+	}
+
+	c.applyAccountCheck(ok, AccountCheck{Status: AccountOK})
+	c.applyAccountCheck(locked, AccountCheck{Status: AccountLocked, Err: errors.New("locked")})
+	c.applyAccountCheck(suspended, AccountCheck{Status: AccountSuspended, Err: errors.New("suspended")})
+
+	assert.True(t, ok.IsActive())
+	assert.True(t, ok.ReactivateAt().IsZero())
+	assert.False(t, locked.IsActive())
+	assert.WithinDuration(t, time.Now().Add(time.Hour), locked.ReactivateAt(), time.Minute)
+	assert.False(t, suspended.IsActive())
+}
+
+func TestCheckAccounts_CancelledContext(t *testing.T) {
+	accs := []*Account{{Username: "b", active: true}, {Username: "a", active: true}}
+	c := &Client{pool: pool.New(accs, pool.Config{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checks := c.CheckAccounts(ctx)
+	require.Len(t, checks, 2)
+	assert.Equal(t, "a", checks[0].Username)
+	assert.Equal(t, AccountUnreachable, checks[0].Status)
+	assert.ErrorIs(t, checks[1].Err, context.Canceled)
+	assert.True(t, accs[0].IsActive(), "unreachable accounts are left alone")
+}
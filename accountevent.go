@@ -0,0 +1,164 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// AccountEventType names a change in an account's standing.
+type AccountEventType string
+
+const (
+	AccountEventDeactivated      AccountEventType = "deactivated"       // permanently removed from rotation (too many failures)
+	AccountEventSoftDeactivated  AccountEventType = "soft_deactivated"  // benched until Until (auth error, code 88)
+	AccountEventSuspended        AccountEventType = "suspended"         // suspended by Twitter (64), permanently deactivated
+	AccountEventLocked           AccountEventType = "locked"            // locked (326) and not unlocked, benched until Until
+	AccountEventReloginSucceeded AccountEventType = "relogin_succeeded" // automatic re-login got fresh credentials
+	AccountEventReloginFailed    AccountEventType = "relogin_failed"    // automatic re-login failed or was blocked
+	AccountEventProxyDown        AccountEventType = "proxy_down"        // the account's proxy failed; backing off until Until
+)
+
+// AccountEvent reports a change in a pool account's standing. See
+// ClientConfig.AccountEventHook.
+type AccountEvent struct {
+	Type     AccountEventType
+	Username string
+	At       time.Time
+	Until    time.Time // end of the soft-deactivation or proxy backoff, if any
+	Err      error     // what triggered the event, if known
+}
+
+// emitAccountEvent passes an event for acc to ClientConfig.AccountEventHook.
+func (c *Client) emitAccountEvent(typ AccountEventType, acc *Account, until time.Time, cause error) {
+	if c.cfg.AccountEventHook == nil {
+		return
+	}
+	c.cfg.AccountEventHook(AccountEvent{Type: typ, Username: acc.Username, At: time.Now(), Until: until, Err: cause})
+}
+
+// softDeactivate benches acc for d and reports it as typ.
+func (c *Client) softDeactivate(acc *Account, d time.Duration, typ AccountEventType, cause error) {
+	c.pool.SoftDeactivate(acc, d)
+	c.emitAccountEvent(typ, acc, acc.ReactivateAt(), cause)
+}
+
+// deactivate removes acc from rotation and reports it as typ.
+func (c *Client) deactivate(acc *Account, typ AccountEventType, cause error) {
+	c.pool.DeactivateItem(acc)
+	c.emitAccountEvent(typ, acc, time.Time{}, cause)
+}
+
+// WebhookFormat selects the payload a WebhookNotifier posts.
+type WebhookFormat string
+
+const (
+	WebhookJSON     WebhookFormat = "json"     // the event as a JSON object
+	WebhookSlack    WebhookFormat = "slack"    // a Slack incoming-webhook message
+	WebhookTelegram WebhookFormat = "telegram" // a Telegram Bot API sendMessage call
+)
+
+// WebhookNotifier posts account events to a webhook. Use its Hook as
+// ClientConfig.AccountEventHook:
+//
+//	n := &twitter.WebhookNotifier{URL: slackURL, Format: twitter.WebhookSlack}
+//	cfg.AccountEventHook = n.Hook
+type WebhookNotifier struct {
+	// URL receives the POSTs. For WebhookTelegram it is the sendMessage
+	// endpoint, https://api.telegram.org/bot<token>/sendMessage.
+	URL string
+
+	// Format selects the payload. Default: WebhookJSON.
+	Format WebhookFormat
+
+	// ChatID is the Telegram chat to message. Required for WebhookTelegram.
+	ChatID string
+
+	// Types limits the events sent. Default: all.
+	Types []AccountEventType
+
+	// HTTPClient sends the requests. Default: a client with a 10s timeout.
+	HTTPClient *http.Client
+}
+
+// Hook sends ev in the background, so it never holds up the request that
+// triggered it. Failures are logged.
+func (n *WebhookNotifier) Hook(ev AccountEvent) {
+	if len(n.Types) > 0 && !slices.Contains(n.Types, ev.Type) {
+		return
+	}
+	go func() {
+		if err := n.Send(context.Background(), ev); err != nil {
+			slog.Warn("account event webhook failed", slog.String("type", string(ev.Type)),
+				slog.String("user", ev.Username), slog.Any("error", err))
+		}
+	}()
+}
+
+// Send posts ev and waits for the response.
+func (n *WebhookNotifier) Send(ctx context.Context, ev AccountEvent) error {
+	payload, err := n.payload(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	hc := n.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// payload encodes ev in the notifier's format.
+func (n *WebhookNotifier) payload(ev AccountEvent) ([]byte, error) {
+	switch n.Format {
+	case WebhookSlack:
+		return json.Marshal(map[string]string{"text": ev.String()})
+	case WebhookTelegram:
+		if n.ChatID == "" {
+			return nil, fmt.Errorf("telegram webhook: ChatID is required")
+		}
+		return json.Marshal(map[string]string{"chat_id": n.ChatID, "text": ev.String()})
+	}
+	out := struct {
+		Type     AccountEventType `json:"type"`
+		Username string           `json:"username"`
+		At       time.Time        `json:"at"`
+		Until    time.Time        `json:"until,omitzero"`
+		Error    string           `json:"error,omitempty"`
+	}{Type: ev.Type, Username: ev.Username, At: ev.At, Until: ev.Until}
+	if ev.Err != nil {
+		out.Error = ev.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// String renders ev as a one-line message, e.g.
+// "twitter account alice: locked until 15:04 UTC (account locked)".
+func (ev AccountEvent) String() string {
+	msg := fmt.Sprintf("twitter account %s: %s", ev.Username, ev.Type)
+	if !ev.Until.IsZero() {
+		msg += " until " + ev.Until.UTC().Format("15:04 MST")
+	}
+	if ev.Err != nil {
+		msg += " (" + ev.Err.Error() + ")"
+	}
+	return msg
+}
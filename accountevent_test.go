@@ -0,0 +1,73 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountEventHook_Deactivations(t *testing.T) {
+	var events []AccountEvent
+	locked := &Account{Username: "locked", active: true}
+	suspended := &Account{Username: "suspended", active: true}
+	c := &Client{
+		pool: pool.New([]*Account{locked, suspended}, pool.Config{}),
+		cfg: ClientConfig{
+			BanCooldown:      time.Hour,
+			AccountEventHook: func(ev AccountEvent) { events = append(events, ev) },
+		},
+	}
+	c.applyAccountCheck(locked, AccountCheck{Username: "locked", Status: AccountLocked, Err: errors.New("HTTP 403")})
+	c.applyAccountCheck(suspended, AccountCheck{Username: "suspended", Status: AccountSuspended, Err: errors.New("HTTP 403")})
+
+	require.Len(t, events, 2)
+	assert.Equal(t, AccountEventLocked, events[0].Type)
+	assert.Equal(t, "locked", events[0].Username)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), events[0].Until, time.Second)
+	assert.Equal(t, AccountEventSuspended, events[1].Type)
+	assert.True(t, events[1].Until.IsZero())
+	assert.EqualError(t, events[1].Err, "HTTP 403")
+}
+
+func TestWebhookNotifier_Formats(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = nil
+		_ = json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	until := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+	ev := AccountEvent{Type: AccountEventLocked, Username: "alice", At: until.Add(-time.Hour), Until: until, Err: errors.New("account locked")}
+
+	require.NoError(t, (&WebhookNotifier{URL: srv.URL}).Send(context.Background(), ev))
+	assert.Equal(t, "locked", got["type"])
+	assert.Equal(t, "alice", got["username"])
+	assert.Equal(t, "account locked", got["error"])
+
+	require.NoError(t, (&WebhookNotifier{URL: srv.URL, Format: WebhookSlack}).Send(context.Background(), ev))
+	assert.Equal(t, "twitter account alice: locked until 15:04 UTC (account locked)", got["text"])
+
+	require.NoError(t, (&WebhookNotifier{URL: srv.URL, Format: WebhookTelegram, ChatID: "42"}).Send(context.Background(), ev))
+	assert.Equal(t, "42", got["chat_id"])
+	assert.Error(t, (&WebhookNotifier{URL: srv.URL, Format: WebhookTelegram}).Send(context.Background(), ev))
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	err := (&WebhookNotifier{URL: srv.URL}).Send(context.Background(), AccountEvent{Type: AccountEventProxyDown, Username: "bob"})
+	assert.ErrorContains(t, err, "502")
+}
@@ -0,0 +1,132 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// ActionStep is one step of a composite write run by RunActions, e.g.
+// "create tweet" then "pin it". Steps share data through the closures, such
+// as a tweet ID set by one Do and read by the next.
+type ActionStep struct {
+	Name string
+	Do   func(ctx context.Context, acc *Account) error
+	// Undo reverts the step after a later one failed; nil if there is
+	// nothing to revert.
+	Undo func(ctx context.Context, acc *Account) error
+	// Idempotent steps (likes, follows, pins) are retried after a failure.
+	// Others, such as CreateTweet, may have taken effect despite the error,
+	// so they fail the run at once.
+	Idempotent bool
+}
+
+// ActionsOptions configures RunActions.
+type ActionsOptions struct {
+	// MinDelay and MaxDelay bound the random pause between steps, so that a
+	// sequence does not look scripted. Default: 2s to 8s.
+	MinDelay, MaxDelay time.Duration
+	// Retry sets the attempts and backoff for idempotent steps. Default:
+	// three attempts with the default backoff.
+	Retry RetryPolicy
+	// NoRollback keeps completed steps when one fails instead of running
+	// their Undo in reverse order.
+	NoRollback bool
+}
+
+// ActionsReport says how far a RunActions run got.
+type ActionsReport struct {
+	Completed  []string // steps that succeeded, in order
+	Failed     string   // step that failed; "" if all completed
+	RolledBack []string // completed steps undone after the failure, in undo order
+	Err        error    // failure of Failed, joined with any Undo errors
+}
+
+// OK reports whether every step completed.
+func (r *ActionsReport) OK() bool {
+	return r.Err == nil
+}
+
+const (
+	defaultActionMinDelay = 2 * time.Second
+	defaultActionMaxDelay = 8 * time.Second
+)
+
+// RunActions runs steps in order on acc, pausing a random MinDelay to
+// MaxDelay between them. A failed idempotent step is retried per
+// opts.Retry; quota errors and cancellation are not retried. When a step
+// fails for good the completed ones are undone in reverse order, unless
+// opts.NoRollback is set. Use AcquireWriteAccount to pick acc.
+func (c *Client) RunActions(ctx context.Context, acc *Account, steps []ActionStep, opts ActionsOptions) *ActionsReport {
+	rep := &ActionsReport{}
+	if acc == nil {
+		rep.Err = errors.New("run actions: no account")
+		return rep
+	}
+	if opts.MinDelay <= 0 && opts.MaxDelay <= 0 {
+		opts.MinDelay, opts.MaxDelay = defaultActionMinDelay, defaultActionMaxDelay
+	}
+	opts.MaxDelay = max(opts.MaxDelay, opts.MinDelay)
+	opts.Retry.defaults()
+
+	var done []ActionStep
+	for i, step := range steps {
+		if i > 0 {
+			select {
+			case <-time.After(opts.MinDelay + rand.N(opts.MaxDelay-opts.MinDelay+1)):
+			case <-ctx.Done():
+				rep.Failed, rep.Err = step.Name, ctx.Err()
+			}
+			if rep.Err != nil {
+				break
+			}
+		}
+		if err := runStep(ctx, acc, step, opts.Retry); err != nil {
+			rep.Failed, rep.Err = step.Name, fmt.Errorf("%s: %w", step.Name, err)
+			break
+		}
+		done = append(done, step)
+		rep.Completed = append(rep.Completed, step.Name)
+	}
+	if rep.Err == nil || opts.NoRollback {
+		return rep
+	}
+
+	// Undo even if ctx is done: the point is to leave no half-made change.
+	undoCtx := context.WithoutCancel(ctx)
+	errs := []error{rep.Err}
+	for i := len(done) - 1; i >= 0; i-- {
+		step := done[i]
+		if step.Undo == nil {
+			continue
+		}
+		if err := step.Undo(undoCtx, acc); err != nil {
+			slog.Warn("action rollback failed", slog.String("step", step.Name),
+				slog.String("account", acc.Username), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("undo %s: %w", step.Name, err))
+			continue
+		}
+		rep.RolledBack = append(rep.RolledBack, step.Name)
+	}
+	rep.Err = errors.Join(errs...)
+	return rep
+}
+
+// runStep runs one step, retrying it per p if it is idempotent.
+func runStep(ctx context.Context, acc *Account, step ActionStep, p RetryPolicy) error {
+	for attempt := 1; ; attempt++ {
+		err := step.Do(ctx, acc)
+		if err == nil || !step.Idempotent || attempt >= p.MaxAttempts ||
+			ctx.Err() != nil || errors.Is(err, ErrQuotaExceeded) {
+			return err
+		}
+		slog.Debug("action step failed, retrying", slog.String("step", step.Name),
+			slog.Int("attempt", attempt), slog.Any("error", err))
+		if werr := p.wait(ctx, attempt); werr != nil {
+			return err
+		}
+	}
+}
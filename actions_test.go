@@ -0,0 +1,72 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunActions(t *testing.T) {
+	c := &Client{}
+	acc := &Account{Username: "writer"}
+	fast := ActionsOptions{
+		MinDelay: time.Nanosecond, MaxDelay: time.Nanosecond,
+		Retry: RetryPolicy{MaxAttempts: 3, Backoff: stealth.BackoffConfig{InitialWait: time.Millisecond, MaxWait: time.Millisecond, Multiplier: 1}},
+	}
+	var log []string
+	step := func(name string, idempotent bool, fails int) ActionStep {
+		return ActionStep{
+			Name: name, Idempotent: idempotent,
+			Do: func(context.Context, *Account) error {
+				log = append(log, "do "+name)
+				if fails > 0 {
+					fails--
+					return errors.New("boom")
+				}
+				return nil
+			},
+			Undo: func(context.Context, *Account) error {
+				log = append(log, "undo "+name)
+				return nil
+			},
+		}
+	}
+
+	t.Run("idempotent steps are retried", func(t *testing.T) {
+		log = nil
+		rep := c.RunActions(context.Background(), acc, []ActionStep{step("tweet", false, 0), step("pin", true, 2)}, fast)
+		require.True(t, rep.OK(), rep.Err)
+		assert.Equal(t, []string{"tweet", "pin"}, rep.Completed)
+		assert.Equal(t, []string{"do tweet", "do pin", "do pin", "do pin"}, log)
+	})
+
+	t.Run("failure rolls back completed steps", func(t *testing.T) {
+		log = nil
+		steps := []ActionStep{step("upload", false, 0), step("tweet", false, 0), step("pin", false, 1)}
+		steps[0].Undo = nil
+		rep := c.RunActions(context.Background(), acc, steps, fast)
+		assert.False(t, rep.OK())
+		assert.Equal(t, []string{"upload", "tweet"}, rep.Completed)
+		assert.Equal(t, "pin", rep.Failed)
+		assert.Equal(t, []string{"tweet"}, rep.RolledBack)
+		assert.Equal(t, []string{"do upload", "do tweet", "do pin", "undo tweet"}, log)
+	})
+
+	t.Run("quota errors are not retried", func(t *testing.T) {
+		calls := 0
+		quota := ActionStep{Name: "follow", Idempotent: true, Do: func(context.Context, *Account) error {
+			calls++
+			return ErrQuotaExceeded
+		}}
+		opts := fast
+		opts.NoRollback = true
+		rep := c.RunActions(context.Background(), acc, []ActionStep{quota}, opts)
+		assert.ErrorIs(t, rep.Err, ErrQuotaExceeded)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,96 @@
+package twitter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// ActivityWindow is the time of day an account is in use, in its own
+// timezone, so it does not show round-the-clock activity. The zero value
+// means always active.
+type ActivityWindow struct {
+	Start time.Duration // since local midnight, e.g. 7*time.Hour
+	End   time.Duration // since local midnight; End < Start wraps past midnight
+	// Location is the account's timezone. Default: UTC.
+	Location *time.Location
+	// Jitter moves both ends by up to ±Jitter, by a different amount each
+	// day and account, so the pool does not wake up on the minute.
+	Jitter time.Duration
+}
+
+// ParseActivityWindow parses "HH:MM-HH:MM" with an optional IANA timezone,
+// e.g. "07:00-23:00 Europe/Berlin".
+func ParseActivityWindow(s string) (ActivityWindow, error) {
+	var w ActivityWindow
+	span, tz, _ := strings.Cut(strings.TrimSpace(s), " ")
+	from, to, ok := strings.Cut(span, "-")
+	if !ok {
+		return w, fmt.Errorf("activity window %q: want HH:MM-HH:MM", s)
+	}
+	var err error
+	if w.Start, err = parseClock(from); err != nil {
+		return w, fmt.Errorf("activity window %q: %w", s, err)
+	}
+	if w.End, err = parseClock(to); err != nil {
+		return w, fmt.Errorf("activity window %q: %w", s, err)
+	}
+	if tz = strings.TrimSpace(tz); tz != "" {
+		if w.Location, err = time.LoadLocation(tz); err != nil {
+			return w, fmt.Errorf("activity window %q: %w", s, err)
+		}
+	}
+	return w, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// IsZero reports whether w is unset, i.e. always active.
+func (w ActivityWindow) IsZero() bool {
+	return w.Start == 0 && w.End == 0
+}
+
+// Contains reports whether t falls inside the window. seed picks the day's
+// jitter; NewClient uses the username.
+func (w ActivityWindow) Contains(t time.Time, seed string) bool {
+	if w.IsZero() {
+		return true
+	}
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	day := midnight.Format(time.DateOnly)
+	start := w.Start + w.jitter(seed, day, "start")
+	end := w.End + w.jitter(seed, day, "end")
+	since := t.Sub(midnight)
+	if start <= end {
+		return since >= start && since < end
+	}
+	return since >= start || since < end
+}
+
+// jitter returns a stable offset in [-Jitter, Jitter] for one window edge.
+func (w ActivityWindow) jitter(seed, day, edge string) time.Duration {
+	if w.Jitter <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(seed + "\x00" + day + "\x00" + edge))
+	return time.Duration(h.Sum64()%uint64(2*w.Jitter+1)) - w.Jitter
+}
+
+// offHours reports whether the account is outside its ActiveHours at now.
+func (a *Account) offHours(now time.Time) bool {
+	return !a.ActiveHours.Contains(now, a.Username)
+}
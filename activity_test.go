@@ -0,0 +1,84 @@
+package twitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActivityWindow(t *testing.T) {
+	w, err := ParseActivityWindow("07:00-23:30 Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, 7*time.Hour, w.Start)
+	assert.Equal(t, 23*time.Hour+30*time.Minute, w.End)
+	require.NotNil(t, w.Location)
+	assert.Equal(t, "Europe/Berlin", w.Location.String())
+
+	w, err = ParseActivityWindow("22:00-06:00")
+	require.NoError(t, err)
+	assert.Nil(t, w.Location)
+
+	for _, bad := range []string{"7-23", "07:00", "07:00-25:00", "07:00-23:00 Mars/Olympus"} {
+		_, err := ParseActivityWindow(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestActivityWindow_Contains(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 3, 18, h, m, 0, 0, time.UTC) }
+
+	assert.True(t, ActivityWindow{}.Contains(at(3, 0), "a"), "zero window is always active")
+
+	day := ActivityWindow{Start: 7 * time.Hour, End: 23 * time.Hour}
+	assert.False(t, day.Contains(at(6, 59), "a"))
+	assert.True(t, day.Contains(at(7, 0), "a"))
+	assert.False(t, day.Contains(at(23, 0), "a"))
+
+	night := ActivityWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	assert.True(t, night.Contains(at(23, 0), "a"))
+	assert.True(t, night.Contains(at(5, 0), "a"))
+	assert.False(t, night.Contains(at(12, 0), "a"))
+
+	// 07:00-23:00 in New York is 11:00-03:00 UTC in March (EDT).
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	local := ActivityWindow{Start: 7 * time.Hour, End: 23 * time.Hour, Location: ny}
+	assert.False(t, local.Contains(at(10, 0), "a"))
+	assert.True(t, local.Contains(at(2, 0), "a"))
+}
+
+func TestActivityWindow_Jitter(t *testing.T) {
+	w := ActivityWindow{Start: 7 * time.Hour, End: 23 * time.Hour, Jitter: 30 * time.Minute}
+	edge := func(seed string) time.Duration {
+		return w.jitter(seed, "2025-03-18", "start")
+	}
+	assert.Equal(t, edge("alice"), edge("alice"), "stable within a day")
+	for _, seed := range []string{"alice", "bob", "carol"} {
+		j := edge(seed)
+		assert.LessOrEqual(t, j, 30*time.Minute)
+		assert.GreaterOrEqual(t, j, -30*time.Minute)
+	}
+	at := func(h, m int) time.Time { return time.Date(2025, 3, 18, h, m, 0, 0, time.UTC) }
+	assert.False(t, w.Contains(at(6, 29), "alice"))
+	assert.True(t, w.Contains(at(7, 31), "alice"))
+}
+
+func TestEligibleAccounts_OffHours(t *testing.T) {
+	hour := time.Now().UTC().Hour()
+	asleep := &Account{Username: "asleep", active: true, ActiveHours: ActivityWindow{
+		Start: time.Duration(hour+1) * time.Hour,
+		End:   time.Duration(hour+2) * time.Hour,
+	}}
+	if hour >= 22 {
+		asleep.ActiveHours = ActivityWindow{Start: time.Duration(hour-2) * time.Hour, End: time.Duration(hour-1) * time.Hour}
+	}
+	awake := &Account{Username: "awake", active: true}
+	c := &Client{pool: pool.New([]*Account{asleep, awake}, pool.Config{})}
+
+	assert.Equal(t, []*Account{awake}, c.eligibleAccounts("UserTweets"))
+	assert.True(t, asleep.snapshot().OffHours)
+	assert.False(t, awake.snapshot().OffHours)
+}
@@ -0,0 +1,188 @@
+package twitter
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// AdaptiveConfig bounds the adaptive concurrency controller. Every Interval it
+// compares the share of 429/403 responses against TargetThrottleRate: above
+// it, the in-flight limit is halved and extra jitter doubled; well below it,
+// the limit grows by one and extra jitter halves (AIMD).
+type AdaptiveConfig struct {
+	// MinConcurrency and MaxConcurrency bound in-flight requests.
+	// Defaults: 1 and 16.
+	MinConcurrency int
+	MaxConcurrency int
+
+	// Interval is the measurement window between adjustments. Default: 30s.
+	Interval time.Duration
+
+	// TargetThrottleRate is the tolerated share of 429/403 responses.
+	// Default: 0.05.
+	TargetThrottleRate float64
+
+	// MaxExtraJitter caps the random delay added on top of the built-in
+	// anti-fingerprint jitter while throttled. Default: 5s.
+	MaxExtraJitter time.Duration
+}
+
+func (cfg *AdaptiveConfig) defaults() {
+	if cfg.MinConcurrency <= 0 {
+		cfg.MinConcurrency = 1
+	}
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 16
+	}
+	if cfg.MaxConcurrency < cfg.MinConcurrency {
+		cfg.MaxConcurrency = cfg.MinConcurrency
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.TargetThrottleRate <= 0 {
+		cfg.TargetThrottleRate = 0.05
+	}
+	if cfg.MaxExtraJitter <= 0 {
+		cfg.MaxExtraJitter = 5 * time.Second
+	}
+}
+
+// minExtraJitter is the first step of extra jitter once throttling starts.
+const minExtraJitter = 250 * time.Millisecond
+
+// adaptiveController limits in-flight requests and tunes the limit from
+// observed throttling. A nil controller admits everything; all methods are
+// nil-safe.
+type adaptiveController struct {
+	cfg AdaptiveConfig
+
+	mu          sync.Mutex
+	limit       int
+	inFlight    int
+	wake        chan struct{} // closed and replaced whenever a slot frees up
+	windowStart time.Time
+	total       int
+	throttled   int
+	extraJitter time.Duration
+}
+
+func newAdaptiveController(cfg *AdaptiveConfig) *adaptiveController {
+	if cfg == nil {
+		return nil
+	}
+	a := &adaptiveController{cfg: *cfg}
+	a.cfg.defaults()
+	a.limit = a.cfg.MaxConcurrency
+	a.wake = make(chan struct{})
+	a.windowStart = time.Now()
+	return a
+}
+
+// acquire blocks until an in-flight slot is free or ctx is done.
+func (a *adaptiveController) acquire(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+	for {
+		a.mu.Lock()
+		if a.inFlight < a.limit {
+			a.inFlight++
+			a.mu.Unlock()
+			return nil
+		}
+		wake := a.wake
+		a.mu.Unlock()
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees a slot taken by acquire.
+func (a *adaptiveController) release() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	a.inFlight--
+	a.notifyLocked()
+	a.mu.Unlock()
+}
+
+func (a *adaptiveController) notifyLocked() {
+	close(a.wake)
+	a.wake = make(chan struct{})
+}
+
+// observe records an HTTP status and adjusts the limit once per interval.
+func (a *adaptiveController) observe(status int) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.total++
+	if status == 429 || status == 403 {
+		a.throttled++
+	}
+	if time.Since(a.windowStart) < a.cfg.Interval {
+		return
+	}
+
+	rate := float64(a.throttled) / float64(a.total)
+	prev := a.limit
+	switch {
+	case rate > a.cfg.TargetThrottleRate:
+		a.limit = max(a.cfg.MinConcurrency, a.limit/2)
+		a.extraJitter = min(a.cfg.MaxExtraJitter, max(minExtraJitter, a.extraJitter*2))
+	case rate <= a.cfg.TargetThrottleRate/2:
+		a.limit = min(a.cfg.MaxConcurrency, a.limit+1)
+		a.extraJitter /= 2
+		if a.extraJitter < minExtraJitter {
+			a.extraJitter = 0
+		}
+		a.notifyLocked()
+	}
+	if a.limit != prev {
+		slog.Info("adaptive concurrency adjusted",
+			slog.Int("limit", a.limit), slog.Int("prev", prev),
+			slog.Float64("throttle_rate", rate), slog.Duration("extra_jitter", a.extraJitter))
+	}
+	a.windowStart = time.Now()
+	a.total, a.throttled = 0, 0
+}
+
+// jitter sleeps for a random share of the current extra jitter.
+func (a *adaptiveController) jitter(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	d := a.extraJitter
+	a.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(rand.N(d)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// state returns the current limit, in-flight count, and extra jitter.
+func (a *adaptiveController) state() (limit, inFlight int, extraJitter time.Duration) {
+	if a == nil {
+		return 0, 0, 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.limit, a.inFlight, a.extraJitter
+}
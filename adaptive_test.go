@@ -0,0 +1,117 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveController_AIMD(t *testing.T) {
+	a := newAdaptiveController(&AdaptiveConfig{MinConcurrency: 2, MaxConcurrency: 8, Interval: time.Nanosecond})
+
+	// Throttled window halves the limit and starts extra jitter.
+	a.observe(429)
+	limit, _, jitter := a.state()
+	if limit != 4 || jitter != minExtraJitter {
+		t.Fatalf("after throttle: limit=%d jitter=%v", limit, jitter)
+	}
+	a.observe(403)
+	a.observe(429)
+	if limit, _, _ := a.state(); limit != 2 {
+		t.Fatalf("limit must not drop below min, got %d", limit)
+	}
+
+	// Clean windows grow the limit by one and decay jitter to zero.
+	for range 10 {
+		a.observe(200)
+	}
+	limit, _, jitter = a.state()
+	if limit != 8 || jitter != 0 {
+		t.Fatalf("after recovery: limit=%d jitter=%v", limit, jitter)
+	}
+}
+
+func TestAdaptiveController_Acquire(t *testing.T) {
+	a := newAdaptiveController(&AdaptiveConfig{MaxConcurrency: 1})
+	ctx := context.Background()
+	if err := a.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Second acquire blocks until release.
+	done := make(chan error, 1)
+	go func() { done <- a.acquire(ctx) }()
+	select {
+	case <-done:
+		t.Fatal("acquire should block at the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+	a.release()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := a.acquire(short); err == nil {
+		t.Fatal("acquire should fail when ctx expires")
+	}
+}
+
+func TestAdaptiveController_Nil(t *testing.T) {
+	var a *adaptiveController
+	if err := a.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	a.release()
+	a.observe(429)
+	if err := a.jitter(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdaptive_GatesWritesOnceAccountIsPicked(t *testing.T) {
+	tr := &tokenTransport{}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "alice", AuthToken: "tok-alice", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+		AdaptiveConcurrency:  &AdaptiveConfig{MaxConcurrency: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := c.AccountByUsername("alice")
+	if err := c.adaptive.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.doPOST(ctx, acc, "CreateTweet", Endpoints["CreateTweet"].URL(), []byte(`{}`)); err == nil {
+		t.Fatal("write sent past a full limit")
+	}
+	c.adaptive.release()
+	if _, err := c.doPOST(context.Background(), acc, "CreateTweet", Endpoints["CreateTweet"].URL(), []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.tokens) != 1 {
+		t.Fatalf("%d requests sent, want 1", len(tr.tokens))
+	}
+
+	// A read waiting for a rate-limited account holds no slot.
+	acc.MarkEndpointRateLimited("UserByScreenName", time.Now().Add(time.Hour))
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.doPoolRequest(ctx, "GET", "UserByScreenName", Endpoints["UserByScreenName"].URL(), nil)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if _, inFlight, _ := c.adaptive.state(); inFlight != 0 {
+		t.Fatalf("%d slots held while waiting for an account", inFlight)
+	}
+	<-done
+}
@@ -0,0 +1,99 @@
+package twitter
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// AdminHandler returns an http.Handler for inspecting the client remotely,
+// to mount in the operator's own server (under a prefix via
+// http.StripPrefix):
+//
+//	GET /healthz   200 while at least one account is active, 503 otherwise
+//	GET /accounts  PoolReport as JSON, proxy passwords redacted
+//	GET /limits    configured rate limits and each account's blocked endpoints
+//	GET /metrics   Prometheus metrics, as MetricsHandler
+//
+// It reveals account names and state; put it behind the same access control
+// as any other admin endpoint.
+func (c *Client) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", c.serveHealthz)
+	mux.HandleFunc("GET /accounts", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, c.PoolReport())
+	})
+	mux.HandleFunc("GET /limits", c.serveLimits)
+	mux.Handle("GET /metrics", c.MetricsHandler())
+	return mux
+}
+
+// adminHealth is the /healthz body.
+type adminHealth struct {
+	Status         string `json:"status"` // ok, degraded (guest tokens only), down
+	ActiveAccounts int    `json:"active_accounts"`
+	Accounts       int    `json:"accounts"`
+	GuestToken     bool   `json:"guest_token"`
+}
+
+func (c *Client) serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	stats := c.Stats()
+	h := adminHealth{Accounts: len(stats.Accounts), GuestToken: stats.GuestTokenAvailable}
+	for _, s := range stats.Accounts {
+		if s.Active && !s.OffHours {
+			h.ActiveAccounts++
+		}
+	}
+	status := http.StatusOK
+	switch {
+	case h.ActiveAccounts > 0:
+		h.Status = "ok"
+	case h.GuestToken && !c.cfg.DisableGuestFallback:
+		h.Status = "degraded"
+	default:
+		h.Status = "down"
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, h)
+}
+
+// adminLimit is one rate limit in /limits.
+type adminLimit struct {
+	Requests int    `json:"requests"`
+	Window   string `json:"window"`
+}
+
+// adminLimits is the /limits body.
+type adminLimits struct {
+	Default   adminLimit            `json:"default"`
+	Endpoints map[string]adminLimit `json:"endpoints"`
+	// RateLimited maps accounts to their blocked endpoints and when each
+	// window ends; accounts with none are left out.
+	RateLimited map[string]map[string]time.Time `json:"rate_limited"`
+}
+
+func (c *Client) serveLimits(w http.ResponseWriter, _ *http.Request) {
+	l := adminLimits{
+		Default:     adminLimit{c.cfg.RateLimit.RequestsPerWindow, c.cfg.RateLimit.WindowDuration.String()},
+		Endpoints:   make(map[string]adminLimit, len(c.cfg.EndpointLimits)),
+		RateLimited: make(map[string]map[string]time.Time),
+	}
+	for name, lc := range c.cfg.EndpointLimits {
+		l.Endpoints[name] = adminLimit{lc.RequestsPerWindow, lc.WindowDuration.String()}
+	}
+	for _, s := range c.Stats().Accounts {
+		if len(s.RateLimited) > 0 {
+			l.RateLimited[s.Username] = s.RateLimited
+		}
+	}
+	writeJSON(w, http.StatusOK, l)
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,59 @@
+package twitter
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/anatolykoptev/go-stealth/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler(t *testing.T) {
+	alice := &Account{Username: "alice", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	bob := &Account{Username: "bob", HealthTracker: pool.DefaultHealthTracker()}
+	c := &Client{
+		pool:    pool.New([]*Account{alice, bob}, pool.Config{}),
+		metrics: newMetrics(),
+		cfg: ClientConfig{
+			DisableGuestFallback: true,
+			RateLimit:            ratelimit.Config{RequestsPerWindow: 50, WindowDuration: 15 * time.Minute},
+			EndpointLimits:       map[string]ratelimit.Config{"Followers": {RequestsPerWindow: 10, WindowDuration: time.Minute}},
+		},
+	}
+	h := c.AdminHandler()
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec
+	}
+
+	rec := get("/healthz")
+	require.Equal(t, 200, rec.Code)
+	var health adminHealth
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+	assert.Equal(t, adminHealth{Status: "ok", ActiveAccounts: 1, Accounts: 2}, health)
+
+	alice.SetActive(false)
+	assert.Equal(t, 503, get("/healthz").Code)
+
+	rec = get("/accounts")
+	require.Equal(t, 200, rec.Code)
+	var rows []PoolReportRow
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rows))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "alice", rows[0].Username)
+
+	rec = get("/limits")
+	require.Equal(t, 200, rec.Code)
+	var limits adminLimits
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &limits))
+	assert.Equal(t, adminLimit{50, "15m0s"}, limits.Default)
+	assert.Equal(t, map[string]adminLimit{"Followers": {10, "1m0s"}}, limits.Endpoints)
+
+	assert.Equal(t, 200, get("/metrics").Code)
+	assert.Equal(t, 404, get("/other").Code)
+}
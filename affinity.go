@@ -0,0 +1,78 @@
+package twitter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type affinityKeyType struct{}
+
+// WithAffinity returns a context whose requests prefer the same pool account
+// as earlier requests with the same key, e.g. every page of one crawl. The
+// preference is dropped when that account is unavailable. Requires
+// ClientConfig.StickyAffinity.
+func WithAffinity(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, affinityKeyType{}, key)
+}
+
+// affinityKey returns the key set by WithAffinity, or "".
+func affinityKey(ctx context.Context) string {
+	k, _ := ctx.Value(affinityKeyType{}).(string)
+	return k
+}
+
+// withTargetAffinity tags ctx with a per-target key unless the caller already
+// chose one, so paginating a user's followers stays on one account.
+func (c *Client) withTargetAffinity(ctx context.Context, kind, id string) context.Context {
+	if !c.cfg.StickyAffinity || affinityKey(ctx) != "" {
+		return ctx
+	}
+	return WithAffinity(ctx, kind+":"+id)
+}
+
+// affinityTable maps affinity keys to the account that last served them.
+// Entries expire after ttl without use.
+type affinityTable struct {
+	mu      sync.Mutex
+	entries map[string]affinityEntry
+}
+
+type affinityEntry struct {
+	acc      *Account
+	lastUsed time.Time
+}
+
+// lookup returns the account bound to key, or nil if none or expired.
+func (t *affinityTable) lookup(key string, ttl time.Duration) *Account {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Since(e.lastUsed) > ttl {
+		delete(t.entries, key)
+		return nil
+	}
+	return e.acc
+}
+
+// bind records acc as the account serving key, sweeping expired entries
+// while the lock is held.
+func (t *affinityTable) bind(key string, acc *Account, ttl time.Duration) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[string]affinityEntry)
+	}
+	if _, ok := t.entries[key]; !ok {
+		for k, e := range t.entries {
+			if now.Sub(e.lastUsed) > ttl {
+				delete(t.entries, k)
+			}
+		}
+	}
+	t.entries[key] = affinityEntry{acc: acc, lastUsed: now}
+}
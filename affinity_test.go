@@ -0,0 +1,60 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextAccount_StickyAffinity(t *testing.T) {
+	a := &Account{Username: "a", active: true}
+	b := &Account{Username: "b", active: true}
+	c := &Client{
+		pool: pool.New([]*Account{a, b}, pool.Config{}),
+		cfg:  ClientConfig{StickyAffinity: true, AffinityTTL: time.Minute},
+	}
+	allow := func(*Account) bool { return true }
+	ctx := c.withTargetAffinity(context.Background(), "user", "42")
+
+	first, err := c.nextAccount(ctx, "Followers", allow, 0)
+	require.NoError(t, err)
+	for range 3 {
+		got, err := c.nextAccount(ctx, "Followers", allow, 0)
+		require.NoError(t, err)
+		assert.Same(t, first, got, "round-robin must not move a sticky target")
+	}
+
+	// When the bound account can't serve, another takes over and is rebound.
+	other, err := c.nextAccount(ctx, "Followers", func(x *Account) bool { return x != first }, 0)
+	require.NoError(t, err)
+	assert.NotSame(t, first, other)
+	got, err := c.nextAccount(ctx, "Followers", allow, 0)
+	require.NoError(t, err)
+	assert.Same(t, other, got)
+}
+
+func TestWithTargetAffinity(t *testing.T) {
+	ctx := context.Background()
+	off := &Client{}
+	assert.Empty(t, affinityKey(off.withTargetAffinity(ctx, "user", "1")))
+
+	on := &Client{cfg: ClientConfig{StickyAffinity: true}}
+	assert.Equal(t, "user:1", affinityKey(on.withTargetAffinity(ctx, "user", "1")))
+
+	// A caller-chosen key wins.
+	custom := WithAffinity(ctx, "crawl-7")
+	assert.Equal(t, "crawl-7", affinityKey(on.withTargetAffinity(custom, "user", "1")))
+}
+
+func TestAffinityTable_Expiry(t *testing.T) {
+	var tbl affinityTable
+	acc := &Account{Username: "a"}
+	tbl.bind("k", acc, time.Minute)
+	assert.Same(t, acc, tbl.lookup("k", time.Minute))
+	assert.Nil(t, tbl.lookup("k", 0), "expired binding must be dropped")
+	assert.Nil(t, tbl.lookup("k", time.Minute))
+}
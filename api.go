@@ -0,0 +1,78 @@
+package twitter
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// TwitterAPI is the read/write surface of *Client. Accept it instead of
+// *Client to inject a fake or a generated mock (gomock, counterfeiter) in
+// tests. Pool administration (Pool, Stats, CheckAccounts, metrics) stays on
+// *Client.
+type TwitterAPI interface {
+	// Users
+	GetUserByScreenName(ctx context.Context, handle string) (*TwitterUser, error)
+	GetUsersByScreenNames(ctx context.Context, handles []string, concurrency int, opts ...BulkOption) []UserResult
+	GetUsersByIDs(ctx context.Context, ids []string, concurrency int, opts ...BulkOption) []UserResult
+	GetUsersByIDsBatch(ctx context.Context, ids []string) ([]*TwitterUser, error)
+	GetFollowers(ctx context.Context, userID string, maxCount int) ([]*TwitterUser, error)
+	GetFollowerIDs(ctx context.Context, userID string, maxCount int) ([]string, error)
+	GetFollowing(ctx context.Context, userID string, maxCount int) ([]*TwitterUser, error)
+	GetFollowersPaged(ctx context.Context, userID, cursor string, maxCount int) PagedResult[*TwitterUser]
+	GetFollowingPaged(ctx context.Context, userID, cursor string, maxCount int) PagedResult[*TwitterUser]
+	FollowersJob(userID string, onPage func([]*TwitterUser) error) *Job[*TwitterUser]
+	FollowingJob(userID string, onPage func([]*TwitterUser) error) *Job[*TwitterUser]
+	Intersect(ctx context.Context, userA, userB string, maxPerUser int) ([]*TwitterUser, error)
+	Mutuals(ctx context.Context, userID string, maxCount int) ([]*TwitterUser, error)
+	SampleFollowersOfFollowers(ctx context.Context, userID string, opts FoFOptions) (*FoFSample, error)
+	GetRelationship(ctx context.Context, sourceID, targetID string) (*Relationship, error)
+	Typeahead(ctx context.Context, prefix string) ([]*TwitterUser, error)
+	CheckSearchBan(ctx context.Context, handle string) (bool, error)
+	CheckSuggestionBan(ctx context.Context, handle string) (bool, error)
+
+	// Tweets
+	GetUserTweets(ctx context.Context, userID string, count int) ([]*Tweet, error)
+	GetUserTweetsPaged(ctx context.Context, userID, cursor string, maxCount int, opts ...TweetPageOption) PagedResult[*Tweet]
+	Backfill(ctx context.Context, handle string, opts BackfillOptions, fn func([]*Tweet) error) (int, error)
+	SyncUserTweets(ctx context.Context, userID string, state *SyncState) ([]*Tweet, error)
+	GetTweetByID(ctx context.Context, tweetID string) (*Tweet, error)
+	GetTweetEdits(ctx context.Context, tweetID string) ([]*TweetRevision, error)
+	GetThread(ctx context.Context, tweetID string) ([]*Tweet, error)
+	GetRetweeters(ctx context.Context, tweetID string, maxCount int) ([]*TwitterUser, error)
+	GetRetweetersPaged(ctx context.Context, tweetID, cursor string, maxCount int) PagedResult[*TwitterUser]
+	SearchTimeline(ctx context.Context, query string, count int) ([]*Tweet, error)
+	SearchTimelinePaged(ctx context.Context, query, cursor string, maxCount int, opts ...TweetPageOption) PagedResult[*Tweet]
+	FetchColumns(ctx context.Context, cols []Column) ([][]*Tweet, error)
+	GetTrends(ctx context.Context, count int) ([]*Trend, error)
+	GraphQL(ctx context.Context, op Endpoint, variables, features map[string]any) ([]byte, error)
+
+	// Media
+	DownloadMedia(ctx context.Context, mediaURL string, w io.Writer) error
+	DownloadVideo(ctx context.Context, m Media, w io.Writer) error
+	DownloadHLS(ctx context.Context, playlistURL string, w io.Writer) error
+
+	// Streams
+	PollSearch(ctx context.Context, query string, interval time.Duration, opts ...PollOption) <-chan *Tweet
+	PollUserTweets(ctx context.Context, userID string, interval time.Duration, opts ...PollOption) <-chan *Tweet
+	WatchProfiles(ctx context.Context, userIDs []string, interval time.Duration, thresholds ...int) <-chan ProfileChange
+	TrackEngagement(ctx context.Context, tweetIDs []string, interval time.Duration) <-chan EngagementSnapshot
+
+	// Writes
+	AcquireWriteAccount(ctx context.Context, endpoint string) (*Account, func(), error)
+	RunActions(ctx context.Context, acc *Account, steps []ActionStep, opts ActionsOptions) *ActionsReport
+	CreateTweet(ctx context.Context, acc *Account, text string, opts ...TweetOption) (string, error)
+	CreateNoteTweet(ctx context.Context, acc *Account, text string, richtext ...RichTextTag) (string, error)
+	PostWithAccount(ctx context.Context, username, text string, opts ...TweetOption) (string, error)
+	VotePoll(ctx context.Context, acc *Account, cardURI, tweetID string, choice int) (*Poll, error)
+	CreateList(ctx context.Context, acc *Account, name, description string, private bool) (*TwitterList, error)
+	DeleteList(ctx context.Context, acc *Account, listID string) error
+	AddListMember(ctx context.Context, acc *Account, listID, userID string) error
+	RemoveListMember(ctx context.Context, acc *Account, listID, userID string) error
+	UpdateProfile(ctx context.Context, acc *Account, update ProfileUpdate) (*TwitterUser, error)
+	UpdateAvatar(ctx context.Context, acc *Account, image io.Reader) error
+	UpdateBanner(ctx context.Context, acc *Account, image io.Reader) error
+	Me(ctx context.Context, acc *Account) (*AccountInfo, error)
+}
+
+var _ TwitterAPI = (*Client)(nil)
@@ -0,0 +1,321 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiV2BaseURL = "https://api.x.com/2"
+
+// APIv2Config enables the official Twitter API v2 backend for the calls it
+// supports: GetUserByScreenName, GetTweetByID and SearchTimeline (recent
+// search, last 7 days). It needs an app bearer token from a paid API plan.
+type APIv2Config struct {
+	// BearerToken is the app-only bearer token from the developer portal.
+	BearerToken string
+
+	// Fallback serves supported calls from API v2 when the scraping pool is
+	// exhausted. Without it, API v2 is only used for calls made with
+	// WithAPIv2.
+	Fallback bool
+
+	// BaseURL overrides the API root. Default: https://api.x.com/2.
+	BaseURL string
+
+	// HTTP overrides the HTTP client. Default: 30s timeout client.
+	HTTP *http.Client
+}
+
+// ErrPoolExhausted is wrapped by errors returned when no pool account could
+// serve a request and the guest fallback, if the endpoint has one, failed too.
+var ErrPoolExhausted = errors.New("pool exhausted")
+
+type apiV2Key struct{}
+
+// WithAPIv2 returns a context that sends supported calls straight to the
+// official API v2 backend (ClientConfig.APIv2) instead of the scraping pool.
+func WithAPIv2(ctx context.Context) context.Context {
+	return context.WithValue(ctx, apiV2Key{}, true)
+}
+
+// apiV2For reports whether a supported call should go to API v2 right away.
+func (c *Client) apiV2For(ctx context.Context) bool {
+	v, _ := ctx.Value(apiV2Key{}).(bool)
+	return v && c.cfg.APIv2 != nil
+}
+
+// apiV2Fallback reports whether a supported call that failed with err should
+// be retried on API v2.
+func (c *Client) apiV2Fallback(ctx context.Context, err error) bool {
+	return c.cfg.APIv2 != nil && c.cfg.APIv2.Fallback && ctx.Err() == nil &&
+		requestOptionsFrom(ctx).Account == "" && errors.Is(err, ErrPoolExhausted)
+}
+
+const (
+	apiV2UserFields  = "created_at,description,public_metrics,verified,profile_image_url,location,url,entities,pinned_tweet_id,protected"
+	apiV2TweetFields = "created_at,public_metrics,author_id,edit_history_tweet_ids,lang,geo"
+)
+
+type apiV2User struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Username        string    `json:"username"`
+	Description     string    `json:"description"`
+	CreatedAt       time.Time `json:"created_at"`
+	Verified        bool      `json:"verified"`
+	ProfileImageURL string    `json:"profile_image_url"`
+	Location        string    `json:"location"`
+	URL             string    `json:"url"`
+	PinnedTweetID   string    `json:"pinned_tweet_id"`
+	Protected       bool      `json:"protected"`
+	Entities        struct {
+		URL struct {
+			URLs []struct {
+				ExpandedURL string `json:"expanded_url"`
+			} `json:"urls"`
+		} `json:"url"`
+	} `json:"entities"`
+	PublicMetrics struct {
+		Followers int64 `json:"followers_count"`
+		Following int64 `json:"following_count"`
+		Tweets    int64 `json:"tweet_count"`
+		Listed    int64 `json:"listed_count"`
+	} `json:"public_metrics"`
+}
+
+func (u *apiV2User) toUser() *TwitterUser {
+	link := u.URL
+	if urls := u.Entities.URL.URLs; len(urls) > 0 && urls[0].ExpandedURL != "" {
+		link = urls[0].ExpandedURL
+	}
+	return &TwitterUser{
+		ID:          u.ID,
+		Handle:      u.Username,
+		DisplayName: u.Name,
+		Bio:         u.Description,
+		Followers:   u.PublicMetrics.Followers,
+		Following:   u.PublicMetrics.Following,
+		TweetCount:  u.PublicMetrics.Tweets,
+		ListedCount: u.PublicMetrics.Listed,
+		CreatedAt:   u.CreatedAt,
+		IsVerified:  u.Verified,
+		HasAvatar:   u.ProfileImageURL != "" && !strings.Contains(u.ProfileImageURL, "default_profile"),
+		HasBio:      u.Description != "",
+		AvatarURL:   u.ProfileImageURL,
+
+		Location:      u.Location,
+		URL:           link,
+		PinnedTweetID: u.PinnedTweetID,
+		Protected:     u.Protected,
+	}
+}
+
+type apiV2Tweet struct {
+	ID            string    `json:"id"`
+	Text          string    `json:"text"`
+	AuthorID      string    `json:"author_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	EditHistory   []string  `json:"edit_history_tweet_ids"`
+	PublicMetrics struct {
+		Retweets    int64 `json:"retweet_count"`
+		Replies     int64 `json:"reply_count"`
+		Likes       int64 `json:"like_count"`
+		Quotes      int64 `json:"quote_count"`
+		Impressions int64 `json:"impression_count"`
+	} `json:"public_metrics"`
+	Lang string `json:"lang"`
+	Geo  struct {
+		PlaceID     string        `json:"place_id"`
+		Coordinates *geoJSONPoint `json:"coordinates"`
+	} `json:"geo"`
+}
+
+func (t *apiV2Tweet) toTweet(authors map[string]apiV2User) *Tweet {
+	tw := &Tweet{
+		ID:            t.ID,
+		AuthorID:      t.AuthorID,
+		Text:          t.Text,
+		CreatedAt:     t.CreatedAt,
+		Views:         t.PublicMetrics.Impressions,
+		Likes:         t.PublicMetrics.Likes,
+		Retweets:      t.PublicMetrics.Retweets,
+		Quotes:        t.PublicMetrics.Quotes,
+		ReplyCount:    t.PublicMetrics.Replies,
+		TokenMentions: extractTokenMentions(t.Text),
+		Lang:          t.Lang,
+		Coordinates:   t.Geo.Coordinates.toGeoPoint(),
+	}
+	if t.Geo.PlaceID != "" {
+		// Place details need the geo.place_id expansion; the ID alone
+		// already works as a search filter.
+		tw.Place = &Place{ID: t.Geo.PlaceID}
+	}
+	if len(t.EditHistory) > 1 {
+		tw.EditIDs = t.EditHistory
+	}
+	if a, ok := authors[t.AuthorID]; ok {
+		tw.AuthorHandle = a.Username
+		tw.AuthorName = a.Name
+	}
+	return tw
+}
+
+// apiV2Errors is the error list of an API v2 response.
+type apiV2Errors []struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+func (e apiV2Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	if e[0].Detail != "" {
+		return fmt.Errorf("api v2: %s", e[0].Detail)
+	}
+	return fmt.Errorf("api v2: %s", e[0].Title)
+}
+
+type apiV2Includes struct {
+	Users []apiV2User `json:"users"`
+}
+
+func (i apiV2Includes) authors() map[string]apiV2User {
+	m := make(map[string]apiV2User, len(i.Users))
+	for _, u := range i.Users {
+		m[u.ID] = u
+	}
+	return m
+}
+
+// apiV2Get calls path on the API v2 backend and decodes the JSON body into out.
+func (c *Client) apiV2Get(ctx context.Context, endpoint, path string, query url.Values, out any) error {
+	cfg := c.cfg.APIv2
+	if cfg == nil {
+		return errors.New("api v2 backend not configured")
+	}
+	base := strings.TrimRight(cfg.BaseURL, "/")
+	if base == "" {
+		base = apiV2BaseURL
+	}
+	hc := cfg.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+cfg.BearerToken)
+
+	if err := c.waitGlobal(ctx); err != nil {
+		return err
+	}
+	requestInfoFrom(ctx).served(SourceAPIv2, "")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("api v2 %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	body, err := readLimited(resp.Body, c.cfg.MaxResponseBytes)
+	if err != nil {
+		return fmt.Errorf("api v2 %s: read body: %w", endpoint, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		c.recordAPICall("v2/"+endpoint, resp.StatusCode, false, resp.StatusCode == http.StatusTooManyRequests)
+		return fmt.Errorf("api v2 %s HTTP %d: %s", endpoint, resp.StatusCode, truncateBytes(body, 200))
+	}
+	c.recordAPICall("v2/"+endpoint, resp.StatusCode, true, false)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("api v2 %s: decode: %w", endpoint, err)
+	}
+	return nil
+}
+
+// apiV2UserByScreenName looks up a user by handle.
+func (c *Client) apiV2UserByScreenName(ctx context.Context, handle string) (*TwitterUser, error) {
+	var resp struct {
+		Data   *apiV2User  `json:"data"`
+		Errors apiV2Errors `json:"errors"`
+	}
+	err := c.apiV2Get(ctx, "UserByScreenName", "/users/by/username/"+url.PathEscape(handle),
+		url.Values{"user.fields": {apiV2UserFields}}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		if err := resp.Errors.err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("api v2: user %s not found", handle)
+	}
+	return resp.Data.toUser(), nil
+}
+
+// apiV2TweetByID looks up a single tweet.
+func (c *Client) apiV2TweetByID(ctx context.Context, tweetID string) (*Tweet, error) {
+	var resp struct {
+		Data     *apiV2Tweet   `json:"data"`
+		Includes apiV2Includes `json:"includes"`
+		Errors   apiV2Errors   `json:"errors"`
+	}
+	err := c.apiV2Get(ctx, "TweetDetail", "/tweets/"+url.PathEscape(tweetID), url.Values{
+		"tweet.fields": {apiV2TweetFields},
+		"expansions":   {"author_id"},
+		"user.fields":  {"username,name"},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		if err := resp.Errors.err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("api v2: tweet %s not found", tweetID)
+	}
+	return c.enrichTweet(resp.Data.toTweet(resp.Includes.authors()), nil)
+}
+
+// apiV2SearchRecent runs a recent search (last 7 days). API v2 returns
+// between 10 and 100 results per request, so count is clamped to that range
+// and the result trimmed back to count.
+func (c *Client) apiV2SearchRecent(ctx context.Context, query string, count int) ([]*Tweet, error) {
+	var resp struct {
+		Data     []apiV2Tweet  `json:"data"`
+		Includes apiV2Includes `json:"includes"`
+		Errors   apiV2Errors   `json:"errors"`
+	}
+	err := c.apiV2Get(ctx, "SearchTimeline", "/tweets/search/recent", url.Values{
+		"query":        {query},
+		"max_results":  {strconv.Itoa(min(max(count, 10), 100))},
+		"tweet.fields": {apiV2TweetFields},
+		"expansions":   {"author_id"},
+		"user.fields":  {"username,name"},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		if err := resp.Errors.err(); err != nil {
+			return nil, err
+		}
+	}
+	authors := resp.Includes.authors()
+	tweets := make([]*Tweet, 0, len(resp.Data))
+	for i := range resp.Data {
+		if count > 0 && len(tweets) == count {
+			break
+		}
+		tweets = append(tweets, resp.Data[i].toTweet(authors))
+	}
+	return c.enrichTweets(tweets, nil)
+}
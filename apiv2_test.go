@@ -0,0 +1,86 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAPIv2Server(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer app-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/users/by/username/jack":
+			fmt.Fprint(w, `{"data":{"id":"12","name":"jack","username":"jack","description":"bio",
+				"created_at":"2006-03-21T20:50:14.000Z","profile_image_url":"https://pbs.twimg.com/a.jpg",
+				"public_metrics":{"followers_count":5,"following_count":6,"tweet_count":7,"listed_count":8}}}`)
+		case "/tweets/20":
+			fmt.Fprint(w, `{"data":{"id":"20","text":"just setting up my twttr $BTC","author_id":"12",
+				"created_at":"2006-03-21T20:50:14.000Z","edit_history_tweet_ids":["20"],
+				"public_metrics":{"retweet_count":1,"reply_count":2,"like_count":3,"quote_count":4,"impression_count":5}},
+				"includes":{"users":[{"id":"12","name":"jack","username":"jack"}]}}`)
+		case "/tweets/search/recent":
+			assert.Equal(t, "10", r.URL.Query().Get("max_results"))
+			fmt.Fprint(w, `{"data":[{"id":"1","text":"a","author_id":"12"},{"id":"2","text":"b","author_id":"12"}]}`)
+		case "/users/by/username/ghost":
+			fmt.Fprint(w, `{"errors":[{"title":"Not Found Error","detail":"Could not find user with username: [ghost]."}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAPIv2_PerRequest(t *testing.T) {
+	srv := newAPIv2Server(t)
+	c := &Client{cfg: ClientConfig{APIv2: &APIv2Config{BearerToken: "app-token", BaseURL: srv.URL}}}
+	info := &RequestInfo{}
+	ctx := WithRequestInfo(WithAPIv2(context.Background()), info)
+
+	u, err := c.GetUserByScreenName(ctx, "@Jack")
+	require.NoError(t, err)
+	assert.Equal(t, "12", u.ID)
+	assert.Equal(t, int64(5), u.Followers)
+	assert.Equal(t, 2006, u.CreatedAt.Year())
+	assert.True(t, u.HasAvatar)
+	assert.Equal(t, SourceAPIv2, info.Source())
+
+	tw, err := c.GetTweetByID(ctx, "20")
+	require.NoError(t, err)
+	assert.Equal(t, "jack", tw.AuthorHandle)
+	assert.Equal(t, int64(5), tw.Views)
+	assert.Equal(t, []string{"BTC"}, tw.TokenMentions)
+	assert.Nil(t, tw.EditIDs)
+
+	tweets, err := c.SearchTimeline(ctx, "twttr", 1)
+	require.NoError(t, err)
+	require.Len(t, tweets, 1, "trimmed back to count")
+
+	_, err = c.GetUserByScreenName(ctx, "ghost")
+	assert.ErrorContains(t, err, "Could not find user")
+}
+
+func TestAPIv2_FallbackWhenPoolExhausted(t *testing.T) {
+	srv := newAPIv2Server(t)
+	c := &Client{
+		pool: pool.New([]*Account{}, pool.Config{}),
+		cfg:  ClientConfig{APIv2: &APIv2Config{BearerToken: "app-token", BaseURL: srv.URL, Fallback: true}},
+	}
+	info := &RequestInfo{}
+	u, err := c.GetUserByScreenName(WithRequestInfo(context.Background(), info), "jack")
+	require.NoError(t, err)
+	assert.Equal(t, "jack", u.Handle)
+	assert.Equal(t, SourceAPIv2, info.Source())
+
+	c.cfg.APIv2.Fallback = false
+	_, err = c.GetUserByScreenName(context.Background(), "jack")
+	assert.ErrorIs(t, err, ErrPoolExhausted)
+}
@@ -0,0 +1,541 @@
+// Package archive keeps tweets, users and engagement time series in a local
+// SQLite database, for monitors that need history beyond what the API
+// returns.
+//
+// The package does not link a SQLite driver; import the one you prefer and
+// pass its name to Open:
+//
+//	import _ "modernc.org/sqlite"
+//
+//	a, err := archive.Open(ctx, "sqlite", "tweets.db")
+//	go a.Consume(ctx, client.PollSearch(ctx, "$BTC", time.Minute))
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+// ErrNotFound is returned when a tweet or user is not in the archive.
+var ErrNotFound = errors.New("not found in archive")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS tweets (
+	id            TEXT PRIMARY KEY,
+	author_id     TEXT NOT NULL,
+	author_handle TEXT NOT NULL,
+	author_name   TEXT NOT NULL,
+	text          TEXT NOT NULL,
+	created_at    INTEGER NOT NULL,
+	views         INTEGER NOT NULL,
+	likes         INTEGER NOT NULL,
+	retweets      INTEGER NOT NULL,
+	quotes        INTEGER NOT NULL,
+	replies       INTEGER NOT NULL,
+	edit_ids      TEXT,
+	entities      TEXT,
+	archived_at   INTEGER NOT NULL,
+	conversation_id     TEXT NOT NULL DEFAULT '',
+	in_reply_to_id      TEXT NOT NULL DEFAULT '',
+	in_reply_to_user_id TEXT NOT NULL DEFAULT '',
+	lang                TEXT NOT NULL DEFAULT '',
+	media               TEXT,
+	place               TEXT,
+	coordinates         TEXT
+);
+CREATE INDEX IF NOT EXISTS tweets_author ON tweets (author_id, created_at);
+CREATE INDEX IF NOT EXISTS tweets_created ON tweets (created_at);
+CREATE TABLE IF NOT EXISTS tweet_tokens (
+	tweet_id TEXT NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+	token    TEXT NOT NULL,
+	PRIMARY KEY (token, tweet_id)
+);
+CREATE TABLE IF NOT EXISTS users (
+	id           TEXT PRIMARY KEY,
+	handle       TEXT NOT NULL,
+	display_name TEXT NOT NULL,
+	bio          TEXT NOT NULL,
+	followers    INTEGER NOT NULL,
+	following    INTEGER NOT NULL,
+	tweet_count  INTEGER NOT NULL,
+	listed_count INTEGER NOT NULL,
+	created_at   INTEGER NOT NULL,
+	is_verified  INTEGER NOT NULL,
+	avatar_url   TEXT NOT NULL,
+	archived_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS users_handle ON users (handle COLLATE NOCASE);
+CREATE TABLE IF NOT EXISTS engagement (
+	tweet_id          TEXT NOT NULL,
+	at                INTEGER NOT NULL,
+	views             INTEGER NOT NULL,
+	likes             INTEGER NOT NULL,
+	retweets          INTEGER NOT NULL,
+	quotes            INTEGER NOT NULL,
+	replies           INTEGER NOT NULL,
+	views_per_hour    REAL NOT NULL,
+	likes_per_hour    REAL NOT NULL,
+	retweets_per_hour REAL NOT NULL,
+	PRIMARY KEY (tweet_id, at)
+);
+`
+
+// Archive stores tweets and users in a SQLite database. Saving a tweet or
+// user that is already stored updates it in place, so counters stay current
+// and each ID appears once. Safe for concurrent use.
+type Archive struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at dsn with the registered driver and
+// prepares the schema.
+func Open(ctx context.Context, driver, dsn string) (*Archive, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	a, err := New(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// New prepares the schema in db, an open SQLite database.
+func New(ctx context.Context, db *sql.DB) (*Archive, error) {
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		return nil, fmt.Errorf("create archive schema: %w", err)
+	}
+	if err := addTweetColumns(ctx, db); err != nil {
+		return nil, err
+	}
+	return &Archive{db: db}, nil
+}
+
+// laterTweetColumns are the tweets columns archives created before them
+// lack; addTweetColumns adds them.
+var laterTweetColumns = []struct{ name, decl string }{
+	{"conversation_id", "TEXT NOT NULL DEFAULT ''"},
+	{"in_reply_to_id", "TEXT NOT NULL DEFAULT ''"},
+	{"in_reply_to_user_id", "TEXT NOT NULL DEFAULT ''"},
+	{"lang", "TEXT NOT NULL DEFAULT ''"},
+	{"media", "TEXT"},
+	{"place", "TEXT"},
+	{"coordinates", "TEXT"},
+}
+
+// addTweetColumns upgrades the tweets table of an older archive.
+func addTweetColumns(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info('tweets')`)
+	if err != nil {
+		return fmt.Errorf("read archive schema: %w", err)
+	}
+	have := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("read archive schema: %w", err)
+		}
+		have[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read archive schema: %w", err)
+	}
+	for _, c := range laterTweetColumns {
+		if have[c.name] {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, `ALTER TABLE tweets ADD COLUMN `+c.name+` `+c.decl); err != nil {
+			return fmt.Errorf("add archive column %s: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// DB returns the underlying database, for queries the helpers don't cover.
+func (a *Archive) DB() *sql.DB { return a.db }
+
+// Close closes the database.
+func (a *Archive) Close() error { return a.db.Close() }
+
+const upsertTweet = `
+INSERT INTO tweets (id, author_id, author_handle, author_name, text, created_at,
+	views, likes, retweets, quotes, replies, edit_ids, entities, archived_at,
+	conversation_id, in_reply_to_id, in_reply_to_user_id, lang, media, place, coordinates)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	author_handle = excluded.author_handle,
+	author_name   = excluded.author_name,
+	text          = excluded.text,
+	views         = max(views, excluded.views), -- not every source reports views
+	likes         = excluded.likes,
+	retweets      = excluded.retweets,
+	quotes        = excluded.quotes,
+	replies       = excluded.replies,
+	edit_ids      = coalesce(excluded.edit_ids, edit_ids),
+	entities      = coalesce(excluded.entities, entities),
+	archived_at   = excluded.archived_at,
+	-- sources that leave these out keep what another one reported
+	conversation_id     = coalesce(nullif(excluded.conversation_id, ''), conversation_id),
+	in_reply_to_id      = coalesce(nullif(excluded.in_reply_to_id, ''), in_reply_to_id),
+	in_reply_to_user_id = coalesce(nullif(excluded.in_reply_to_user_id, ''), in_reply_to_user_id),
+	lang                = coalesce(nullif(excluded.lang, ''), lang),
+	media               = coalesce(excluded.media, media),
+	place               = coalesce(excluded.place, place),
+	coordinates         = coalesce(excluded.coordinates, coordinates)`
+
+// SaveTweets stores tweets in one transaction.
+func (a *Archive) SaveTweets(ctx context.Context, tweets ...*twitter.Tweet) error {
+	return a.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().Unix()
+		for _, t := range tweets {
+			if t == nil || t.ID == "" {
+				continue
+			}
+			editIDs, err := encodeJSON(t.EditIDs)
+			if err != nil {
+				return err
+			}
+			entities, err := encodeJSON(t.Entities)
+			if err != nil {
+				return err
+			}
+			media, err := encodeJSON(t.Media)
+			if err != nil {
+				return err
+			}
+			place, err := encodeJSON(t.Place)
+			if err != nil {
+				return err
+			}
+			coords, err := encodeJSON(t.Coordinates)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, upsertTweet,
+				t.ID, t.AuthorID, t.AuthorHandle, t.AuthorName, t.Text, t.CreatedAt.Unix(),
+				t.Views, t.Likes, t.Retweets, t.Quotes, t.ReplyCount, editIDs, entities, now,
+				t.ConversationID, t.InReplyToID, t.InReplyToUserID, t.Lang, media, place, coords,
+			); err != nil {
+				return fmt.Errorf("save tweet %s: %w", t.ID, err)
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM tweet_tokens WHERE tweet_id = ?`, t.ID); err != nil {
+				return fmt.Errorf("save tweet %s tokens: %w", t.ID, err)
+			}
+			for _, tok := range t.TokenMentions {
+				if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO tweet_tokens (tweet_id, token) VALUES (?, ?)`,
+					t.ID, normalizeToken(tok)); err != nil {
+					return fmt.Errorf("save tweet %s tokens: %w", t.ID, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+const upsertUser = `
+INSERT INTO users (id, handle, display_name, bio, followers, following, tweet_count,
+	listed_count, created_at, is_verified, avatar_url, archived_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	handle       = excluded.handle,
+	display_name = excluded.display_name,
+	bio          = excluded.bio,
+	followers    = excluded.followers,
+	following    = excluded.following,
+	tweet_count  = excluded.tweet_count,
+	listed_count = excluded.listed_count,
+	is_verified  = excluded.is_verified,
+	avatar_url   = excluded.avatar_url,
+	archived_at  = excluded.archived_at`
+
+// SaveUsers stores users in one transaction.
+func (a *Archive) SaveUsers(ctx context.Context, users ...*twitter.TwitterUser) error {
+	return a.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().Unix()
+		for _, u := range users {
+			if u == nil || u.ID == "" {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, upsertUser,
+				u.ID, u.Handle, u.DisplayName, u.Bio, u.Followers, u.Following, u.TweetCount,
+				u.ListedCount, u.CreatedAt.Unix(), u.IsVerified, u.AvatarURL, now,
+			); err != nil {
+				return fmt.Errorf("save user %s: %w", u.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Consume saves tweets from ch, typically a PollSearch or PollUserTweets
+// subscription, until ch is closed or ctx is done. Each tweet is saved as it
+// arrives; the first failure stops consumption and is returned.
+func (a *Archive) Consume(ctx context.Context, ch <-chan *twitter.Tweet) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case t, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := a.SaveTweets(ctx, t); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SaveEngagement stores engagement snapshots in one transaction; a second
+// snapshot of a tweet at the same second replaces the first.
+func (a *Archive) SaveEngagement(ctx context.Context, snaps ...twitter.EngagementSnapshot) error {
+	return a.inTx(ctx, func(tx *sql.Tx) error {
+		for _, s := range snaps {
+			if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO engagement (tweet_id, at, views, likes,
+				retweets, quotes, replies, views_per_hour, likes_per_hour, retweets_per_hour)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				s.TweetID, s.At.Unix(), s.Views, s.Likes, s.Retweets, s.Quotes, s.Replies,
+				s.ViewsPerHour, s.LikesPerHour, s.RetweetsPerHour,
+			); err != nil {
+				return fmt.Errorf("save engagement of %s: %w", s.TweetID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ConsumeEngagement saves snapshots from ch, typically a TrackEngagement
+// stream, until ch is closed or ctx is done. The first failure stops
+// consumption and is returned.
+func (a *Archive) ConsumeEngagement(ctx context.Context, ch <-chan twitter.EngagementSnapshot) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case s, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := a.SaveEngagement(ctx, s); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Engagement returns the stored snapshots of tweetID, oldest first.
+func (a *Archive) Engagement(ctx context.Context, tweetID string) ([]twitter.EngagementSnapshot, error) {
+	rows, err := a.db.QueryContext(ctx, `SELECT at, views, likes, retweets, quotes, replies,
+		views_per_hour, likes_per_hour, retweets_per_hour
+		FROM engagement WHERE tweet_id = ? ORDER BY at`, tweetID)
+	if err != nil {
+		return nil, fmt.Errorf("query engagement: %w", err)
+	}
+	defer rows.Close()
+	var out []twitter.EngagementSnapshot
+	for rows.Next() {
+		s := twitter.EngagementSnapshot{TweetID: tweetID}
+		var at int64
+		if err := rows.Scan(&at, &s.Views, &s.Likes, &s.Retweets, &s.Quotes, &s.Replies,
+			&s.ViewsPerHour, &s.LikesPerHour, &s.RetweetsPerHour); err != nil {
+			return nil, fmt.Errorf("scan engagement: %w", err)
+		}
+		s.At = fromUnix(at)
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query engagement: %w", err)
+	}
+	return out, nil
+}
+
+const selectTweet = `SELECT t.id, t.author_id, t.author_handle, t.author_name, t.text, t.created_at,
+	t.views, t.likes, t.retweets, t.quotes, t.replies, t.edit_ids, t.entities,
+	t.conversation_id, t.in_reply_to_id, t.in_reply_to_user_id, t.lang, t.media, t.place, t.coordinates,
+	(SELECT group_concat(token, ' ') FROM tweet_tokens WHERE tweet_id = t.id)
+FROM tweets t`
+
+// Tweet returns the stored tweet with id, or ErrNotFound.
+func (a *Archive) Tweet(ctx context.Context, id string) (*twitter.Tweet, error) {
+	tweets, err := a.queryTweets(ctx, selectTweet+` WHERE t.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(tweets) == 0 {
+		return nil, ErrNotFound
+	}
+	return tweets[0], nil
+}
+
+// TweetsByAuthor returns authorID's stored tweets, newest first. limit <= 0
+// returns all of them.
+func (a *Archive) TweetsByAuthor(ctx context.Context, authorID string, limit int) ([]*twitter.Tweet, error) {
+	return a.queryTweets(ctx, selectTweet+` WHERE t.author_id = ? ORDER BY t.created_at DESC, t.id DESC LIMIT ?`,
+		authorID, sqlLimit(limit))
+}
+
+// TweetsBetween returns stored tweets created in [from, to), newest first. A
+// zero from or to leaves that end open. limit <= 0 returns all of them.
+func (a *Archive) TweetsBetween(ctx context.Context, from, to time.Time, limit int) ([]*twitter.Tweet, error) {
+	lo, hi := int64(0), int64(1<<62)
+	if !from.IsZero() {
+		lo = from.Unix()
+	}
+	if !to.IsZero() {
+		hi = to.Unix()
+	}
+	return a.queryTweets(ctx, selectTweet+` WHERE t.created_at >= ? AND t.created_at < ? ORDER BY t.created_at DESC, t.id DESC LIMIT ?`,
+		lo, hi, sqlLimit(limit))
+}
+
+// TweetsMentioning returns stored tweets mentioning token ("BTC" or "$btc"),
+// newest first. limit <= 0 returns all of them.
+func (a *Archive) TweetsMentioning(ctx context.Context, token string, limit int) ([]*twitter.Tweet, error) {
+	return a.queryTweets(ctx, selectTweet+` JOIN tweet_tokens k ON k.tweet_id = t.id WHERE k.token = ? ORDER BY t.created_at DESC, t.id DESC LIMIT ?`,
+		normalizeToken(token), sqlLimit(limit))
+}
+
+// User returns the stored user with id, or ErrNotFound.
+func (a *Archive) User(ctx context.Context, id string) (*twitter.TwitterUser, error) {
+	return a.queryUser(ctx, `WHERE id = ?`, id)
+}
+
+// UserByHandle returns the stored user with handle (case-insensitive, with
+// or without "@"), or ErrNotFound.
+func (a *Archive) UserByHandle(ctx context.Context, handle string) (*twitter.TwitterUser, error) {
+	return a.queryUser(ctx, `WHERE handle = ? COLLATE NOCASE`, strings.TrimPrefix(handle, "@"))
+}
+
+func (a *Archive) queryUser(ctx context.Context, where string, arg any) (*twitter.TwitterUser, error) {
+	row := a.db.QueryRowContext(ctx, `SELECT id, handle, display_name, bio, followers, following,
+		tweet_count, listed_count, created_at, is_verified, avatar_url FROM users `+where, arg)
+	var u twitter.TwitterUser
+	var created int64
+	err := row.Scan(&u.ID, &u.Handle, &u.DisplayName, &u.Bio, &u.Followers, &u.Following,
+		&u.TweetCount, &u.ListedCount, &created, &u.IsVerified, &u.AvatarURL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query user: %w", err)
+	}
+	u.CreatedAt = fromUnix(created)
+	u.HasBio = u.Bio != ""
+	u.HasAvatar = u.AvatarURL != ""
+	return &u, nil
+}
+
+func (a *Archive) queryTweets(ctx context.Context, query string, args ...any) ([]*twitter.Tweet, error) {
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query tweets: %w", err)
+	}
+	defer rows.Close()
+	var out []*twitter.Tweet
+	for rows.Next() {
+		var t twitter.Tweet
+		var created int64
+		var editIDs, entities, media, place, coords, tokens sql.NullString
+		if err := rows.Scan(&t.ID, &t.AuthorID, &t.AuthorHandle, &t.AuthorName, &t.Text, &created,
+			&t.Views, &t.Likes, &t.Retweets, &t.Quotes, &t.ReplyCount, &editIDs, &entities,
+			&t.ConversationID, &t.InReplyToID, &t.InReplyToUserID, &t.Lang, &media, &place, &coords,
+			&tokens); err != nil {
+			return nil, fmt.Errorf("scan tweet: %w", err)
+		}
+		t.CreatedAt = fromUnix(created)
+		if err := decodeJSON(editIDs, &t.EditIDs); err != nil {
+			return nil, fmt.Errorf("tweet %s edit ids: %w", t.ID, err)
+		}
+		if err := decodeJSON(entities, &t.Entities); err != nil {
+			return nil, fmt.Errorf("tweet %s entities: %w", t.ID, err)
+		}
+		if err := decodeJSON(media, &t.Media); err != nil {
+			return nil, fmt.Errorf("tweet %s media: %w", t.ID, err)
+		}
+		if err := decodeJSON(place, &t.Place); err != nil {
+			return nil, fmt.Errorf("tweet %s place: %w", t.ID, err)
+		}
+		if err := decodeJSON(coords, &t.Coordinates); err != nil {
+			return nil, fmt.Errorf("tweet %s coordinates: %w", t.ID, err)
+		}
+		if tokens.String != "" {
+			t.TokenMentions = strings.Fields(tokens.String)
+		}
+		out = append(out, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query tweets: %w", err)
+	}
+	return out, nil
+}
+
+func (a *Archive) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin archive transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit archive transaction: %w", err)
+	}
+	return nil
+}
+
+// normalizeToken strips a leading "$" and upper-cases the ticker, matching
+// how Tweet.TokenMentions are extracted.
+func normalizeToken(tok string) string {
+	return strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(tok), "$"))
+}
+
+// sqlLimit maps limit <= 0 to SQLite's "no limit".
+func sqlLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+// encodeJSON encodes v, or returns NULL for an empty value so an upsert
+// keeps what is stored.
+func encodeJSON[T any](v T) (sql.NullString, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if s := string(b); s == "null" || s == "[]" || s == "{}" {
+		return sql.NullString{}, nil
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+func decodeJSON(s sql.NullString, v any) error {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(s.String), v)
+}
+
+// zeroUnix is how the zero time.Time is stored.
+var zeroUnix = time.Time{}.Unix()
+
+// fromUnix converts a stored timestamp back, keeping the zero time zero.
+func fromUnix(sec int64) time.Time {
+	if sec == zeroUnix {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
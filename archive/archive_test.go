@@ -0,0 +1,43 @@
+package archive
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeToken(t *testing.T) {
+	assert.Equal(t, "BTC", normalizeToken("$btc"))
+	assert.Equal(t, "ETH", normalizeToken(" ETH "))
+}
+
+func TestEncodeJSON_EmptyIsNull(t *testing.T) {
+	for _, v := range []any{nil, []string{}, map[string][]string{}} {
+		s, err := encodeJSON(v)
+		require.NoError(t, err)
+		assert.False(t, s.Valid, "%v", v)
+	}
+
+	s, err := encodeJSON([]string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, sql.NullString{String: `["1","2"]`, Valid: true}, s)
+
+	var ids []string
+	require.NoError(t, decodeJSON(s, &ids))
+	assert.Equal(t, []string{"1", "2"}, ids)
+	require.NoError(t, decodeJSON(sql.NullString{}, &ids))
+}
+
+func TestFromUnix(t *testing.T) {
+	assert.True(t, fromUnix(zeroUnix).IsZero())
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, ts, fromUnix(ts.Unix()))
+}
+
+func TestSQLLimit(t *testing.T) {
+	assert.Equal(t, -1, sqlLimit(0))
+	assert.Equal(t, 10, sqlLimit(10))
+}
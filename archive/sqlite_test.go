@@ -0,0 +1,160 @@
+//go:build cgo
+
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+func openTestArchive(t *testing.T) *Archive {
+	t.Helper()
+	a, err := Open(context.Background(), "sqlite3", "file::memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	a.DB().SetMaxOpenConns(1) // every connection would open its own in-memory database
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestArchive_TweetRoundTrip(t *testing.T) {
+	a := openTestArchive(t)
+	ctx := context.Background()
+	in := &twitter.Tweet{
+		ID: "100", AuthorID: "1", AuthorHandle: "gopher", AuthorName: "Gopher", Text: "hello $BTC",
+		CreatedAt: t0, Views: 50, Likes: 5, Retweets: 2, Quotes: 1, ReplyCount: 3,
+		TokenMentions:   []string{"BTC"},
+		EditIDs:         []string{"99", "100"},
+		ConversationID:  "90",
+		InReplyToID:     "95",
+		InReplyToUserID: "2",
+		Media: []twitter.Media{{
+			Type: "video", URL: "https://pbs.twimg.com/poster.jpg", Duration: 12 * time.Second,
+			Variants: []twitter.MediaVariant{{URL: "https://video.twimg.com/v.mp4", ContentType: "video/mp4", Bitrate: 832000}},
+		}},
+		Lang:        "en",
+		Place:       &twitter.Place{ID: "p1", Name: "Berlin", FullName: "Berlin, Germany", Type: "city", Country: "Germany", CountryCode: "DE"},
+		Coordinates: &twitter.GeoPoint{Lat: 52.52, Lon: 13.4},
+		Entities:    map[string][]string{"urls": {"https://go.dev"}},
+	}
+	require.NoError(t, a.SaveTweets(ctx, in))
+
+	got, err := a.Tweet(ctx, "100")
+	require.NoError(t, err)
+	assert.Equal(t, in, got)
+
+	_, err = a.Tweet(ctx, "404")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestArchive_UpsertDedupesByID(t *testing.T) {
+	a := openTestArchive(t)
+	ctx := context.Background()
+	first := &twitter.Tweet{
+		ID: "100", AuthorID: "1", Text: "v1", CreatedAt: t0, Views: 50, Likes: 5,
+		EditIDs: []string{"100"}, Lang: "en", ConversationID: "90",
+		Media: []twitter.Media{{Type: "photo", URL: "https://pbs.twimg.com/a.jpg"}},
+	}
+	require.NoError(t, a.SaveTweets(ctx, first, first))
+	require.NoError(t, a.SaveTweets(ctx, &twitter.Tweet{ID: "100", AuthorID: "1", Text: "v2", CreatedAt: t0, Likes: 9}))
+
+	var n int
+	require.NoError(t, a.DB().QueryRow(`SELECT count(*) FROM tweets`).Scan(&n))
+	assert.Equal(t, 1, n)
+
+	got, err := a.Tweet(ctx, "100")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got.Text)
+	assert.Equal(t, int64(9), got.Likes)
+	assert.Equal(t, int64(50), got.Views, "a source without views keeps the stored count")
+	assert.Equal(t, []string{"100"}, got.EditIDs)
+	assert.Equal(t, "en", got.Lang)
+	assert.Equal(t, "90", got.ConversationID)
+	assert.Equal(t, first.Media, got.Media)
+}
+
+func TestArchive_Queries(t *testing.T) {
+	a := openTestArchive(t)
+	ctx := context.Background()
+	require.NoError(t, a.SaveTweets(ctx,
+		&twitter.Tweet{ID: "1", AuthorID: "a", CreatedAt: t0, TokenMentions: []string{"BTC"}},
+		&twitter.Tweet{ID: "2", AuthorID: "b", CreatedAt: t0.Add(time.Hour), TokenMentions: []string{"ETH", "BTC"}},
+		&twitter.Tweet{ID: "3", AuthorID: "a", CreatedAt: t0.Add(2 * time.Hour)},
+	))
+
+	ids := func(tweets []*twitter.Tweet, err error) []string {
+		t.Helper()
+		require.NoError(t, err)
+		var out []string
+		for _, tw := range tweets {
+			out = append(out, tw.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"3", "1"}, ids(a.TweetsByAuthor(ctx, "a", 0)))
+	assert.Equal(t, []string{"3"}, ids(a.TweetsByAuthor(ctx, "a", 1)))
+	assert.Equal(t, []string{"2", "1"}, ids(a.TweetsBetween(ctx, t0, t0.Add(2*time.Hour), 0)))
+	assert.Equal(t, []string{"3", "2"}, ids(a.TweetsBetween(ctx, t0.Add(time.Hour), time.Time{}, 0)))
+	assert.Equal(t, []string{"2", "1"}, ids(a.TweetsMentioning(ctx, "$btc", 0)))
+	assert.Equal(t, []string{"2"}, ids(a.TweetsMentioning(ctx, "ETH", 0)))
+}
+
+func TestArchive_Users(t *testing.T) {
+	a := openTestArchive(t)
+	ctx := context.Background()
+	require.NoError(t, a.SaveUsers(ctx, &twitter.TwitterUser{ID: "1", Handle: "Gopher", Followers: 10, CreatedAt: t0}))
+	require.NoError(t, a.SaveUsers(ctx, &twitter.TwitterUser{ID: "1", Handle: "Gopher", Followers: 11, Bio: "hi", CreatedAt: t0}))
+
+	u, err := a.UserByHandle(ctx, "@gopher")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), u.Followers)
+	assert.True(t, u.HasBio)
+	assert.Equal(t, t0, u.CreatedAt)
+
+	_, err = a.User(ctx, "2")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestArchive_Engagement(t *testing.T) {
+	a := openTestArchive(t)
+	ctx := context.Background()
+	snaps := []twitter.EngagementSnapshot{
+		{TweetID: "1", At: t0.Add(time.Minute), Views: 200, ViewsPerHour: 6000},
+		{TweetID: "1", At: t0, Views: 100},
+	}
+	require.NoError(t, a.SaveEngagement(ctx, snaps...))
+
+	got, err := a.Engagement(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []twitter.EngagementSnapshot{snaps[1], snaps[0]}, got)
+}
+
+func TestNew_UpgradesOlderArchive(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE tweets (
+		id TEXT PRIMARY KEY, author_id TEXT NOT NULL, author_handle TEXT NOT NULL, author_name TEXT NOT NULL,
+		text TEXT NOT NULL, created_at INTEGER NOT NULL, views INTEGER NOT NULL, likes INTEGER NOT NULL,
+		retweets INTEGER NOT NULL, quotes INTEGER NOT NULL, replies INTEGER NOT NULL,
+		edit_ids TEXT, entities TEXT, archived_at INTEGER NOT NULL)`)
+	require.NoError(t, err)
+
+	a, err := New(context.Background(), db)
+	require.NoError(t, err)
+	in := &twitter.Tweet{ID: "1", CreatedAt: t0, Lang: "de", Place: &twitter.Place{ID: "p"}}
+	require.NoError(t, a.SaveTweets(context.Background(), in))
+	got, err := a.Tweet(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, in, got)
+}
@@ -13,6 +13,8 @@ import (
 	stealth "github.com/anatolykoptev/go-stealth"
 	"github.com/anatolykoptev/go-twitter/captcha"
 	"github.com/pquerna/otp/totp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // arkosePublicKey is Twitter's well-known FunCaptcha public key for login flows.
@@ -27,6 +29,14 @@ func sessionDir(override string) string {
 	return filepath.Join(home, ".go-twitter", "sessions")
 }
 
+// sessionDirFile is the path of the client state file name, e.g. the
+// account health, inside the session directory dir. State file names start
+// with a dot: handles cannot contain dots, so they never collide with a
+// session file.
+func sessionDirFile(dir, name string) string {
+	return filepath.Join(sessionDir(dir), name)
+}
+
 // sessionPath returns the file path for a given username's session.
 func sessionPath(dir, username string) string {
 	return filepath.Join(dir, username+".json")
@@ -34,18 +44,27 @@ func sessionPath(dir, username string) string {
 
 // savedSession holds serialized cookie data for persistence.
 type savedSession struct {
-	AuthToken string    `json:"auth_token"`
-	CT0       string    `json:"ct0"`
-	SavedAt   time.Time `json:"saved_at"`
+	AuthToken string                  `json:"auth_token"`
+	CT0       string                  `json:"ct0"`
+	Web       webIdentity             `json:"web"`
+	Profile   *stealth.BrowserProfile `json:"profile,omitempty"`
+	SavedAt   time.Time               `json:"saved_at"`
 }
 
-// saveSession persists auth_token and ct0 to disk.
-func saveSession(dir, username, authToken, ct0 string) error {
+// saveSession persists acc's auth_token, ct0 and web identity to disk.
+func saveSession(dir string, acc *Account) error {
 	d := sessionDir(dir)
 	if err := os.MkdirAll(d, 0700); err != nil {
 		return fmt.Errorf("create session dir: %w", err)
 	}
-	s := savedSession{AuthToken: authToken, CT0: ct0, SavedAt: time.Now()}
+	acc.mu.Lock()
+	s := savedSession{AuthToken: acc.AuthToken, CT0: acc.CT0, Web: acc.web, SavedAt: time.Now()}
+	if acc.Profile != (stealth.BrowserProfile{}) {
+		profile := acc.Profile
+		s.Profile = &profile
+	}
+	username := acc.Username
+	acc.mu.Unlock()
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
 		return err
@@ -58,24 +77,38 @@ func saveSession(dir, username, authToken, ct0 string) error {
 	return nil
 }
 
-// loadSession loads a persisted session from disk.
-func loadSession(dir, username string, ttl time.Duration) (authToken, ct0 string, err error) {
+// loadSession loads a persisted session from disk. The web identity is
+// returned even when the credentials have expired.
+func loadSession(dir, username string, ttl time.Duration) (authToken, ct0 string, web webIdentity, err error) {
+	s, err := readSession(dir, username)
+	if err != nil {
+		return "", "", web, err
+	}
+	if s.SavedAt.IsZero() {
+		return "", "", s.Web, nil // no session file
+	}
+	if time.Since(s.SavedAt) > ttl {
+		slog.Debug("session expired", slog.String("user", username))
+		return "", "", s.Web, nil
+	}
+	return s.AuthToken, s.CT0, s.Web, nil
+}
+
+// readSession reads username's session file; a missing file yields a zero
+// savedSession.
+func readSession(dir, username string) (savedSession, error) {
+	var s savedSession
 	data, err := os.ReadFile(sessionPath(sessionDir(dir), username))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", "", nil
+			return s, nil
 		}
-		return "", "", err
+		return s, err
 	}
-	var s savedSession
 	if err := json.Unmarshal(data, &s); err != nil {
-		return "", "", err
+		return s, err
 	}
-	if time.Since(s.SavedAt) > ttl {
-		slog.Debug("session expired", slog.String("user", username))
-		return "", "", nil
-	}
-	return s.AuthToken, s.CT0, nil
+	return s, nil
 }
 
 // relogin clears auth credentials and performs a fresh login.
@@ -84,31 +117,45 @@ func (c *Client) relogin(acc *Account) error {
 		if ok, reason := c.reloginGate.Allowed(context.Background(), acc.Username); !ok {
 			slog.Warn("twitter: auto-relogin blocked by gate",
 				slog.String("user", acc.Username), slog.String("reason", reason))
-			return fmt.Errorf("relogin blocked: %s", reason)
+			err := fmt.Errorf("relogin blocked: %s", reason)
+			c.emitAccountEvent(AccountEventReloginFailed, acc, time.Time{}, err)
+			return err
 		}
 	}
 	slog.Info("attempting relogin", slog.String("user", acc.Username))
 
+	acc.rotateProxySession(true) // fresh exit IP per login
 	bc := c.clientForAccount(acc)
 
 	acc.SetCredentials("", "")
 	_ = os.Remove(sessionPath(sessionDir(c.cfg.SessionDir), acc.Username))
 
+	acc.countRelogin()
 	if err := c.loadOrLogin(acc, bc); err != nil {
+		c.metrics.observeRelogin(err)
+		c.emitAccountEvent(AccountEventReloginFailed, acc, time.Time{}, err)
 		return fmt.Errorf("relogin %s: %w", acc.Username, err)
 	}
+	c.metrics.observeRelogin(nil)
 
 	acc.Reset()
 	slog.Info("relogin succeeded", slog.String("user", acc.Username))
+	c.emitAccountEvent(AccountEventReloginSucceeded, acc, time.Time{}, nil)
 	return nil
 }
 
 // loadOrLogin attempts to load a persisted session, falling back to login.
 func (c *Client) loadOrLogin(acc *Account, client *stealth.BrowserClient) error {
-	authToken, ct0, err := loadSession(c.cfg.SessionDir, acc.Username, c.cfg.SessionTTL)
+	authToken, ct0, web, err := loadSession(c.cfg.SessionDir, acc.Username, c.cfg.SessionTTL)
 	if err != nil {
 		slog.Warn("error loading session", slog.String("user", acc.Username), slog.Any("error", err))
 	}
+	acc.mu.Lock()
+	if web != (webIdentity{}) {
+		acc.web = web
+	}
+	acc.web.fill()
+	acc.mu.Unlock()
 	if authToken != "" && ct0 != "" {
 		acc.AuthToken = authToken
 		acc.CT0 = ct0
@@ -120,7 +167,7 @@ func (c *Client) loadOrLogin(acc *Account, client *stealth.BrowserClient) error
 	if acc.AuthToken != "" && acc.CT0 != "" {
 		acc.ct0RefreshedAt = time.Now()
 		slog.Info("using provided credentials", slog.String("user", acc.Username))
-		if err := saveSession(c.cfg.SessionDir, acc.Username, acc.AuthToken, acc.CT0); err != nil {
+		if err := saveSession(c.cfg.SessionDir, acc); err != nil {
 			slog.Warn("session save failed", slog.String("user", acc.Username), slog.Any("error", err))
 		}
 		return nil
@@ -134,19 +181,22 @@ func (c *Client) loadOrLogin(acc *Account, client *stealth.BrowserClient) error
 		return fmt.Errorf("login failed for %s: %w", acc.Username, err)
 	}
 
-	if err := saveSession(c.cfg.SessionDir, acc.Username, acc.AuthToken, acc.CT0); err != nil {
+	if err := saveSession(c.cfg.SessionDir, acc); err != nil {
 		slog.Warn("session save failed", slog.String("user", acc.Username), slog.Any("error", err))
 	}
 	return nil
 }
 
 // login performs Twitter's multi-step login flow.
-func (c *Client) login(acc *Account, client *stealth.BrowserClient) error {
+func (c *Client) login(acc *Account, client *stealth.BrowserClient) (err error) {
 	slog.Info("logging in", slog.String("user", acc.Username))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
 	defer cancel()
 
+	ctx, span := c.startSpan(ctx, "twitter.login", attribute.String("twitter.account", hashAccount(acc.Username)))
+	defer func() { endSpan(span, err) }()
+
 	guestToken, err := c.getGuestToken(client)
 	if err != nil {
 		return fmt.Errorf("get guest token: %w", err)
@@ -164,6 +214,7 @@ func (c *Client) login(acc *Account, client *stealth.BrowserClient) error {
 
 		subtaskID := fr.Subtasks[0].SubtaskID
 		slog.Debug("login subtask", slog.String("user", acc.Username), slog.String("subtask", subtaskID))
+		span.AddEvent("subtask", trace.WithAttributes(attribute.String("twitter.subtask", subtaskID)))
 
 		switch subtaskID {
 		case "LoginJsInstrumentationSubtask":
@@ -179,7 +230,9 @@ func (c *Client) login(acc *Account, client *stealth.BrowserClient) error {
 			if c.cfg.CaptchaSolver == nil {
 				return fmt.Errorf("CAPTCHA required but no solver configured for %s", acc.Username)
 			}
-			token, solveErr := c.cfg.CaptchaSolver.Solve(ctx, arkosePublicKey, "https://twitter.com")
+			token, solveErr := c.cfg.CaptchaSolver.Solve(ctx, arkosePublicKey, c.cfg.Domain.origin())
+			c.metrics.observeCaptcha(solveErr)
+			acc.countCaptcha()
 			if solveErr != nil {
 				return fmt.Errorf("CAPTCHA solve failed for %s: %w", acc.Username, solveErr)
 			}
@@ -218,14 +271,7 @@ func (c *Client) login(acc *Account, client *stealth.BrowserClient) error {
 	}
 
 done:
-	authToken := client.GetCookieValue("https://api.twitter.com", "auth_token")
-	if authToken == "" {
-		authToken = client.GetCookieValue("https://twitter.com", "auth_token")
-	}
-	ct0 := client.GetCookieValue("https://api.twitter.com", "ct0")
-	if ct0 == "" {
-		ct0 = client.GetCookieValue("https://twitter.com", "ct0")
-	}
+	authToken, ct0 := c.cfg.Domain.sessionCookies(client)
 	if ct0 == "" {
 		ct0 = GenerateCT0()
 	}
@@ -235,15 +281,18 @@ done:
 	}
 
 	acc.SetCredentials(authToken, ct0)
+	acc.mu.Lock()
+	acc.web.adoptCookies(c.cfg.Domain, client)
+	acc.mu.Unlock()
 	slog.Info("login successful", slog.String("user", acc.Username))
 	return nil
 }
 
 // loginOpenAccount creates an anonymous Twitter session.
 func (c *Client) loginOpenAccount(ctx context.Context) (*Account, error) {
-	bc, err := stealth.NewClient(
+	bc, err := stealth.NewClient(append([]stealth.ClientOption{
 		stealth.WithHeaderOrder(twitterHeaderOrder),
-	)
+	}, c.cfg.backendOptions(c.conns)...)...)
 	if err != nil {
 		return nil, fmt.Errorf("new client: %w", err)
 	}
@@ -254,8 +303,10 @@ func (c *Client) loginOpenAccount(ctx context.Context) (*Account, error) {
 	}
 
 	headers := loginFlowHeaders(guestToken, "")
+	c.cfg.Domain.setOrigin(headers)
+	c.setBearer(headers)
 	body, _, status, err := bc.DoWithHeaderOrder("POST",
-		twitterAPIURL+"/1.1/onboarding/task.json?flow_name=welcome",
+		c.cfg.Domain.apiURL()+"/1.1/onboarding/task.json?flow_name=welcome",
 		headers, strings.NewReader(openAccountPayload), twitterHeaderOrder,
 	)
 	if err != nil {
@@ -280,7 +331,7 @@ func (c *Client) loginOpenAccount(ctx context.Context) (*Account, error) {
 		if st.SubtaskID == "LoginJsInstrumentationSubtask" {
 			payload := fmt.Sprintf(`{"flow_token":%q,"subtask_inputs":[{"subtask_id":"LoginJsInstrumentationSubtask","js_instrumentation":{"response":"{\"rf\":{\"a\":\"b\"},\"s\":\"s\"}","link":"next_link"}}]}`, flowToken)
 			body2, _, status2, err := bc.DoWithHeaderOrder("POST",
-				twitterAPIURL+"/1.1/onboarding/task.json",
+				c.cfg.Domain.apiURL()+"/1.1/onboarding/task.json",
 				headers, strings.NewReader(payload), twitterHeaderOrder,
 			)
 			if err != nil {
@@ -301,14 +352,7 @@ func (c *Client) loginOpenAccount(ctx context.Context) (*Account, error) {
 
 	_ = flowToken
 
-	authToken := bc.GetCookieValue("https://api.twitter.com", "auth_token")
-	if authToken == "" {
-		authToken = bc.GetCookieValue("https://twitter.com", "auth_token")
-	}
-	ct0 := bc.GetCookieValue("https://api.twitter.com", "ct0")
-	if ct0 == "" {
-		ct0 = bc.GetCookieValue("https://twitter.com", "ct0")
-	}
+	authToken, ct0 := c.cfg.Domain.sessionCookies(bc)
 
 	if authToken == "" {
 		return nil, fmt.Errorf("open account: no auth_token in cookies after welcome flow")
@@ -327,11 +371,11 @@ func (c *Client) loginOpenAccount(ctx context.Context) (*Account, error) {
 // getGuestToken fetches a Twitter guest token.
 func (c *Client) getGuestToken(client *stealth.BrowserClient) (string, error) {
 	headers := map[string]string{
-		"authorization": "Bearer " + BearerToken,
+		"authorization": "Bearer " + c.bearer.current(),
 		"content-type":  "application/json",
 		"user-agent":    defaultUserAgent,
 	}
-	body, _, status, err := client.DoWithHeaderOrder("POST", twitterAPIURL+"/1.1/guest/activate.json", headers, nil, twitterHeaderOrder)
+	body, _, status, err := c.send(context.Background(), client, "POST", c.cfg.Domain.apiURL()+"/1.1/guest/activate.json", headers, nil)
 	if err != nil {
 		return "", err
 	}
@@ -427,10 +471,12 @@ func parseFlowResponse(body []byte) (*flowResponse, error) {
 
 func (c *Client) initLoginFlowFull(client *stealth.BrowserClient, guestToken string) (*flowResponse, error) {
 	headers := loginFlowHeaders(guestToken, "")
+	c.cfg.Domain.setOrigin(headers)
+	c.setBearer(headers)
 	payload := `{"input_flow_data":{"flow_context":{"debug_overrides":{},"start_location":{"location":"splash_screen"}}},"subtask_versions":{"action_list":2,"alert_dialog":1,"app_download_cta":1,"check_logged_in_account":1,"choice_selection":3,"contacts_live_sync_permission_prompt":0,"cta":7,"email_verification":2,"end_flow":1,"enter_date":1,"enter_email":2,"enter_password":5,"enter_phone":2,"enter_recaptcha":1,"enter_text":5,"enter_username":2,"generic_urt":3,"in_app_notification":1,"interest_picker":3,"js_instrumentation":1,"menu_dialog":1,"notifications_permission_prompt":2,"open_account":2,"open_home_timeline":1,"open_link":1,"phone_verification":4,"privacy_options":1,"security_key":3,"select_avatar":4,"select_banner":2,"settings_list":7,"show_code":1,"sign_up":2,"sign_up_review":4,"tweet_selection_urt":1,"update_users":1,"upload_media":1,"user_recommendations_list":4,"user_recommendations_urt":1,"wait_spinner":3,"web_modal":1}}`
 
 	body, _, status, err := client.DoWithHeaderOrder("POST",
-		twitterAPIURL+"/1.1/onboarding/task.json?flow_name=login",
+		c.cfg.Domain.apiURL()+"/1.1/onboarding/task.json?flow_name=login",
 		headers,
 		strings.NewReader(payload),
 		twitterHeaderOrder,
@@ -446,8 +492,10 @@ func (c *Client) initLoginFlowFull(client *stealth.BrowserClient, guestToken str
 
 func (c *Client) submitFlowStep(client *stealth.BrowserClient, guestToken, payload string) (*flowResponse, error) {
 	headers := loginFlowHeaders(guestToken, "")
+	c.cfg.Domain.setOrigin(headers)
+	c.setBearer(headers)
 	body, _, status, err := client.DoWithHeaderOrder("POST",
-		twitterAPIURL+"/1.1/onboarding/task.json",
+		c.cfg.Domain.apiURL()+"/1.1/onboarding/task.json",
 		headers,
 		strings.NewReader(payload),
 		twitterHeaderOrder,
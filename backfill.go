@@ -0,0 +1,167 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// twitterLaunch is the first day any tweet can date from.
+var twitterLaunch = time.Date(2006, 3, 21, 0, 0, 0, 0, time.UTC)
+
+const (
+	backfillDay           = 24 * time.Hour
+	defaultBackfillWindow = 30 * backfillDay
+	defaultBackfillCap    = 500
+)
+
+// BackfillOptions configures Backfill.
+type BackfillOptions struct {
+	// Since and Until bound the tweets fetched, rounded out to whole UTC
+	// days. Default: from Twitter's launch to now. Set Since to the
+	// account's creation date to skip years of empty windows.
+	Since, Until time.Time
+	// Window is the widest date slice searched at once. Default: 30 days.
+	Window time.Duration
+	// WindowCap is how many results a window may return before it counts
+	// as capped: the rest of it is searched again as a narrower window, and
+	// the following windows start narrower. Default: 500.
+	WindowCap int
+	// Query holds extra search operators, e.g. "-filter:replies".
+	Query string
+}
+
+// Backfill fetches the tweets of handle between opts.Since and opts.Until,
+// newest first, beyond the ~3200 tweets the user timeline reaches. It
+// searches "from:handle since:… until:…" one date window at a time and
+// narrows windows whose results hit opts.WindowCap, since X stops paging
+// long searches early. fn receives each batch and may stop the backfill by
+// returning an error. Backfill returns how many tweets fn received.
+func (c *Client) Backfill(ctx context.Context, handle string, opts BackfillOptions, fn func([]*Tweet) error) (int, error) {
+	h, err := NormalizeHandle(handle)
+	if err != nil {
+		return 0, err
+	}
+	if fn == nil {
+		return 0, errors.New("backfill: nil callback")
+	}
+	if opts.Window < backfillDay {
+		opts.Window = defaultBackfillWindow
+	}
+	if opts.WindowCap <= 0 {
+		opts.WindowCap = defaultBackfillCap
+	}
+	since := opts.Since.UTC().Truncate(backfillDay)
+	if opts.Since.IsZero() {
+		since = twitterLaunch
+	}
+	until := opts.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	until = ceilDay(until.UTC())
+
+	b := &backfill{c: c, handle: h, opts: opts, fn: fn}
+	width := opts.Window
+	for until.After(since) {
+		from := maxTime(since, until.Add(-width))
+		capped, err := b.window(ctx, from, until)
+		if err != nil {
+			return b.total, err
+		}
+		if capped {
+			width = max(width/2, backfillDay).Truncate(backfillDay)
+		} else {
+			width = min(width*2, opts.Window)
+		}
+		until = from
+	}
+	return b.total, nil
+}
+
+// backfill is the state of one Backfill call.
+type backfill struct {
+	c      *Client
+	handle string
+	opts   BackfillOptions
+	fn     func([]*Tweet) error
+	total  int
+	seen   map[string]bool // IDs delivered from the current boundary day
+}
+
+// window delivers the tweets of [from, until) and reports whether its
+// results were capped. A capped search is resumed as the narrower window
+// ending on the day of the oldest tweet it returned; when all of them are
+// from the window's last day, which cannot be split further, its cursor is
+// followed instead.
+func (b *backfill) window(ctx context.Context, from, until time.Time) (bool, error) {
+	capped := false
+	cursor := ""
+	for {
+		q := b.query(from, until)
+		r := b.c.SearchTimelinePaged(ctx, q, cursor, b.opts.WindowCap)
+		if err := b.deliver(r.Items); err != nil {
+			return capped, err
+		}
+		if r.PartialErr != nil {
+			return capped, fmt.Errorf("backfill %s: %w", q, r.PartialErr)
+		}
+		if r.NextCursor == "" || len(r.Items) == 0 {
+			return capped, nil
+		}
+		capped = true
+		if next := ceilDay(r.Items[len(r.Items)-1].CreatedAt.UTC()); next.Before(until) && next.After(from) {
+			slog.Debug("backfill window capped, narrowing", slog.String("query", q),
+				slog.String("until", next.Format(time.DateOnly)))
+			until, cursor = next, ""
+		} else {
+			cursor = r.NextCursor
+		}
+	}
+}
+
+// deliver hands the tweets not delivered yet to fn. Narrowed windows
+// overlap by the boundary day, so the IDs of the last batch are kept.
+func (b *backfill) deliver(tweets []*Tweet) error {
+	fresh := tweets[:0:0]
+	for _, t := range tweets {
+		if !b.seen[t.ID] {
+			fresh = append(fresh, t)
+		}
+	}
+	b.seen = make(map[string]bool, len(tweets))
+	for _, t := range tweets {
+		b.seen[t.ID] = true
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+	if err := b.fn(fresh); err != nil {
+		return err
+	}
+	b.total += len(fresh)
+	return nil
+}
+
+// query is the search for the tweets of [from, until); until: is exclusive.
+func (b *backfill) query(from, until time.Time) string {
+	return SearchQuery{Text: b.opts.Query, From: b.handle, Since: from, Until: until}.String()
+}
+
+// ceilDay rounds t up to the next UTC midnight, t itself if it is one.
+func ceilDay(t time.Time) time.Time {
+	d := t.Truncate(backfillDay)
+	if d.Before(t) {
+		d = d.Add(backfillDay)
+	}
+	return d
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,115 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveSearch answers from:/since:/until: searches over tweets, newest
+// first, paging by offset.
+type archiveSearch struct {
+	mu      sync.Mutex
+	tweets  []*Tweet // newest first
+	queries []string
+}
+
+var dateOps = regexp.MustCompile(`since:(\S+) until:(\S+)`)
+
+func (a *archiveSearch) Do(_ context.Context, _, _ string, _ map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	var req struct {
+		Variables struct {
+			RawQuery string `json:"rawQuery"`
+			Count    int    `json:"count"`
+			Cursor   string `json:"cursor"`
+		} `json:"variables"`
+	}
+	data, _ := io.ReadAll(body)
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, nil, 400, err
+	}
+	a.mu.Lock()
+	a.queries = append(a.queries, req.Variables.RawQuery)
+	a.mu.Unlock()
+	m := dateOps.FindStringSubmatch(req.Variables.RawQuery)
+	since, _ := time.Parse(time.DateOnly, m[1])
+	until, _ := time.Parse(time.DateOnly, m[2])
+	var hits []*Tweet
+	for _, t := range a.tweets {
+		if !t.CreatedAt.Before(since) && t.CreatedAt.Before(until) {
+			hits = append(hits, t)
+		}
+	}
+	offset, _ := strconv.Atoi(req.Variables.Cursor)
+	end := min(offset+req.Variables.Count, len(hits))
+	var entries []string
+	for _, t := range hits[offset:end] {
+		entries = append(entries, fmt.Sprintf(`{"entryId":"tweet-%s","content":{"entryType":"TimelineTimelineItem","itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{"__typename":"Tweet","rest_id":"%s","legacy":{"full_text":"t","created_at":"%s"}}}}}}`,
+			t.ID, t.ID, t.CreatedAt.Format(time.RubyDate)))
+	}
+	if end < len(hits) {
+		entries = append(entries, `{"entryId":"cursor-bottom-1","content":{"entryType":"TimelineTimelineCursor","cursorType":"Bottom","value":"`+strconv.Itoa(end)+`"}}`)
+	}
+	page := `{"data":{"search_by_raw_query":{"search_timeline":{"timeline":{"instructions":[{"type":"TimelineAddEntries","entries":[` + strings.Join(entries, ",") + `]}]}}}}}`
+	return []byte(page), nil, 200, nil
+}
+
+func TestBackfill_NarrowsCappedWindows(t *testing.T) {
+	// Tweets per day from Mar 10 back to Mar 1; Mar 8 has more than the cap.
+	perDay := []int{1, 0, 5, 2, 0, 0, 1, 3, 0, 1}
+	a := &archiveSearch{}
+	id := 1000
+	for i, n := range perDay {
+		d := time.Date(2025, 3, 10-i, 0, 0, 0, 0, time.UTC)
+		for h := range n {
+			a.tweets = append(a.tweets, &Tweet{ID: strconv.Itoa(id), CreatedAt: d.Add(time.Duration(20-h) * time.Hour)})
+			id--
+		}
+	}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            a,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	var got []string
+	n, err := c.Backfill(context.Background(), "@Someone", BackfillOptions{
+		Since:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Until:     time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+		WindowCap: 3,
+	}, func(tweets []*Tweet) error {
+		for _, t := range tweets {
+			got = append(got, t.ID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	var want []string
+	for _, t := range a.tweets {
+		want = append(want, t.ID)
+	}
+	assert.Equal(t, want, got, "every tweet once, newest first")
+	assert.Equal(t, len(want), n)
+	assert.Equal(t, "from:someone since:2025-03-01 until:2025-03-11", a.queries[0])
+	assert.Greater(t, len(a.queries), 1, "the capped window was narrowed")
+}
+
+func TestBackfill_InvalidHandle(t *testing.T) {
+	c := &Client{}
+	_, err := c.Backfill(context.Background(), "not a handle", BackfillOptions{}, func([]*Tweet) error { return nil })
+	var verr *ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
@@ -0,0 +1,89 @@
+package twitter
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// bearerFallbackThreshold is the number of consecutive non-account 403s after
+// which the client switches to the next bearer token.
+const bearerFallbackThreshold = 5
+
+// bearerRotation picks the bearer token sent with every request. The known
+// web and official-app tokens have separate rate limits, so persistent 403s
+// on one are worked around by moving to the next. Safe for concurrent use;
+// a nil *bearerRotation always yields BearerToken.
+type bearerRotation struct {
+	mu        sync.Mutex
+	tokens    []string
+	idx       int
+	forbidden int // consecutive 403s on the current token
+}
+
+// newBearerRotation returns a rotation starting at override (if set),
+// followed by the built-in tokens.
+func newBearerRotation(override string) *bearerRotation {
+	var tokens []string
+	if override != "" {
+		tokens = append(tokens, override)
+	}
+	for _, t := range bearerTokens {
+		if !slices.Contains(tokens, t) {
+			tokens = append(tokens, t)
+		}
+	}
+	return &bearerRotation{tokens: tokens}
+}
+
+// current returns the token to send.
+func (b *bearerRotation) current() string {
+	if b == nil {
+		return BearerToken
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens[b.idx]
+}
+
+// success resets the 403 streak.
+func (b *bearerRotation) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.forbidden = 0
+	b.mu.Unlock()
+}
+
+// noteForbidden counts a 403 not explained by the account and moves to the
+// next token once the streak reaches bearerFallbackThreshold. Reports
+// whether the token changed.
+func (b *bearerRotation) noteForbidden() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.forbidden++
+	if b.forbidden < bearerFallbackThreshold || len(b.tokens) < 2 {
+		return false
+	}
+	b.forbidden = 0
+	b.idx = (b.idx + 1) % len(b.tokens)
+	slog.Warn("persistent 403s, switching bearer token", slog.Int("token_index", b.idx))
+	return true
+}
+
+// setBearer puts the client's current bearer token into headers.
+func (c *Client) setBearer(headers map[string]string) {
+	headers["authorization"] = "Bearer " + c.bearer.current()
+}
+
+// noteForbidden records a non-account 403. Guest tokens are bound to the
+// bearer token they were issued for, so a token switch drops the cached one.
+func (c *Client) noteForbidden() {
+	if c.bearer.noteForbidden() {
+		c.setGuestToken("")
+	}
+}
@@ -0,0 +1,45 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBearerRotation_Override(t *testing.T) {
+	b := newBearerRotation("custom")
+	assert.Equal(t, "custom", b.current())
+	assert.Equal(t, append([]string{"custom"}, bearerTokens...), b.tokens)
+
+	assert.Len(t, newBearerRotation(bearerTokens[1]).tokens, len(bearerTokens), "known override is not duplicated")
+}
+
+func TestBearerRotation_FallbackOnPersistent403(t *testing.T) {
+	b := newBearerRotation("")
+	for range bearerFallbackThreshold - 1 {
+		assert.False(t, b.noteForbidden())
+	}
+	b.success()
+	for range bearerFallbackThreshold - 1 {
+		assert.False(t, b.noteForbidden(), "success resets the streak")
+	}
+	assert.True(t, b.noteForbidden())
+	assert.Equal(t, bearerTokens[1], b.current())
+}
+
+func TestClient_NoteForbiddenDropsGuestToken(t *testing.T) {
+	c := &Client{bearer: newBearerRotation("")}
+	c.setGuestToken("gt")
+	for range bearerFallbackThreshold {
+		c.noteForbidden()
+	}
+	_, ok := c.getGuestTokenCached()
+	assert.False(t, ok)
+
+	h := map[string]string{}
+	c.setBearer(h)
+	assert.Equal(t, "Bearer "+bearerTokens[1], h["authorization"])
+
+	var nilRotation *bearerRotation
+	assert.Equal(t, BearerToken, nilRotation.current())
+}
@@ -0,0 +1,51 @@
+package twitter
+
+import (
+	"hash/fnv"
+	"log/slog"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+)
+
+// resolveProfile settles acc's browser profile before its client is built,
+// so the account keeps one User-Agent, client hints and TLS fingerprint
+// across pool reorders and restarts:
+//   - an explicit Account.Profile or Account.UserAgent wins; a UserAgent
+//     alone is matched to the built-in profile with that UA, if any;
+//   - otherwise the profile saved with the account's session is restored;
+//   - otherwise the app's profile for a mobile Mode, or a built-in profile
+//     picked by a hash of the username.
+//
+// saveSession then keeps the profile in the session file.
+func resolveProfile(acc *Account, saved *stealth.BrowserProfile) {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	switch {
+	case acc.Profile != (stealth.BrowserProfile{}):
+		if acc.UserAgent == "" {
+			acc.UserAgent = acc.Profile.UserAgent
+		} else if acc.Profile.UserAgent != acc.UserAgent {
+			slog.Warn("account UserAgent differs from its Profile; client hints follow UserAgent",
+				slog.String("user", acc.Username))
+		}
+	case acc.UserAgent != "":
+		acc.Profile = stealth.BrowserProfile{UserAgent: acc.UserAgent}
+		for _, p := range stealth.BuiltinProfiles {
+			if p.UserAgent == acc.UserAgent {
+				acc.Profile = p
+				break
+			}
+		}
+	case saved != nil && saved.UserAgent != "" && saved.Mobile == (acc.Mode != ModeWeb):
+		acc.Profile = *saved
+		acc.UserAgent = saved.UserAgent
+	case acc.Mode != ModeWeb:
+		acc.Profile, _ = mobileProfile(acc.Mode)
+		acc.UserAgent = acc.Profile.UserAgent
+	default:
+		h := fnv.New32a()
+		h.Write([]byte(acc.Username))
+		acc.Profile = stealth.BuiltinProfiles[h.Sum32()%uint32(len(stealth.BuiltinProfiles))]
+		acc.UserAgent = acc.Profile.UserAgent
+	}
+}
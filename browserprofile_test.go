@@ -0,0 +1,47 @@
+package twitter
+
+import (
+	"testing"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveProfile(t *testing.T) {
+	// Picked by username: the same account gets the same profile wherever it
+	// sits in the pool.
+	a1, a2 := &Account{Username: "alice"}, &Account{Username: "alice"}
+	resolveProfile(a1, nil)
+	resolveProfile(a2, nil)
+	assert.Equal(t, a1.Profile, a2.Profile)
+	assert.Equal(t, a1.Profile.UserAgent, a1.UserAgent)
+
+	// The saved profile beats the pick.
+	saved := stealth.BuiltinProfiles[len(stealth.BuiltinProfiles)-1]
+	b := &Account{Username: "alice"}
+	resolveProfile(b, &saved)
+	assert.Equal(t, saved, b.Profile)
+
+	// An explicit UA beats both and takes the matching profile.
+	ua := stealth.BuiltinProfiles[0].UserAgent
+	c := &Account{Username: "alice", UserAgent: ua}
+	resolveProfile(c, &saved)
+	assert.Equal(t, stealth.BuiltinProfiles[0], c.Profile)
+
+	custom := &Account{Username: "alice", UserAgent: "custom/1.0"}
+	resolveProfile(custom, &saved)
+	assert.Equal(t, stealth.BrowserProfile{UserAgent: "custom/1.0"}, custom.Profile)
+}
+
+func TestSaveSession_KeepsProfile(t *testing.T) {
+	dir := t.TempDir()
+	acc := &Account{Username: "alice", AuthToken: "tok", CT0: "csrf"}
+	resolveProfile(acc, nil)
+	require.NoError(t, saveSession(dir, acc))
+
+	s, err := readSession(dir, "alice")
+	require.NoError(t, err)
+	require.NotNil(t, s.Profile)
+	assert.Equal(t, acc.Profile, *s.Profile)
+}
@@ -0,0 +1,234 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultBulkConcurrency is used when a bulk helper gets concurrency <= 0.
+const defaultBulkConcurrency = 4
+
+// ErrUserUnavailable is reported by GetUsersByIDs for IDs that did not
+// resolve (suspended, deactivated, or nonexistent accounts).
+var ErrUserUnavailable = errors.New("user unavailable")
+
+// UserResult is the outcome of one item of a bulk user lookup.
+type UserResult struct {
+	Input string // handle or user ID as passed in
+	User  *TwitterUser
+	Err   error
+}
+
+// BulkOption configures a bulk fetch helper.
+type BulkOption func(*bulkOptions)
+
+type bulkOptions struct {
+	progress func(done, total int)
+	eta      func(BulkProgress)
+}
+
+// WithProgress registers fn to be called after each item completes. Calls are
+// serialized, so fn needs no locking of its own.
+func WithProgress(fn func(done, total int)) BulkOption {
+	return func(o *bulkOptions) { o.progress = fn }
+}
+
+// BulkProgress reports how far a bulk helper got, for WithProgressETA.
+type BulkProgress struct {
+	Done, Total int
+	Elapsed     time.Duration
+
+	// ETA is the projected time until all items are done: the pace so far
+	// extrapolated, or the pool's rate-limit estimate for the remaining
+	// items (see EstimateDuration) if that is longer. -1 when no account
+	// can serve the remaining items.
+	ETA time.Duration
+}
+
+// WithProgressETA is WithProgress with elapsed time and a projected ETA.
+// Calls are serialized.
+func WithProgressETA(fn func(BulkProgress)) BulkOption {
+	return func(o *bulkOptions) { o.eta = fn }
+}
+
+// GetUsersByScreenNames looks up handles with at most concurrency requests in
+// flight, spread across the pool. Results are in input order; each carries
+// its own error, so one bad handle never fails the batch.
+func (c *Client) GetUsersByScreenNames(ctx context.Context, handles []string, concurrency int, opts ...BulkOption) []UserResult {
+	results := make([]UserResult, len(handles))
+	p := newBulkProgress(len(handles), opts, func(n int) time.Duration {
+		return c.EstimateDuration("UserByScreenName", n)
+	})
+	runBounded(ctx, len(handles), concurrency, func(i int) {
+		results[i] = UserResult{Input: handles[i]}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+		} else {
+			results[i].User, results[i].Err = c.GetUserByScreenName(ctx, handles[i])
+		}
+		p.add(1)
+	})
+	return results
+}
+
+// GetUsersByIDs hydrates user IDs through UsersByRestIds in batches of up to
+// 200, with at most concurrency batches in flight. Results are in input
+// order; IDs that did not resolve carry ErrUserUnavailable.
+func (c *Client) GetUsersByIDs(ctx context.Context, ids []string, concurrency int, opts ...BulkOption) []UserResult {
+	results := make([]UserResult, len(ids))
+	p := newBulkProgress(len(ids), opts, func(n int) time.Duration {
+		return c.EstimateDuration("UsersByRestIds", n)
+	})
+
+	// Invalid IDs fail up front; the rest are batched.
+	var valid []int
+	for i, id := range ids {
+		results[i].Input = id
+		if err := validateUserID(id); err != nil {
+			results[i].Err = err
+			p.add(1)
+			continue
+		}
+		valid = append(valid, i)
+	}
+	var batches [][]int
+	for len(valid) > maxUsersByRestIDsBatch {
+		batches = append(batches, valid[:maxUsersByRestIDsBatch:maxUsersByRestIDsBatch])
+		valid = valid[maxUsersByRestIDsBatch:]
+	}
+	if len(valid) > 0 {
+		batches = append(batches, valid)
+	}
+
+	runBounded(ctx, len(batches), concurrency, func(b int) {
+		idx := batches[b]
+		defer p.add(len(idx))
+		if err := ctx.Err(); err != nil {
+			for _, i := range idx {
+				results[i].Err = err
+			}
+			return
+		}
+		chunk := make([]string, len(idx))
+		for j, i := range idx {
+			chunk[j] = ids[i]
+		}
+		users, err := c.getUsersByRestIDs(ctx, chunk)
+		if err != nil {
+			for _, i := range idx {
+				results[i].Err = err
+			}
+			return
+		}
+		byID := make(map[string]*TwitterUser, len(users))
+		for _, u := range users {
+			byID[u.ID] = u
+		}
+		for _, i := range idx {
+			if u, ok := byID[ids[i]]; ok {
+				results[i].User = u
+			} else {
+				results[i].Err = fmt.Errorf("%s: %w", ids[i], ErrUserUnavailable)
+			}
+		}
+	})
+	return results
+}
+
+// GetUsersByIDsBatch is GetUsersByIDs one batch at a time, for callers that
+// want the users rather than per-ID results: it returns the users that
+// resolved, in input order, leaving out suspended and deactivated accounts.
+// On error it returns the users resolved before the failing ID.
+func (c *Client) GetUsersByIDsBatch(ctx context.Context, ids []string) ([]*TwitterUser, error) {
+	users := make([]*TwitterUser, 0, len(ids))
+	for _, r := range c.GetUsersByIDs(ctx, ids, 1) {
+		switch {
+		case r.Err == nil:
+			users = append(users, r.User)
+		case !errors.Is(r.Err, ErrUserUnavailable):
+			return users, r.Err
+		}
+	}
+	return users, nil
+}
+
+// runBounded calls fn(0..n-1) with at most concurrency calls running at once
+// and waits for all of them. Once ctx is done the remaining items are passed
+// to fn inline so it can record the cancellation per item.
+func runBounded(ctx context.Context, n, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fn(i)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// bulkProgress counts completed items and reports them to the progress
+// callbacks.
+type bulkProgress struct {
+	mu       sync.Mutex
+	done     int
+	total    int
+	start    time.Time
+	fn       func(done, total int)
+	eta      func(BulkProgress)
+	estimate func(remaining int) time.Duration
+}
+
+func newBulkProgress(total int, opts []BulkOption, estimate func(remaining int) time.Duration) *bulkProgress {
+	var o bulkOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &bulkProgress{total: total, start: time.Now(), fn: o.progress, eta: o.eta, estimate: estimate}
+}
+
+func (p *bulkProgress) add(n int) {
+	if (p.fn == nil && p.eta == nil) || n == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	if p.fn != nil {
+		p.fn(p.done, p.total)
+	}
+	if p.eta != nil {
+		elapsed := time.Since(p.start)
+		p.eta(BulkProgress{Done: p.done, Total: p.total, Elapsed: elapsed, ETA: projectETA(elapsed, p.done, p.total, p.estimate)})
+	}
+}
+
+// projectETA extrapolates the pace of done items out of total, taking the
+// rate-limit estimate for the rest instead when it is longer.
+func projectETA(elapsed time.Duration, done, total int, estimate func(int) time.Duration) time.Duration {
+	left := total - done
+	if left <= 0 {
+		return 0
+	}
+	eta := time.Duration(float64(elapsed) / float64(done) * float64(left))
+	if estimate != nil {
+		if limit := estimate(left); limit < 0 || limit > eta {
+			return limit
+		}
+	}
+	return eta
+}
@@ -0,0 +1,151 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBounded(t *testing.T) {
+	var running, peak atomic.Int32
+	seen := make([]bool, 20)
+	runBounded(context.Background(), len(seen), 3, func(i int) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		seen[i] = true
+		running.Add(-1)
+	})
+	for i, ok := range seen {
+		if !ok {
+			t.Fatalf("item %d not processed", i)
+		}
+	}
+	if p := peak.Load(); p > 3 {
+		t.Fatalf("peak concurrency %d exceeds limit 3", p)
+	}
+}
+
+func TestGetUsersByScreenNames_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var last int
+	c := &Client{}
+	res := c.GetUsersByScreenNames(ctx, []string{"a", "b", "c"}, 2, WithProgress(func(done, total int) {
+		if total != 3 {
+			t.Errorf("total = %d, want 3", total)
+		}
+		last = done
+	}))
+	if len(res) != 3 {
+		t.Fatalf("got %d results", len(res))
+	}
+	for i, r := range res {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Fatalf("result %d: err = %v, want context.Canceled", i, r.Err)
+		}
+	}
+	if last != 3 {
+		t.Fatalf("progress ended at %d, want 3", last)
+	}
+}
+
+func TestGetUsersByIDs_InvalidIDs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res := (&Client{}).GetUsersByIDs(ctx, []string{"abc", "12", "0"}, 1)
+	var verr *ValidationError
+	if !errors.As(res[0].Err, &verr) || !errors.As(res[2].Err, &verr) {
+		t.Fatalf("invalid IDs should fail validation: %v, %v", res[0].Err, res[2].Err)
+	}
+	if !errors.Is(res[1].Err, context.Canceled) {
+		t.Fatalf("valid ID: err = %v, want context.Canceled", res[1].Err)
+	}
+	if res[1].Input != "12" {
+		t.Fatalf("input not preserved: %q", res[1].Input)
+	}
+}
+
+// usersByIDTransport answers UsersByRestIds with a user per requested ID,
+// except suspended ones, and counts requests.
+type usersByIDTransport struct {
+	suspended map[string]bool
+	requests  atomic.Int32
+}
+
+func (u *usersByIDTransport) Do(_ context.Context, _, rawURL string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	u.requests.Add(1)
+	parsed, _ := url.Parse(rawURL)
+	var vars struct {
+		UserIDs []string `json:"userIds"`
+	}
+	_ = json.Unmarshal([]byte(parsed.Query().Get("variables")), &vars)
+	var users []string
+	for _, id := range vars.UserIDs {
+		if !u.suspended[id] {
+			users = append(users, fmt.Sprintf(`{"result":{"__typename":"User","rest_id":"%s","legacy":{"screen_name":"u%s"}}}`, id, id))
+		}
+	}
+	return []byte(`{"data":{"users":[` + strings.Join(users, ",") + `]}}`), nil, 200, nil
+}
+
+func TestGetUsersByIDsBatch(t *testing.T) {
+	tr := &usersByIDTransport{suspended: map[string]bool{"150": true}}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, 250)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + 1)
+	}
+
+	users, err := c.GetUsersByIDsBatch(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 249 || users[0].ID != "1" || users[149].ID != "151" {
+		t.Fatalf("got %d users, want 249 in input order without 150", len(users))
+	}
+	if n := tr.requests.Load(); n != 2 {
+		t.Fatalf("%d requests, want 2", n)
+	}
+
+	if _, err := c.GetUsersByIDsBatch(context.Background(), []string{"1", "x"}); err == nil {
+		t.Fatal("invalid ID accepted")
+	}
+}
+
+func TestProjectETA(t *testing.T) {
+	pace := projectETA(10*time.Second, 10, 40, func(int) time.Duration { return time.Second })
+	if pace != 30*time.Second {
+		t.Fatalf("pace ETA = %v, want 30s", pace)
+	}
+	limited := projectETA(10*time.Second, 10, 40, func(left int) time.Duration { return time.Duration(left) * time.Minute })
+	if limited != 30*time.Minute {
+		t.Fatalf("rate-limited ETA = %v, want 30m", limited)
+	}
+	if eta := projectETA(time.Second, 5, 5, nil); eta != 0 {
+		t.Fatalf("finished ETA = %v, want 0", eta)
+	}
+}
@@ -0,0 +1,103 @@
+package twitter
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw GraphQL response bodies for read endpoints. Implementations
+// must be safe for concurrent use; MemoryCache is the built-in one.
+type Cache interface {
+	// Get returns the cached body for key, or false if absent or expired.
+	Get(key string) ([]byte, bool)
+	// Set stores body under key for ttl.
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+// DefaultCacheTTL is used when ClientConfig.Cache is set without CacheTTL.
+// Profiles change rarely; timelines are only deduplicated over short bursts.
+var DefaultCacheTTL = map[string]time.Duration{
+	"UserByScreenName": 10 * time.Minute,
+	"UserByRestId":     10 * time.Minute,
+	"UsersByRestIds":   10 * time.Minute,
+	"UserTweets":       1 * time.Minute,
+}
+
+// cacheLookup returns the cache key and TTL for a GET, or a zero TTL when the
+// endpoint is not cached. X Pro operations share entries with their web
+// counterparts since the responses are identical.
+func (c *Client) cacheLookup(endpoint, rawURL string) (string, time.Duration) {
+	if c.cfg.Cache == nil {
+		return "", 0
+	}
+	op := strings.TrimPrefix(endpoint, tweetdeckPrefix)
+	ttl := c.cfg.CacheTTL[op]
+	if ttl <= 0 {
+		return "", 0
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", 0
+	}
+	return op + "?" + u.Query().Get("variables"), ttl
+}
+
+// memoryCacheSweepEvery is the number of Set calls between expired-entry sweeps.
+const memoryCacheSweepEvery = 256
+
+// MemoryCache is an in-process Cache with per-entry expiry.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	sets    int
+}
+
+type memoryCacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.body, true
+}
+
+// Set implements Cache. Expired entries are swept periodically.
+func (m *MemoryCache) Set(key string, body []byte, ttl time.Duration) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryCacheEntry{body: body, expiresAt: now.Add(ttl)}
+	m.sets++
+	if m.sets%memoryCacheSweepEvery == 0 {
+		for k, e := range m.entries {
+			if now.After(e.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+	}
+}
+
+// Len returns the number of stored entries, including expired ones not yet swept.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
@@ -0,0 +1,53 @@
+package twitter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	m := NewMemoryCache()
+	m.Set("a", []byte("1"), time.Minute)
+	m.Set("b", []byte("2"), -time.Second)
+
+	if body, ok := m.Get("a"); !ok || string(body) != "1" {
+		t.Fatalf("Get(a) = %q, %v", body, ok)
+	}
+	if _, ok := m.Get("b"); ok {
+		t.Fatal("expired entry returned")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expired entry not dropped on Get, len = %d", m.Len())
+	}
+}
+
+func TestCacheLookup(t *testing.T) {
+	cfg := ClientConfig{Cache: NewMemoryCache()}
+	cfg.defaults()
+	c := &Client{cfg: cfg}
+
+	url := addGraphQLParams(Endpoints["UserByScreenName"].URL(), map[string]any{"screen_name": "jack"}, nil)
+	key, ttl := c.cacheLookup("UserByScreenName", url)
+	if ttl != 10*time.Minute {
+		t.Fatalf("ttl = %v, want 10m", ttl)
+	}
+	if key != `UserByScreenName?{"screen_name":"jack"}` {
+		t.Fatalf("key = %q", key)
+	}
+
+	// X Pro variant shares the web entry.
+	tdURL := addGraphQLParams(Endpoints["TweetDeck/UserTweets"].URL(), map[string]any{"userId": "1"}, nil)
+	webURL := addGraphQLParams(Endpoints["UserTweets"].URL(), map[string]any{"userId": "1"}, nil)
+	tdKey, _ := c.cacheLookup("TweetDeck/UserTweets", tdURL)
+	webKey, _ := c.cacheLookup("UserTweets", webURL)
+	if tdKey != webKey {
+		t.Fatalf("keys differ: %q vs %q", tdKey, webKey)
+	}
+
+	if _, ttl := c.cacheLookup("Followers", url); ttl != 0 {
+		t.Fatalf("uncached endpoint got ttl %v", ttl)
+	}
+	if _, ttl := (&Client{}).cacheLookup("UserByScreenName", url); ttl != 0 {
+		t.Fatal("client without cache must not cache")
+	}
+}
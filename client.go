@@ -3,6 +3,7 @@ package twitter
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -13,19 +14,35 @@ import (
 
 	stealth "github.com/anatolykoptev/go-stealth"
 	"github.com/anatolykoptev/go-stealth/pool"
-	"github.com/anatolykoptev/go-stealth/ratelimit"
 	"github.com/anatolykoptev/go-twitter/xpff"
 	"github.com/anatolykoptev/go-twitter/xtid"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
 // Client is the top-level Twitter scraping client.
 type Client struct {
-	client      *stealth.BrowserClient
-	pool        *pool.Pool[*Account]
-	xtidMgr     *xtid.Manager
-	xpffGen     *xpff.Generator
-	cfg         ClientConfig
-	reloginGate AutoReloginGate // nil = always allow
+	client        *stealth.BrowserClient
+	pool          *pool.Pool[*Account]
+	xtidMgr       *xtid.Manager
+	xtidPerClient xtidManagers // per account client; used with PerProxyXTID
+	xpffGen       *xpff.Generator
+	cfg           ClientConfig
+	reloginGate   AutoReloginGate // nil = always allow
+	metrics       *Metrics
+	tracer        trace.Tracer
+	flight        singleflight.Group  // dedupes concurrent identical GETs
+	adaptive      *adaptiveController // nil unless cfg.AdaptiveConcurrency is set
+	globalLimiter *rate.Limiter       // nil unless cfg.GlobalRateLimit is set
+	affinity      affinityTable       // sticky account per affinity key
+	queue         requestQueue        // priority turns for requests waiting on an account
+	learned       learnedFeatures     // flags added after missing-feature errors
+	pageSizes     pageSizes           // reduced page sizes per paginated operation
+	bearer        *bearerRotation     // nil = always BearerToken
+	health        *healthSaver        // nil unless health or action counts persist
+	vcr           *vcr                // nil unless ClientConfig.VCR is set
+	conns         *connCounter        // nil unless ClientConfig.Connections or DNS is set
 
 	mu                sync.Mutex
 	guestToken        string
@@ -38,15 +55,22 @@ type Client struct {
 func NewClient(cfg ClientConfig) (*Client, error) {
 	cfg.defaults()
 
+	var conns *connCounter
+	if cfg.Connections != nil || cfg.DNS != nil {
+		conns = &connCounter{}
+	}
+	backend := cfg.backendOptions(conns)
+
 	for _, acc := range cfg.Accounts {
 		acc.active = true
-		acc.rateLimiter = ratelimit.NewLimiter(cfg.RateLimit)
+		acc.setupLimiters(&cfg)
 		acc.HealthTracker = pool.DefaultHealthTracker()
+		acc.clientOpts = backend
 	}
 
-	opts := []stealth.ClientOption{
+	opts := append([]stealth.ClientOption{
 		stealth.WithHeaderOrder(twitterHeaderOrder),
-	}
+	}, backend...)
 	if cfg.DefaultProxy != "" {
 		opts = append(opts, stealth.WithProxy(cfg.DefaultProxy))
 	}
@@ -55,9 +79,21 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 		return nil, fmt.Errorf("stealth client: %w", err)
 	}
 
-	mgr := xtid.NewManager()
-	if err := mgr.Initialize(); err != nil {
-		slog.Warn("xtid: init failed, x-client-transaction-id will be missing", slog.Any("error", err))
+	online := cfg.Transport == nil && (cfg.VCR == nil || cfg.VCR.Mode != VCRReplay)
+	xtidOpts := []xtid.Option{xtid.WithFetcher(browserFetcher(bc))}
+	if online {
+		xtidOpts = append(xtidOpts, xtid.WithCacheFile(sessionDirFile(cfg.SessionDir, xtidCacheFile)))
+	}
+	mgr := xtid.NewManager(xtidOpts...)
+	if online && !mgr.Fresh() {
+		if err := mgr.Initialize(); err != nil {
+			slog.Warn("xtid: init failed, x-client-transaction-id will be missing", slog.Any("error", err))
+		}
+	}
+	if cfg.DiscoverEndpoints {
+		if _, err := NewEndpointResolver(mgr).Resolve(context.Background()); err != nil {
+			slog.Warn("endpoint discovery failed, using built-in query IDs", slog.Any("error", err))
+		}
 	}
 
 	alertHook := cfg.PoolAlertHook
@@ -89,15 +125,35 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 		xtidMgr: mgr,
 		xpffGen: xpffGen,
 		cfg:     cfg,
+		metrics: newMetrics(),
+		tracer:  newTracer(cfg.TracerProvider),
+
+		bearer:        newBearerRotation(cfg.BearerToken),
+		adaptive:      newAdaptiveController(cfg.AdaptiveConcurrency),
+		globalLimiter: newGlobalLimiter(&cfg),
+		vcr:           newVCR(cfg.VCR),
+		conns:         conns,
+	}
+
+	if err := c.learned.load(cfg.SessionDir); err != nil {
+		slog.Warn("load learned features failed", slog.Any("error", err))
 	}
 
 	for _, acc := range cfg.Accounts {
+		saved, err := readSession(cfg.SessionDir, acc.Username)
+		if err != nil {
+			slog.Warn("error loading session", slog.String("user", acc.Username), slog.Any("error", err))
+		}
+		resolveProfile(acc, saved.Profile)
+
+		if acc.Proxy == "" && cfg.ProxyPool != nil {
+			if proxy, ok := cfg.ProxyPool.Assign(acc.Username); ok {
+				acc.Proxy = proxy
+				acc.pooledProxy = true
+			}
+		}
 		if acc.Proxy != "" {
-			accClient, err := stealth.NewClient(
-				stealth.WithProxy(acc.Proxy),
-				stealth.WithProfile(acc.Profile.TLSProfile),
-				stealth.WithHeaderOrder(twitterHeaderOrder),
-			)
+			accClient, err := newAccountClient(acc, acc.Proxy)
 			if err != nil {
 				slog.Warn("per-account client failed", slog.String("user", acc.Username), slog.Any("error", err))
 			} else {
@@ -121,47 +177,85 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 				slog.Warn("open account failed", slog.Int("attempt", i+1), slog.Any("error", err))
 				continue
 			}
-			acc.rateLimiter = ratelimit.NewLimiter(cfg.RateLimit)
+			acc.setupLimiters(&cfg)
 			acc.HealthTracker = pool.DefaultHealthTracker()
 			p.Add(acc)
 		}
 	}
 
+	if cfg.PersistHealth || cfg.ActionQuotas != nil {
+		// Action counts persist even without PersistHealth, or a restart
+		// would reset every quota.
+		c.health = &healthSaver{actionsOnly: !cfg.PersistHealth}
+		if err := c.restoreHealth(); err != nil {
+			slog.Warn("restore account health failed", slog.Any("error", err))
+		}
+	}
+
 	return c, nil
 }
 
 // clientForAccount returns the per-account client if available, otherwise the shared client.
 func (c *Client) clientForAccount(acc *Account) *stealth.BrowserClient {
-	if acc.client != nil {
-		return acc.client
+	acc.mu.Lock()
+	bc := acc.client
+	acc.mu.Unlock()
+	if bc != nil {
+		return bc
 	}
 	return c.client
 }
 
+// newAccountClient builds a browser client for acc that egresses via proxy,
+// filling in the account's proxy session placeholders.
+func newAccountClient(acc *Account, proxy string) (*stealth.BrowserClient, error) {
+	return stealth.NewClient(append([]stealth.ClientOption{
+		stealth.WithProxy(acc.renderedProxy(proxy)),
+		stealth.WithProfile(acc.Profile.TLSProfile),
+		stealth.WithHeaderOrder(twitterHeaderOrder),
+	}, acc.clientOpts...)...)
+}
+
 // doPoolReq is a helper for doPoolRequest: executes method+payload via doRequestWithBody.
-func (c *Client) doPoolReq(bc *stealth.BrowserClient, method, urlStr string, payload []byte, headers map[string]string) ([]byte, map[string]string, int, error) {
+func (c *Client) doPoolReq(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, payload []byte, headers map[string]string) ([]byte, map[string]string, int, error) {
 	var body io.Reader
 	if len(payload) > 0 {
 		body = bytes.NewReader(payload)
 	}
-	return c.doRequestWithBody(bc, method, urlStr, headers, body)
+	return c.doRequestWithBody(ctx, bc, method, urlStr, headers, body)
 }
 
 // doRequest executes a request with xtid header injection (no body).
-func (c *Client) doRequest(bc *stealth.BrowserClient, method, urlStr string, headers map[string]string) ([]byte, map[string]string, int, error) {
-	return c.doRequestWithBody(bc, method, urlStr, headers, nil)
+func (c *Client) doRequest(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string) ([]byte, map[string]string, int, error) {
+	return c.doRequestWithBody(ctx, bc, method, urlStr, headers, nil)
 }
 
 // doRequestWithBody executes a request with xtid header injection and an optional body.
-func (c *Client) doRequestWithBody(bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+func (c *Client) doRequestWithBody(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	urlStr = c.cfg.Domain.rewriteURL(urlStr)
+	if o := oauth1From(ctx); o != nil && isRESTv11(urlStr) {
+		signedURL, signedBody, err := signOAuth1(o, method, urlStr, headers, body)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("oauth1: %w", err)
+		}
+		return c.execute(ctx, bc, method, signedURL, headers, signedBody)
+	}
+	// Mobile apps call the API host with their own bearer and send neither
+	// xtid nor xpff.
+	if isMobileRequest(headers) {
+		return c.execute(ctx, bc, method, mobileURL(urlStr), headers, body)
+	}
 	urlPath := urlStr
 	if u, parseErr := url.Parse(urlStr); parseErr == nil {
 		urlPath = u.Path
 	}
-	if txID, txErr := c.xtidMgr.GenerateID(method, urlPath); txErr == nil {
-		headers["x-client-transaction-id"] = txID
-	} else {
-		slog.Debug("xtid: failed to generate transaction id", slog.Any("error", txErr))
+	// Offline clients skip xtid: generating one fetches x.com to load keys.
+	if !c.offline() {
+		if txID, txErr := c.xtidFor(bc, headers["user-agent"]).GenerateID(method, urlPath); txErr == nil {
+			headers["x-client-transaction-id"] = txID
+		} else {
+			slog.Debug("xtid: failed to generate transaction id", slog.Any("error", txErr))
+		}
 	}
 
 	if xpffVal, xpffErr := c.xpffGen.Generate(); xpffErr == nil {
@@ -170,7 +264,40 @@ func (c *Client) doRequestWithBody(bc *stealth.BrowserClient, method, urlStr str
 		slog.Debug("xpff: failed to generate header", slog.Any("error", xpffErr))
 	}
 
-	return bc.DoWithHeaderOrder(method, urlStr, headers, body, twitterHeaderOrder)
+	if _, ok := headers["authorization"]; ok {
+		c.setBearer(headers)
+	}
+	if isTweetDeckURL(urlStr) {
+		headers["origin"] = tweetdeckOrigin
+		headers["referer"] = tweetdeckOrigin + "/"
+	} else {
+		c.cfg.Domain.setOrigin(headers)
+	}
+
+	return c.execute(ctx, bc, method, urlStr, headers, body)
+}
+
+// errAttemptTimeout marks an attempt cut off by ClientConfig.RequestTimeout.
+// The request may still have reached the server, so callers must not resend
+// a non-idempotent request that failed with it.
+var errAttemptTimeout = errors.New("request attempt timed out")
+
+// execute sends the request on bc, bounded by ctx and RequestTimeout. The
+// browser client returns as soon as the context is done, but cannot abort a
+// request already on the wire: it finishes in the background and its
+// response is dropped.
+func (c *Client) execute(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	attemptCtx := ctx
+	if c.cfg.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, c.cfg.RequestTimeout)
+		defer cancel()
+	}
+	respBody, respHeaders, status, err := c.send(attemptCtx, bc, method, urlStr, headers, body)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return nil, nil, 0, fmt.Errorf("%s %s: %w after %s", method, urlStr, errAttemptTimeout, c.cfg.RequestTimeout)
+	}
+	return respBody, respHeaders, status, err
 }
 
 // Pool returns the underlying account pool.
@@ -215,11 +342,18 @@ func (c *Client) HealthReport() []AccountHealth {
 	return report
 }
 
-// recordAPICall calls the metrics hook if configured.
-func (c *Client) recordAPICall(endpoint string, success, rateLimited bool) {
+// recordAPICall updates built-in metrics and calls the metrics hook if configured.
+func (c *Client) recordAPICall(endpoint string, status int, success, rateLimited bool) {
+	c.metrics.observeRequest(endpoint, status, success, rateLimited)
+	if success {
+		c.bearer.success()
+	}
 	if c.cfg.MetricsHook != nil {
 		c.cfg.MetricsHook(endpoint, success, rateLimited)
 	}
+	if !success && !c.health.savesActionsOnly() {
+		c.health.schedule(c)
+	}
 }
 
 // setGuestToken stores a fresh guest token.
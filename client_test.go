@@ -0,0 +1,62 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute_ContextDoneBeforeSend(t *testing.T) {
+	c := &Client{cfg: ClientConfig{RequestTimeout: time.Second}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, _, err := c.execute(ctx, nil, "GET", "https://x.com/i/api/graphql/x/UserByScreenName", map[string]string{}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// hangingTransport answers nothing until the request's context is done.
+type hangingTransport struct{ calls atomic.Int32 }
+
+func (h *hangingTransport) Do(ctx context.Context, _, _ string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	h.calls.Add(1)
+	<-ctx.Done()
+	return nil, nil, 0, ctx.Err()
+}
+
+func TestDoPOST_AttemptTimeoutIsFinal(t *testing.T) {
+	tr := &hangingTransport{}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		RequestTimeout:       20 * time.Millisecond,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	_, err = c.doPOST(context.Background(), c.AccountByUsername("u"), "CreateTweet", "https://x.com/i/api/graphql/x/CreateTweet", []byte(`{}`))
+	assert.ErrorIs(t, err, errAttemptTimeout)
+	assert.Equal(t, int32(1), tr.calls.Load(), "a write that may have landed is not resent")
+}
+
+func TestIsProxyError_AttemptTimeout(t *testing.T) {
+	err := fmt.Errorf("GET https://x.com/: %w after %s", errAttemptTimeout, 30*time.Second)
+	assert.True(t, isProxyError(err), "a hung attempt counts against the proxy")
+	assert.False(t, isContextError(err), "an attempt timeout is not the caller's deadline")
+}
+
+func TestConfigDefaults_RequestTimeout(t *testing.T) {
+	cfg := ClientConfig{}
+	cfg.defaults()
+	assert.Equal(t, 30*time.Second, cfg.RequestTimeout)
+
+	cfg = ClientConfig{RequestTimeout: -1}
+	cfg.defaults()
+	assert.Negative(t, cfg.RequestTimeout, "negative disables the timeout")
+}
@@ -0,0 +1,45 @@
+// Command twitter-mcp runs the mcpserver tools over stdio, for MCP clients
+// that launch their servers as subprocesses. Configure it in the client
+// with the environment:
+//
+//	TWITTER_ACCOUNTS     accounts in the twitter.ParseAccounts format
+//	TWITTER_PROXY        default proxy for accounts without their own
+//	TWITTER_SESSION_DIR  session directory (default ~/.go-twitter/sessions)
+//
+// Logs go to stderr; stdout carries the protocol.
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+	"github.com/anatolykoptev/go-twitter/mcpserver"
+)
+
+func main() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err := run(); err != nil {
+		slog.Error("twitter-mcp", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	client, err := twitter.NewClient(twitter.ClientConfig{
+		Accounts:     twitter.ParseAccounts(os.Getenv("TWITTER_ACCOUNTS")),
+		DefaultProxy: os.Getenv("TWITTER_PROXY"),
+		SessionDir:   os.Getenv("TWITTER_SESSION_DIR"),
+	})
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return mcpserver.New(client, mcpserver.Options{}).Run(ctx, &mcp.StdioTransport{})
+}
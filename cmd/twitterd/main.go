@@ -0,0 +1,96 @@
+// Command twitterd serves the read endpoints of a go-twitter client over
+// HTTP, so that services in other languages can use one account pool.
+//
+// It is configured from the environment:
+//
+//	TWITTERD_ADDR        listen address (default ":8080")
+//	TWITTERD_API_KEYS    comma-separated keys accepted in X-API-Key or
+//	                     "Authorization: Bearer"; required
+//	TWITTER_ACCOUNTS     accounts in the twitter.ParseAccounts format
+//	TWITTER_PROXY        default proxy for accounts without their own
+//	TWITTER_SESSION_DIR  session directory (default ~/.go-twitter/sessions)
+//
+// Routes (JSON; count defaults to 20, at most 200):
+//
+//	GET /v1/users/{handle}
+//	GET /v1/users/{id}/tweets?count=
+//	GET /v1/users/{id}/followers?cursor=&count=
+//	GET /v1/users/{id}/following?cursor=&count=
+//	GET /v1/tweets/{id}
+//	GET /v1/tweets/{id}/thread
+//	GET /v1/search?q=&cursor=&count=
+//	GET /admin/...    the client's AdminHandler (/healthz, /accounts, ...)
+//	GET /healthz      unauthenticated liveness probe
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+func main() {
+	if err := run(); err != nil {
+		slog.Error("twitterd", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	keys := splitList(os.Getenv("TWITTERD_API_KEYS"))
+	if len(keys) == 0 {
+		return errors.New("TWITTERD_API_KEYS is required")
+	}
+	addr := os.Getenv("TWITTERD_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+
+	client, err := twitter.NewClient(twitter.ClientConfig{
+		Accounts:     twitter.ParseAccounts(os.Getenv("TWITTER_ACCOUNTS")),
+		DefaultProxy: os.Getenv("TWITTER_PROXY"),
+		SessionDir:   os.Getenv("TWITTER_SESSION_DIR"),
+	})
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           newServer(client, keys, client.AdminHandler()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("twitterd listening", slog.String("addr", addr))
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+const (
+	defaultCount = 20
+	maxCount     = 200
+)
+
+// server serves the read endpoints of api to callers holding one of keys.
+type server struct {
+	api  twitter.TwitterAPI
+	keys [][sha256.Size]byte
+}
+
+// newServer returns the twitterd handler. admin, if not nil, is mounted
+// under /admin/ behind the same API keys.
+func newServer(api twitter.TwitterAPI, keys []string, admin http.Handler) http.Handler {
+	s := &server{api: api}
+	for _, k := range keys {
+		s.keys = append(s.keys, sha256.Sum256([]byte(k)))
+	}
+
+	v1 := http.NewServeMux()
+	v1.HandleFunc("GET /v1/users/{handle}", s.user)
+	v1.HandleFunc("GET /v1/users/{id}/tweets", s.userTweets)
+	v1.HandleFunc("GET /v1/users/{id}/followers", s.users(api.GetFollowersPaged))
+	v1.HandleFunc("GET /v1/users/{id}/following", s.users(api.GetFollowingPaged))
+	v1.HandleFunc("GET /v1/tweets/{id}", s.tweet)
+	v1.HandleFunc("GET /v1/tweets/{id}/thread", s.thread)
+	v1.HandleFunc("GET /v1/search", s.search)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("/v1/", s.authorize(v1))
+	if admin != nil {
+		mux.Handle("/admin/", s.authorize(http.StripPrefix("/admin", admin)))
+	}
+	return mux
+}
+
+// authorize rejects requests without a valid key in X-API-Key or an
+// "Authorization: Bearer" header.
+func (s *server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if key == "" {
+			if v, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+				key = strings.TrimSpace(v)
+			}
+		}
+		if key == "" || !s.validKey(key) {
+			writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validKey compares key against every configured key in constant time.
+func (s *server) validKey(key string) bool {
+	sum := sha256.Sum256([]byte(key))
+	ok := 0
+	for i := range s.keys {
+		ok |= subtle.ConstantTimeCompare(sum[:], s.keys[i][:])
+	}
+	return ok == 1
+}
+
+// page is the body of list responses; NextCursor is passed back as
+// ?cursor= to fetch the following page.
+type page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (s *server) user(w http.ResponseWriter, r *http.Request) {
+	u, err := s.api.GetUserByScreenName(r.Context(), r.PathValue("handle"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, u)
+}
+
+func (s *server) userTweets(w http.ResponseWriter, r *http.Request) {
+	count, ok := countParam(w, r)
+	if !ok {
+		return
+	}
+	res := s.api.GetUserTweetsPaged(r.Context(), r.PathValue("id"), r.URL.Query().Get("cursor"), count)
+	writePage(w, res)
+}
+
+// users serves a paged user list fetched by fetch.
+func (s *server) users(fetch func(ctx context.Context, userID, cursor string, maxCount int) twitter.PagedResult[*twitter.TwitterUser]) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, ok := countParam(w, r)
+		if !ok {
+			return
+		}
+		writePage(w, fetch(r.Context(), r.PathValue("id"), r.URL.Query().Get("cursor"), count))
+	}
+}
+
+func (s *server) tweet(w http.ResponseWriter, r *http.Request) {
+	t, err := s.api.GetTweetByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *server) thread(w http.ResponseWriter, r *http.Request) {
+	tweets, err := s.api.GetThread(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page[*twitter.Tweet]{Data: tweets})
+}
+
+func (s *server) search(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	count, ok := countParam(w, r)
+	if !ok {
+		return
+	}
+	writePage(w, s.api.SearchTimelinePaged(r.Context(), q, r.URL.Query().Get("cursor"), count))
+}
+
+// countParam parses ?count=, defaulting to defaultCount and capped at
+// maxCount. It writes a 400 and reports false if count is malformed.
+func countParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("count")
+	if raw == "" {
+		return defaultCount, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		writeError(w, http.StatusBadRequest, "count must be a positive integer")
+		return 0, false
+	}
+	return min(n, maxCount), true
+}
+
+// writePage writes res, or its error if no page was fetched. A page cut
+// short by an error is still served; its cursor resumes from where it
+// stopped.
+func writePage[T any](w http.ResponseWriter, res twitter.PagedResult[T]) {
+	if res.PartialErr != nil && res.Pages == 0 {
+		writeAPIError(w, res.PartialErr)
+		return
+	}
+	if res.PartialErr != nil {
+		slog.Warn("twitterd: partial page", slog.Any("error", res.PartialErr))
+	}
+	data := res.Items
+	if data == nil {
+		data = []T{}
+	}
+	writeJSON(w, http.StatusOK, page[T]{Data: data, NextCursor: res.NextCursor})
+}
+
+// writeAPIError maps a client error to an HTTP status.
+func writeAPIError(w http.ResponseWriter, err error) {
+	var invalid *twitter.ValidationError
+	status := http.StatusBadGateway
+	switch {
+	case errors.As(err, &invalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, twitter.ErrTweetUnavailable), errors.Is(err, twitter.ErrUserUnavailable):
+		status = http.StatusNotFound
+	case errors.Is(err, twitter.ErrPoolExhausted):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	}
+	if status == http.StatusBadGateway {
+		slog.Warn("twitterd: upstream error", slog.Any("error", err))
+	}
+	writeError(w, status, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
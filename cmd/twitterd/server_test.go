@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+// fakeAPI serves canned data; methods not overridden panic through the
+// nil embedded interface.
+type fakeAPI struct {
+	twitter.TwitterAPI
+	lastCursor string
+	lastCount  int
+}
+
+func (f *fakeAPI) GetUserByScreenName(_ context.Context, handle string) (*twitter.TwitterUser, error) {
+	switch handle {
+	case "gone":
+		return nil, fmt.Errorf("parse: %w", twitter.ErrUserUnavailable)
+	case "busy":
+		return nil, fmt.Errorf("UserByScreenName: %w", twitter.ErrPoolExhausted)
+	}
+	return &twitter.TwitterUser{ID: "42", Handle: handle}, nil
+}
+
+func (f *fakeAPI) GetFollowersPaged(_ context.Context, userID, cursor string, maxCount int) twitter.PagedResult[*twitter.TwitterUser] {
+	f.lastCursor, f.lastCount = cursor, maxCount
+	return twitter.PagedResult[*twitter.TwitterUser]{
+		Items:      []*twitter.TwitterUser{{ID: "1"}, {ID: "2"}},
+		NextCursor: "c2",
+		Pages:      1,
+	}
+}
+
+func (f *fakeAPI) SearchTimelinePaged(_ context.Context, _, _ string, _ int, _ ...twitter.TweetPageOption) twitter.PagedResult[*twitter.Tweet] {
+	return twitter.PagedResult[*twitter.Tweet]{PartialErr: fmt.Errorf("upstream 500")}
+}
+
+func get(t *testing.T, h http.Handler, path string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_RequiresAPIKey(t *testing.T) {
+	h := newServer(&fakeAPI{}, []string{"k1", "k2"}, nil)
+
+	assert.Equal(t, http.StatusUnauthorized, get(t, h, "/v1/users/jack").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(t, h, "/v1/users/jack", "X-API-Key", "wrong").Code)
+	assert.Equal(t, http.StatusOK, get(t, h, "/v1/users/jack", "X-API-Key", "k2").Code)
+	assert.Equal(t, http.StatusOK, get(t, h, "/v1/users/jack", "Authorization", "Bearer k1").Code)
+	assert.Equal(t, http.StatusOK, get(t, h, "/healthz").Code, "health probe is open")
+}
+
+func TestServer_User(t *testing.T) {
+	h := newServer(&fakeAPI{}, []string{"k"}, nil)
+
+	rec := get(t, h, "/v1/users/jack", "X-API-Key", "k")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var u twitter.TwitterUser
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &u))
+	assert.Equal(t, "42", u.ID)
+	assert.Equal(t, "jack", u.Handle)
+
+	assert.Equal(t, http.StatusNotFound, get(t, h, "/v1/users/gone", "X-API-Key", "k").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, h, "/v1/users/busy", "X-API-Key", "k").Code)
+}
+
+func TestServer_PagedList(t *testing.T) {
+	api := &fakeAPI{}
+	h := newServer(api, []string{"k"}, nil)
+
+	rec := get(t, h, "/v1/users/42/followers?cursor=c1&count=500", "X-API-Key", "k")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var p page[*twitter.TwitterUser]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Len(t, p.Data, 2)
+	assert.Equal(t, "c2", p.NextCursor)
+	assert.Equal(t, "c1", api.lastCursor)
+	assert.Equal(t, maxCount, api.lastCount, "count is capped")
+
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/v1/users/42/followers?count=x", "X-API-Key", "k").Code)
+}
+
+func TestServer_Search(t *testing.T) {
+	h := newServer(&fakeAPI{}, []string{"k"}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, h, "/v1/search", "X-API-Key", "k").Code)
+	assert.Equal(t, http.StatusBadGateway, get(t, h, "/v1/search?q=go", "X-API-Key", "k").Code)
+}
+
+func TestServer_Admin(t *testing.T) {
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /accounts", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	h := newServer(&fakeAPI{}, []string{"k"}, admin)
+
+	assert.Equal(t, http.StatusUnauthorized, get(t, h, "/admin/accounts").Code)
+	assert.Equal(t, http.StatusTeapot, get(t, h, "/admin/accounts", "X-API-Key", "k").Code)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/anatolykoptev/go-stealth/ratelimit"
 	"github.com/anatolykoptev/go-twitter/captcha"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ClientConfig holds all configuration for the Twitter client.
@@ -24,12 +25,98 @@ type ClientConfig struct {
 	// BanCooldown is the soft-deactivation duration for banned/locked accounts.
 	BanCooldown time.Duration
 
+	// Domain selects the web domain for requests, origin/referer headers and
+	// the REST API host. Session cookies are read from both domains either
+	// way. Default: DomainX.
+	Domain Domain
+
+	// FeatureOverrides sets GraphQL feature flags per operation (Endpoints
+	// key), on top of the built-in flags and any learned from "features cannot
+	// be null" errors. Learned flags are kept in SessionDir.
+	FeatureOverrides map[string]map[string]any
+
+	// ResponseValidators check successful read responses per operation
+	// (Endpoints key); a rejected response is retried on another account.
+	// They replace the built-in ones (NonEmptyUserList for Followers,
+	// Following and Retweeters); a nil entry turns validation off.
+	ResponseValidators map[string]ResponseValidator
+
+	// APIv2 enables the official API v2 backend for supported reads, either
+	// per call (WithAPIv2) or as a fallback when the pool is exhausted.
+	APIv2 *APIv2Config
+
+	// Mirror serves GetUserByScreenName and GetUserTweets when both the pool
+	// and the guest token are exhausted (after APIv2, if that is configured
+	// as a fallback), e.g. &NitterMirror{BaseURL: ...}. Such responses are
+	// marked SourceMirror in RequestInfo.
+	Mirror Mirror
+
+	// TweetEnrichers run on every tweet a read call returns, after the
+	// built-in $TICKER extraction, e.g. RegexpEnricher for contract addresses.
+	// They may add to Tweet.Entities or rewrite any field.
+	TweetEnrichers []func(*Tweet)
+
+	// DisableTokenMentions turns off the built-in $TICKER extraction, leaving
+	// Tweet.TokenMentions nil.
+	DisableTokenMentions bool
+
+	// MinimalUsers leaves the extended TwitterUser profile fields (Location,
+	// URL, PinnedTweetID, ...) empty, so large user lists kept in memory hold
+	// only the core fields.
+	MinimalUsers bool
+
+	// BearerToken overrides the web-app bearer token sent with requests. The
+	// built-in tokens remain as fallbacks: after repeated 403s not explained
+	// by the account, the client moves to the next token.
+	BearerToken string
+
+	// DiscoverEndpoints refreshes GraphQL query IDs and feature flags from the
+	// x.com web bundle once in NewClient. For periodic refresh, run
+	// Client.EndpointResolver().Run in a goroutine.
+	DiscoverEndpoints bool
+
 	// CaptchaSolver is the optional CAPTCHA solver for locked accounts.
 	CaptchaSolver captcha.Solver
 
-	// RateLimit configures per-account per-endpoint rate limiting.
+	// RateLimit configures per-account per-endpoint rate limiting for
+	// operations without an entry in EndpointLimits.
 	RateLimit ratelimit.Config
 
+	// EndpointLimits overrides per-operation limits (requests per window per
+	// account). Entries are merged over DefaultEndpointLimits; an entry with
+	// zero RequestsPerWindow falls back to RateLimit.
+	EndpointLimits map[string]ratelimit.Config
+
+	// ActionQuotas caps each account's write actions per UTC day by class
+	// (e.g. {ActionTweet: 50, ActionLike: 300}); a write past its cap fails
+	// with ErrQuotaExceeded. Counts are saved with account health in
+	// SessionDir and survive restarts, with or without PersistHealth.
+	// Default: nil (no caps).
+	ActionQuotas map[ActionClass]int
+
+	// PoolStrategy controls how the next account is picked from the pool.
+	// Default: StrategyRoundRobin.
+	PoolStrategy PoolStrategy
+
+	// StickyAffinity keeps requests about the same target (a user's tweets,
+	// followers or following; a tweet's retweeters) on the same account while
+	// it stays available, which looks more organic and avoids cursor
+	// invalidation mid-pagination. See also WithAffinity.
+	StickyAffinity bool
+
+	// AffinityTTL is how long an unused affinity binding is kept.
+	// Default: 30m.
+	AffinityTTL time.Duration
+
+	// GlobalRateLimit caps aggregate upstream requests per second across all
+	// accounts and the guest path, e.g. to protect a shared egress IP.
+	// Default: 0 (unlimited).
+	GlobalRateLimit float64
+
+	// GlobalBurst is the number of requests allowed at once under
+	// GlobalRateLimit. Default: GlobalRateLimit rounded up, at least 1.
+	GlobalBurst int
+
 	// OpenAccountCount is the number of anonymous guest accounts to create at startup.
 	OpenAccountCount int
 
@@ -47,20 +134,140 @@ type ClientConfig struct {
 	// ProxyBackoffMax is the maximum backoff for proxy failures.
 	ProxyBackoffMax time.Duration
 
+	// ProxyPool supplies proxies to accounts without their own Proxy and
+	// replaces a pooled proxy when it goes down, instead of only backing
+	// off. Call ProxyPool.Run to enable background health checks.
+	// Default: nil.
+	ProxyPool *ProxyPool
+
+	// PerProxyXTID gives each account client its own x-client-transaction-id
+	// state, bootstrapped by fetching x.com through that account's proxy with
+	// its User-Agent, so the page load and the API traffic share an exit IP
+	// and fingerprint. Costs one x.com fetch per account client, repeated
+	// after a proxy swap or session rotation. Default: false (one shared
+	// state, fetched through the default client).
+	PerProxyXTID bool
+
 	// PoolAlertHook is called when the pool emits alerts (account deactivation, proxy failures, etc.).
 	// topic is the alert type (e.g. "pool.deactivated"), payload contains details.
 	PoolAlertHook func(topic string, payload any)
 
+	// Transport, when set, sends every API and guest-token request instead
+	// of the TLS-fingerprinted browser client; login flows still use the
+	// network. It also skips the anti-fingerprint jitter and the
+	// x-client-transaction-id bootstrap. Meant for tests (see twittertest).
+	Transport Transport
+
+	// VCR records sanitized request/response pairs to disk (credentials
+	// stripped) or replays them instead of the network, e.g. to
+	// regression-test parsers against real payloads. Like Transport, replay
+	// skips the anti-fingerprint jitter and the x-client-transaction-id
+	// bootstrap. Default: nil (off).
+	VCR *VCRConfig
+
+	// RequestInterceptors run in order before every API and guest-token
+	// request and may rewrite its URL and headers, or answer it themselves
+	// (e.g. from a custom cache) by returning a response. ResponseInterceptors
+	// then run in order on every response, including those short-circuited,
+	// and may rewrite it or fail the request. Login flows are not
+	// intercepted.
+	RequestInterceptors  []RequestInterceptor
+	ResponseInterceptors []ResponseInterceptor
+
+	// RequestTimeout bounds each HTTP attempt, so a hung proxy costs one
+	// attempt instead of stalling the request until the caller's context
+	// ends. A timed-out attempt through a proxy counts as a proxy failure.
+	// Default: 30s; negative disables.
+	RequestTimeout time.Duration
+
+	// MaxResponseBytes bounds each response body, so a broken or hostile
+	// proxy cannot exhaust memory; larger responses fail with
+	// ErrResponseTooLarge. The browser clients decode bodies as they stream
+	// in and stop reading at the limit; bodies from a custom Transport or a
+	// VCR cassette are checked before and after decompression. Default:
+	// 32 MiB; negative disables.
+	MaxResponseBytes int64
+
+	// Connections tunes connection reuse (idle connections per host, idle
+	// timeout, HTTP/2) of every browser client and enables connection
+	// counts in Stats. Default: nil (the TLS client's own settings).
+	Connections *ConnectionConfig
+
+	// DNS resolves X's and the proxies' host names over DNS-over-HTTPS or
+	// from static entries instead of the system resolver, to keep lookups
+	// from leaking past the proxies. Default: nil (system resolver).
+	DNS *DNSConfig
+
+	// Retry sets the attempt count, backoff and 429 handling of pool
+	// requests and posts, optionally per operation. Default: nil (three
+	// attempts with stealth.DefaultBackoff, retrying 429s on another account).
+	Retry *RetryPolicy
+
+	// AccountEventHook is called synchronously whenever an account is
+	// deactivated, suspended, found locked, re-logged in (or fails to) or
+	// loses its proxy, so operators learn within minutes that accounts are
+	// dying. It must not block; WebhookNotifier.Hook posts events to a
+	// webhook, Slack or Telegram in the background.
+	AccountEventHook func(AccountEvent)
+
 	// DisableGuestFallback disables the guest-token fallback path entirely.
 	// When true, endpoints that would normally fall back to guest mode after
-	// pool exhaustion will return an error instead. Recommended in production
+	// pool exhaustion return ErrGuestFallbackDisabled instead (per call:
+	// WithoutGuestFallback). Recommended in production
 	// where guest tokens from datacenter IPs return persistent 403 errors.
 	// Default: false (guest fallback enabled for backward compatibility).
 	DisableGuestFallback bool
+
+	// Coordinator shares the account pool with Clients in other processes
+	// configured with the same accounts, e.g. redispool.New on one Redis:
+	// no account serves two requests at once and rate limits are counted
+	// once. Default: nil (accounts are this Client's alone).
+	Coordinator Coordinator
+
+	// PersistHealth saves account health (soft-deactivations, failure
+	// counts, rate-limit windows, proxy backoff, action counts) to
+	// SessionDir and restores it in NewClient. Default: false (every
+	// restart starts with healthy accounts).
+	PersistHealth bool
+
+	// GuestDowngradeOnDeadline lets auth-preferred reads that Twitter also
+	// serves to guests (see guestCapable) fall back to the guest-token path when
+	// no account frees up before the caller's context deadline, instead of
+	// returning pool-exhausted. Only applies when ctx has a deadline and
+	// DisableGuestFallback is false.
+	GuestDowngradeOnDeadline bool
+
+	// GuestDowngradeReserve is the part of the caller's remaining deadline kept
+	// for the guest request when GuestDowngradeOnDeadline is set.
+	// Default: 3s.
+	GuestDowngradeReserve time.Duration
+
+	// TracerProvider enables OpenTelemetry spans for pool requests, account
+	// POSTs, and login flows. Accounts are recorded as a short hash, never the
+	// username. Default: nil (tracing disabled).
+	TracerProvider trace.TracerProvider
+
+	// Cache enables response caching for GET read endpoints, keyed by
+	// operation and request variables. Only successful responses are stored.
+	// Default: nil (no caching).
+	Cache Cache
+
+	// CacheTTL sets per-operation TTLs; operations not listed are never
+	// cached. Default (when Cache is set): DefaultCacheTTL.
+	CacheTTL map[string]time.Duration
+
+	// AdaptiveConcurrency enables a feedback controller that limits in-flight
+	// requests, reads and writes alike, and adds jitter based on the recent
+	// 429/403 rate. A request counts as in flight once it has an account.
+	// Default: nil (unbounded concurrency, fixed jitter).
+	AdaptiveConcurrency *AdaptiveConfig
 }
 
 // defaults fills in zero-value config fields with sensible defaults.
 func (cfg *ClientConfig) defaults() {
+	if cfg.Domain == "" {
+		cfg.Domain = DomainX
+	}
 	if cfg.SessionTTL == 0 {
 		cfg.SessionTTL = 24 * time.Hour
 	}
@@ -73,10 +280,26 @@ func (cfg *ClientConfig) defaults() {
 	if cfg.RateLimit.RequestsPerWindow == 0 {
 		cfg.RateLimit = ratelimit.DefaultConfig
 	}
+	cfg.EndpointLimits = mergeEndpointLimits(cfg.EndpointLimits)
 	if cfg.ProxyBackoffInitial == 0 {
 		cfg.ProxyBackoffInitial = 30 * time.Second
 	}
 	if cfg.ProxyBackoffMax == 0 {
 		cfg.ProxyBackoffMax = 30 * time.Minute
 	}
+	if cfg.GuestDowngradeReserve == 0 {
+		cfg.GuestDowngradeReserve = 3 * time.Second
+	}
+	if cfg.RequestTimeout == 0 {
+		cfg.RequestTimeout = 30 * time.Second
+	}
+	if cfg.MaxResponseBytes == 0 {
+		cfg.MaxResponseBytes = defaultMaxResponseBytes
+	}
+	if cfg.AffinityTTL == 0 {
+		cfg.AffinityTTL = 30 * time.Minute
+	}
+	if cfg.Cache != nil && cfg.CacheTTL == nil {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
 }
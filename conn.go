@@ -0,0 +1,221 @@
+package twitter
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	fhttp "github.com/bogdanfinn/fhttp"
+	"github.com/bogdanfinn/fhttp/httptrace"
+	tls_client "github.com/bogdanfinn/tls-client"
+	"github.com/bogdanfinn/tls-client/profiles"
+)
+
+// ConnectionConfig tunes how the browser clients keep connections to X.
+// The defaults of the underlying TLS client keep few idle connections per
+// host, so bursts through slow (e.g. SOCKS) proxies pay a fresh TLS
+// handshake per request.
+type ConnectionConfig struct {
+	// MaxIdleConnsPerHost is how many idle connections each client keeps
+	// per host. Default: 8.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer. Default: 90s.
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 forces HTTP/1.1, for proxies that mishandle HTTP/2.
+	// Changes the client's fingerprint.
+	DisableHTTP2 bool
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool
+}
+
+const (
+	defaultMaxIdleConnsPerHost = 8
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// ConnStats counts the connections behind requests sent by the browser
+// clients; only collected when ClientConfig.Connections or DNS is set.
+type ConnStats struct {
+	Opened int64 // requests that dialed a new connection (TCP and TLS handshake)
+	Reused int64 // requests served on a kept-alive connection
+}
+
+// ReuseRatio is the share of requests served on a reused connection.
+func (s ConnStats) ReuseRatio() float64 {
+	if total := s.Opened + s.Reused; total > 0 {
+		return float64(s.Reused) / float64(total)
+	}
+	return 0
+}
+
+// connCounter accumulates ConnStats across all clients.
+type connCounter struct {
+	opened, reused atomic.Int64
+}
+
+func (c *connCounter) snapshot() ConnStats {
+	if c == nil {
+		return ConnStats{}
+	}
+	return ConnStats{Opened: c.opened.Load(), Reused: c.reused.Load()}
+}
+
+// backendOptions returns the client option installing the tuned backend,
+// and nil when neither ClientConfig.Connections, DNS nor MaxResponseBytes
+// asks for it. counter may be nil.
+func (cfg *ClientConfig) backendOptions(counter *connCounter) []stealth.ClientOption {
+	if cfg.Connections == nil && cfg.DNS == nil && cfg.MaxResponseBytes <= 0 {
+		return nil
+	}
+	var resolver *net.Resolver
+	if cfg.DNS != nil {
+		resolver = cfg.DNS.resolver()
+	}
+	return []stealth.ClientOption{stealth.WithBackend(cfg.Connections.backend(counter, cfg.MaxResponseBytes, resolver))}
+}
+
+// backend returns a stealth backend factory for the TLS client tuned by
+// cfg (nil: the TLS client's own settings), counting connections into
+// counter (nil: not counted), reading at most maxBody bytes per response
+// and resolving names with resolver (nil: the system's).
+func (cfg *ConnectionConfig) backend(counter *connCounter, maxBody int64, resolver *net.Resolver) stealth.BackendFactory {
+	var transport *tls_client.TransportOptions
+	forceHTTP1 := false
+	if cfg != nil {
+		conn := *cfg
+		if conn.MaxIdleConnsPerHost <= 0 {
+			conn.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+		}
+		if conn.IdleConnTimeout <= 0 {
+			conn.IdleConnTimeout = defaultIdleConnTimeout
+		}
+		transport = &tls_client.TransportOptions{
+			MaxIdleConnsPerHost: conn.MaxIdleConnsPerHost,
+			IdleConnTimeout:     &conn.IdleConnTimeout,
+			DisableKeepAlives:   conn.DisableKeepAlives,
+		}
+		forceHTTP1 = conn.DisableHTTP2
+	}
+	return func(bcfg stealth.BackendConfig) (stealth.HTTPDoer, error) {
+		opts := []tls_client.HttpClientOption{
+			tls_client.WithTimeoutSeconds(bcfg.TimeoutSeconds),
+			tls_client.WithClientProfile(tlsProfile(bcfg.Profile)),
+			tls_client.WithCookieJar(tls_client.NewCookieJar()),
+			tls_client.WithInsecureSkipVerify(),
+		}
+		if transport != nil {
+			opts = append(opts, tls_client.WithTransportOptions(transport))
+		}
+		if !bcfg.FollowRedirects {
+			opts = append(opts, tls_client.WithNotFollowRedirects())
+		}
+		if bcfg.ProxyURL != "" {
+			opts = append(opts, tls_client.WithProxyUrl(bcfg.ProxyURL))
+		}
+		if resolver != nil {
+			opts = append(opts, tls_client.WithDialer(net.Dialer{Resolver: resolver}))
+		}
+		if forceHTTP1 {
+			opts = append(opts, tls_client.WithForceHttp1())
+		}
+		hc, err := tls_client.NewHttpClient(nil, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("tls-client init: %w", err)
+		}
+		return &tunedDoer{client: hc, counter: counter, maxBody: maxBody}, nil
+	}
+}
+
+// tunedDoer is the stealth TLS client backend with transport options,
+// connection accounting and bounded, streamed body decoding.
+type tunedDoer struct {
+	client  tls_client.HttpClient
+	counter *connCounter
+	maxBody int64
+}
+
+func (d *tunedDoer) Do(req *stealth.Request) (*stealth.Response, error) {
+	httpReq, err := fhttp.NewRequest(req.Method, req.URL, req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
+	}
+	if len(req.HeaderOrder) > 0 {
+		httpReq.Header[fhttp.HeaderOrderKey] = req.HeaderOrder
+	}
+	if d.counter != nil {
+		httpReq = httpReq.WithContext(httptrace.WithClientTrace(httpReq.Context(), &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				if info.Reused {
+					d.counter.reused.Add(1)
+				} else {
+					d.counter.opened.Add(1)
+				}
+			},
+		}))
+	}
+
+	resp, err := d.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("tls request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	headers := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		if strings.EqualFold(k, "set-cookie") {
+			headers["set-cookie"] = strings.Join(v, "; ")
+		} else if len(v) > 0 {
+			headers[strings.ToLower(k)] = v[0]
+		}
+	}
+	body, decoded, err := readBody(resp.Body, headers, d.maxBody)
+	if err != nil {
+		return &stealth.Response{StatusCode: resp.StatusCode}, fmt.Errorf("read body: %w", err)
+	}
+	if decoded {
+		delete(headers, "content-encoding")
+	}
+	return &stealth.Response{Body: body, Headers: headers, StatusCode: resp.StatusCode}, nil
+}
+
+func (d *tunedDoer) SetProxy(proxyURL string) error {
+	return d.client.SetProxy(proxyURL)
+}
+
+func (d *tunedDoer) GetCookieValue(rawURL, name string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	for _, c := range d.client.GetCookies(u) {
+		if c.Name == name {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// tlsProfiles maps stealth profiles to TLS client ones, as go-stealth does.
+var tlsProfiles = map[stealth.TLSProfile]profiles.ClientProfile{
+	stealth.ProfileChrome131:   profiles.Chrome_131,
+	stealth.ProfileChrome133:   profiles.Chrome_133,
+	stealth.ProfileFirefox133:  profiles.Firefox_133,
+	stealth.ProfileSafari16:    profiles.Safari_16_0,
+	stealth.ProfileSafariIOS18: profiles.Safari_IOS_18_0,
+	stealth.ProfileSafariIOS17: profiles.Safari_IOS_17_0,
+}
+
+// tlsProfile returns the TLS client profile for p, Chrome 131 if unknown.
+func tlsProfile(p stealth.TLSProfile) profiles.ClientProfile {
+	if mapped, ok := tlsProfiles[p]; ok {
+		return mapped
+	}
+	return profiles.Chrome_131
+}
@@ -0,0 +1,48 @@
+package twitter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTunedDoer_CountsConnections(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, cfg := range []ConnectionConfig{{}, {DisableHTTP2: true}} {
+		counter := &connCounter{}
+		doer, err := cfg.backend(counter, 1<<20, nil)(stealth.BackendConfig{TimeoutSeconds: 5})
+		require.NoError(t, err)
+		for range 3 {
+			resp, err := doer.Do(&stealth.Request{Method: "GET", URL: srv.URL})
+			require.NoError(t, err)
+			assert.Equal(t, `{"ok":true}`, string(resp.Body))
+		}
+		stats := counter.snapshot()
+		assert.Equal(t, ConnStats{Opened: 1, Reused: 2}, stats, "%+v", cfg)
+		assert.InDelta(t, 2.0/3, stats.ReuseRatio(), 1e-9)
+	}
+}
+
+func TestTunedDoer_CapsBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"a":"` + strings.Repeat("x", 4096) + `"}`))
+	}))
+	defer srv.Close()
+
+	var cfg *ConnectionConfig // the TLS client's own settings
+	doer, err := cfg.backend(nil, 1024, nil)(stealth.BackendConfig{TimeoutSeconds: 5})
+	require.NoError(t, err)
+	_, err = doer.Do(&stealth.Request{Method: "GET", URL: srv.URL})
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
@@ -0,0 +1,163 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Coordinator lets Clients in several processes share one account
+// inventory. With ClientConfig.Coordinator set, an account serves one
+// request, read or write, at a time across all clients, request budgets
+// (RateLimit and EndpointLimits) are counted once for all of them, and a
+// 429 seen by one client rests the account for every client. The redispool package
+// implements it on Redis.
+//
+// Errors from the Coordinator are logged and the account is used anyway,
+// so an outage of the shared store degrades to per-process accounting
+// rather than stopping every client.
+type Coordinator interface {
+	// Lease claims username for one request for at most ttl. It returns a
+	// token naming the lease, or "" if the account is already leased, by
+	// another client or by another request of this one.
+	Lease(ctx context.Context, username string, ttl time.Duration) (string, error)
+	// Unlease gives back the lease token of username.
+	Unlease(ctx context.Context, username, token string) error
+	// Spend counts one request of username on endpoint against limit
+	// requests per window, and reports false, without counting it, when
+	// the budget is used up or the endpoint is rate-limited.
+	Spend(ctx context.Context, username, endpoint string, limit int, window time.Duration) (bool, error)
+	// MarkRateLimited rests username's endpoint until until for all
+	// clients.
+	MarkRateLimited(ctx context.Context, username, endpoint string, until time.Time) error
+}
+
+// defaultLeaseTTL bounds a lease when RequestTimeout is disabled; a
+// client that dies holding one blocks the account for at most this long.
+const defaultLeaseTTL = 2 * time.Minute
+
+// leaseTTL is how long a request may hold its account: a few attempts'
+// worth, to cover CSRF rotation and relogin retries on the same account.
+func (c *Client) leaseTTL() time.Duration {
+	if c.cfg.RequestTimeout > 0 {
+		return 3 * c.cfg.RequestTimeout
+	}
+	return defaultLeaseTTL
+}
+
+// nextSharedAccount is nextAccount for a pool shared through
+// ClientConfig.Coordinator. Accounts leased or out of budget in another
+// client are skipped; when all eligible ones are, the search repeats every
+// queueSlice until wait runs out. release gives the account back and is
+// never nil.
+func (c *Client) nextSharedAccount(ctx context.Context, endpoint string, eligible func(*Account) bool, wait time.Duration) (*Account, func(), error) {
+	co := c.cfg.Coordinator
+	if co == nil {
+		acc, err := c.nextAccount(ctx, endpoint, eligible, wait)
+		return acc, func() {}, err
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		refused := make(map[*Account]bool)
+		accWait := wait
+		for {
+			acc, err := c.nextAccount(ctx, endpoint, func(a *Account) bool { return !refused[a] && eligible(a) }, accWait)
+			if err != nil {
+				if len(refused) == 0 {
+					return nil, func() {}, err
+				}
+				break
+			}
+			if release, ok := c.claimShared(ctx, co, acc, endpoint); ok {
+				return acc, release, nil
+			}
+			refused[acc] = true
+			accWait = 0 // the rest are only worth a try if free right now
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, func() {}, fmt.Errorf("%s: %d eligible accounts busy in other clients: %w", endpoint, len(refused), ErrPoolExhausted)
+		}
+		select {
+		case <-time.After(min(queueSlice, remaining)):
+		case <-ctx.Done():
+			return nil, func() {}, ctx.Err()
+		}
+		wait = time.Until(deadline)
+	}
+}
+
+// leaseAccount is nextSharedAccount for a request that must use acc, such
+// as a write: it tries to claim acc every queueSlice until wait runs out.
+// release gives the account back and is never nil.
+func (c *Client) leaseAccount(ctx context.Context, acc *Account, endpoint string, wait time.Duration) (func(), error) {
+	co := c.cfg.Coordinator
+	if co == nil {
+		return func() {}, nil
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		if release, ok := c.claimShared(ctx, co, acc, endpoint); ok {
+			return release, nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return func() {}, fmt.Errorf("%s: account %s busy in other clients: %w", endpoint, acc.Username, ErrPoolExhausted)
+		}
+		select {
+		case <-time.After(min(queueSlice, remaining)):
+		case <-ctx.Done():
+			return func() {}, ctx.Err()
+		}
+	}
+}
+
+// claimShared leases acc through co and spends one request of its shared
+// budget for endpoint. It reports false if another client holds acc or
+// the budget is used up.
+func (c *Client) claimShared(ctx context.Context, co Coordinator, acc *Account, endpoint string) (func(), bool) {
+	token, err := co.Lease(ctx, acc.Username, c.leaseTTL())
+	if err != nil {
+		slog.Warn("coordinator lease failed, using account unshared",
+			slog.String("user", acc.Username), slog.Any("error", err))
+		return func() {}, true
+	}
+	if token == "" {
+		return nil, false
+	}
+	release := func() {
+		if err := co.Unlease(context.WithoutCancel(ctx), acc.Username, token); err != nil {
+			slog.Warn("coordinator unlease failed", slog.String("user", acc.Username), slog.Any("error", err))
+		}
+	}
+	lc := c.cfg.rateLimitFor(endpoint)
+	if lc.RequestsPerWindow <= 0 || lc.WindowDuration <= 0 {
+		return release, true
+	}
+	ok, err := co.Spend(ctx, acc.Username, endpoint, lc.RequestsPerWindow, lc.WindowDuration)
+	if err != nil {
+		slog.Warn("coordinator spend failed, counting locally only",
+			slog.String("user", acc.Username), slog.String("endpoint", endpoint), slog.Any("error", err))
+		return release, true
+	}
+	if !ok {
+		release()
+		return nil, false
+	}
+	return release, true
+}
+
+// shareRateLimit tells the other clients that acc is rate-limited on
+// endpoint until until.
+func (c *Client) shareRateLimit(ctx context.Context, acc *Account, endpoint string, until time.Time) {
+	co := c.cfg.Coordinator
+	if co == nil || until.IsZero() {
+		return
+	}
+	if err := co.MarkRateLimited(context.WithoutCancel(ctx), acc.Username, endpoint, until); err != nil {
+		slog.Warn("coordinator rate-limit mark failed", slog.String("user", acc.Username),
+			slog.String("endpoint", endpoint), slog.Any("error", err))
+	}
+}
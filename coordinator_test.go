@@ -0,0 +1,101 @@
+package twitter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCoordinator leases each username to one holder at a time; tests
+// mark the accounts held by other clients in held.
+type fakeCoordinator struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (f *fakeCoordinator) Lease(_ context.Context, username string, _ time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.held[username] {
+		return "", nil
+	}
+	f.held[username] = true
+	return "token-" + username, nil
+}
+
+func (f *fakeCoordinator) Unlease(_ context.Context, username, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.held, username)
+	return nil
+}
+
+func (f *fakeCoordinator) Spend(context.Context, string, string, int, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeCoordinator) MarkRateLimited(context.Context, string, string, time.Time) error {
+	return nil
+}
+
+func TestCoordinator_SkipsAccountsLeasedElsewhere(t *testing.T) {
+	co := &fakeCoordinator{held: map[string]bool{"alice": true}}
+	tr := &tokenTransport{}
+	c, err := NewClient(ClientConfig{
+		Accounts: []*Account{
+			{Username: "alice", AuthToken: "tok-alice", CT0: "c"},
+			{Username: "bob", AuthToken: "tok-bob", CT0: "c"},
+		},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		Coordinator:          co,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	url := addGraphQLParams(Endpoints["UserByScreenName"].URL(), map[string]any{"screen_name": "jack"}, nil)
+
+	for range 3 {
+		_, _, err := c.doPoolRequest(context.Background(), "GET", "UserByScreenName", url, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"tok-bob", "tok-bob", "tok-bob"}, tr.tokens)
+	assert.Equal(t, map[string]bool{"alice": true}, co.held, "bob's lease is given back after each request")
+
+	co.held["bob"] = true
+	_, _, err = c.doPoolRequest(context.Background(), "GET", "UserByRestId", url, nil)
+	assert.ErrorIs(t, err, ErrPoolExhausted)
+	assert.ErrorContains(t, err, "busy in other clients")
+}
+
+func TestCoordinator_LeasesWrites(t *testing.T) {
+	co := &fakeCoordinator{held: map[string]bool{"alice": true}}
+	tr := &tokenTransport{}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "alice", AuthToken: "tok-alice", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		Coordinator:          co,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	acc := c.AccountByUsername("alice")
+	url := Endpoints["CreateTweet"].URL()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.doPOST(ctx, acc, "CreateTweet", url, []byte(`{}`))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, tr.tokens, "a write waits for the account's lease")
+
+	co.mu.Lock()
+	delete(co.held, "alice")
+	co.mu.Unlock()
+	_, err = c.doPOST(context.Background(), acc, "CreateTweet", url, []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tok-alice"}, tr.tokens)
+	assert.Empty(t, co.held, "the lease is given back after the write")
+}
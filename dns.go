@@ -0,0 +1,160 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNSConfig makes the browser clients resolve host names (X's hosts and
+// proxy hosts) without the host machine's resolver, so that no lookup
+// leaks outside the proxies.
+type DNSConfig struct {
+	// DoHURL is an RFC 8484 DNS-over-HTTPS endpoint. Use an IP literal
+	// (e.g. "https://1.1.1.1/dns-query"): the endpoint's own name would be
+	// resolved by the system. Empty: names not in Hosts go to the system
+	// resolver.
+	DoHURL string
+	// Hosts pins names to addresses, e.g. {"api.x.com": "104.244.42.66"};
+	// consulted before DoHURL.
+	Hosts map[string]string
+	// HTTP sends the DoH queries. Default: a client with a 10s timeout.
+	HTTP *http.Client
+}
+
+// maxDNSMessage bounds DoH answers; DNS messages fit in 64 KiB.
+const maxDNSMessage = 64 << 10
+
+// resolver returns a Go resolver that answers from d. Go's resolver
+// speaks DNS over the conn its Dial returns; a pipe end served by
+// serveDNS stands in for a nameserver.
+func (d *DNSConfig) resolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go d.serveDNS(ctx, server)
+			return client, nil
+		},
+	}
+}
+
+// serveDNS answers the length-prefixed queries written to conn, as a
+// nameserver over TCP would, until conn is closed.
+func (d *DNSConfig) serveDNS(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	for {
+		var n uint16
+		if err := binary.Read(conn, binary.BigEndian, &n); err != nil {
+			return
+		}
+		query := make([]byte, n)
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		answer, err := d.exchange(ctx, query)
+		if err != nil {
+			return // the resolver sees a failed exchange
+		}
+		if err := binary.Write(conn, binary.BigEndian, uint16(len(answer))); err != nil {
+			return
+		}
+		if _, err := conn.Write(answer); err != nil {
+			return
+		}
+	}
+}
+
+// exchange answers one DNS query from Hosts, DoH or the system resolver.
+func (d *DNSConfig) exchange(ctx context.Context, query []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+	hdr, err := p.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil, err
+	}
+	host := strings.ToLower(strings.TrimSuffix(q.Name.String(), "."))
+	if addr, ok := d.Hosts[host]; ok {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("dns hosts entry %s: %w", host, err)
+		}
+		return dnsAnswer(hdr, q, []netip.Addr{ip})
+	}
+	if d.DoHURL != "" {
+		return d.queryDoH(ctx, query)
+	}
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	return dnsAnswer(hdr, q, ips)
+}
+
+// queryDoH sends query to DoHURL and returns the answer message.
+func (d *DNSConfig) queryDoH(ctx context.Context, query []byte) ([]byte, error) {
+	hc := d.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.DoHURL, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("doh: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("doh: HTTP %d", resp.StatusCode)
+	}
+	return readLimited(resp.Body, maxDNSMessage)
+}
+
+// dnsAnswer builds the response to question q (of the query with header
+// hdr) holding the addresses of ips that match its type.
+func dnsAnswer(hdr dnsmessage.Header, q dnsmessage.Question, ips []netip.Addr) ([]byte, error) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID: hdr.ID, Response: true, RecursionDesired: hdr.RecursionDesired, RecursionAvailable: true,
+	})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(q); err != nil {
+		return nil, err
+	}
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 60}
+	for _, ip := range ips {
+		ip = ip.Unmap()
+		switch {
+		case q.Type == dnsmessage.TypeA && ip.Is4():
+			if err := b.AResource(rh, dnsmessage.AResource{A: ip.As4()}); err != nil {
+				return nil, err
+			}
+		case q.Type == dnsmessage.TypeAAAA && ip.Is6():
+			if err := b.AAAAResource(rh, dnsmessage.AAAAResource{AAAA: ip.As16()}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.Finish()
+}
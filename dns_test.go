@@ -0,0 +1,52 @@
+package twitter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestDNSConfig_Hosts(t *testing.T) {
+	d := &DNSConfig{Hosts: map[string]string{"api.x.com": "104.244.42.66", "x.com": "2606:4700::1"}}
+	r := d.resolver()
+
+	ips, err := r.LookupNetIP(context.Background(), "ip4", "API.x.com")
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("104.244.42.66")}, ips)
+
+	ips, err = r.LookupNetIP(context.Background(), "ip6", "x.com")
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("2606:4700::1")}, ips)
+}
+
+func TestDNSConfig_DoH(t *testing.T) {
+	var queried []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/dns-message", r.Header.Get("Content-Type"))
+		query, _ := io.ReadAll(r.Body)
+		var p dnsmessage.Parser
+		hdr, err := p.Start(query)
+		require.NoError(t, err)
+		q, err := p.Question()
+		require.NoError(t, err)
+		queried = append(queried, q.Name.String())
+		answer, err := dnsAnswer(hdr, q, []netip.Addr{netip.MustParseAddr("198.51.100.7")})
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(answer)
+	}))
+	defer srv.Close()
+
+	d := &DNSConfig{DoHURL: srv.URL, Hosts: map[string]string{"x.com": "104.244.42.1"}}
+	ips, err := d.resolver().LookupNetIP(context.Background(), "ip4", "gate.proxy.example")
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("198.51.100.7")}, ips)
+	assert.Equal(t, []string{"gate.proxy.example."}, queried)
+}
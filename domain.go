@@ -0,0 +1,91 @@
+package twitter
+
+import (
+	"net/url"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+)
+
+// Domain selects the web domain requests are sent to and present themselves
+// from (origin, referer, REST API host).
+type Domain string
+
+const (
+	DomainX       Domain = "x.com" // default
+	DomainTwitter Domain = "twitter.com"
+)
+
+// host returns the bare domain, defaulting to x.com.
+func (d Domain) host() string {
+	if d == "" {
+		return string(DomainX)
+	}
+	return string(d)
+}
+
+// other returns the domain not selected by d.
+func (d Domain) other() string {
+	if d.host() == string(DomainTwitter) {
+		return string(DomainX)
+	}
+	return string(DomainTwitter)
+}
+
+// origin returns the web origin, e.g. "https://x.com".
+func (d Domain) origin() string { return "https://" + d.host() }
+
+// apiURL returns the REST API base, e.g. "https://api.x.com".
+func (d Domain) apiURL() string { return "https://api." + d.host() }
+
+// rewriteURL moves a request for either web or API host onto d. Other hosts
+// (e.g. pro.x.com) are left alone.
+func (d Domain) rewriteURL(urlStr string) string {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return urlStr
+	}
+	switch u.Host {
+	case "x.com", "twitter.com":
+		u.Host = d.host()
+	case "api.x.com", "api.twitter.com":
+		u.Host = "api." + d.host()
+	default:
+		return urlStr
+	}
+	return u.String()
+}
+
+// setOrigin points the origin and referer headers at d, if present.
+func (d Domain) setOrigin(headers map[string]string) {
+	if _, ok := headers["origin"]; ok {
+		headers["origin"] = d.origin()
+	}
+	if _, ok := headers["referer"]; ok {
+		headers["referer"] = d.origin() + "/"
+	}
+}
+
+// cookieOrigins lists the origins whose cookies may hold session credentials,
+// d's own first. Some accounts now only receive x.com-scoped cookies while
+// others still get twitter.com ones, so both are checked.
+func (d Domain) cookieOrigins() []string {
+	return []string{
+		"https://api." + d.host(), "https://" + d.host(),
+		"https://api." + d.other(), "https://" + d.other(),
+	}
+}
+
+// sessionCookies returns the auth_token and ct0 cookies from the first
+// origin that has each. Requests carry them in an explicit Cookie header, so
+// credentials found under either domain work on both.
+func (d Domain) sessionCookies(bc *stealth.BrowserClient) (authToken, ct0 string) {
+	for _, o := range d.cookieOrigins() {
+		if authToken == "" {
+			authToken = bc.GetCookieValue(o, "auth_token")
+		}
+		if ct0 == "" {
+			ct0 = bc.GetCookieValue(o, "ct0")
+		}
+	}
+	return authToken, ct0
+}
@@ -0,0 +1,42 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomain_RewriteURL(t *testing.T) {
+	tests := []struct {
+		d    Domain
+		in   string
+		want string
+	}{
+		{"", "https://x.com/i/api/graphql/abc/UserByRestId?x=1", "https://x.com/i/api/graphql/abc/UserByRestId?x=1"},
+		{DomainTwitter, "https://x.com/i/api/graphql/abc/UserByRestId?x=1", "https://twitter.com/i/api/graphql/abc/UserByRestId?x=1"},
+		{DomainX, "https://api.twitter.com/1.1/account/settings.json", "https://api.x.com/1.1/account/settings.json"},
+		{DomainTwitter, "https://api.x.com/1.1/guest/activate.json", "https://api.twitter.com/1.1/guest/activate.json"},
+		{DomainTwitter, "https://pro.x.com/i/api/graphql/abc/UserTweets", "https://pro.x.com/i/api/graphql/abc/UserTweets"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.d.rewriteURL(tt.in), "%s %s", tt.d, tt.in)
+	}
+}
+
+func TestDomain_SetOrigin(t *testing.T) {
+	h := guestHeaders("gt")
+	DomainTwitter.setOrigin(h)
+	assert.Equal(t, "https://twitter.com", h["origin"])
+	assert.Equal(t, "https://twitter.com/", h["referer"])
+
+	h = map[string]string{}
+	DomainTwitter.setOrigin(h)
+	assert.Empty(t, h, "absent headers are not added")
+}
+
+func TestDomain_CookieOrigins(t *testing.T) {
+	assert.Equal(t, []string{
+		"https://api.x.com", "https://x.com", "https://api.twitter.com", "https://twitter.com",
+	}, Domain("").cookieOrigins())
+	assert.Equal(t, "https://api.twitter.com", DomainTwitter.cookieOrigins()[0])
+}
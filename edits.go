@@ -0,0 +1,121 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// DiffOp is the kind of a TextEdit.
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffInsert
+	DiffDelete
+)
+
+// TextEdit is one span of a word-level diff between two revisions.
+type TextEdit struct {
+	Op   DiffOp
+	Text string
+}
+
+// TweetRevision is one version of an edited tweet.
+type TweetRevision struct {
+	*Tweet
+	// Diff transforms the previous revision's text into this one. Nil for
+	// the original revision.
+	Diff []TextEdit
+}
+
+// GetTweetEdits returns every revision of a tweet, oldest first, each with a
+// word-level diff against its predecessor. tweetID may be any revision. A
+// tweet that was never edited yields a single revision.
+func (c *Client) GetTweetEdits(ctx context.Context, tweetID string) ([]*TweetRevision, error) {
+	tw, err := c.GetTweetByID(ctx, tweetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tw.EditIDs) == 0 {
+		return []*TweetRevision{{Tweet: tw}}, nil
+	}
+
+	revs := make([]*TweetRevision, 0, len(tw.EditIDs))
+	for _, id := range tw.EditIDs {
+		rev := tw
+		if id != tw.ID {
+			rev, err = c.GetTweetByID(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("revision %s: %w", id, err)
+			}
+			if rev.ID != id {
+				return nil, fmt.Errorf("revision %s: got tweet %s instead", id, rev.ID)
+			}
+		}
+		r := &TweetRevision{Tweet: rev}
+		if n := len(revs); n > 0 {
+			r.Diff = diffWords(revs[n-1].Text, rev.Text)
+		}
+		revs = append(revs, r)
+	}
+	return revs, nil
+}
+
+// diffTokenRe splits text into alternating word and whitespace tokens, so
+// joining the tokens of a diff reproduces the text exactly.
+var diffTokenRe = regexp.MustCompile(`\S+|\s+`)
+
+// diffWords computes a word-level diff from a to b using the longest common
+// subsequence. Adjacent spans of the same kind are merged.
+func diffWords(a, b string) []TextEdit {
+	x := diffTokenRe.FindAllString(a, -1)
+	y := diffTokenRe.FindAllString(b, -1)
+
+	// lcs[i][j] = LCS length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []TextEdit
+	emit := func(op DiffOp, tok string) {
+		if n := len(edits); n > 0 && edits[n-1].Op == op {
+			edits[n-1].Text += tok
+			return
+		}
+		edits = append(edits, TextEdit{Op: op, Text: tok})
+	}
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			emit(DiffEqual, x[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			emit(DiffDelete, x[i])
+			i++
+		default:
+			emit(DiffInsert, y[j])
+			j++
+		}
+	}
+	for _, tok := range x[i:] {
+		emit(DiffDelete, tok)
+	}
+	for _, tok := range y[j:] {
+		emit(DiffInsert, tok)
+	}
+	return slices.Clip(edits)
+}
@@ -0,0 +1,63 @@
+package twitter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffWords(t *testing.T) {
+	a := "the quick brown fox"
+	b := "the slow brown fox jumps"
+	edits := diffWords(a, b)
+
+	var before, after strings.Builder
+	for _, e := range edits {
+		if e.Op != DiffInsert {
+			before.WriteString(e.Text)
+		}
+		if e.Op != DiffDelete {
+			after.WriteString(e.Text)
+		}
+	}
+	if before.String() != a || after.String() != b {
+		t.Fatalf("diff does not reproduce inputs: %q / %q", before.String(), after.String())
+	}
+
+	want := []TextEdit{
+		{DiffEqual, "the "},
+		{DiffDelete, "quick"},
+		{DiffInsert, "slow"},
+		{DiffEqual, " brown fox"},
+		{DiffInsert, " jumps"},
+	}
+	if len(edits) != len(want) {
+		t.Fatalf("got %+v, want %+v", edits, want)
+	}
+	for i := range want {
+		if edits[i] != want[i] {
+			t.Fatalf("edit %d: got %+v, want %+v", i, edits[i], want[i])
+		}
+	}
+}
+
+func TestEditControlIDs(t *testing.T) {
+	latest := editControl{EditTweetIDs: []string{"1", "2"}}
+	if got := latest.ids(); len(got) != 2 || got[0] != "1" {
+		t.Fatalf("latest revision ids = %v", got)
+	}
+	var older editControl
+	older.Initial = &struct {
+		EditTweetIDs []string `json:"edit_tweet_ids"`
+	}{EditTweetIDs: []string{"1", "2", "3"}}
+	if got := older.ids(); len(got) != 3 {
+		t.Fatalf("older revision ids = %v", got)
+	}
+
+	tw, err := parseTweetResult(tweetResult{RestID: "1", EditControl: editControl{EditTweetIDs: []string{"1"}}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tw.EditIDs != nil {
+		t.Fatalf("unedited tweet should have no EditIDs, got %v", tw.EditIDs)
+	}
+}
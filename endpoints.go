@@ -2,17 +2,44 @@ package twitter
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"sync"
 )
 
 const (
-	twitterBase   = "https://x.com/i/api/graphql"
-	twitterAPIURL = "https://api.twitter.com"
+	twitterBase = "https://x.com/i/api/graphql"
 
 	// accountSettingsURL is the authenticated REST endpoint used by
 	// ValidateAccount to check whether an account's credentials are still
 	// alive. Returns 200 on success, 401 on expired auth, 403 on suspension.
-	accountSettingsURL = "https://api.twitter.com/1.1/account/settings.json"
+	// The host follows ClientConfig.Domain.
+	accountSettingsURL = "https://api.x.com/1.1/account/settings.json"
+
+	// verifyCredentialsURL returns the profile of the authenticating account.
+	verifyCredentialsURL = "https://api.x.com/1.1/account/verify_credentials.json"
+
+	// friendshipsShowURL is the REST endpoint behind GetRelationship.
+	friendshipsShowURL = "https://api.x.com/1.1/friendships/show.json"
+
+	// typeaheadURL is the search box autocomplete; it answers guest tokens.
+	typeaheadURL = "https://api.x.com/1.1/search/typeahead.json"
+
+	// retweetsURL lists up to 100 recent retweets of a tweet, each with
+	// its user; GetRetweeters merges it into the GraphQL list.
+	retweetsURL = "https://api.x.com/1.1/statuses/retweets/%s.json"
+
+	// followerIDsURL pages through a user's follower IDs, 5000 at a time.
+	followerIDsURL = "https://api.x.com/1.1/followers/ids.json"
+
+	// Profile mutation endpoints (form-encoded POSTs).
+	updateProfileURL       = "https://api.x.com/1.1/account/update_profile.json"
+	updateProfileImageURL  = "https://api.x.com/1.1/account/update_profile_image.json"
+	updateProfileBannerURL = "https://api.x.com/1.1/account/update_profile_banner.json"
+
+	// capiPassthroughURL takes card interactions such as poll votes.
+	capiPassthroughURL = "https://caps.x.com/v2/capi/passthrough/1"
 )
 
 // bearerTokens is the list of known Twitter web-app bearer tokens.
@@ -21,7 +48,8 @@ var bearerTokens = []string{
 	"AAAAAAAAAAAAAAAAAAAAAFQODgEAAAAAVHTp76lzh3rFzcHbmHVvQxYYpTw%3DckAlMINMjmCwxUcaXbAN4XqJVdgMJaHqNOFgPMK0zN1qLqLQCF",
 }
 
-// BearerToken is the active bearer token (first in list).
+// BearerToken is the default bearer token (first in list). Clients start
+// with ClientConfig.BearerToken when set and may rotate; see bearer.go.
 var BearerToken = bearerTokens[0]
 
 // Endpoint holds the operation ID, path template, and per-operation feature flags.
@@ -29,50 +57,99 @@ type Endpoint struct {
 	ID       string
 	Name     string
 	Features map[string]any
+	Base     string // GraphQL base URL; empty means x.com
 }
 
 // URL returns the full URL for this endpoint.
 func (e Endpoint) URL() string {
-	return fmt.Sprintf("%s/%s/%s", twitterBase, e.ID, e.Name)
+	base := e.Base
+	if base == "" {
+		base = twitterBase
+	}
+	return fmt.Sprintf("%s/%s/%s", base, e.ID, e.Name)
 }
 
 // EndpointURL returns the URL for a named operation, or an error if unknown.
 func EndpointURL(operation string) (string, error) {
-	ep, ok := Endpoints[operation]
+	ep, ok := lookupEndpoint(operation)
 	if !ok {
 		return "", fmt.Errorf("unknown operation: %s", operation)
 	}
 	return ep.URL(), nil
 }
 
+// lookupEndpoint returns the current entry for operation.
+func lookupEndpoint(operation string) (Endpoint, bool) {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	ep, ok := Endpoints[operation]
+	return ep, ok
+}
+
+// endpointNames returns the names of the current Endpoints entries.
+func endpointNames() []string {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	return slices.Collect(maps.Keys(Endpoints))
+}
+
+// endpointsMu guards Endpoints against runtime updates (EndpointResolver).
+var endpointsMu sync.RWMutex
+
 // Endpoints maps operation names to their current GraphQL IDs and feature flags.
+// Modify it before creating clients; runtime updates go through EndpointResolver.
 var Endpoints = map[string]Endpoint{
-	"UserByScreenName": {ID: "IGgvgiOx4QZndDHuD3x9TQ", Name: "UserByScreenName", Features: gqlFeatures()},
-	"UserByRestId":     {ID: "VQfQ9wwYdk6j_u2O4vt64Q", Name: "UserByRestId", Features: gqlFeatures()},
-	"Followers":        {ID: "FpGYzBsUxUOecYYfso0yA", Name: "Followers", Features: gqlFeatures()},
-	"Following":        {ID: "UCFedrkjMz7PeEAWCWhqFw", Name: "Following", Features: gqlFeatures()},
-	"UserTweets":       {ID: "FOlovQsiHGDls3c0Q_HaSQ", Name: "UserTweets", Features: gqlFeatures()},
-	"SearchTimeline":   {ID: "GcXk9vN_d1jUfHNqLacXQA", Name: "SearchTimeline", Features: gqlFeatures()},
-	"TweetDetail":      {ID: "VWFGPVAGkZMGRKGe3GFFnA", Name: "TweetDetail", Features: gqlFeatures()},
-	"Retweeters":       {ID: "0BoJlKAxoNPQUHRftlwZ2w", Name: "Retweeters", Features: gqlFeatures()},
-	"CreateTweet":      {ID: "7TKRKCPuAGsmYde0CudbVg", Name: "CreateTweet", Features: gqlFeatures()},
+	"UserByScreenName":    {ID: "IGgvgiOx4QZndDHuD3x9TQ", Name: "UserByScreenName", Features: gqlFeatures()},
+	"UserByRestId":        {ID: "VQfQ9wwYdk6j_u2O4vt64Q", Name: "UserByRestId", Features: gqlFeatures()},
+	"UsersByRestIds":      {ID: "PyRggX3LQweP9nSF6PHliA", Name: "UsersByRestIds", Features: gqlFeatures()},
+	"Followers":           {ID: "FpGYzBsUxUOecYYfso0yA", Name: "Followers", Features: gqlFeatures()},
+	"Following":           {ID: "UCFedrkjMz7PeEAWCWhqFw", Name: "Following", Features: gqlFeatures()},
+	"UserTweets":          {ID: "FOlovQsiHGDls3c0Q_HaSQ", Name: "UserTweets", Features: gqlFeatures()},
+	"SearchTimeline":      {ID: "GcXk9vN_d1jUfHNqLacXQA", Name: "SearchTimeline", Features: gqlFeatures()},
+	"TweetDetail":         {ID: "VWFGPVAGkZMGRKGe3GFFnA", Name: "TweetDetail", Features: gqlFeatures()},
+	"Retweeters":          {ID: "0BoJlKAxoNPQUHRftlwZ2w", Name: "Retweeters", Features: gqlFeatures()},
+	"CreateTweet":         {ID: "7TKRKCPuAGsmYde0CudbVg", Name: "CreateTweet", Features: gqlFeatures()},
+	"CreateNoteTweet":     {ID: "iCUB42lIfXf9qPKctjE5rQ", Name: "CreateNoteTweet", Features: gqlFeatures()},
+	"GenericTimelineById": {ID: "6U7K1x9KZ9vQ-f5WF0g9_Q", Name: "GenericTimelineById", Features: gqlFeatures()},
+	"CreateList":          {ID: "EYg7JZU3A1eJ-wr2eygPHQ", Name: "CreateList", Features: gqlFeatures()},
+	"DeleteList":          {ID: "UnN9Th1BDbeLjpgjGSpL3Q", Name: "DeleteList", Features: gqlFeatures()},
+	"ListAddMember":       {ID: "lLNsL7mW6gSEQG6rXP7TNw", Name: "ListAddMember", Features: gqlFeatures()},
+	"ListRemoveMember":    {ID: "cvDFkG5WjcXV0Qw5nfe1qQ", Name: "ListRemoveMember", Features: gqlFeatures()},
+
+	// X Pro (TweetDeck) operations; see tweetdeck.go.
+	"TweetDeck/UserTweets":               {ID: "FOlovQsiHGDls3c0Q_HaSQ", Name: "UserTweets", Features: tweetdeckFeatures(), Base: tweetdeckBase},
+	"TweetDeck/SearchTimeline":           {ID: "GcXk9vN_d1jUfHNqLacXQA", Name: "SearchTimeline", Features: tweetdeckFeatures(), Base: tweetdeckBase},
+	"TweetDeck/ListLatestTweetsTimeline": {ID: "2TemLyqrMpTeAmysdbnVqw", Name: "ListLatestTweetsTimeline", Features: tweetdeckFeatures(), Base: tweetdeckBase},
 }
 
 // envOverrides maps endpoint names to their env var names for queryId overrides.
 var envOverrides = map[string]string{
-	"TweetDetail":      "TWITTER_QID_TWEET_DETAIL",
-	"UserByScreenName": "TWITTER_QID_USER_BY_SCREEN_NAME",
-	"UserTweets":       "TWITTER_QID_USER_TWEETS",
-	"SearchTimeline":   "TWITTER_QID_SEARCH_TIMELINE",
-	"Followers":        "TWITTER_QID_FOLLOWERS",
-	"Following":        "TWITTER_QID_FOLLOWING",
-	"Retweeters":       "TWITTER_QID_RETWEETERS",
-	"CreateTweet":      "TWITTER_QID_CREATE_TWEET",
+	"TweetDetail":         "TWITTER_QID_TWEET_DETAIL",
+	"UserByScreenName":    "TWITTER_QID_USER_BY_SCREEN_NAME",
+	"UserTweets":          "TWITTER_QID_USER_TWEETS",
+	"SearchTimeline":      "TWITTER_QID_SEARCH_TIMELINE",
+	"Followers":           "TWITTER_QID_FOLLOWERS",
+	"Following":           "TWITTER_QID_FOLLOWING",
+	"Retweeters":          "TWITTER_QID_RETWEETERS",
+	"CreateTweet":         "TWITTER_QID_CREATE_TWEET",
+	"CreateNoteTweet":     "TWITTER_QID_CREATE_NOTE_TWEET",
+	"UsersByRestIds":      "TWITTER_QID_USERS_BY_REST_IDS",
+	"GenericTimelineById": "TWITTER_QID_GENERIC_TIMELINE_BY_ID",
+	"CreateList":          "TWITTER_QID_CREATE_LIST",
+	"DeleteList":          "TWITTER_QID_DELETE_LIST",
+	"ListAddMember":       "TWITTER_QID_LIST_ADD_MEMBER",
+	"ListRemoveMember":    "TWITTER_QID_LIST_REMOVE_MEMBER",
+
+	"TweetDeck/UserTweets":               "TWITTER_QID_TD_USER_TWEETS",
+	"TweetDeck/SearchTimeline":           "TWITTER_QID_TD_SEARCH_TIMELINE",
+	"TweetDeck/ListLatestTweetsTimeline": "TWITTER_QID_TD_LIST_LATEST_TWEETS",
 }
 
 // ApplyEnvOverrides reads TWITTER_QID_* env vars and overrides queryIds in Endpoints.
 // Called automatically by init(); can also be called manually in tests.
 func ApplyEnvOverrides() {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
 	for name, envKey := range envOverrides {
 		if qid := os.Getenv(envKey); qid != "" {
 			if ep, ok := Endpoints[name]; ok {
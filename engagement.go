@@ -0,0 +1,112 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EngagementSnapshot is a tweet's counters at one point in time, with the
+// rates at which they grew since the previous snapshot.
+type EngagementSnapshot struct {
+	TweetID string
+	At      time.Time
+
+	Views, Likes, Retweets, Quotes, Replies int64
+
+	// Per-hour growth since the previous snapshot of the tweet; zero for
+	// the first one.
+	ViewsPerHour, LikesPerHour, RetweetsPerHour float64
+}
+
+// EngagementTracker turns successive fetches of the same tweets into
+// snapshots with velocity. Safe for concurrent use.
+type EngagementTracker struct {
+	mu   sync.Mutex
+	last map[string]EngagementSnapshot
+}
+
+// Observe records t's counters as of at and returns the snapshot, with rates
+// computed against the previous one of the same tweet.
+func (e *EngagementTracker) Observe(t *Tweet, at time.Time) EngagementSnapshot {
+	s := EngagementSnapshot{
+		TweetID: t.ID, At: at,
+		Views: t.Views, Likes: t.Likes, Retweets: t.Retweets, Quotes: t.Quotes, Replies: t.ReplyCount,
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.last == nil {
+		e.last = make(map[string]EngagementSnapshot)
+	}
+	if prev, ok := e.last[t.ID]; ok {
+		if hours := at.Sub(prev.At).Hours(); hours > 0 {
+			perHour := func(cur, old int64) float64 { return float64(cur-old) / hours }
+			s.ViewsPerHour = perHour(s.Views, prev.Views)
+			s.LikesPerHour = perHour(s.Likes, prev.Likes)
+			s.RetweetsPerHour = perHour(s.Retweets, prev.Retweets)
+		}
+	}
+	e.last[t.ID] = s
+	return s
+}
+
+// Forget drops the history of tweetID.
+func (e *EngagementTracker) Forget(tweetID string) {
+	e.mu.Lock()
+	delete(e.last, tweetID)
+	e.mu.Unlock()
+}
+
+// TrackEngagement re-fetches tweetIDs every interval (default 1m) and
+// delivers a snapshot of each, e.g. into archive.ConsumeEngagement. Tweets
+// that become unavailable (deleted, withheld) stop being tracked. The
+// channel is closed when ctx is done or no tweet is left.
+func (c *Client) TrackEngagement(ctx context.Context, tweetIDs []string, interval time.Duration) <-chan EngagementSnapshot {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ids := slices.Clone(tweetIDs)
+	var tracker EngagementTracker
+	out := make(chan EngagementSnapshot, 16)
+	go func() {
+		defer close(out)
+		ctx := WithPriority(ctx, PriorityLow)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for len(ids) > 0 {
+			live := ids[:0]
+			for _, id := range ids {
+				t, err := c.GetTweetByID(ctx, id)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					var gone *TweetUnavailableError
+					if errors.As(err, &gone) {
+						slog.Info("tweet unavailable, no longer tracking engagement", slog.String("tweet_id", id), slog.Any("error", err))
+						continue
+					}
+					slog.Warn("engagement fetch failed", slog.String("tweet_id", id), slog.Any("error", err))
+					live = append(live, id)
+					continue
+				}
+				live = append(live, id)
+				select {
+				case out <- tracker.Observe(t, time.Now()):
+				case <-ctx.Done():
+					return
+				}
+			}
+			ids = live
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,69 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngagementTracker_Velocity(t *testing.T) {
+	var e EngagementTracker
+	t0 := time.Date(2025, 3, 18, 12, 0, 0, 0, time.UTC)
+
+	first := e.Observe(&Tweet{ID: "1", Views: 1000, Likes: 10, Retweets: 1}, t0)
+	assert.Equal(t, int64(1000), first.Views)
+	assert.Zero(t, first.ViewsPerHour, "no rate without a previous snapshot")
+
+	second := e.Observe(&Tweet{ID: "1", Views: 4000, Likes: 40, Retweets: 4, ReplyCount: 2}, t0.Add(30*time.Minute))
+	assert.InDelta(t, 6000, second.ViewsPerHour, 1e-9)
+	assert.InDelta(t, 60, second.LikesPerHour, 1e-9)
+	assert.InDelta(t, 6, second.RetweetsPerHour, 1e-9)
+	assert.Equal(t, int64(2), second.Replies)
+
+	e.Forget("1")
+	assert.Zero(t, e.Observe(&Tweet{ID: "1", Views: 5000}, t0.Add(time.Hour)).ViewsPerHour)
+}
+
+func TestTrackEngagement(t *testing.T) {
+	tr := &detailTransport{bodies: map[string]string{
+		"1": detailBody([]threadTweet{{"1", "alice", "", ""}}),
+		"2": `{"data":{"threaded_conversation_with_injections_v2":{"instructions":[{"entries":[
+			{"entryId":"tweet-2","content":{"itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{
+				"__typename":"TweetTombstone","tombstone":{"text":{"text":"This Post was deleted by the Post author."}}}}}}}]}]}}}`,
+	}}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.TrackEngagement(ctx, []string{"1", "2"}, 10*time.Millisecond)
+	for range 3 {
+		select {
+		case s := <-ch:
+			assert.Equal(t, "1", s.TweetID, "deleted tweets are dropped")
+		case <-time.After(2 * time.Second):
+			t.Fatal("no snapshot")
+		}
+	}
+	cancel()
+	for range ch {
+	}
+	assert.Equal(t, 1, countOf(tr.focals, "2"), "a deleted tweet is fetched once")
+}
+
+func countOf(ss []string, s string) int {
+	n := 0
+	for _, v := range ss {
+		if v == s {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,85 @@
+package twitter
+
+import (
+	"regexp"
+	"slices"
+)
+
+// RegexpEnricher returns a ClientConfig.TweetEnrichers entry that stores the
+// distinct matches of re in the tweet text under Tweet.Entities[name]. If re
+// has a capture group, the first group is stored instead of the whole match.
+func RegexpEnricher(name string, re *regexp.Regexp) func(*Tweet) {
+	return func(t *Tweet) {
+		for _, m := range re.FindAllStringSubmatch(t.Text, -1) {
+			v := m[0]
+			if len(m) > 1 {
+				v = m[1]
+			}
+			if v == "" || slices.Contains(t.Entities[name], v) {
+				continue
+			}
+			if t.Entities == nil {
+				t.Entities = make(map[string][]string)
+			}
+			t.Entities[name] = append(t.Entities[name], v)
+		}
+	}
+}
+
+// enrichTweets applies DisableTokenMentions and TweetEnrichers to tweets.
+// It passes err through, so parse results can be handed over directly.
+func (c *Client) enrichTweets(tweets []*Tweet, err error) ([]*Tweet, error) {
+	if err != nil {
+		return tweets, err
+	}
+	for _, t := range tweets {
+		c.enrich(t)
+	}
+	return tweets, nil
+}
+
+// enrichTweet is enrichTweets for a single tweet.
+func (c *Client) enrichTweet(t *Tweet, err error) (*Tweet, error) {
+	if err == nil && t != nil {
+		c.enrich(t)
+	}
+	return t, err
+}
+
+func (c *Client) enrich(t *Tweet) {
+	if t == nil {
+		return
+	}
+	if c.cfg.DisableTokenMentions {
+		t.TokenMentions = nil
+	}
+	for _, fn := range c.cfg.TweetEnrichers {
+		fn(t)
+	}
+}
+
+// shapeUsers applies MinimalUsers to users. Like enrichTweets, it passes err
+// through.
+func (c *Client) shapeUsers(users []*TwitterUser, err error) ([]*TwitterUser, error) {
+	if c.cfg.MinimalUsers {
+		for _, u := range users {
+			minimizeUser(u)
+		}
+	}
+	return users, err
+}
+
+// shapeUser is shapeUsers for a single user.
+func (c *Client) shapeUser(u *TwitterUser, err error) (*TwitterUser, error) {
+	if c.cfg.MinimalUsers && u != nil {
+		minimizeUser(u)
+	}
+	return u, err
+}
+
+// minimizeUser clears the extended profile fields of u.
+func minimizeUser(u *TwitterUser) {
+	u.Location, u.URL, u.PinnedTweetID = "", "", ""
+	u.ProfessionalCategory, u.BirthdateVisibility = "", ""
+	u.Protected, u.CanDM = false, false
+}
@@ -0,0 +1,45 @@
+package twitter
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexpEnricher(t *testing.T) {
+	contracts := RegexpEnricher("contract", regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`))
+	cashtags := RegexpEnricher("cashtag", regexp.MustCompile(`\$([A-Za-z][A-Za-z0-9]{1,19})`))
+
+	tw := &Tweet{Text: "ape $PEPECOIN2 at 0x6982508145454ce325ddbe47a25d4ec3d2311933 (again: 0x6982508145454ce325ddbe47a25d4ec3d2311933)"}
+	contracts(tw)
+	cashtags(tw)
+	assert.Equal(t, []string{"0x6982508145454ce325ddbe47a25d4ec3d2311933"}, tw.Entities["contract"])
+	assert.Equal(t, []string{"PEPECOIN2"}, tw.Entities["cashtag"])
+
+	plain := &Tweet{Text: "gm"}
+	contracts(plain)
+	assert.Nil(t, plain.Entities)
+}
+
+func TestClient_TweetEnrichers(t *testing.T) {
+	srv := newAPIv2Server(t)
+	var calls int
+	c := &Client{cfg: ClientConfig{
+		APIv2:                &APIv2Config{BearerToken: "app-token", BaseURL: srv.URL},
+		DisableTokenMentions: true,
+		TweetEnrichers:       []func(*Tweet){func(*Tweet) { calls++ }},
+	}}
+	ctx := WithAPIv2(context.Background())
+
+	tw, err := c.GetTweetByID(ctx, "20")
+	require.NoError(t, err)
+	assert.Nil(t, tw.TokenMentions)
+
+	tweets, err := c.SearchTimeline(ctx, "twttr", 10)
+	require.NoError(t, err)
+	require.Len(t, tweets, 2)
+	assert.Equal(t, 3, calls)
+}
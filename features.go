@@ -0,0 +1,185 @@
+package twitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// missingFeaturesRegex matches the GraphQL error returned when a required
+// feature flag was not sent: "The following features cannot be null: a, b".
+var missingFeaturesRegex = regexp.MustCompile(`features cannot be null: ([\w, ]+)`)
+
+// missingFeatures returns the flag names a missing-feature error asks for.
+func missingFeatures(body []byte) []string {
+	m := missingFeaturesRegex.FindSubmatch(body)
+	if m == nil {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(string(m[1]), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// learnedFeaturesFile holds the learned flags inside SessionDir.
+const learnedFeaturesFile = ".features.json"
+
+// learnedFeatures are flags added after missing-feature errors, per
+// operation. They are persisted so restarts don't repeat the failure.
+type learnedFeatures struct {
+	mu  sync.Mutex
+	ops map[string]map[string]any
+}
+
+// get returns the flags learned for operation (read-only).
+func (l *learnedFeatures) get(operation string) map[string]any {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ops[operation]
+}
+
+// add records names as enabled for operation and returns the full set for
+// the operation, or nil if nothing was new.
+func (l *learnedFeatures) add(operation string, names []string) map[string]any {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ops == nil {
+		l.ops = make(map[string]map[string]any)
+	}
+	next := maps.Clone(l.ops[operation])
+	if next == nil {
+		next = make(map[string]any)
+	}
+	added := false
+	for _, name := range names {
+		if _, ok := next[name]; !ok {
+			next[name] = true
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	l.ops[operation] = next
+	return next
+}
+
+// load reads learned flags from dir; a missing file is not an error.
+func (l *learnedFeatures) load(dir string) error {
+	data, err := os.ReadFile(sessionDirFile(dir, learnedFeaturesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var ops map[string]map[string]any
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return fmt.Errorf("parse learned features: %w", err)
+	}
+	l.mu.Lock()
+	l.ops = ops
+	l.mu.Unlock()
+	return nil
+}
+
+// save writes the learned flags to dir.
+func (l *learnedFeatures) save(dir string) error {
+	l.mu.Lock()
+	data, err := json.MarshalIndent(l.ops, "", "  ")
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	d := sessionDir(dir)
+	if err := os.MkdirAll(d, 0700); err != nil {
+		return fmt.Errorf("create session dir: %w", err)
+	}
+	return os.WriteFile(sessionDirFile(dir, learnedFeaturesFile), data, 0600)
+}
+
+// features returns the flags to send for operation: the Endpoints entry, then
+// flags learned from missing-feature errors, then ClientConfig.FeatureOverrides.
+func (c *Client) features(operation string) map[string]any {
+	ep, _ := lookupEndpoint(operation)
+	learned := c.learned.get(operation)
+	override := c.cfg.FeatureOverrides[operation]
+	if len(learned) == 0 && len(override) == 0 {
+		return ep.Features
+	}
+	f := maps.Clone(ep.Features)
+	if f == nil {
+		f = make(map[string]any)
+	}
+	maps.Copy(f, learned)
+	maps.Copy(f, override)
+	return f
+}
+
+// recoverFeatures handles a missing-feature error in body: it learns the
+// named flags for endpoint, persists them, and returns the request rewritten
+// to send them. ok is false if body is not such an error.
+func (c *Client) recoverFeatures(endpoint, urlStr string, payload, body []byte) (string, []byte, bool) {
+	names := missingFeatures(body)
+	if len(names) == 0 {
+		return urlStr, payload, false
+	}
+	slog.Warn("GraphQL requires unknown features, retrying with them enabled",
+		slog.String("endpoint", endpoint), slog.Any("features", names))
+	if c.learned.add(endpoint, names) != nil {
+		if err := c.learned.save(c.cfg.SessionDir); err != nil {
+			slog.Warn("save learned features failed", slog.Any("error", err))
+		}
+	}
+	enable := make(map[string]any, len(names))
+	for _, name := range names {
+		enable[name] = true
+	}
+	urlStr, payload = withFeatures(urlStr, payload, enable)
+	return urlStr, payload, true
+}
+
+// withFeatures adds flags to the features of a GraphQL request, which live in
+// the JSON payload for POSTs and in the features query parameter for GETs.
+func withFeatures(urlStr string, payload []byte, flags map[string]any) (string, []byte) {
+	if len(payload) > 0 {
+		var req map[string]any
+		if err := json.Unmarshal(payload, &req); err == nil {
+			f, _ := req["features"].(map[string]any)
+			if f == nil {
+				f = make(map[string]any)
+			}
+			maps.Copy(f, flags)
+			req["features"] = f
+			if p, err := json.Marshal(req); err == nil {
+				return urlStr, p
+			}
+		}
+		return urlStr, payload
+	}
+
+	base, rawQuery, ok := strings.Cut(urlStr, "?")
+	if !ok {
+		return urlStr, payload
+	}
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return urlStr, payload
+	}
+	f := make(map[string]any)
+	_ = json.Unmarshal([]byte(q.Get("features")), &f)
+	maps.Copy(f, flags)
+	fj, _ := json.Marshal(f)
+	q.Set("features", string(fj))
+	return base + "?" + q.Encode(), payload
+}
@@ -0,0 +1,60 @@
+package twitter
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingFeatures(t *testing.T) {
+	body := []byte(`{"errors":[{"message":"The following features cannot be null: rweb_video_screen_enabled, payments_enabled","code":336}]}`)
+	assert.Equal(t, []string{"rweb_video_screen_enabled", "payments_enabled"}, missingFeatures(body))
+	assert.Nil(t, missingFeatures([]byte(`{"errors":[{"message":"Bad request"}]}`)))
+}
+
+func TestWithFeatures_GET(t *testing.T) {
+	u := addGraphQLParams("https://x.com/i/api/graphql/id/UserTweets", map[string]any{"userId": "1"}, map[string]any{"a": true})
+	got, _ := withFeatures(u, nil, map[string]any{"b": true})
+
+	_, rawQuery, _ := strings.Cut(got, "?")
+	q, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":true,"b":true}`, q.Get("features"))
+	assert.JSONEq(t, `{"userId":"1"}`, q.Get("variables"))
+}
+
+func TestWithFeatures_POST(t *testing.T) {
+	_, payload := withFeatures("https://x.com/i/api/graphql/id/CreateTweet", []byte(`{"queryId":"id","features":{"a":false}}`), map[string]any{"b": true})
+	var req map[string]any
+	require.NoError(t, json.Unmarshal(payload, &req))
+	assert.Equal(t, map[string]any{"a": false, "b": true}, req["features"])
+	assert.Equal(t, "id", req["queryId"])
+}
+
+func TestRecoverFeatures_LearnsAndPersists(t *testing.T) {
+	dir := t.TempDir()
+	c := &Client{cfg: ClientConfig{
+		SessionDir:       dir,
+		FeatureOverrides: map[string]map[string]any{"UserTweets": {"new_flag": false}},
+	}}
+	body := []byte(`{"errors":[{"message":"The following features cannot be null: new_flag, other_flag"}]}`)
+
+	_, _, ok := c.recoverFeatures("UserTweets", "https://x.com/i/api/graphql/id/UserTweets?features=%7B%7D", nil, body)
+	require.True(t, ok)
+
+	f := c.features("UserTweets")
+	assert.Equal(t, false, f["new_flag"], "overrides win over learned flags")
+	assert.Equal(t, true, f["other_flag"])
+	assert.NotContains(t, Endpoints["UserTweets"].Features, "other_flag", "Endpoints is not modified")
+
+	var reloaded learnedFeatures
+	require.NoError(t, reloaded.load(dir))
+	assert.Equal(t, map[string]any{"new_flag": true, "other_flag": true}, reloaded.get("UserTweets"))
+
+	_, _, ok = c.recoverFeatures("UserTweets", "https://x.com/", nil, []byte(`{"errors":[]}`))
+	assert.False(t, ok)
+}
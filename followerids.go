@@ -0,0 +1,68 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// followerIDsPageSize is the most IDs followers/ids returns per call.
+const followerIDsPageSize = 5000
+
+// GetFollowerIDs returns up to maxCount follower IDs of userID, newest
+// first, from the 1.1 followers/ids endpoint: 5000 IDs per request instead of
+// 100 user objects, for collecting graph structure at scale. Hydrate the IDs
+// that matter with GetUsersByIDsBatch. On error it returns the IDs fetched
+// so far.
+func (c *Client) GetFollowerIDs(ctx context.Context, userID string, maxCount int) ([]string, error) {
+	if err := validateUserID(userID); err != nil {
+		return nil, err
+	}
+	ctx = c.withTargetAffinity(ctx, "user", userID)
+	var ids []string
+	cursor := "-1"
+	for len(ids) < maxCount {
+		if err := ctx.Err(); err != nil {
+			return ids, err
+		}
+		params := url.Values{
+			"user_id":       {userID},
+			"count":         {strconv.Itoa(min(followerIDsPageSize, maxCount-len(ids)))},
+			"cursor":        {cursor},
+			"stringify_ids": {"true"},
+		}
+		body, _, err := c.doGET(ctx, "FollowerIDs", followerIDsURL+"?"+params.Encode())
+		if err != nil {
+			return ids, fmt.Errorf("FollowerIDs: %w", err)
+		}
+		batch, next, err := parseFollowerIDs(body)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, batch...)
+		if len(batch) == 0 || next == "" || next == "0" {
+			break
+		}
+		cursor = next
+	}
+	return ids[:min(len(ids), maxCount)], nil
+}
+
+// parseFollowerIDs parses a followers/ids page and its next cursor ("0" at
+// the end of the list).
+func parseFollowerIDs(body []byte) ([]string, string, error) {
+	var raw struct {
+		IDs        []json.Number `json:"ids"`
+		NextCursor string        `json:"next_cursor_str"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, "", fmt.Errorf("unmarshal follower ids: %w", err)
+	}
+	ids := make([]string, len(raw.IDs))
+	for i, id := range raw.IDs {
+		ids[i] = id.String()
+	}
+	return ids, raw.NextCursor, nil
+}
@@ -0,0 +1,44 @@
+package twitter
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cursorTransport serves followers/ids pages keyed by the cursor parameter.
+type cursorTransport map[string]string
+
+func (p cursorTransport) Do(_ context.Context, _, rawURL string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	u, _ := url.Parse(rawURL)
+	return []byte(p[u.Query().Get("cursor")]), nil, 200, nil
+}
+
+func TestGetFollowerIDs(t *testing.T) {
+	tr := cursorTransport{
+		"-1": `{"ids":["3","2"],"next_cursor_str":"c2","previous_cursor_str":"0"}`,
+		"c2": `{"ids":[1],"next_cursor_str":"0"}`,
+	}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	ids, err := c.GetFollowerIDs(context.Background(), "42", 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "2", "1"}, ids)
+
+	ids, err = c.GetFollowerIDs(context.Background(), "42", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3"}, ids)
+
+	_, err = c.GetFollowerIDs(context.Background(), "nope", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package twitter
+
+import (
+	"container/heap"
+	"time"
+)
+
+// Forecast estimates how long n requests to endpoint will take given the
+// current pool: which accounts are active or due back from soft-deactivation,
+// their proxy backoff, per-endpoint rate-limit resets, and the endpoint's
+// configured budget (its EndpointLimits entry, else RateLimit: RequestsPerWindow
+// per WindowDuration per account).
+//
+// Each account is assumed to spend its full window budget as soon as it is
+// available, so the estimate is a lower bound: it ignores jitter, retries, and
+// budget already consumed inside the current window. Returns 0 for n <= 0 and
+// -1 if no account can ever serve the endpoint.
+func (c *Client) Forecast(endpoint string, n int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	limit := c.cfg.rateLimitFor(endpoint)
+	perWindow := limit.RequestsPerWindow
+	window := limit.WindowDuration
+	if perWindow <= 0 || window <= 0 {
+		return -1
+	}
+
+	now := time.Now()
+	var starts slotHeap
+	for _, acc := range c.pool.Items() {
+		start, ok := acc.availableFrom(endpoint, now)
+		if ok {
+			starts = append(starts, start)
+		}
+	}
+	if len(starts) == 0 {
+		return -1
+	}
+	heap.Init(&starts)
+
+	// Pop the earliest window opening, spend its budget, and schedule the
+	// same account's next window until n requests are covered.
+	remaining := n
+	for {
+		t := heap.Pop(&starts).(time.Time)
+		remaining -= perWindow
+		if remaining <= 0 {
+			return t.Sub(now)
+		}
+		heap.Push(&starts, t.Add(window))
+	}
+}
+
+// itemsPerRequest is how many items (users, IDs or tweets) one request of
+// an operation returns at most; operations not listed fetch one item.
+var itemsPerRequest = map[string]int{
+	"UsersByRestIds": maxUsersByRestIDsBatch,
+	"Followers":      100,
+	"Following":      100,
+	"Retweeters":     20,
+	"FollowerIDs":    followerIDsPageSize,
+	"UserTweets":     defaultPollCount,
+	"SearchTimeline": defaultPollCount,
+}
+
+// EstimateDuration estimates how long fetching n items through endpoint
+// will take with the current pool, e.g. hydrating n users through
+// UsersByRestIds (200 per request) or looking up n handles through
+// UserByScreenName (one each). Items are converted to full-size requests
+// and passed to Forecast, whose lower-bound caveats apply. Returns 0 for
+// n <= 0 and -1 if no account can ever serve the endpoint.
+func (c *Client) EstimateDuration(endpoint string, n int) time.Duration {
+	per := max(itemsPerRequest[endpoint], 1)
+	return c.Forecast(endpoint, (n+per-1)/per)
+}
+
+// availableFrom returns when the account can next serve endpoint, or false if
+// it is permanently deactivated.
+func (a *Account) availableFrom(endpoint string, now time.Time) (time.Time, bool) {
+	start := now
+	if !a.IsActive() {
+		re := a.ReactivateAt()
+		if re.IsZero() {
+			return time.Time{}, false
+		}
+		if re.After(now) {
+			start = re
+		}
+	}
+	if t := a.EndpointAvailableAt(endpoint); t.After(start) {
+		start = t
+	}
+	a.mu.Lock()
+	if a.proxyBackoff.After(start) {
+		start = a.proxyBackoff
+	}
+	a.mu.Unlock()
+	return start, true
+}
+
+// slotHeap is a min-heap of window opening times.
+type slotHeap []time.Time
+
+func (h slotHeap) Len() int           { return len(h) }
+func (h slotHeap) Less(i, j int) bool { return h[i].Before(h[j]) }
+func (h slotHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *slotHeap) Push(x any)        { *h = append(*h, x.(time.Time)) }
+func (h *slotHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
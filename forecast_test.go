@@ -0,0 +1,65 @@
+package twitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/anatolykoptev/go-stealth/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func forecastClient(accs ...*Account) *Client {
+	return &Client{
+		pool: pool.New(accs, pool.Config{}),
+		cfg:  ClientConfig{RateLimit: ratelimit.Config{RequestsPerWindow: 50, WindowDuration: 15 * time.Minute}},
+	}
+}
+
+func TestForecast(t *testing.T) {
+	c := forecastClient(
+		&Account{Username: "a", active: true},
+		&Account{Username: "b", active: true},
+		&Account{Username: "c", active: true},
+	)
+
+	assert.Equal(t, time.Duration(0), c.Forecast("UserTweets", 0))
+	assert.Less(t, c.Forecast("UserTweets", 150), time.Second)
+	assert.InDelta(t, float64(15*time.Minute), float64(c.Forecast("UserTweets", 151)), float64(time.Second))
+	assert.InDelta(t, float64(30*time.Minute), float64(c.Forecast("UserTweets", 400)), float64(time.Second))
+}
+
+func TestForecast_SoftDeactivatedAndBackoff(t *testing.T) {
+	soft := &Account{Username: "soft", reactivateAt: time.Now().Add(time.Hour)}
+	backoff := &Account{Username: "backoff", active: true, proxyBackoff: time.Now().Add(5 * time.Minute)}
+	c := forecastClient(soft, backoff)
+
+	// backoff opens every 15m from +5m; soft opens at +1h.
+	assert.InDelta(t, float64(5*time.Minute), float64(c.Forecast("UserTweets", 50)), float64(time.Second))
+	assert.InDelta(t, float64(20*time.Minute), float64(c.Forecast("UserTweets", 100)), float64(time.Second))
+	assert.InDelta(t, float64(35*time.Minute), float64(c.Forecast("UserTweets", 150)), float64(time.Second))
+	assert.InDelta(t, float64(50*time.Minute), float64(c.Forecast("UserTweets", 200)), float64(time.Second))
+	assert.InDelta(t, float64(time.Hour), float64(c.Forecast("UserTweets", 250)), float64(time.Second))
+}
+
+func TestForecast_CooldownOver(t *testing.T) {
+	c := forecastClient(&Account{Username: "rested", reactivateAt: time.Now().Add(-time.Minute)})
+	assert.Less(t, c.Forecast("UserTweets", 50), time.Second, "an ended cooldown serves right away")
+}
+
+func TestForecast_NoUsableAccounts(t *testing.T) {
+	c := forecastClient(&Account{Username: "dead"})
+	assert.Equal(t, time.Duration(-1), c.Forecast("UserTweets", 10))
+}
+
+func TestEstimateDuration(t *testing.T) {
+	c := forecastClient(&Account{Username: "a", active: true})
+
+	// 10,000 IDs are 50 UsersByRestIds requests: one window.
+	assert.Less(t, c.EstimateDuration("UsersByRestIds", 10000), time.Second)
+	// 10,001 need a 51st request in the next window.
+	assert.InDelta(t, float64(15*time.Minute), float64(c.EstimateDuration("UsersByRestIds", 10001)), float64(time.Second))
+	// Handle lookups cost a request each.
+	assert.InDelta(t, float64(15*time.Minute), float64(c.EstimateDuration("UserByScreenName", 51)), float64(time.Second))
+	assert.Equal(t, time.Duration(0), c.EstimateDuration("UserByScreenName", 0))
+}
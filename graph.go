@@ -0,0 +1,165 @@
+package twitter
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Intersect returns the users who follow both userA and userB, in userA's
+// follower order. Each follower list is read up to maxPerUser entries
+// (newest first), so for large accounts it is the overlap of their recent
+// audiences. On error it returns the overlap of what was fetched.
+func (c *Client) Intersect(ctx context.Context, userA, userB string, maxPerUser int) ([]*TwitterUser, error) {
+	a, b := bothPaged(ctx,
+		func(ctx context.Context) PagedResult[*TwitterUser] {
+			return c.GetFollowersPaged(ctx, userA, "", maxPerUser)
+		},
+		func(ctx context.Context) PagedResult[*TwitterUser] {
+			return c.GetFollowersPaged(ctx, userB, "", maxPerUser)
+		})
+	return intersectUsers(a.Items, b.Items), errors.Join(a.PartialErr, b.PartialErr)
+}
+
+// Mutuals returns the users userID follows who follow it back, in follower
+// order, reading each list up to maxCount entries. On error it returns the
+// mutuals among what was fetched.
+func (c *Client) Mutuals(ctx context.Context, userID string, maxCount int) ([]*TwitterUser, error) {
+	followers, following := bothPaged(ctx,
+		func(ctx context.Context) PagedResult[*TwitterUser] {
+			return c.GetFollowersPaged(ctx, userID, "", maxCount)
+		},
+		func(ctx context.Context) PagedResult[*TwitterUser] {
+			return c.GetFollowingPaged(ctx, userID, "", maxCount)
+		})
+	return intersectUsers(followers.Items, following.Items), errors.Join(followers.PartialErr, following.PartialErr)
+}
+
+// bothPaged runs two list fetches concurrently; they draw on different
+// accounts when the pool has them.
+func bothPaged[T any](ctx context.Context, a, b func(context.Context) PagedResult[T]) (PagedResult[T], PagedResult[T]) {
+	var ra, rb PagedResult[T]
+	var wg sync.WaitGroup
+	wg.Go(func() { ra = a(ctx) })
+	rb = b(ctx)
+	wg.Wait()
+	return ra, rb
+}
+
+// intersectUsers returns the users of a whose ID is also in b, deduplicated.
+func intersectUsers(a, b []*TwitterUser) []*TwitterUser {
+	inB := make(map[string]bool, len(b))
+	for _, u := range b {
+		inB[u.ID] = true
+	}
+	var out []*TwitterUser
+	for _, u := range a {
+		if inB[u.ID] {
+			out = append(out, u)
+			delete(inB, u.ID)
+		}
+	}
+	return out
+}
+
+// FoFOptions configures SampleFollowersOfFollowers.
+type FoFOptions struct {
+	// Seeds is the number of the user's followers sampled. Default: 20.
+	Seeds int
+	// PerSeed caps the followers read per seed. Default: 200.
+	PerSeed int
+	// MaxWait stops sampling once the pool forecasts that the next seed's
+	// pages would take longer than this (see Forecast). Default: no limit.
+	MaxWait time.Duration
+}
+
+// FoFSample is the result of SampleFollowersOfFollowers.
+type FoFSample struct {
+	Seeds []*TwitterUser // sampled followers whose followers were read
+	Users []FoFUser      // second-degree accounts, most connected first
+	// Truncated is set when MaxWait stopped sampling before all seeds.
+	Truncated bool
+}
+
+// FoFUser is an account that follows one or more sampled seeds.
+type FoFUser struct {
+	User *TwitterUser
+	Via  int // number of sampled seeds it follows
+}
+
+// SampleFollowersOfFollowers samples followers of userID and reads their
+// followers, counting how many sampled seeds each second-degree account
+// follows. Requests run at PriorityLow so interactive lookups go first, and
+// opts.MaxWait bounds how long the pool may be tied up. Seeds whose
+// followers fail to load are skipped and their errors joined into the
+// returned error.
+func (c *Client) SampleFollowersOfFollowers(ctx context.Context, userID string, opts FoFOptions) (*FoFSample, error) {
+	if opts.Seeds <= 0 {
+		opts.Seeds = 20
+	}
+	if opts.PerSeed <= 0 {
+		opts.PerSeed = 200
+	}
+	ctx = WithPriority(ctx, PriorityLow)
+
+	// Draw seeds from a larger slice of recent followers.
+	first := c.GetFollowersPaged(ctx, userID, "", opts.Seeds*5)
+	if len(first.Items) == 0 && first.PartialErr != nil {
+		return nil, fmt.Errorf("followers of %s: %w", userID, first.PartialErr)
+	}
+	seeds := first.Items
+	rand.Shuffle(len(seeds), func(i, j int) { seeds[i], seeds[j] = seeds[j], seeds[i] })
+	seeds = seeds[:min(opts.Seeds, len(seeds))]
+
+	sample := &FoFSample{}
+	via := make(map[string]*FoFUser)
+	var errs []error
+	pages := (opts.PerSeed + 99) / 100
+	for _, seed := range seeds {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if opts.MaxWait > 0 {
+			if eta := c.Forecast("Followers", pages); eta < 0 || eta > opts.MaxWait {
+				sample.Truncated = true
+				break
+			}
+		}
+		r := c.GetFollowersPaged(ctx, seed.ID, "", opts.PerSeed)
+		if r.PartialErr != nil {
+			errs = append(errs, fmt.Errorf("followers of seed %s: %w", seed.ID, r.PartialErr))
+			if len(r.Items) == 0 {
+				continue
+			}
+		}
+		sample.Seeds = append(sample.Seeds, seed)
+		for _, u := range r.Items {
+			if u.ID == userID {
+				continue
+			}
+			if e, ok := via[u.ID]; ok {
+				e.Via++
+			} else {
+				via[u.ID] = &FoFUser{User: u, Via: 1}
+			}
+		}
+	}
+
+	sample.Users = make([]FoFUser, 0, len(via))
+	for _, e := range via {
+		sample.Users = append(sample.Users, *e)
+	}
+	slices.SortFunc(sample.Users, func(a, b FoFUser) int {
+		if a.Via != b.Via {
+			return b.Via - a.Via
+		}
+		return cmp.Compare(a.User.ID, b.User.ID)
+	})
+	return sample, errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphTransport serves one-page Followers and Following lists by user ID.
+type graphTransport struct {
+	mu        sync.Mutex
+	followers map[string][]string
+	following map[string][]string
+}
+
+func (g *graphTransport) Do(_ context.Context, _, rawURL string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	u, _ := url.Parse(rawURL)
+	var vars struct {
+		UserID string `json:"userId"`
+	}
+	_ = json.Unmarshal([]byte(u.Query().Get("variables")), &vars)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	lists := g.followers
+	if strings.HasSuffix(u.Path, "/Following") {
+		lists = g.following
+	}
+	return []byte(followersPage("", lists[vars.UserID]...)), nil, 200, nil
+}
+
+func newGraphClient(t *testing.T, tr *graphTransport) *Client {
+	t.Helper()
+	c, err := NewClient(ClientConfig{
+		Accounts: []*Account{
+			{Username: "a", AuthToken: "a", CT0: "c"},
+			{Username: "b", AuthToken: "b", CT0: "c"},
+		},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func userIDs(users []*TwitterUser) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+func TestIntersectAndMutuals(t *testing.T) {
+	c := newGraphClient(t, &graphTransport{
+		followers: map[string][]string{"1": {"10", "11", "12", "13"}, "2": {"13", "99", "11"}},
+		following: map[string][]string{"1": {"12", "50", "10"}},
+	})
+	ctx := context.Background()
+
+	common, err := c.Intersect(ctx, "1", "2", 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"11", "13"}, userIDs(common))
+
+	mutuals, err := c.Mutuals(ctx, "1", 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10", "12"}, userIDs(mutuals))
+
+	_, err = c.Intersect(ctx, "1", "bad", 100)
+	assert.Error(t, err)
+}
+
+func TestSampleFollowersOfFollowers(t *testing.T) {
+	c := newGraphClient(t, &graphTransport{followers: map[string][]string{
+		"1":  {"10", "11"},
+		"10": {"20", "21", "1"},
+		"11": {"21", "22"},
+	}})
+
+	s, err := c.SampleFollowersOfFollowers(context.Background(), "1", FoFOptions{Seeds: 5})
+	require.NoError(t, err)
+	assert.Len(t, s.Seeds, 2)
+	assert.False(t, s.Truncated)
+	require.Len(t, s.Users, 3, "the user itself is not counted")
+	assert.Equal(t, "21", s.Users[0].User.ID)
+	assert.Equal(t, 2, s.Users[0].Via)
+	assert.Equal(t, []string{"20", "22"}, []string{s.Users[1].User.ID, s.Users[2].User.ID})
+
+	// Seeds whose pages the pool cannot serve within MaxWait are not read.
+	c.cfg.EndpointLimits = map[string]ratelimit.Config{"Followers": {RequestsPerWindow: 1, WindowDuration: time.Hour}}
+	s, err = c.SampleFollowersOfFollowers(context.Background(), "1", FoFOptions{PerSeed: 500, MaxWait: time.Minute})
+	require.NoError(t, err)
+	assert.True(t, s.Truncated)
+	assert.Empty(t, s.Seeds)
+}
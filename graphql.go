@@ -9,6 +9,13 @@ import (
 
 // GetUserByScreenName fetches a user profile by Twitter handle.
 func (c *Client) GetUserByScreenName(ctx context.Context, handle string) (*TwitterUser, error) {
+	handle, err := NormalizeHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiV2For(ctx) {
+		return c.shapeUser(c.apiV2UserByScreenName(ctx, handle))
+	}
 	variables := map[string]any{
 		"screen_name":              handle,
 		"withSafetyModeUserFields": true,
@@ -17,132 +24,187 @@ func (c *Client) GetUserByScreenName(ctx context.Context, handle string) (*Twitt
 	if err != nil {
 		return nil, err
 	}
-	url = addGraphQLParams(url, variables, Endpoints["UserByScreenName"].Features)
+	url = addGraphQLParams(url, variables, c.features("UserByScreenName"))
 
 	body, _, err := c.doGET(ctx, "UserByScreenName", url)
 	if err != nil {
+		if c.apiV2Fallback(ctx, err) {
+			u, v2Err := c.apiV2UserByScreenName(ctx, handle)
+			if v2Err == nil || c.cfg.Mirror == nil {
+				return c.shapeUser(u, v2Err)
+			}
+		}
+		if c.mirrorFallback(ctx, err) {
+			return c.shapeUser(c.mirrorUserByScreenName(ctx, handle))
+		}
 		return nil, fmt.Errorf("UserByScreenName: %w", err)
 	}
-	return parseUserByScreenName(body)
+	return c.shapeUser(parseUserByScreenName(body))
+}
+
+// maxUsersByRestIDsBatch is the largest userIds list accepted by UsersByRestIds.
+const maxUsersByRestIDsBatch = 200
+
+// getUsersByRestIDs hydrates up to maxUsersByRestIDsBatch users in a single request.
+// IDs that don't resolve (suspended, deactivated) are absent from the result.
+func (c *Client) getUsersByRestIDs(ctx context.Context, ids []string) ([]*TwitterUser, error) {
+	if len(ids) > maxUsersByRestIDsBatch {
+		return nil, fmt.Errorf("UsersByRestIds: %d ids exceeds batch limit %d", len(ids), maxUsersByRestIDsBatch)
+	}
+	for _, id := range ids {
+		if err := validateUserID(id); err != nil {
+			return nil, err
+		}
+	}
+	variables := map[string]any{
+		"userIds":                  ids,
+		"withSafetyModeUserFields": true,
+	}
+	url, err := EndpointURL("UsersByRestIds")
+	if err != nil {
+		return nil, err
+	}
+	url = addGraphQLParams(url, variables, c.features("UsersByRestIds"))
+
+	body, _, err := c.doGET(ctx, "UsersByRestIds", url)
+	if err != nil {
+		return nil, fmt.Errorf("UsersByRestIds: %w", err)
+	}
+	return c.shapeUsers(parseUsersByRestIDs(body))
 }
 
-// GetFollowers fetches followers for a user (paginated).
+// GetFollowers fetches followers for a user (paginated). On error it returns
+// the users fetched so far; use GetFollowersPaged to see how far it got.
 func (c *Client) GetFollowers(ctx context.Context, userID string, maxCount int) ([]*TwitterUser, error) {
-	return c.fetchUserList(ctx, "Followers", userID, maxCount)
+	r := c.GetFollowersPaged(ctx, userID, "", maxCount)
+	return r.Items, r.PartialErr
 }
 
-// GetFollowing fetches accounts a user follows (paginated).
+// GetFollowing fetches accounts a user follows (paginated). On error it
+// returns the users fetched so far; use GetFollowingPaged to see how far it got.
 func (c *Client) GetFollowing(ctx context.Context, userID string, maxCount int) ([]*TwitterUser, error) {
-	return c.fetchUserList(ctx, "Following", userID, maxCount)
+	r := c.GetFollowingPaged(ctx, userID, "", maxCount)
+	return r.Items, r.PartialErr
 }
 
-// fetchUserList is a generic paginated user list fetcher.
-func (c *Client) fetchUserList(ctx context.Context, operation, userID string, maxCount int) ([]*TwitterUser, error) {
-	var users []*TwitterUser
-	var cursor string
-
-	for {
-		select {
-		case <-ctx.Done():
-			return users, ctx.Err()
-		default:
-		}
-
-		variables := map[string]any{
-			"userId":                 userID,
-			"count":                  min(100, maxCount-len(users)),
-			"includePromotedContent": false,
-		}
-		if cursor != "" {
-			variables["cursor"] = cursor
-		}
-
-		url, err := EndpointURL(operation)
-		if err != nil {
-			return users, err
-		}
-		url = addGraphQLParams(url, variables, Endpoints[operation].Features)
-
-		body, _, err := c.doGET(ctx, operation, url)
-		if err != nil {
-			return users, fmt.Errorf("%s: %w", operation, err)
-		}
+// GetFollowersPaged fetches up to maxCount followers of a user starting at
+// cursor ("" = first page), reporting how far pagination got.
+func (c *Client) GetFollowersPaged(ctx context.Context, userID, cursor string, maxCount int) PagedResult[*TwitterUser] {
+	return c.fetchUserList(ctx, "Followers", userID, cursor, maxCount)
+}
 
-		batch, nextCursor, err := parseUserList(body)
-		if err != nil {
-			return users, fmt.Errorf("parse %s: %w", operation, err)
-		}
-		users = append(users, batch...)
+// GetFollowingPaged is GetFollowersPaged for the accounts a user follows.
+func (c *Client) GetFollowingPaged(ctx context.Context, userID, cursor string, maxCount int) PagedResult[*TwitterUser] {
+	return c.fetchUserList(ctx, "Following", userID, cursor, maxCount)
+}
 
-		if nextCursor == "" || len(users) >= maxCount {
-			break
-		}
-		cursor = nextCursor
+// fetchUserList is a generic paginated user list fetcher.
+func (c *Client) fetchUserList(ctx context.Context, operation, userID, cursor string, maxCount int) PagedResult[*TwitterUser] {
+	if err := validateUserID(userID); err != nil {
+		return PagedResult[*TwitterUser]{NextCursor: cursor, PartialErr: err}
 	}
-	return users, nil
+	ctx = c.withTargetAffinity(ctx, "user", userID)
+	r := paginate(ctx, c, listPage[*TwitterUser]{
+		operation: operation,
+		pageSize:  100,
+		variables: func(count int) map[string]any {
+			return map[string]any{
+				"userId":                 userID,
+				"count":                  count,
+				"includePromotedContent": false,
+			}
+		},
+		parse: parseUserList,
+	}, cursor, maxCount)
+	r.Items, _ = c.shapeUsers(r.Items, nil)
+	return r
 }
 
-// GetRetweeters fetches users who retweeted a tweet (paginated).
+// GetRetweeters fetches users who retweeted a tweet (paginated). When the
+// GraphQL list falls short of maxCount, which it often does after the first
+// few pages, the recent retweets from the 1.1 statuses/retweets endpoint are
+// merged in. On error it returns the users fetched so far; use
+// GetRetweetersPaged to see how far the GraphQL list got.
 func (c *Client) GetRetweeters(ctx context.Context, tweetID string, maxCount int) ([]*TwitterUser, error) {
-	return c.fetchTweetUserList(ctx, "Retweeters", tweetID, maxCount)
+	r := c.GetRetweetersPaged(ctx, tweetID, "", maxCount)
+	if len(r.Items) >= maxCount || ctx.Err() != nil {
+		return r.Items, r.PartialErr
+	}
+	recent, err := c.recentRetweeters(ctx, tweetID)
+	if err != nil {
+		slog.Debug("retweets fallback failed", slog.String("tweet_id", tweetID), slog.Any("error", err))
+		return r.Items, r.PartialErr
+	}
+	users := mergeUsers(r.Items, recent)
+	return users[:min(len(users), maxCount)], r.PartialErr
 }
 
-// fetchTweetUserList is a paginated user list fetcher for tweet-centric endpoints.
-func (c *Client) fetchTweetUserList(ctx context.Context, operation, tweetID string, maxCount int) ([]*TwitterUser, error) {
-	var users []*TwitterUser
-	var cursor string
-
-	for {
-		select {
-		case <-ctx.Done():
-			return users, ctx.Err()
-		default:
-		}
-
-		variables := map[string]any{
-			"tweetId":                     tweetID,
-			"count":                       min(20, maxCount-len(users)),
-			"includePromotedContent":      true,
-			"withDownvotePerspective":     false,
-			"withReactionsMetadata":       false,
-			"withReactionsPerspective":    false,
-			"withSuperFollowsTweetFields": true,
-			"withSuperFollowsUserFields":  true,
-			"withVoice":                   true,
-			"withBirdwatchNotes":          true,
-			"withCommunity":               true,
-		}
-		if cursor != "" {
-			variables["cursor"] = cursor
-		}
+// GetRetweetersPaged fetches up to maxCount retweeters of a tweet starting at
+// cursor ("" = first page), reporting how far pagination got.
+func (c *Client) GetRetweetersPaged(ctx context.Context, tweetID, cursor string, maxCount int) PagedResult[*TwitterUser] {
+	return c.fetchTweetUserList(ctx, "Retweeters", tweetID, cursor, maxCount)
+}
 
-		url, err := EndpointURL(operation)
-		if err != nil {
-			return users, err
-		}
-		url = addGraphQLParams(url, variables, Endpoints[operation].Features)
+// fetchTweetUserList is a paginated user list fetcher for tweet-centric endpoints.
+func (c *Client) fetchTweetUserList(ctx context.Context, operation, tweetID, cursor string, maxCount int) PagedResult[*TwitterUser] {
+	ctx = c.withTargetAffinity(ctx, "tweet", tweetID)
+	r := paginate(ctx, c, listPage[*TwitterUser]{
+		operation: operation,
+		pageSize:  20,
+		variables: func(count int) map[string]any {
+			return map[string]any{
+				"tweetId":                     tweetID,
+				"count":                       count,
+				"includePromotedContent":      true,
+				"withDownvotePerspective":     false,
+				"withReactionsMetadata":       false,
+				"withReactionsPerspective":    false,
+				"withSuperFollowsTweetFields": true,
+				"withSuperFollowsUserFields":  true,
+				"withVoice":                   true,
+				"withBirdwatchNotes":          true,
+				"withCommunity":               true,
+			}
+		},
+		parse: parseRetweeterList,
+	}, cursor, maxCount)
+	r.Items, _ = c.shapeUsers(r.Items, nil)
+	return r
+}
 
-		body, _, err := c.doGET(ctx, operation, url)
-		if err != nil {
-			return users, fmt.Errorf("%s: %w", operation, err)
+// GetTweetByID fetches a single tweet by its ID.
+func (c *Client) GetTweetByID(ctx context.Context, tweetID string) (*Tweet, error) {
+	if c.apiV2For(ctx) {
+		return c.apiV2TweetByID(ctx, tweetID)
+	}
+	tweets, unavailable, err := c.tweetDetail(ctx, tweetID)
+	if err != nil {
+		if c.apiV2Fallback(ctx, err) {
+			return c.apiV2TweetByID(ctx, tweetID)
 		}
-
-		batch, nextCursor, err := parseRetweeterList(body)
-		if err != nil {
-			return users, fmt.Errorf("parse %s: %w", operation, err)
+		return nil, err
+	}
+	for _, t := range tweets {
+		if t.ID == tweetID {
+			return t, nil
 		}
-		users = append(users, batch...)
-
-		if nextCursor == "" || len(users) >= maxCount {
-			break
+	}
+	for _, u := range unavailable {
+		if u.TweetID == tweetID || (u.TweetID == "" && len(tweets) == 0) {
+			u.TweetID = tweetID
+			return nil, u
 		}
-		cursor = nextCursor
 	}
-	return users, nil
+	if len(tweets) > 0 {
+		return tweets[0], nil
+	}
+	return nil, fmt.Errorf("tweet %s not found in response", tweetID)
 }
 
-// GetTweetByID fetches a single tweet by its ID.
-func (c *Client) GetTweetByID(ctx context.Context, tweetID string) (*Tweet, error) {
+// tweetDetail fetches the conversation around tweetID: its ancestors, the
+// tweet itself and the first page of replies, plus the tombstones of those
+// that cannot be shown.
+func (c *Client) tweetDetail(ctx context.Context, tweetID string) ([]*Tweet, []*TweetUnavailableError, error) {
 	variables := map[string]any{
 		"focalTweetId":                           tweetID,
 		"with_rux_injections":                    false,
@@ -160,38 +222,61 @@ func (c *Client) GetTweetByID(ctx context.Context, tweetID string) (*Tweet, erro
 	}
 	url, err := EndpointURL("TweetDetail")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	url = addGraphQLParams(url, variables, Endpoints["TweetDetail"].Features)
+	url = addGraphQLParams(url, variables, c.features("TweetDetail"))
 
 	body, _, err := c.doGET(ctx, "TweetDetail", url)
 	if err != nil {
-		return nil, fmt.Errorf("TweetDetail: %w", err)
+		return nil, nil, fmt.Errorf("TweetDetail: %w", err)
 	}
-	tweets, err := parseTweetDetail(body)
+	tweets, unavailable, err := parseTweetDetail(body)
 	if err != nil {
 		// If parsing fails, log the raw response for debugging
 		slog.Debug("TweetDetail parse failed", slog.String("body_prefix", string(body[:min(500, len(body))])))
-		return nil, fmt.Errorf("parse TweetDetail: %w", err)
+		return nil, nil, fmt.Errorf("parse TweetDetail: %w", err)
+	}
+	for _, t := range tweets {
+		c.enrich(t)
 	}
 	slog.Debug("TweetDetail parsed", slog.Int("count", len(tweets)), slog.String("target", tweetID))
 	for _, t := range tweets {
 		slog.Debug("TweetDetail tweet", slog.String("id", t.ID), slog.String("text_prefix", t.Text[:min(50, len(t.Text))]))
-		if t.ID == tweetID {
-			return t, nil
-		}
 	}
-	if len(tweets) > 0 {
-		return tweets[0], nil
+	if len(tweets) == 0 && len(unavailable) == 0 {
+		// Log raw body prefix to understand why parsing returned empty
+		slog.Warn("TweetDetail no tweets", slog.String("body_prefix", string(body[:min(1000, len(body))])))
 	}
-	// Log raw body prefix to understand why parsing returned empty
-	slog.Warn("TweetDetail no tweets", slog.String("body_prefix", string(body[:min(1000, len(body))])))
-	return nil, fmt.Errorf("tweet %s not found in response", tweetID)
+	return tweets, unavailable, nil
 }
 
 // GetUserTweets fetches recent tweets for a user.
 func (c *Client) GetUserTweets(ctx context.Context, userID string, count int) ([]*Tweet, error) {
-	variables := map[string]any{
+	if err := validateUserID(userID); err != nil {
+		return nil, err
+	}
+	ctx = c.withTargetAffinity(ctx, "user", userID)
+	variables := userTweetsVariables(userID, count)
+	op := readOperation(ctx, "UserTweets")
+	url, err := EndpointURL(op)
+	if err != nil {
+		return nil, err
+	}
+	url = addGraphQLParams(url, variables, c.features(op))
+
+	body, _, err := c.doGET(ctx, op, url)
+	if err != nil {
+		if c.mirrorFallback(ctx, err) {
+			return c.mirrorUserTweets(ctx, userID, count)
+		}
+		return nil, fmt.Errorf("UserTweets: %w", err)
+	}
+	return c.enrichTweets(parseTweetTimeline(body, userID))
+}
+
+// userTweetsVariables are the UserTweets variables for a page of count tweets.
+func userTweetsVariables(userID string, count int) map[string]any {
+	return map[string]any{
 		"userId":                                 userID,
 		"count":                                  count,
 		"includePromotedContent":                 false,
@@ -199,81 +284,142 @@ func (c *Client) GetUserTweets(ctx context.Context, userID string, count int) ([
 		"withVoice":                              true,
 		"withV2Timeline":                         true,
 	}
-	url, err := EndpointURL("UserTweets")
+}
+
+// exploreTrendingTimelineID is the Explore "Trending" tab timeline.
+const exploreTrendingTimelineID = "VGltZWxpbmU6DAC2CwABAAAACHRyZW5kaW5nAAA="
+
+// GetTrends returns the Explore trending timeline with each trend's genre,
+// Grok context summary, and associated events.
+func (c *Client) GetTrends(ctx context.Context, count int) ([]*Trend, error) {
+	variables := map[string]any{
+		"timelineId":                             exploreTrendingTimelineID,
+		"count":                                  count,
+		"withQuickPromoteEligibilityTweetFields": true,
+	}
+	url, err := EndpointURL("GenericTimelineById")
 	if err != nil {
 		return nil, err
 	}
-	url = addGraphQLParams(url, variables, Endpoints["UserTweets"].Features)
+	url = addGraphQLParams(url, variables, c.features("GenericTimelineById"))
 
-	body, _, err := c.doGET(ctx, "UserTweets", url)
+	body, _, err := c.doGET(ctx, "GenericTimelineById", url)
 	if err != nil {
-		return nil, fmt.Errorf("UserTweets: %w", err)
+		return nil, fmt.Errorf("GenericTimelineById: %w", err)
 	}
-	return parseTweetTimeline(body, userID)
+	return parseTrends(body)
 }
 
 // SearchTimeline searches for tweets matching a query.
-// Uses POST (Twitter migrated this endpoint from GET in March 2026).
+// Uses POST (Twitter migrated this endpoint from GET in March 2026); the X Pro
+// variant selected by WithTweetDeck still takes GET.
 func (c *Client) SearchTimeline(ctx context.Context, query string, count int) ([]*Tweet, error) {
-	variables := map[string]any{
+	if c.apiV2For(ctx) {
+		return c.apiV2SearchRecent(ctx, query, count)
+	}
+	body, err := c.searchRequest(ctx, searchVariables(query, count))
+	if err != nil {
+		if readOperation(ctx, "SearchTimeline") == "SearchTimeline" && c.apiV2Fallback(ctx, err) {
+			return c.apiV2SearchRecent(ctx, query, count)
+		}
+		return nil, fmt.Errorf("SearchTimeline: %w", err)
+	}
+	return c.enrichTweets(parseSearchTimeline(body))
+}
+
+// searchVariables are the SearchTimeline variables of a Latest-tab search.
+func searchVariables(query string, count int) map[string]any {
+	return map[string]any{
 		"rawQuery":    query,
 		"count":       count,
 		"querySource": "typed_query",
 		"product":     "Latest",
 	}
+}
+
+// searchRequest fetches one SearchTimeline page: a POST, or a GET for the
+// X Pro variant selected by WithTweetDeck.
+func (c *Client) searchRequest(ctx context.Context, variables map[string]any) ([]byte, error) {
 	fieldToggles := map[string]any{
 		"withArticleRichContentState": false,
 	}
-	url, err := EndpointURL("SearchTimeline")
+	op := readOperation(ctx, "SearchTimeline")
+	url, err := EndpointURL(op)
 	if err != nil {
 		return nil, err
 	}
+	if op != "SearchTimeline" {
+		url = addGraphQLParams(url, variables, c.features(op), fieldToggles)
+		body, _, err := c.doGET(ctx, op, url)
+		return body, err
+	}
 	payload, err := json.Marshal(map[string]any{
 		"variables":    variables,
-		"features":     Endpoints["SearchTimeline"].Features,
+		"features":     c.features("SearchTimeline"),
 		"fieldToggles": fieldToggles,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("SearchTimeline: marshal payload: %w", err)
+		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
-
 	body, _, err := c.doPoolPOST(ctx, "SearchTimeline", url, payload)
-	if err != nil {
-		return nil, fmt.Errorf("SearchTimeline: %w", err)
-	}
-	return parseSearchTimeline(body)
+	return body, err
 }
 
 // CreateTweet posts a tweet from a specific account.
 // Returns the tweet ID on success.
-func (c *Client) CreateTweet(ctx context.Context, acc *Account, text string) (string, error) {
-	variables := map[string]any{
+func (c *Client) CreateTweet(ctx context.Context, acc *Account, text string, opts ...TweetOption) (string, error) {
+	o, err := newTweetOptions(opts)
+	if err != nil {
+		return "", err
+	}
+	variables := createTweetVariables(text)
+	o.apply(variables)
+	return c.postTweet(ctx, acc, "CreateTweet", variables)
+}
+
+// createTweetVariables are the variables of a plain text tweet.
+func createTweetVariables(text string) map[string]any {
+	return map[string]any{
 		"tweet_text":              text,
 		"dark_request":            false,
 		"media":                   map[string]any{"media_entities": []any{}, "possibly_sensitive": false},
 		"semantic_annotation_ids": []any{},
 	}
+}
+
+// postTweet runs a tweet-creating mutation (CreateTweet, CreateNoteTweet)
+// from acc and returns the new tweet's ID.
+func (c *Client) postTweet(ctx context.Context, acc *Account, operation string, variables map[string]any) (string, error) {
+	body, err := c.mutate(ctx, acc, operation, variables)
+	if err != nil {
+		return "", err
+	}
+	return parseCreateTweet(operation, body)
+}
 
-	ep := Endpoints["CreateTweet"]
+// mutate POSTs a GraphQL mutation from acc; errors are prefixed with the
+// operation.
+func (c *Client) mutate(ctx context.Context, acc *Account, operation string, variables map[string]any) ([]byte, error) {
+	ep, _ := lookupEndpoint(operation)
 	payload, err := json.Marshal(map[string]any{
 		"variables": variables,
-		"features":  ep.Features,
+		"features":  c.features(operation),
 		"queryId":   ep.ID,
 	})
 	if err != nil {
-		return "", fmt.Errorf("marshal CreateTweet payload: %w", err)
+		return nil, fmt.Errorf("marshal %s payload: %w", operation, err)
 	}
 
-	body, err := c.doPOST(ctx, acc, "CreateTweet", ep.URL(), payload)
+	body, err := c.doPOST(ctx, acc, operation, ep.URL(), payload)
 	if err != nil {
-		return "", fmt.Errorf("CreateTweet: %w", err)
+		return nil, fmt.Errorf("%s: %w", operation, err)
 	}
-	return parseCreateTweet(body)
+	return body, nil
 }
 
 // PostWithAccount posts a tweet from a named account (by username).
 // Returns the tweet ID on success.
-func (c *Client) PostWithAccount(ctx context.Context, username, text string) (string, error) {
+func (c *Client) PostWithAccount(ctx context.Context, username, text string, opts ...TweetOption) (string, error) {
 	acc := c.AccountByUsername(username)
 	if acc == nil {
 		return "", fmt.Errorf("account %q not found in pool", username)
@@ -281,5 +427,5 @@ func (c *Client) PostWithAccount(ctx context.Context, username, text string) (st
 	if !acc.IsActive() {
 		return "", fmt.Errorf("account %q is not active", username)
 	}
-	return c.CreateTweet(ctx, acc, text)
+	return c.CreateTweet(ctx, acc, text, opts...)
 }
@@ -0,0 +1,58 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// RegisterEndpoint adds or replaces the GraphQL operation name in Endpoints,
+// so Client.GraphQL can call operations this package does not wrap. nil
+// features means the default web feature flags. Registered x.com operations
+// are kept current by EndpointResolver like the built-in ones.
+func RegisterEndpoint(name, id string, features map[string]any) (Endpoint, error) {
+	if name == "" || id == "" {
+		return Endpoint{}, errors.New("RegisterEndpoint: name and id are required")
+	}
+	if features == nil {
+		features = gqlFeatures()
+	}
+	ep := Endpoint{ID: id, Name: name, Features: maps.Clone(features)}
+	endpointsMu.Lock()
+	Endpoints[name] = ep
+	endpointsMu.Unlock()
+	return ep, nil
+}
+
+// GraphQL runs the GraphQL query op with variables and returns the raw
+// response body. It goes through the same path as the wrapped reads: pool
+// rotation, retries, xtid headers, guest fallback and the response cache
+// (keyed by op.Name in CacheTTL).
+//
+// features nil means op's current flags, including learned ones and
+// ClientConfig.FeatureOverrides when op is registered under op.Name, or the
+// default web flags if op has none.
+func (c *Client) GraphQL(ctx context.Context, op Endpoint, variables, features map[string]any) ([]byte, error) {
+	if op.ID == "" || op.Name == "" {
+		return nil, errors.New("GraphQL: operation needs an ID and a Name")
+	}
+	if features == nil {
+		features = op.Features
+		if _, ok := lookupEndpoint(op.Name); ok {
+			features = c.features(op.Name)
+		}
+		if features == nil {
+			features = gqlFeatures()
+		}
+	}
+	if variables == nil {
+		variables = map[string]any{}
+	}
+	url := addGraphQLParams(op.URL(), variables, features)
+	body, _, err := c.doGET(ctx, op.Name, url)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op.Name, err)
+	}
+	return body, nil
+}
@@ -0,0 +1,54 @@
+package twitter
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQL_CustomOperation(t *testing.T) {
+	ep, err := RegisterEndpoint("CustomOp", "QID123", map[string]any{"custom_flag": true})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		endpointsMu.Lock()
+		delete(Endpoints, "CustomOp")
+		endpointsMu.Unlock()
+	})
+	_, err = RegisterEndpoint("", "QID", nil)
+	assert.Error(t, err)
+
+	tr := &headerTransport{}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+		FeatureOverrides:     map[string]map[string]any{"CustomOp": {"override_flag": false}},
+	})
+	require.NoError(t, err)
+
+	body, err := c.GraphQL(context.Background(), ep, map[string]any{"id": "42"}, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(body))
+
+	require.Len(t, tr.urls, 1)
+	u, err := url.Parse(tr.urls[0])
+	require.NoError(t, err)
+	assert.Equal(t, "/i/api/graphql/QID123/CustomOp", u.Path)
+	assert.JSONEq(t, `{"id":"42"}`, u.Query().Get("variables"))
+	assert.JSONEq(t, `{"custom_flag":true,"override_flag":false}`, u.Query().Get("features"))
+	assert.Contains(t, tr.headers[0]["cookie"], "auth_token=a")
+
+	// Explicit features win; unregistered operations can be called directly.
+	_, err = c.GraphQL(context.Background(), Endpoint{ID: "Z", Name: "Other"}, nil, map[string]any{"x": true})
+	require.NoError(t, err)
+	u, err = url.Parse(tr.urls[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"x":true}`, u.Query().Get("features"))
+
+	_, err = c.GraphQL(context.Background(), Endpoint{Name: "NoID"}, nil, nil)
+	assert.Error(t, err)
+}
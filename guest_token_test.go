@@ -80,3 +80,45 @@ func TestGuestCircuitBreakerResetOnSuccess(t *testing.T) {
 		t.Fatal("circuit breaker should not be tripped after successful reset")
 	}
 }
+
+func TestCanDowngradeToGuest(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := &Client{cfg: ClientConfig{GuestDowngradeOnDeadline: true}}
+	if !c.canDowngradeToGuest(ctx, "UserByScreenName") {
+		t.Fatal("expected downgrade for guest-capable endpoint with deadline")
+	}
+	if c.canDowngradeToGuest(ctx, "SearchTimeline") {
+		t.Fatal("SearchTimeline is not guest-capable")
+	}
+	if c.canDowngradeToGuest(context.Background(), "UserByScreenName") {
+		t.Fatal("expected no downgrade without a deadline")
+	}
+
+	c.cfg.DisableGuestFallback = true
+	if c.canDowngradeToGuest(ctx, "UserByScreenName") {
+		t.Fatal("DisableGuestFallback must take precedence")
+	}
+}
+
+func TestAccountWait(t *testing.T) {
+	c := &Client{cfg: ClientConfig{GuestDowngradeOnDeadline: true, GuestDowngradeReserve: 3 * time.Second}}
+
+	if got := c.accountWait(context.Background(), "UserByScreenName"); got != maxAccountWait {
+		t.Fatalf("no deadline: got %v, want %v", got, maxAccountWait)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	got := c.accountWait(ctx, "UserByScreenName")
+	if got <= 6*time.Second || got > 7*time.Second {
+		t.Fatalf("expected ~7s wait, got %v", got)
+	}
+
+	short, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	if got := c.accountWait(short, "UserByScreenName"); got != 0 {
+		t.Fatalf("deadline inside reserve: got %v, want 0", got)
+	}
+}
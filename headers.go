@@ -80,6 +80,7 @@ var twitterHeaderOrder = []string{
 	"x-csrf-token",
 	"x-twitter-active-user",
 	"x-twitter-client-language",
+	"x-twitter-client-uuid",
 	"x-client-transaction-id",
 	"x-xp-forwarded-for",
 	"sec-ch-ua",
@@ -94,3 +95,25 @@ var twitterHeaderOrder = []string{
 	"accept-language",
 	"accept-encoding",
 }
+
+// mediaHeaders returns browser headers for a media CDN request made from an
+// x.com page.
+func mediaHeaders(userAgent string) map[string]string {
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	h := map[string]string{
+		"user-agent":      userAgent,
+		"accept":          "*/*",
+		"accept-language": "en-US,en;q=0.9",
+		"referer":         "https://x.com/",
+		"origin":          "https://x.com",
+		"sec-fetch-dest":  "empty",
+		"sec-fetch-mode":  "cors",
+		"sec-fetch-site":  "cross-site",
+	}
+	for k, v := range stealth.ClientHintsHeaders(userAgent) {
+		h[k] = v
+	}
+	return h
+}
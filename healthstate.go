@@ -0,0 +1,217 @@
+package twitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+// healthStateFile holds account health inside SessionDir.
+const healthStateFile = ".health.json"
+
+// healthSaveDelay batches the saves triggered by a burst of failures.
+const healthSaveDelay = 5 * time.Second
+
+// savedHealth is the persisted health of one account. Only state that still
+// matters after a restart is kept: windows and cooldowns that have not ended.
+type savedHealth struct {
+	ReactivateAt     time.Time            `json:"reactivate_at,omitzero"`
+	Requests         int                  `json:"requests,omitempty"`
+	Failures         int                  `json:"failures,omitempty"`
+	ConsecFailures   int                  `json:"consec_failures,omitempty"`
+	RateLimitedUntil map[string]time.Time `json:"rate_limited_until,omitempty"`
+	ProxyBackoff     time.Time            `json:"proxy_backoff,omitzero"`
+	LastError        string               `json:"last_error,omitempty"`
+	LastErrorAt      time.Time            `json:"last_error_at,omitzero"`
+	ActionsDay       string               `json:"actions_day,omitempty"`
+	Actions          map[ActionClass]int  `json:"actions,omitempty"`
+}
+
+// healthSaver debounces health saves. Safe on nil (persistence disabled).
+type healthSaver struct {
+	mu          sync.Mutex
+	pending     bool
+	actionsOnly bool // only action counts are restored: quotas without PersistHealth
+}
+
+// savesActionsOnly reports whether only action counts persist.
+func (h *healthSaver) savesActionsOnly() bool {
+	return h != nil && h.actionsOnly
+}
+
+// schedule saves c's account health after healthSaveDelay unless a save is
+// already pending.
+func (h *healthSaver) schedule(c *Client) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.pending {
+		return
+	}
+	h.pending = true
+	time.AfterFunc(healthSaveDelay, func() {
+		h.mu.Lock()
+		h.pending = false
+		h.mu.Unlock()
+		if err := c.SaveHealth(); err != nil {
+			slog.Warn("save account health failed", slog.Any("error", err))
+		}
+	})
+}
+
+// snapshotHealth returns the account's persistable health as of now.
+func (a *Account) snapshotHealth(now time.Time) savedHealth {
+	var s savedHealth
+	if !a.IsActive() && a.ReactivateAt().After(now) {
+		s.ReactivateAt = a.ReactivateAt()
+	}
+	s.Requests, s.Failures, s.ConsecFailures = a.Stats()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for endpoint, until := range a.rateLimitedUntil {
+		if until.After(now) {
+			if s.RateLimitedUntil == nil {
+				s.RateLimitedUntil = make(map[string]time.Time)
+			}
+			s.RateLimitedUntil[endpoint] = until
+		}
+	}
+	if a.proxyBackoff.After(now) {
+		s.ProxyBackoff = a.proxyBackoff
+	}
+	s.LastError, s.LastErrorAt = a.lastError, a.lastErrorAt
+	if a.actionsDay == quotaDay(now) && len(a.actions) > 0 {
+		s.ActionsDay, s.Actions = a.actionsDay, maps.Clone(a.actions)
+	}
+	return s
+}
+
+// restoreHealth applies a saved snapshot that is still in effect.
+func (a *Account) restoreHealth(s savedHealth, now time.Time) {
+	if s.ReactivateAt.After(now) {
+		a.SetActive(false)
+		a.SetReactivateAt(s.ReactivateAt)
+	}
+	a.restoreCounters(s.Requests, s.Failures, s.ConsecFailures)
+	for endpoint, until := range s.RateLimitedUntil {
+		if until.After(now) {
+			a.MarkEndpointRateLimited(endpoint, until)
+		}
+	}
+	a.mu.Lock()
+	if s.ProxyBackoff.After(now) {
+		a.proxyBackoff = s.ProxyBackoff
+	}
+	if a.lastError == "" {
+		a.lastError, a.lastErrorAt = s.LastError, s.LastErrorAt
+	}
+	if s.ActionsDay == quotaDay(now) && a.actionsDay != s.ActionsDay {
+		a.actionsDay, a.actions = s.ActionsDay, s.Actions
+	}
+	a.mu.Unlock()
+}
+
+// maxReplayedRequests bounds the outcomes restoreCounters records per
+// account.
+const maxReplayedRequests = 1000
+
+// restoreCounters sets the account's request counters to the saved ones.
+// pool.HealthTracker keeps them unexported, so they are rebuilt by
+// recording the shortest run of outcomes that yields them: the failures
+// before the last success, the successes, then the consecutive failures.
+// Counts past maxReplayedRequests are scaled down first, keeping the
+// failure rate and the consecutive failures.
+func (a *Account) restoreCounters(total, failed, consec int) {
+	a.Reset()
+	if total > maxReplayedRequests {
+		scaled := max(maxReplayedRequests, min(consec+1, total))
+		f := int(math.Round(float64(failed) * float64(scaled) / float64(total)))
+		if failed < total {
+			f = min(f, scaled-1) // keep a success, or consec would grow
+		}
+		total, failed = scaled, max(f, consec)
+	}
+	for range failed - consec {
+		a.RecordFailure()
+	}
+	for range total - failed {
+		a.RecordSuccess()
+	}
+	for range consec {
+		a.RecordFailure()
+	}
+}
+
+// SaveHealth writes the health of every pool account (soft-deactivations,
+// consecutive failures, rate-limit windows, proxy backoff, today's action
+// counts) to SessionDir. The client saves on its own after failures and
+// counted actions; call SaveHealth on shutdown to capture the final state.
+func (c *Client) SaveHealth() error {
+	now := time.Now()
+	state := make(map[string]savedHealth)
+	for _, acc := range c.pool.Items() {
+		state[acc.Username] = acc.snapshotHealth(now)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	d := sessionDir(c.cfg.SessionDir)
+	if err := os.MkdirAll(d, 0700); err != nil {
+		return fmt.Errorf("create session dir: %w", err)
+	}
+	// A unique temp file keeps concurrent saves from clobbering each other.
+	f, err := os.CreateTemp(d, healthStateFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), sessionDirFile(c.cfg.SessionDir, healthStateFile))
+}
+
+// restoreHealth loads saved health, or only the action counts when just
+// ActionQuotas asks for persistence, into the pool accounts; a missing file
+// is not an error. Permanent deactivations are not restored, since NewClient
+// has just re-checked every account's login.
+func (c *Client) restoreHealth() error {
+	data, err := os.ReadFile(sessionDirFile(c.cfg.SessionDir, healthStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var state map[string]savedHealth
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("parse account health: %w", err)
+	}
+	now := time.Now()
+	restored := 0
+	for _, acc := range c.pool.Items() {
+		if s, ok := state[acc.Username]; ok {
+			if c.health.savesActionsOnly() {
+				s = savedHealth{ActionsDay: s.ActionsDay, Actions: s.Actions}
+			}
+			acc.restoreHealth(s, now)
+			restored++
+		}
+	}
+	slog.Debug("account health restored", slog.Int("accounts", restored))
+	return nil
+}
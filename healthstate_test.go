@@ -0,0 +1,67 @@
+package twitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthPersistence_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	banned := &Account{Username: "banned", HealthTracker: pool.DefaultHealthTracker()}
+	banned.SetReactivateAt(now.Add(time.Hour))
+	flaky := &Account{Username: "flaky", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	flaky.RecordFailure()
+	flaky.RecordSuccess()
+	flaky.RecordSuccess()
+	flaky.RecordFailure()
+	flaky.RecordFailure()
+	flaky.MarkEndpointRateLimited("UserTweets", now.Add(10*time.Minute))
+	flaky.MarkEndpointRateLimited("SearchTimeline", now.Add(-time.Minute)) // already over
+	flaky.proxyBackoff = now.Add(time.Minute)
+	flaky.recordError(assert.AnError)
+
+	c := &Client{pool: pool.New([]*Account{banned, flaky}, pool.Config{}), cfg: ClientConfig{SessionDir: dir}}
+	require.NoError(t, c.SaveHealth())
+
+	// A restarted client: fresh accounts, all logged in.
+	banned2 := &Account{Username: "banned", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	flaky2 := &Account{Username: "flaky", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	flaky2.RecordSuccess() // the login check of the new client
+	c2 := &Client{pool: pool.New([]*Account{banned2, flaky2}, pool.Config{}), cfg: ClientConfig{SessionDir: dir}}
+	require.NoError(t, c2.restoreHealth())
+
+	assert.False(t, banned2.IsActive(), "cooldown survives the restart")
+	assert.WithinDuration(t, now.Add(time.Hour), banned2.ReactivateAt(), time.Second)
+
+	assert.True(t, flaky2.IsActive())
+	total, failed, consec := flaky2.Stats()
+	assert.Equal(t, [3]int{5, 3, 2}, [3]int{total, failed, consec}, "counters are restored, not added to")
+	require.Len(t, flaky2.rateLimitedUntil, 1, "expired windows are dropped")
+	assert.WithinDuration(t, now.Add(10*time.Minute), flaky2.rateLimitedUntil["UserTweets"], time.Second)
+	assert.WithinDuration(t, now.Add(time.Minute), flaky2.proxyBackoff, time.Second)
+	assert.Equal(t, assert.AnError.Error(), flaky2.lastError)
+}
+
+func TestRestoreHealth_MissingFile(t *testing.T) {
+	c := &Client{pool: pool.New([]*Account{}, pool.Config{}), cfg: ClientConfig{SessionDir: t.TempDir()}}
+	assert.NoError(t, c.restoreHealth())
+}
+
+func TestRestoreCounters_Bounded(t *testing.T) {
+	acc := &Account{Username: "busy", HealthTracker: pool.DefaultHealthTracker()}
+	acc.restoreCounters(2_000_000, 500_000, 3)
+
+	total, failed, consec := acc.Stats()
+	assert.Equal(t, maxReplayedRequests, total)
+	assert.InDelta(t, 0.25, float64(failed)/float64(total), 0.001, "the failure rate is kept")
+	assert.Equal(t, 3, consec)
+
+	acc.restoreCounters(5000, 5000, 5000)
+	total, failed, consec = acc.Stats()
+	assert.Equal(t, [3]int{5000, 5000, 5000}, [3]int{total, failed, consec}, "a failure streak is kept whole")
+}
@@ -0,0 +1,156 @@
+package twitter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type hedgeDelayKey struct{}
+
+// WithHedging returns a context whose pool reads start a second request on a
+// different account when the first has not answered within delay. The first
+// success is returned and the other request cancelled. Slow reads cost up to
+// twice the budget, so use it for latency-critical lookups only. Writes are
+// never hedged.
+func WithHedging(ctx context.Context, delay time.Duration) context.Context {
+	return context.WithValue(ctx, hedgeDelayKey{}, delay)
+}
+
+// hedgeDelay returns the delay set by WithHedging, or 0.
+func hedgeDelay(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(hedgeDelayKey{}).(time.Duration)
+	return d
+}
+
+type hedgeClaimsKey struct{}
+
+// hedgeClaims records which branch of a hedged request holds which accounts,
+// so the branches use different accounts. A branch's claims are released when
+// it finishes.
+type hedgeClaims struct {
+	mu     sync.Mutex
+	claims map[*Account]int // account → branch
+}
+
+// hedgeBranch identifies one branch of a hedged request in its context.
+// excludes and claim are nil-safe, so unhedged requests can call them.
+type hedgeBranch struct {
+	claims *hedgeClaims
+	id     int
+}
+
+// hedgeBranchFrom returns the hedge branch ctx belongs to; nil outside a
+// hedged request.
+func hedgeBranchFrom(ctx context.Context) *hedgeBranch {
+	b, _ := ctx.Value(hedgeClaimsKey{}).(*hedgeBranch)
+	return b
+}
+
+// excludes reports whether acc is held by another branch.
+func (b *hedgeBranch) excludes(acc *Account) bool {
+	if b == nil {
+		return false
+	}
+	b.claims.mu.Lock()
+	defer b.claims.mu.Unlock()
+	owner, ok := b.claims.claims[acc]
+	return ok && owner != b.id
+}
+
+// claim marks acc as held by the branch.
+func (b *hedgeBranch) claim(acc *Account) {
+	if b == nil {
+		return
+	}
+	b.claims.mu.Lock()
+	defer b.claims.mu.Unlock()
+	if b.claims.claims == nil {
+		b.claims.claims = make(map[*Account]int)
+	}
+	b.claims.claims[acc] = b.id
+}
+
+// release drops the branch's claims.
+func (b *hedgeBranch) release() {
+	b.claims.mu.Lock()
+	defer b.claims.mu.Unlock()
+	for acc, owner := range b.claims.claims {
+		if owner == b.id {
+			delete(b.claims.claims, acc)
+		}
+	}
+}
+
+// hedgedResult is one branch's outcome.
+type hedgedResult struct {
+	body    []byte
+	headers map[string]string
+	err     error
+	info    *RequestInfo
+}
+
+// hedgedPoolRequest runs poolRequest, adding a second branch on another
+// account when ctx asks for hedging and the first is still running after the
+// delay. The first success wins; if both fail, the last error is returned.
+// Pool requests are all reads, so POST-based ones such as SearchTimeline
+// are hedged too.
+func (c *Client) hedgedPoolRequest(ctx context.Context, span trace.Span, method, endpoint, url string, payload []byte) ([]byte, map[string]string, error) {
+	delay := hedgeDelay(ctx)
+	if delay <= 0 {
+		return c.poolRequest(ctx, span, method, endpoint, url, payload)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops the losing branch
+
+	claims := &hedgeClaims{}
+	results := make(chan hedgedResult, 2)
+	launch := func(id int) {
+		branch := &hedgeBranch{claims: claims, id: id}
+		info := &RequestInfo{}
+		bctx := WithRequestInfo(context.WithValue(ctx, hedgeClaimsKey{}, branch), info)
+		go func() {
+			body, headers, err := c.poolRequest(bctx, span, method, endpoint, url, payload)
+			branch.release()
+			results <- hedgedResult{body, headers, err, info}
+		}()
+	}
+	launch(0)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	running, hedged := 1, false
+	var attempts int
+	for {
+		select {
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				running++
+				span.AddEvent("hedge")
+				launch(1)
+			}
+		case r := <-results:
+			running--
+			attempts += r.info.Attempts()
+			if r.err == nil || running == 0 {
+				requestInfoFrom(ctx).adopt(r.info, attempts)
+				return r.body, r.headers, r.err
+			}
+		}
+	}
+}
+
+// adopt copies how a hedge branch was served into r, counting attempts
+// across all branches. Safe on nil.
+func (r *RequestInfo) adopt(branch *RequestInfo, attempts int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.source, r.account = branch.Source(), branch.Account()
+	r.attempts += attempts
+}
@@ -0,0 +1,139 @@
+package twitter
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowFirstTransport stalls the first request it gets for delay, or until
+// it is cancelled, and answers the others at once.
+type slowFirstTransport struct {
+	delay time.Duration
+
+	mu        sync.Mutex
+	tokens    []string
+	cancelled bool
+}
+
+func (tr *slowFirstTransport) Do(ctx context.Context, _, _ string, headers map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	tr.mu.Lock()
+	tok, _, _ := strings.Cut(strings.TrimPrefix(headers["cookie"], "auth_token="), ";")
+	tr.tokens = append(tr.tokens, tok)
+	first := len(tr.tokens) == 1
+	tr.mu.Unlock()
+	if first {
+		select {
+		case <-time.After(tr.delay):
+		case <-ctx.Done():
+			tr.mu.Lock()
+			tr.cancelled = true
+			tr.mu.Unlock()
+			return nil, nil, 0, ctx.Err()
+		}
+	}
+	return []byte(`{"data":{}}`), nil, 200, nil
+}
+
+func (tr *slowFirstTransport) wasCancelled() bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.cancelled
+}
+
+func newHedgeClient(t *testing.T, tr Transport) *Client {
+	t.Helper()
+	c, err := NewClient(ClientConfig{
+		Accounts: []*Account{
+			{Username: "alice", AuthToken: "tok-alice", CT0: "c"},
+			{Username: "bob", AuthToken: "tok-bob", CT0: "c"},
+		},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestWithHedging(t *testing.T) {
+	assert.Zero(t, hedgeDelay(context.Background()))
+	assert.Equal(t, 2*time.Second, hedgeDelay(WithHedging(context.Background(), 2*time.Second)))
+}
+
+func TestHedgeBranch_Claims(t *testing.T) {
+	a, b := &Account{Username: "a"}, &Account{Username: "b"}
+	claims := &hedgeClaims{}
+	first, second := &hedgeBranch{claims: claims, id: 0}, &hedgeBranch{claims: claims, id: 1}
+
+	first.claim(a)
+	assert.False(t, first.excludes(a), "a branch may retry its own account")
+	assert.True(t, second.excludes(a))
+	assert.False(t, second.excludes(b))
+
+	first.release()
+	assert.False(t, second.excludes(a), "a finished branch frees its accounts")
+
+	var none *hedgeBranch
+	none.claim(a)
+	assert.False(t, none.excludes(a))
+}
+
+func TestRequestInfo_Adopt(t *testing.T) {
+	branch := &RequestInfo{}
+	branch.served(SourceAccount, "b")
+	info := &RequestInfo{}
+	info.adopt(branch, 3)
+	assert.Equal(t, SourceAccount, info.Source())
+	assert.Equal(t, "b", info.Account())
+	assert.Equal(t, 3, info.Attempts())
+
+	var nilInfo *RequestInfo
+	nilInfo.adopt(branch, 1)
+}
+
+func TestHedgedPoolRequest_SlowFirstBranch(t *testing.T) {
+	for _, method := range []string{"GET", "POST"} {
+		t.Run(method, func(t *testing.T) {
+			tr := &slowFirstTransport{delay: 5 * time.Second}
+			c := newHedgeClient(t, tr)
+			ctx := WithHedging(context.Background(), 20*time.Millisecond)
+
+			start := time.Now()
+			_, _, err := c.doPoolRequest(ctx, method, "SearchTimeline", Endpoints["SearchTimeline"].URL(), []byte(`{}`))
+			require.NoError(t, err)
+			assert.Less(t, time.Since(start), time.Second, "the second branch answers")
+
+			tr.mu.Lock()
+			tokens := tr.tokens
+			tr.mu.Unlock()
+			require.Len(t, tokens, 2)
+			assert.NotEqual(t, tokens[0], tokens[1], "the branches use different accounts")
+			assert.Eventually(t, tr.wasCancelled, time.Second, 5*time.Millisecond, "the losing branch is cancelled")
+			assert.Never(t, func() bool {
+				for _, acc := range c.pool.Items() {
+					if _, failed, _ := acc.Stats(); failed > 0 {
+						return true
+					}
+				}
+				return false
+			}, 50*time.Millisecond, 5*time.Millisecond, "the cancelled branch is not held against its account")
+		})
+	}
+}
+
+func TestHedgedPoolRequest_FastFirstBranch(t *testing.T) {
+	tr := &slowFirstTransport{delay: time.Millisecond}
+	c := newHedgeClient(t, tr)
+	ctx := WithHedging(context.Background(), time.Second)
+
+	_, _, err := c.doPoolRequest(ctx, "GET", "SearchTimeline", Endpoints["SearchTimeline"].URL(), nil)
+	require.NoError(t, err)
+	assert.Len(t, tr.tokens, 1, "no second branch before the delay")
+}
@@ -0,0 +1,162 @@
+package twitter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DownloadVideo writes video or GIF m to w: its best MP4 variant when there
+// is one, otherwise the highest-bandwidth stream of its HLS playlist (see
+// DownloadHLS).
+func (c *Client) DownloadVideo(ctx context.Context, m Media, w io.Writer) error {
+	if v, ok := m.BestVariant(); ok {
+		return c.DownloadMedia(ctx, v.URL, w)
+	}
+	for _, v := range m.Variants {
+		if v.isHLS() {
+			return c.DownloadHLS(ctx, v.URL, w)
+		}
+	}
+	return fmt.Errorf("DownloadVideo: %s has no video variants", m.Type)
+}
+
+// DownloadHLS downloads the HLS playlist at playlistURL and writes its
+// segments to w in order. For a master playlist, the highest-bandwidth
+// stream is used. X serves fragmented-MP4 segments after an init segment,
+// so the output is a playable MP4. Where the stream keeps audio in a
+// separate rendition only the video track is written; prefer an MP4 variant
+// when the tweet has one.
+func (c *Client) DownloadHLS(ctx context.Context, playlistURL string, w io.Writer) error {
+	playlist, base, err := c.fetchPlaylist(ctx, playlistURL)
+	if err != nil {
+		return err
+	}
+	if streams := parseMasterPlaylist(playlist, base); len(streams) > 0 {
+		best := streams[0]
+		for _, s := range streams[1:] {
+			if s.Bandwidth > best.Bandwidth {
+				best = s
+			}
+		}
+		if playlist, base, err = c.fetchPlaylist(ctx, best.URL); err != nil {
+			return err
+		}
+	}
+	segments := parseMediaPlaylist(playlist, base)
+	if len(segments) == 0 {
+		return fmt.Errorf("DownloadHLS: playlist has no segments")
+	}
+	for i, seg := range segments {
+		if err := c.DownloadMedia(ctx, seg, w); err != nil {
+			return fmt.Errorf("DownloadHLS: segment %d/%d: %w", i+1, len(segments), err)
+		}
+	}
+	return nil
+}
+
+// fetchPlaylist downloads an HLS playlist and returns it with the URL its
+// relative references resolve against.
+func (c *Client) fetchPlaylist(ctx context.Context, playlistURL string) ([]byte, *url.URL, error) {
+	base, err := url.Parse(playlistURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DownloadHLS: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := c.DownloadMedia(ctx, playlistURL, &buf); err != nil {
+		return nil, nil, fmt.Errorf("DownloadHLS: playlist: %w", err)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(buf.Bytes()), []byte("#EXTM3U")) {
+		return nil, nil, fmt.Errorf("DownloadHLS: %s is not an HLS playlist", playlistURL)
+	}
+	return buf.Bytes(), base, nil
+}
+
+// hlsStream is one #EXT-X-STREAM-INF entry of a master playlist.
+type hlsStream struct {
+	URL        string
+	Bandwidth  int
+	Resolution string // e.g. "720x1280"
+}
+
+// parseMasterPlaylist returns the streams of a master playlist, or nil for a
+// media playlist.
+func parseMasterPlaylist(playlist []byte, base *url.URL) []hlsStream {
+	var streams []hlsStream
+	var pending *hlsStream
+	sc := bufio.NewScanner(bytes.NewReader(playlist))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			attrs := hlsAttributes(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			bw, _ := strconv.Atoi(attrs["BANDWIDTH"])
+			pending = &hlsStream{Bandwidth: bw, Resolution: attrs["RESOLUTION"]}
+		case line == "" || strings.HasPrefix(line, "#"):
+		case pending != nil:
+			pending.URL = resolveRef(base, line)
+			streams = append(streams, *pending)
+			pending = nil
+		}
+	}
+	return streams
+}
+
+// parseMediaPlaylist returns the URLs to concatenate for a media playlist:
+// the init segment (#EXT-X-MAP), if any, then the media segments.
+func parseMediaPlaylist(playlist []byte, base *url.URL) []string {
+	var urls []string
+	sc := bufio.NewScanner(bytes.NewReader(playlist))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			if uri := hlsAttributes(strings.TrimPrefix(line, "#EXT-X-MAP:"))["URI"]; uri != "" {
+				urls = append(urls, resolveRef(base, uri))
+			}
+		case line == "" || strings.HasPrefix(line, "#"):
+		default:
+			urls = append(urls, resolveRef(base, line))
+		}
+	}
+	return urls
+}
+
+// hlsAttributes parses an HLS attribute list (KEY=value,KEY="quoted, value").
+func hlsAttributes(s string) map[string]string {
+	attrs := make(map[string]string)
+	for s != "" {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		var val string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			val, rest = rest[1:1+end], rest[min(2+end, len(rest)):]
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			val, rest, _ = strings.Cut(rest, ",")
+		}
+		attrs[strings.TrimSpace(key)] = val
+		s = rest
+	}
+	return attrs
+}
+
+// resolveRef resolves a playlist reference against the playlist's URL.
+func resolveRef(base *url.URL, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
@@ -0,0 +1,83 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMasterPlaylist = `#EXTM3U
+#EXT-X-INDEPENDENT-SEGMENTS
+#EXT-X-STREAM-INF:AVERAGE-BANDWIDTH=300000,BANDWIDTH=400000,RESOLUTION=320x568,CODECS="mp4a.40.2,avc1.4d001e"
+/ext_tw_video/1/pu/pl/320x568/low.m3u8
+#EXT-X-STREAM-INF:AVERAGE-BANDWIDTH=2000000,BANDWIDTH=2500000,RESOLUTION=720x1280,CODECS="mp4a.40.2,avc1.640020"
+/ext_tw_video/1/pu/pl/720x1280/high.m3u8
+`
+
+const testMediaPlaylist = `#EXTM3U
+#EXT-X-VERSION:6
+#EXT-X-MAP:URI="/ext_tw_video/1/pu/vid/720x1280/init.mp4"
+#EXTINF:3.000,
+/ext_tw_video/1/pu/vid/720x1280/0.m4s
+#EXTINF:1.500,
+1.m4s
+#EXT-X-ENDLIST
+`
+
+func TestParseMasterPlaylist(t *testing.T) {
+	base, _ := url.Parse("https://video.twimg.com/ext_tw_video/1/pu/pl/master.m3u8")
+	streams := parseMasterPlaylist([]byte(testMasterPlaylist), base)
+	require.Len(t, streams, 2)
+	assert.Equal(t, hlsStream{URL: "https://video.twimg.com/ext_tw_video/1/pu/pl/720x1280/high.m3u8", Bandwidth: 2500000, Resolution: "720x1280"}, streams[1])
+
+	assert.Nil(t, parseMasterPlaylist([]byte(testMediaPlaylist), base))
+}
+
+func TestParseMediaPlaylist(t *testing.T) {
+	base, _ := url.Parse("https://video.twimg.com/ext_tw_video/1/pu/vid/720x1280/high.m3u8")
+	assert.Equal(t, []string{
+		"https://video.twimg.com/ext_tw_video/1/pu/vid/720x1280/init.mp4",
+		"https://video.twimg.com/ext_tw_video/1/pu/vid/720x1280/0.m4s",
+		"https://video.twimg.com/ext_tw_video/1/pu/vid/720x1280/1.m4s",
+	}, parseMediaPlaylist([]byte(testMediaPlaylist), base))
+}
+
+func TestHLSAttributes(t *testing.T) {
+	attrs := hlsAttributes(`BANDWIDTH=400000,CODECS="mp4a.40.2,avc1.4d001e",RESOLUTION=320x568`)
+	assert.Equal(t, map[string]string{"BANDWIDTH": "400000", "CODECS": "mp4a.40.2,avc1.4d001e", "RESOLUTION": "320x568"}, attrs)
+}
+
+// pathTransport serves bodies by URL path.
+type pathTransport map[string]string
+
+func (p pathTransport) Do(_ context.Context, _, rawURL string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	u, _ := url.Parse(rawURL)
+	body, ok := p[u.Path]
+	if !ok {
+		return nil, nil, 404, nil
+	}
+	return []byte(body), nil, 200, nil
+}
+
+func TestDownloadVideo_HLS(t *testing.T) {
+	tr := pathTransport{
+		"/ext_tw_video/1/pu/pl/master.m3u8":        testMasterPlaylist,
+		"/ext_tw_video/1/pu/pl/720x1280/high.m3u8": testMediaPlaylist,
+		"/ext_tw_video/1/pu/vid/720x1280/init.mp4": "init|",
+		"/ext_tw_video/1/pu/vid/720x1280/0.m4s":    "seg0|",
+		"/ext_tw_video/1/pu/pl/720x1280/1.m4s":     "seg1",
+	}
+	c := &Client{cfg: ClientConfig{Transport: tr}, pool: pool.New([]*Account{}, pool.Config{})}
+	m := Media{Type: "video", Variants: []MediaVariant{
+		{URL: "https://video.twimg.com/ext_tw_video/1/pu/pl/master.m3u8", ContentType: "application/x-mpegURL"},
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, c.DownloadVideo(context.Background(), m, &buf))
+	assert.Equal(t, "init|seg0|seg1", buf.String())
+}
@@ -0,0 +1,161 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Hydrator resolves large handle lists to full profiles in two phases:
+// handles with a known user ID are hydrated in bulk via UsersByRestIds
+// (up to BatchSize per request); the rest fall back to one UserByScreenName
+// lookup each, whose IDs are remembered so the next pass is batched.
+//
+// User IDs never change, so a long-lived Hydrator turns repeated handle
+// resolution into roughly len(handles)/BatchSize requests. Safe for
+// concurrent use.
+type Hydrator struct {
+	c *Client
+
+	// BatchSize caps the number of IDs per UsersByRestIds request.
+	// Default (and maximum): 200.
+	BatchSize int
+
+	mu  sync.Mutex
+	ids map[string]string // normalized handle → user ID
+}
+
+// NewHydrator creates a Hydrator backed by c with an empty handle→ID cache.
+func (c *Client) NewHydrator() *Hydrator {
+	return &Hydrator{
+		c:         c,
+		BatchSize: maxUsersByRestIDsBatch,
+		ids:       make(map[string]string),
+	}
+}
+
+// Remember seeds the handle→ID cache, e.g. from a database or an earlier
+// Followers crawl. Invalid handles or IDs are ignored.
+func (h *Hydrator) Remember(handle, userID string) {
+	norm, err := NormalizeHandle(handle)
+	if err != nil || !IsValidUserID(userID) {
+		return
+	}
+	h.mu.Lock()
+	h.ids[norm] = userID
+	h.mu.Unlock()
+}
+
+// lookupID returns the cached user ID for a normalized handle.
+func (h *Hydrator) lookupID(handle string) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id, ok := h.ids[handle]
+	return id, ok
+}
+
+// forget drops a cached handle, e.g. after the ID stopped resolving.
+func (h *Hydrator) forget(handle string) {
+	h.mu.Lock()
+	delete(h.ids, handle)
+	h.mu.Unlock()
+}
+
+// Hydrate returns profiles keyed by normalized handle. Handles that fail to
+// resolve are omitted and their errors joined into the returned error, so
+// partial results are always usable.
+func (h *Hydrator) Hydrate(ctx context.Context, handles []string) (map[string]*TwitterUser, error) {
+	result := make(map[string]*TwitterUser, len(handles))
+	var errs []error
+
+	// Split into handles with a cached ID and unknown handles.
+	byID := make(map[string]string) // user ID → normalized handle
+	var ids, unknown []string
+	seen := make(map[string]bool, len(handles))
+	for _, raw := range handles {
+		handle, err := NormalizeHandle(raw)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		if id, ok := h.lookupID(handle); ok {
+			byID[id] = handle
+			ids = append(ids, id)
+		} else {
+			unknown = append(unknown, handle)
+		}
+	}
+
+	// Phase 1: bulk-hydrate cached IDs.
+	batch := h.BatchSize
+	if batch <= 0 || batch > maxUsersByRestIDsBatch {
+		batch = maxUsersByRestIDsBatch
+	}
+	for _, chunk := range chunkStrings(ids, batch) {
+		users, err := h.c.getUsersByRestIDs(ctx, chunk)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, errors.Join(append(errs, err)...)
+			}
+			// Batch failed as a whole; resolve its handles individually instead.
+			slog.Warn("hydrator: batch lookup failed, falling back to screen-name lookups",
+				slog.Int("batch", len(chunk)), slog.Any("error", err))
+			for _, id := range chunk {
+				unknown = append(unknown, byID[id])
+			}
+			continue
+		}
+		found := make(map[string]bool, len(users))
+		for _, u := range users {
+			handle, ok := byID[u.ID]
+			if !ok || !strings.EqualFold(u.Handle, handle) {
+				continue // renamed accounts are re-resolved by handle below
+			}
+			found[u.ID] = true
+			result[handle] = u
+		}
+		for _, id := range chunk {
+			if !found[id] {
+				// Suspended, deactivated, or the handle moved to a new account.
+				h.forget(byID[id])
+				unknown = append(unknown, byID[id])
+			}
+		}
+	}
+
+	// Phase 2: one-by-one lookups for handles without a usable ID.
+	for _, handle := range unknown {
+		if ctx.Err() != nil {
+			return result, errors.Join(append(errs, ctx.Err())...)
+		}
+		u, err := h.c.GetUserByScreenName(ctx, handle)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", handle, err))
+			continue
+		}
+		h.Remember(handle, u.ID)
+		result[handle] = u
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// chunkStrings splits s into consecutive slices of at most n elements.
+func chunkStrings(s []string, n int) [][]string {
+	var chunks [][]string
+	for len(s) > n {
+		chunks = append(chunks, s[:n:n])
+		s = s[n:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
@@ -0,0 +1,31 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkStrings(t *testing.T) {
+	assert.Nil(t, chunkStrings(nil, 3))
+	assert.Equal(t, [][]string{{"a", "b"}}, chunkStrings([]string{"a", "b"}, 3))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, chunkStrings([]string{"a", "b", "c", "d", "e"}, 2))
+}
+
+func TestHydratorRemember(t *testing.T) {
+	h := (&Client{}).NewHydrator()
+	h.Remember("@ElonMusk", "44196397")
+	h.Remember("bad-handle", "1")
+	h.Remember("jack", "not-an-id")
+
+	id, ok := h.lookupID("elonmusk")
+	assert.True(t, ok)
+	assert.Equal(t, "44196397", id)
+
+	_, ok = h.lookupID("jack")
+	assert.False(t, ok)
+
+	h.forget("elonmusk")
+	_, ok = h.lookupID("elonmusk")
+	assert.False(t, ok)
+}
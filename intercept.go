@@ -0,0 +1,81 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+)
+
+// InterceptedRequest is a request as seen by the interceptors of
+// ClientConfig. Changes to URL and Headers are sent; Body is read-only.
+type InterceptedRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte // nil when the request has none
+}
+
+// InterceptedResponse is the outcome of an intercepted request: what the
+// network returned, or what a RequestInterceptor answered instead.
+type InterceptedResponse struct {
+	Status  int
+	Headers map[string]string // lower-cased names
+	Body    []byte
+	Err     error // transport error, if any; Status is then usually 0
+}
+
+// RequestInterceptor inspects or rewrites req before it is sent. A non-nil
+// response skips the network and is used as the answer; an error fails the
+// request.
+type RequestInterceptor func(ctx context.Context, req *InterceptedRequest) (*InterceptedResponse, error)
+
+// ResponseInterceptor inspects or rewrites resp, the answer to req. An
+// error fails the request with it.
+type ResponseInterceptor func(ctx context.Context, req *InterceptedRequest, resp *InterceptedResponse) error
+
+// intercepted reports whether any interceptors are configured.
+func (c *Client) intercepted() bool {
+	return len(c.cfg.RequestInterceptors) > 0 || len(c.cfg.ResponseInterceptors) > 0
+}
+
+// sendIntercepted runs the configured interceptors around sendDirect.
+func (c *Client) sendIntercepted(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	req := &InterceptedRequest{Method: method, URL: urlStr, Headers: headers}
+	if body != nil {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("read request body: %w", err)
+		}
+		req.Body = b
+	}
+
+	var resp *InterceptedResponse
+	for _, intercept := range c.cfg.RequestInterceptors {
+		r, err := intercept(ctx, req)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("request interceptor: %w", err)
+		}
+		if r != nil {
+			resp = r
+			break
+		}
+	}
+	if resp == nil {
+		var reqBody io.Reader
+		if req.Body != nil {
+			reqBody = bytes.NewReader(req.Body)
+		}
+		resp = &InterceptedResponse{}
+		resp.Body, resp.Headers, resp.Status, resp.Err = c.sendDirect(ctx, bc, req.Method, req.URL, req.Headers, reqBody)
+	}
+
+	for _, intercept := range c.cfg.ResponseInterceptors {
+		if err := intercept(ctx, req, resp); err != nil {
+			return nil, resp.Headers, resp.Status, fmt.Errorf("response interceptor: %w", err)
+		}
+	}
+	return resp.Body, resp.Headers, resp.Status, resp.Err
+}
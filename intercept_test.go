@@ -0,0 +1,69 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptors(t *testing.T) {
+	tr := &headerTransport{}
+	var seen []string
+	c := &Client{cfg: ClientConfig{
+		Transport: tr,
+		RequestInterceptors: []RequestInterceptor{
+			func(_ context.Context, req *InterceptedRequest) (*InterceptedResponse, error) {
+				req.Headers["x-trace-id"] = "abc"
+				return nil, nil
+			},
+			func(_ context.Context, req *InterceptedRequest) (*InterceptedResponse, error) {
+				if req.URL == "https://x.com/cached" {
+					return &InterceptedResponse{Status: 200, Body: []byte(`{"cached":true}`)}, nil
+				}
+				if req.URL == "https://x.com/blocked" {
+					return nil, errors.New("blocked by policy")
+				}
+				return nil, nil
+			},
+		},
+		ResponseInterceptors: []ResponseInterceptor{
+			func(_ context.Context, req *InterceptedRequest, resp *InterceptedResponse) error {
+				seen = append(seen, req.URL+" "+string(resp.Body))
+				return nil
+			},
+		},
+	}}
+	ctx := context.Background()
+
+	body, _, status, err := c.send(ctx, nil, "GET", "https://x.com/live", map[string]string{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, `{}`, string(body))
+	require.Len(t, tr.headers, 1)
+	assert.Equal(t, "abc", tr.headers[0]["x-trace-id"])
+
+	body, _, _, err = c.send(ctx, nil, "GET", "https://x.com/cached", map[string]string{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"cached":true}`, string(body))
+	assert.Len(t, tr.headers, 1, "short-circuited request skips the transport")
+
+	_, _, _, err = c.send(ctx, nil, "GET", "https://x.com/blocked", map[string]string{}, nil)
+	assert.ErrorContains(t, err, "blocked by policy")
+
+	assert.Equal(t, []string{"https://x.com/live {}", `https://x.com/cached {"cached":true}`}, seen)
+}
+
+func TestResponseInterceptor_FailsRequest(t *testing.T) {
+	errRejected := errors.New("rejected")
+	c := &Client{cfg: ClientConfig{
+		Transport: &headerTransport{},
+		ResponseInterceptors: []ResponseInterceptor{
+			func(context.Context, *InterceptedRequest, *InterceptedResponse) error { return errRejected },
+		},
+	}}
+	_, _, _, err := c.send(context.Background(), nil, "GET", "https://x.com/live", map[string]string{}, nil)
+	assert.ErrorIs(t, err, errRejected)
+}
@@ -0,0 +1,190 @@
+package twitter
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultJobPageSize is how many items a Job requests per checkpoint.
+const defaultJobPageSize = 100
+
+// JobCheckpoint is how far a Job got: the cursor of the next page and the
+// number of items handed to OnPage so far.
+type JobCheckpoint struct {
+	Cursor    string    `json:"cursor"`
+	Collected int       `json:"collected"`
+	Done      bool      `json:"done"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// JobStore keeps Job checkpoints between runs. FileJobStore keeps them in a
+// directory; implement it to share them, e.g. through a database.
+type JobStore interface {
+	// LoadCheckpoint returns the checkpoint saved as name, or a zero one if
+	// there is none.
+	LoadCheckpoint(name string) (JobCheckpoint, error)
+	SaveCheckpoint(name string, cp JobCheckpoint) error
+}
+
+// FileJobStore keeps checkpoints as files in Dir, one per job name.
+type FileJobStore struct {
+	Dir string
+}
+
+// jobFileName is the file a checkpoint is kept in inside the store's Dir.
+func jobFileName(name string) string {
+	sum := sha1.Sum([]byte(name))
+	return ".job-" + hex.EncodeToString(sum[:8]) + ".json"
+}
+
+// LoadCheckpoint reads the checkpoint saved as name; a missing file yields a
+// zero checkpoint.
+func (s FileJobStore) LoadCheckpoint(name string) (JobCheckpoint, error) {
+	var cp JobCheckpoint
+	data, err := os.ReadFile(filepath.Join(s.Dir, jobFileName(name)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cp, nil
+		}
+		return cp, err
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("parse job checkpoint %s: %w", name, err)
+	}
+	return cp, nil
+}
+
+// SaveCheckpoint writes cp as name, replacing it atomically.
+func (s FileJobStore) SaveCheckpoint(name string, cp JobCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("create job dir: %w", err)
+	}
+	path := filepath.Join(s.Dir, jobFileName(name))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Job pages through a long list, handing each page to OnPage and saving a
+// checkpoint after it, so that a run cut short by a crash, an error or
+// cancellation resumes where it stopped when run again under the same Name.
+type Job[T any] struct {
+	Name  string
+	Store JobStore
+	// Fetch returns up to maxCount items from cursor, like the *Paged
+	// methods.
+	Fetch func(ctx context.Context, cursor string, maxCount int) PagedResult[T]
+	// OnPage receives each page and should persist it before returning. An
+	// error stops the run without advancing the checkpoint, so the page is
+	// fetched again on the next run.
+	OnPage func(items []T) error
+	// MaxCount caps the items collected across all runs; 0 means the whole
+	// list.
+	MaxCount int
+	// PageSize is how many items are fetched between checkpoints.
+	// Default: 100.
+	PageSize int
+}
+
+// Run fetches the remaining pages from the saved checkpoint and returns
+// the last checkpoint saved. A job that is already done returns at once;
+// save a zero checkpoint under its Name to start it over.
+func (j *Job[T]) Run(ctx context.Context) (JobCheckpoint, error) {
+	if j.Store == nil || j.Fetch == nil || j.OnPage == nil {
+		return JobCheckpoint{}, errors.New("job: Store, Fetch and OnPage are required")
+	}
+	cp, err := j.Store.LoadCheckpoint(j.Name)
+	if err != nil {
+		return cp, fmt.Errorf("job %s: %w", j.Name, err)
+	}
+	pageSize := j.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultJobPageSize
+	}
+	for !cp.Done {
+		n := pageSize
+		if j.MaxCount > 0 {
+			n = min(n, j.MaxCount-cp.Collected)
+		}
+		if n <= 0 {
+			cp.Done = true
+			return cp, j.save(cp)
+		}
+
+		r := j.Fetch(ctx, cp.Cursor, n)
+		if len(r.Items) > 0 {
+			if err := j.OnPage(r.Items); err != nil {
+				return cp, fmt.Errorf("job %s: %w", j.Name, err)
+			}
+		}
+		if r.Pages > 0 {
+			cp.Cursor = r.NextCursor
+			cp.Collected += len(r.Items)
+			cp.Done = r.PartialErr == nil && r.NextCursor == ""
+			if err := j.save(cp); err != nil {
+				return cp, err
+			}
+			slog.Debug("job checkpoint", slog.String("job", j.Name),
+				slog.Int("collected", cp.Collected), slog.Bool("done", cp.Done))
+		}
+		if r.PartialErr != nil {
+			return cp, fmt.Errorf("job %s: %w", j.Name, r.PartialErr)
+		}
+	}
+	return cp, nil
+}
+
+// save stamps and stores cp.
+func (j *Job[T]) save(cp JobCheckpoint) error {
+	cp.UpdatedAt = time.Now()
+	if err := j.Store.SaveCheckpoint(j.Name, cp); err != nil {
+		return fmt.Errorf("job %s: save checkpoint: %w", j.Name, err)
+	}
+	return nil
+}
+
+// JobStore returns the store jobs built by the client keep their
+// checkpoints in: files in SessionDir.
+func (c *Client) JobStore() JobStore {
+	return FileJobStore{Dir: sessionDir(c.cfg.SessionDir)}
+}
+
+// FollowersJob returns a resumable Job over the followers of userID,
+// checkpointed in c.JobStore as "followers:<userID>".
+func (c *Client) FollowersJob(userID string, onPage func([]*TwitterUser) error) *Job[*TwitterUser] {
+	return &Job[*TwitterUser]{
+		Name:   "followers:" + userID,
+		Store:  c.JobStore(),
+		OnPage: onPage,
+		Fetch: func(ctx context.Context, cursor string, maxCount int) PagedResult[*TwitterUser] {
+			return c.GetFollowersPaged(ctx, userID, cursor, maxCount)
+		},
+	}
+}
+
+// FollowingJob returns a resumable Job over the accounts userID follows,
+// checkpointed in c.JobStore as "following:<userID>".
+func (c *Client) FollowingJob(userID string, onPage func([]*TwitterUser) error) *Job[*TwitterUser] {
+	return &Job[*TwitterUser]{
+		Name:   "following:" + userID,
+		Store:  c.JobStore(),
+		OnPage: onPage,
+		Fetch: func(ctx context.Context, cursor string, maxCount int) PagedResult[*TwitterUser] {
+			return c.GetFollowingPaged(ctx, userID, cursor, maxCount)
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowersJob_ResumesFromCheckpoint(t *testing.T) {
+	tr := &pagedTransport{
+		pages: map[string]string{
+			"":   followersPage("c2", "1", "2"),
+			"c2": followersPage("c3", "3"),
+			"c3": followersPage("", "4"),
+		},
+		fail: map[string]bool{"c2": true},
+	}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+		Retry:                &RetryPolicy{MaxAttempts: 1},
+	})
+	require.NoError(t, err)
+
+	var got []string
+	onPage := func(users []*TwitterUser) error {
+		for _, u := range users {
+			got = append(got, u.ID)
+		}
+		return nil
+	}
+	cp, err := c.FollowersJob("42", onPage).Run(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, JobCheckpoint{Cursor: "c2", Collected: 2}, JobCheckpoint{Cursor: cp.Cursor, Collected: cp.Collected})
+	assert.False(t, cp.Done)
+
+	// A fresh job, as after a restart, picks up the saved cursor.
+	delete(tr.fail, "c2")
+	cp, err = c.FollowersJob("42", onPage).Run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cp.Done)
+	assert.Equal(t, 4, cp.Collected)
+	assert.Equal(t, []string{"1", "2", "3", "4"}, got)
+
+	cp, err = c.FollowersJob("42", func([]*TwitterUser) error {
+		t.Fatal("a finished job fetches nothing")
+		return nil
+	}).Run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cp.Done)
+}
+
+func TestJob_OnPageErrorKeepsCheckpoint(t *testing.T) {
+	store := FileJobStore{Dir: t.TempDir()}
+	calls := 0
+	job := &Job[int]{
+		Name:     "ints",
+		Store:    store,
+		PageSize: 2,
+		MaxCount: 3,
+		Fetch: func(_ context.Context, cursor string, maxCount int) PagedResult[int] {
+			calls++
+			if cursor == "" {
+				return PagedResult[int]{Items: []int{1, 2}, NextCursor: "p2", Pages: 1}
+			}
+			return PagedResult[int]{Items: []int{3, 4}[:maxCount], NextCursor: "p3", Pages: 1}
+		},
+	}
+	fail := true
+	job.OnPage = func(items []int) error {
+		if items[0] == 3 && fail {
+			return errors.New("disk full")
+		}
+		return nil
+	}
+
+	_, err := job.Run(context.Background())
+	require.ErrorContains(t, err, "disk full")
+	cp, err := store.LoadCheckpoint("ints")
+	require.NoError(t, err)
+	assert.Equal(t, "p2", cp.Cursor, "the failed page is fetched again")
+	assert.Equal(t, 2, cp.Collected)
+
+	fail = false
+	cp, err = job.Run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cp.Done, "MaxCount reached")
+	assert.Equal(t, 3, cp.Collected)
+	assert.Equal(t, 3, calls)
+}
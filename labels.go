@@ -0,0 +1,33 @@
+package twitter
+
+import "context"
+
+type labelsKeyType struct{}
+
+// WithAccountLabels returns a context whose requests only use pool accounts
+// carrying every label in sel (see Account.Labels), e.g. scraping accounts
+// behind EU proxies:
+//
+//	ctx = twitter.WithAccountLabels(ctx, map[string]string{"region": "eu", "purpose": "scrape"})
+//
+// When no matching account is available the request fails as if the pool
+// were exhausted, or falls back to guest tokens where the endpoint allows.
+func WithAccountLabels(ctx context.Context, sel map[string]string) context.Context {
+	return context.WithValue(ctx, labelsKeyType{}, sel)
+}
+
+// labelsFrom returns the selector set by WithAccountLabels, or nil.
+func labelsFrom(ctx context.Context) map[string]string {
+	sel, _ := ctx.Value(labelsKeyType{}).(map[string]string)
+	return sel
+}
+
+// hasLabels reports whether the account carries every label in sel.
+func (a *Account) hasLabels(sel map[string]string) bool {
+	for k, v := range sel {
+		if a.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package twitter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAccountLabels(t *testing.T) {
+	tr := &headerTransport{}
+	c, err := NewClient(ClientConfig{
+		Accounts: []*Account{
+			{Username: "eu1", AuthToken: "eu1", CT0: "c", Labels: map[string]string{"region": "eu", "purpose": "scrape"}},
+			{Username: "us1", AuthToken: "us1", CT0: "c", Labels: map[string]string{"region": "us", "purpose": "scrape"}},
+			{Username: "eu2", AuthToken: "eu2", CT0: "c", Labels: map[string]string{"region": "eu", "purpose": "post"}},
+		},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	op := Endpoint{ID: "Q", Name: "Other"}
+
+	eu := WithAccountLabels(context.Background(), map[string]string{"region": "eu"})
+	for i := range 4 {
+		_, err := c.GraphQL(eu, op, map[string]any{"i": i}, nil)
+		require.NoError(t, err)
+	}
+	for _, h := range tr.headers {
+		assert.True(t, strings.HasPrefix(h["cookie"], "auth_token=eu"), h["cookie"])
+	}
+
+	tr.headers = nil
+	euScrape := WithAccountLabels(context.Background(), map[string]string{"region": "eu", "purpose": "scrape"})
+	_, err = c.GraphQL(euScrape, op, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(tr.headers[0]["cookie"], "auth_token=eu1;"))
+
+	_, err = c.GraphQL(WithAccountLabels(context.Background(), map[string]string{"region": "ap"}), op, nil, nil)
+	assert.Error(t, err)
+	for _, s := range c.Stats().Accounts {
+		assert.Equal(t, s.Username[:2], s.Labels["region"], "stats carry labels")
+	}
+}
@@ -0,0 +1,87 @@
+package twitter
+
+import (
+	"context"
+	"maps"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/ratelimit"
+	"golang.org/x/time/rate"
+)
+
+// rateWindow is the length of Twitter's rate-limit windows.
+const rateWindow = 15 * time.Minute
+
+// DefaultEndpointLimits are the per-account limits Twitter enforces on the web
+// GraphQL operations, in requests per 15-minute window. They are the default
+// for ClientConfig.EndpointLimits; operations not listed use
+// ClientConfig.RateLimit. X Pro (TweetDeck) variants get the same limits in
+// separate buckets.
+var DefaultEndpointLimits = map[string]ratelimit.Config{
+	"UserByScreenName":         {RequestsPerWindow: 95, WindowDuration: rateWindow},
+	"UserByRestId":             {RequestsPerWindow: 500, WindowDuration: rateWindow},
+	"UsersByRestIds":           {RequestsPerWindow: 500, WindowDuration: rateWindow},
+	"UserTweets":               {RequestsPerWindow: 50, WindowDuration: rateWindow},
+	"SearchTimeline":           {RequestsPerWindow: 50, WindowDuration: rateWindow},
+	"TweetDetail":              {RequestsPerWindow: 150, WindowDuration: rateWindow},
+	"Followers":                {RequestsPerWindow: 50, WindowDuration: rateWindow},
+	"Following":                {RequestsPerWindow: 500, WindowDuration: rateWindow},
+	"Retweeters":               {RequestsPerWindow: 500, WindowDuration: rateWindow},
+	"GenericTimelineById":      {RequestsPerWindow: 500, WindowDuration: rateWindow},
+	"ListLatestTweetsTimeline": {RequestsPerWindow: 500, WindowDuration: rateWindow},
+	"FriendshipsShow":          {RequestsPerWindow: 180, WindowDuration: rateWindow},
+}
+
+// mergeEndpointLimits returns DefaultEndpointLimits overlaid with overrides.
+// Entries with a zero RequestsPerWindow are dropped, so an override can
+// return an operation to the RateLimit fallback.
+func mergeEndpointLimits(overrides map[string]ratelimit.Config) map[string]ratelimit.Config {
+	merged := maps.Clone(DefaultEndpointLimits)
+	maps.Copy(merged, overrides)
+	maps.DeleteFunc(merged, func(_ string, lc ratelimit.Config) bool {
+		return lc.RequestsPerWindow <= 0
+	})
+	return merged
+}
+
+// endpointLimit returns the per-operation limit for endpoint, if any.
+func (cfg *ClientConfig) endpointLimit(endpoint string) (ratelimit.Config, bool) {
+	if lc, ok := cfg.EndpointLimits[endpoint]; ok {
+		return lc, true
+	}
+	lc, ok := cfg.EndpointLimits[strings.TrimPrefix(endpoint, tweetdeckPrefix)]
+	return lc, ok
+}
+
+// rateLimitFor returns the limit that applies to endpoint: its per-operation
+// limit, or RateLimit.
+func (cfg *ClientConfig) rateLimitFor(endpoint string) ratelimit.Config {
+	if lc, ok := cfg.endpointLimit(endpoint); ok {
+		return lc
+	}
+	return cfg.RateLimit
+}
+
+// newGlobalLimiter builds the client-wide throughput limiter, or nil when
+// GlobalRateLimit is unset.
+func newGlobalLimiter(cfg *ClientConfig) *rate.Limiter {
+	if cfg.GlobalRateLimit <= 0 {
+		return nil
+	}
+	burst := cfg.GlobalBurst
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(cfg.GlobalRateLimit)))
+	}
+	return rate.NewLimiter(rate.Limit(cfg.GlobalRateLimit), burst)
+}
+
+// waitGlobal blocks until the global throughput limiter admits one upstream
+// request, or ctx is done.
+func (c *Client) waitGlobal(ctx context.Context) error {
+	if c.globalLimiter == nil {
+		return nil
+	}
+	return c.globalLimiter.Wait(ctx)
+}
@@ -0,0 +1,75 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/ratelimit"
+)
+
+func TestEndpointLimits_Defaults(t *testing.T) {
+	cfg := ClientConfig{
+		EndpointLimits: map[string]ratelimit.Config{
+			"Followers":   {RequestsPerWindow: 10, WindowDuration: time.Minute},
+			"TweetDetail": {}, // back to RateLimit
+		},
+	}
+	cfg.defaults()
+
+	if got := cfg.rateLimitFor("Followers"); got.RequestsPerWindow != 10 {
+		t.Fatalf("override ignored: %+v", got)
+	}
+	if got := cfg.rateLimitFor("UserByScreenName"); got.RequestsPerWindow != 95 {
+		t.Fatalf("built-in limit missing: %+v", got)
+	}
+	if got := cfg.rateLimitFor("TweetDeck/UserTweets"); got.RequestsPerWindow != 50 {
+		t.Fatalf("X Pro variant should use the web limit: %+v", got)
+	}
+	if got := cfg.rateLimitFor("TweetDetail"); got != cfg.RateLimit {
+		t.Fatalf("zero override should fall back to RateLimit: %+v", got)
+	}
+	if DefaultEndpointLimits["Followers"].RequestsPerWindow != 50 {
+		t.Fatal("overrides must not modify DefaultEndpointLimits")
+	}
+}
+
+func TestSetupLimiters(t *testing.T) {
+	cfg := ClientConfig{}
+	cfg.defaults()
+	acc := &Account{Username: "a"}
+	acc.setupLimiters(&cfg)
+
+	for _, name := range []string{"UserTweets", "Followers", "TweetDeck/UserTweets"} {
+		if _, ok := acc.endpointLimiters[name]; !ok {
+			t.Fatalf("%s should have its own limiter", name)
+		}
+	}
+	if _, ok := acc.endpointLimiters["CreateTweet"]; ok {
+		t.Fatal("endpoint without a limit should use the fallback limiter")
+	}
+}
+
+func TestGlobalLimiter(t *testing.T) {
+	if newGlobalLimiter(&ClientConfig{}) != nil {
+		t.Fatal("no limiter expected without GlobalRateLimit")
+	}
+	if err := (&Client{}).waitGlobal(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	lim := newGlobalLimiter(&ClientConfig{GlobalRateLimit: 2.5})
+	if lim.Burst() != 3 {
+		t.Fatalf("burst = %d, want 3", lim.Burst())
+	}
+
+	c := &Client{globalLimiter: newGlobalLimiter(&ClientConfig{GlobalRateLimit: 1})}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := c.waitGlobal(ctx); err != nil {
+		t.Fatalf("first request should pass: %v", err)
+	}
+	if err := c.waitGlobal(ctx); err == nil {
+		t.Fatal("second request within a second should be held back")
+	}
+}
@@ -0,0 +1,127 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TwitterList is a list owned by a pool account.
+type TwitterList struct {
+	ID          string
+	Name        string
+	Description string
+	Private     bool
+	MemberCount int64
+}
+
+// CreateList creates a list owned by acc. Its ID can then feed a ColumnList
+// in FetchColumns.
+func (c *Client) CreateList(ctx context.Context, acc *Account, name, description string, private bool) (*TwitterList, error) {
+	if name == "" || len([]rune(name)) > 25 {
+		return nil, &ValidationError{Field: "name", Value: name, Reason: "must be 1 to 25 characters"}
+	}
+	body, err := c.mutate(ctx, acc, "CreateList", map[string]any{
+		"name":        name,
+		"description": description,
+		"isPrivate":   private,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseListMutation("CreateList", body)
+}
+
+// DeleteList deletes a list owned by acc.
+func (c *Client) DeleteList(ctx context.Context, acc *Account, listID string) error {
+	if err := validateListID(listID); err != nil {
+		return err
+	}
+	body, err := c.mutate(ctx, acc, "DeleteList", map[string]any{"listId": listID})
+	if err != nil {
+		return err
+	}
+	return graphqlError("DeleteList", body)
+}
+
+// AddListMember adds userID to a list owned by acc.
+func (c *Client) AddListMember(ctx context.Context, acc *Account, listID, userID string) error {
+	return c.listMember(ctx, acc, "ListAddMember", listID, userID)
+}
+
+// RemoveListMember removes userID from a list owned by acc.
+func (c *Client) RemoveListMember(ctx context.Context, acc *Account, listID, userID string) error {
+	return c.listMember(ctx, acc, "ListRemoveMember", listID, userID)
+}
+
+// listMember runs a list membership mutation.
+func (c *Client) listMember(ctx context.Context, acc *Account, operation, listID, userID string) error {
+	if err := validateListID(listID); err != nil {
+		return err
+	}
+	if err := validateUserID(userID); err != nil {
+		return err
+	}
+	body, err := c.mutate(ctx, acc, operation, map[string]any{"listId": listID, "userId": userID})
+	if err != nil {
+		return err
+	}
+	_, err = parseListMutation(operation, body)
+	return err
+}
+
+// validateListID checks that s looks like a list ID: numeric, like user IDs.
+func validateListID(s string) error {
+	if !IsValidUserID(s) {
+		return &ValidationError{Field: "list_id", Value: s, Reason: "must be a non-zero numeric ID"}
+	}
+	return nil
+}
+
+// graphqlError returns the first error of a GraphQL response, if any.
+func graphqlError(operation string, body []byte) error {
+	var raw struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return fmt.Errorf("unmarshal %s: %w", operation, err)
+	}
+	if len(raw.Errors) > 0 {
+		return fmt.Errorf("%s API error: %s", operation, raw.Errors[0].Message)
+	}
+	return nil
+}
+
+// parseListMutation parses the list a list mutation returns.
+func parseListMutation(operation string, body []byte) (*TwitterList, error) {
+	if err := graphqlError(operation, body); err != nil {
+		return nil, err
+	}
+	var raw struct {
+		Data struct {
+			List struct {
+				IDStr       string `json:"id_str"`
+				Name        string `json:"name"`
+				Description string `json:"description"`
+				Mode        string `json:"mode"`
+				MemberCount int64  `json:"member_count"`
+			} `json:"list"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", operation, err)
+	}
+	l := raw.Data.List
+	if l.IDStr == "" {
+		return nil, fmt.Errorf("%s returned no list: %s", operation, truncateBytes(body, 300))
+	}
+	return &TwitterList{
+		ID:          l.IDStr,
+		Name:        l.Name,
+		Description: l.Description,
+		Private:     l.Mode == "Private",
+		MemberCount: l.MemberCount,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListMutation(t *testing.T) {
+	l, err := parseListMutation("CreateList", []byte(`{"data":{"list":{"id_str":"1500","name":"watch",
+		"description":"accounts to watch","mode":"Private","member_count":0}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, &TwitterList{ID: "1500", Name: "watch", Description: "accounts to watch", Private: true}, l)
+
+	_, err = parseListMutation("ListAddMember", []byte(`{"errors":[{"message":"You aren't allowed to add members to this list."}]}`))
+	assert.ErrorContains(t, err, "ListAddMember API error")
+
+	_, err = parseListMutation("ListAddMember", []byte(`{"data":{}}`))
+	assert.ErrorContains(t, err, "returned no list")
+}
+
+func TestListMutations_Validation(t *testing.T) {
+	c := &Client{}
+	acc := &Account{Username: "a"}
+	ctx := context.Background()
+	var verr *ValidationError
+
+	_, err := c.CreateList(ctx, acc, "", "", false)
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "name", verr.Field)
+
+	require.ErrorAs(t, c.DeleteList(ctx, acc, "list"), &verr)
+	assert.Equal(t, "list_id", verr.Field)
+
+	require.ErrorAs(t, c.AddListMember(ctx, acc, "1500", "@jack"), &verr)
+	assert.Equal(t, "user_id", verr.Field)
+}
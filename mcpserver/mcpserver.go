@@ -0,0 +1,175 @@
+// Package mcpserver exposes a go-twitter client to LLM agents as Model
+// Context Protocol tools, so an agent can look up users, tweets and
+// searches itself:
+//
+//	srv := mcpserver.New(client, mcpserver.Options{})
+//	err := srv.Run(ctx, &mcp.StdioTransport{})
+//
+// All tools are read-only. Lists take a count and a cursor, and return
+// next_cursor to page on. cmd/twitter-mcp runs it over stdio.
+package mcpserver
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+const (
+	defaultCount = 20
+	maxCount     = 100
+)
+
+// Options configures New.
+type Options struct {
+	// Name and Version identify the server to clients. Default:
+	// "go-twitter", "v1".
+	Name, Version string
+}
+
+// UserInput selects a user by handle.
+type UserInput struct {
+	Handle string `json:"handle" jsonschema:"Twitter/X handle, with or without @"`
+}
+
+// TweetInput selects a tweet.
+type TweetInput struct {
+	TweetID string `json:"tweet_id" jsonschema:"numeric tweet ID"`
+}
+
+// ListInput selects a page of a user's tweets, followers or following.
+type ListInput struct {
+	UserID string `json:"user_id" jsonschema:"numeric user ID, as returned by get_user"`
+	Count  int    `json:"count,omitempty" jsonschema:"items to return, default 20, at most 100"`
+	Cursor string `json:"cursor,omitempty" jsonschema:"next_cursor of the previous page"`
+}
+
+// SearchInput is a tweet search.
+type SearchInput struct {
+	Query  string `json:"query" jsonschema:"search query; X operators such as from: and since: work"`
+	Lang   string `json:"lang,omitempty" jsonschema:"only tweets in this language (BCP 47, e.g. en)"`
+	Count  int    `json:"count,omitempty" jsonschema:"tweets to return, default 20, at most 100"`
+	Cursor string `json:"cursor,omitempty" jsonschema:"next_cursor of the previous page"`
+}
+
+// TweetList is the result of the tweet list tools.
+type TweetList struct {
+	Tweets     []*twitter.Tweet `json:"tweets"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// UserList is the result of the follower list tools.
+type UserList struct {
+	Users      []*twitter.TwitterUser `json:"users"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+// New returns an MCP server with tools backed by api:
+//
+//	get_user         profile by handle
+//	get_user_tweets  a user's recent tweets
+//	get_followers    a user's followers
+//	get_following    the accounts a user follows
+//	get_tweet        one tweet
+//	get_thread       the conversation thread around a tweet
+//	search_tweets    latest tweets matching a query
+func New(api twitter.TwitterAPI, opts Options) *mcp.Server {
+	if opts.Name == "" {
+		opts.Name = "go-twitter"
+	}
+	if opts.Version == "" {
+		opts.Version = "v1"
+	}
+	s := mcp.NewServer(&mcp.Implementation{Name: opts.Name, Version: opts.Version}, nil)
+	t := tools{api: api}
+
+	mcp.AddTool(s, readOnly("get_user", "Get a Twitter/X user profile by handle."), t.getUser)
+	mcp.AddTool(s, readOnly("get_user_tweets", "Get a user's most recent tweets, newest first."), t.getUserTweets)
+	mcp.AddTool(s, readOnly("get_followers", "Get the followers of a user."), t.users(api.GetFollowersPaged))
+	mcp.AddTool(s, readOnly("get_following", "Get the accounts a user follows."), t.users(api.GetFollowingPaged))
+	mcp.AddTool(s, readOnly("get_tweet", "Get a tweet by ID."), t.getTweet)
+	mcp.AddTool(s, readOnly("get_thread", "Get the conversation thread a tweet belongs to, in reading order."), t.getThread)
+	mcp.AddTool(s, readOnly("search_tweets", "Search the latest tweets matching a query."), t.searchTweets)
+	return s
+}
+
+// readOnly describes a tool that reads from X without side effects.
+func readOnly(name, description string) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        name,
+		Description: description,
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+}
+
+// tools holds the tool handlers. Errors are returned to the agent as tool
+// errors, so it can read them and retry or give up. Results are typed any
+// so that the tools declare no output schema: one derived from Tweet would
+// reject its nil slices and pointers.
+type tools struct {
+	api twitter.TwitterAPI
+}
+
+func (t tools) getUser(ctx context.Context, _ *mcp.CallToolRequest, in UserInput) (*mcp.CallToolResult, any, error) {
+	u, err := t.api.GetUserByScreenName(ctx, in.Handle)
+	return nil, u, err
+}
+
+func (t tools) getUserTweets(ctx context.Context, _ *mcp.CallToolRequest, in ListInput) (*mcp.CallToolResult, any, error) {
+	return tweetList(t.api.GetUserTweetsPaged(ctx, in.UserID, in.Cursor, count(in.Count)))
+}
+
+func (t tools) users(fetch func(ctx context.Context, userID, cursor string, maxCount int) twitter.PagedResult[*twitter.TwitterUser]) mcp.ToolHandlerFor[ListInput, any] {
+	return func(ctx context.Context, _ *mcp.CallToolRequest, in ListInput) (*mcp.CallToolResult, any, error) {
+		r := fetch(ctx, in.UserID, in.Cursor, count(in.Count))
+		if r.PartialErr != nil && r.Pages == 0 {
+			return nil, nil, r.PartialErr
+		}
+		return nil, &UserList{Users: nonNil(r.Items), NextCursor: r.NextCursor}, nil
+	}
+}
+
+func (t tools) getTweet(ctx context.Context, _ *mcp.CallToolRequest, in TweetInput) (*mcp.CallToolResult, any, error) {
+	tw, err := t.api.GetTweetByID(ctx, in.TweetID)
+	return nil, tw, err
+}
+
+func (t tools) getThread(ctx context.Context, _ *mcp.CallToolRequest, in TweetInput) (*mcp.CallToolResult, any, error) {
+	tweets, err := t.api.GetThread(ctx, in.TweetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &TweetList{Tweets: nonNil(tweets)}, nil
+}
+
+func (t tools) searchTweets(ctx context.Context, _ *mcp.CallToolRequest, in SearchInput) (*mcp.CallToolResult, any, error) {
+	q := twitter.SearchQuery{Text: in.Query, Lang: in.Lang}.String()
+	return tweetList(t.api.SearchTimelinePaged(ctx, q, in.Cursor, count(in.Count)))
+}
+
+// tweetList converts a page of tweets to a tool result. A page cut short
+// by an error is still returned; its cursor resumes from where it stopped.
+func tweetList(r twitter.PagedResult[*twitter.Tweet]) (*mcp.CallToolResult, any, error) {
+	if r.PartialErr != nil && r.Pages == 0 {
+		return nil, nil, r.PartialErr
+	}
+	return nil, &TweetList{Tweets: nonNil(r.Items), NextCursor: r.NextCursor}, nil
+}
+
+// count applies the default and cap to a requested item count.
+func count(n int) int {
+	if n <= 0 {
+		return defaultCount
+	}
+	return min(n, maxCount)
+}
+
+// nonNil returns s, or an empty slice for nil, so lists encode as [].
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+// fakeAPI serves canned data; methods not overridden panic through the
+// nil embedded interface.
+type fakeAPI struct {
+	twitter.TwitterAPI
+	lastQuery string
+	lastCount int
+}
+
+func (f *fakeAPI) GetUserByScreenName(_ context.Context, handle string) (*twitter.TwitterUser, error) {
+	if handle == "gone" {
+		return nil, twitter.ErrUserUnavailable
+	}
+	return &twitter.TwitterUser{ID: "42", Handle: handle}, nil
+}
+
+func (f *fakeAPI) SearchTimelinePaged(_ context.Context, query, _ string, maxCount int, _ ...twitter.TweetPageOption) twitter.PagedResult[*twitter.Tweet] {
+	f.lastQuery, f.lastCount = query, maxCount
+	return twitter.PagedResult[*twitter.Tweet]{
+		Items:      []*twitter.Tweet{{ID: "1", Text: "hello"}},
+		NextCursor: "c2",
+		Pages:      1,
+	}
+}
+
+func (f *fakeAPI) GetFollowersPaged(context.Context, string, string, int) twitter.PagedResult[*twitter.TwitterUser] {
+	return twitter.PagedResult[*twitter.TwitterUser]{PartialErr: fmt.Errorf("followers: %w", twitter.ErrPoolExhausted)}
+}
+
+// connect returns a client session talking to New(api) in memory.
+func connect(t *testing.T, api twitter.TwitterAPI) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	st, ct := mcp.NewInMemoryTransports()
+	ss, err := New(api, Options{}).Connect(ctx, st, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { ss.Close() })
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v0"}, nil).Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func call(t *testing.T, cs *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	require.NoError(t, err)
+	return res
+}
+
+func TestNew_ListsReadOnlyTools(t *testing.T) {
+	cs := connect(t, &fakeAPI{})
+
+	res, err := cs.ListTools(context.Background(), nil)
+	require.NoError(t, err)
+	var names []string
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+		assert.True(t, tool.Annotations.ReadOnlyHint, tool.Name)
+	}
+	assert.ElementsMatch(t, []string{"get_user", "get_user_tweets", "get_followers", "get_following",
+		"get_tweet", "get_thread", "search_tweets"}, names)
+}
+
+func TestGetUser(t *testing.T) {
+	cs := connect(t, &fakeAPI{})
+
+	res := call(t, cs, "get_user", map[string]any{"handle": "jack"})
+	require.False(t, res.IsError)
+	var u twitter.TwitterUser
+	require.NoError(t, remarshal(res.StructuredContent, &u))
+	assert.Equal(t, "42", u.ID)
+
+	res = call(t, cs, "get_user", map[string]any{"handle": "gone"})
+	assert.True(t, res.IsError)
+	require.NotEmpty(t, res.Content)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "user unavailable")
+}
+
+func TestSearchTweets(t *testing.T) {
+	api := &fakeAPI{}
+	cs := connect(t, api)
+
+	res := call(t, cs, "search_tweets", map[string]any{"query": "golang", "lang": "en", "count": 1000})
+	require.False(t, res.IsError)
+	var list TweetList
+	require.NoError(t, remarshal(res.StructuredContent, &list))
+	require.Len(t, list.Tweets, 1)
+	assert.Equal(t, "hello", list.Tweets[0].Text)
+	assert.Equal(t, "c2", list.NextCursor)
+	assert.Equal(t, "golang lang:en", api.lastQuery)
+	assert.Equal(t, maxCount, api.lastCount, "count is capped")
+}
+
+func TestListError(t *testing.T) {
+	cs := connect(t, &fakeAPI{})
+
+	res := call(t, cs, "get_followers", map[string]any{"user_id": "42"})
+	assert.True(t, res.IsError)
+}
+
+func remarshal(from, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
@@ -0,0 +1,92 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// AccountInfo is what an account's own credentials say about it.
+type AccountInfo struct {
+	User      *TwitterUser // the account's profile; User.ID is its user ID
+	Language  string       // interface language, e.g. "en"
+	Protected bool         // tweets are protected
+
+	// Renamed reports that the screen name no longer matches the pool
+	// account's Username, e.g. after the tokens were swapped or the account
+	// was renamed behind the operator's back.
+	Renamed bool
+}
+
+// Me returns the profile and settings of the account acc's tokens belong to,
+// via account/verify_credentials and account/settings. It uses acc only, with
+// no retries or rotation.
+func (c *Client) Me(ctx context.Context, acc *Account) (*AccountInfo, error) {
+	body, err := c.accountGET(ctx, acc, "VerifyCredentials", verifyCredentialsURL+"?include_entities=false&skip_status=true")
+	if err != nil {
+		return nil, err
+	}
+	u, err := c.shapeUser(parseRESTUser(body))
+	if err != nil {
+		return nil, fmt.Errorf("VerifyCredentials: %w", err)
+	}
+
+	body, err = c.accountGET(ctx, acc, "AccountSettings", accountSettingsURL)
+	if err != nil {
+		return nil, err
+	}
+	var settings struct {
+		ScreenName string `json:"screen_name"`
+		Protected  bool   `json:"protected"`
+		Language   string `json:"language"`
+	}
+	if err := json.Unmarshal(body, &settings); err != nil {
+		return nil, fmt.Errorf("AccountSettings: unmarshal: %w", err)
+	}
+
+	info := &AccountInfo{
+		User:      u,
+		Language:  settings.Language,
+		Protected: settings.Protected,
+		Renamed:   !strings.EqualFold(u.Handle, acc.Username),
+	}
+	if info.Renamed {
+		slog.Warn("account tokens belong to a different screen name",
+			slog.String("user", acc.Username), slog.String("screen_name", u.Handle))
+	}
+	return info, nil
+}
+
+// accountGET fetches urlStr once with acc's credentials: no rotation,
+// retries or guest fallback, for calls about acc itself.
+func (c *Client) accountGET(ctx context.Context, acc *Account, endpoint, urlStr string) ([]byte, error) {
+	body, respHdrs, status, err := c.accountRequest(ctx, acc, urlStr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: request failed: %w", endpoint, err)
+	}
+	switch status {
+	case 200:
+		c.recordAPICall(endpoint, status, true, false)
+		return body, nil
+	case 429:
+		c.recordAPICall(endpoint, status, false, true)
+		acc.MarkEndpointRateLimited(endpoint, parseRateLimitReset(respHdrs["x-rate-limit-reset"]))
+		return nil, fmt.Errorf("%s: 429 rate limited", endpoint)
+	default:
+		c.recordAPICall(endpoint, status, false, false)
+		return nil, fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200))
+	}
+}
+
+// accountRequest sends a single GET to urlStr with acc's credentials.
+func (c *Client) accountRequest(ctx context.Context, acc *Account, urlStr string) ([]byte, map[string]string, int, error) {
+	if err := c.waitGlobal(ctx); err != nil {
+		return nil, nil, 0, err
+	}
+	bc := c.clientForAccount(acc)
+	requestInfoFrom(ctx).served(SourceAccount, acc.Username)
+	authTok, ct0, ua := acc.Credentials()
+	return c.doRequest(acc.authContext(ctx), bc, "GET", urlStr, acc.apiHeaders(authTok, ct0, ua))
+}
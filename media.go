@@ -0,0 +1,136 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// mediaChunkSize is the byte range fetched per DownloadMedia request, so a
+// large video is never held in memory whole.
+const mediaChunkSize = 8 << 20
+
+// DownloadMedia writes the asset at mediaURL (a pbs.twimg.com image or a
+// video.twimg.com video, e.g. from Media.BestVariant) to w. It is fetched
+// through a pool account's proxy with that account's browser fingerprint, in
+// ranged chunks; a chunk that fails is retried on another account, up to the
+// "DownloadMedia" RetryPolicy. Media hosts need no credentials, so none are
+// sent.
+func (c *Client) DownloadMedia(ctx context.Context, mediaURL string, w io.Writer) error {
+	u, err := url.Parse(mediaURL)
+	if err != nil {
+		return fmt.Errorf("DownloadMedia: %w", err)
+	}
+	if u.Scheme != "https" || !strings.HasSuffix(u.Host, ".twimg.com") {
+		return fmt.Errorf("DownloadMedia: %s is not a twimg.com URL", mediaURL)
+	}
+
+	var written int64
+	total := int64(-1) // unknown until the first ranged response
+	for total < 0 || written < total {
+		body, headers, status, err := c.fetchMediaRange(ctx, mediaURL, written)
+		if err != nil {
+			return fmt.Errorf("DownloadMedia: %w", err)
+		}
+		switch status {
+		case 200: // no range support: the whole asset
+			if written > 0 {
+				return fmt.Errorf("DownloadMedia: server ignored range at byte %d", written)
+			}
+			_, err = w.Write(body)
+			return err
+		case 206:
+		case 416: // asked past the end of an asset of unknown size
+			return nil
+		default:
+			return fmt.Errorf("DownloadMedia: status %d", status)
+		}
+		if _, err := w.Write(body); err != nil {
+			return err
+		}
+		written += int64(len(body))
+		total = contentRangeTotal(headers["content-range"])
+		if total < 0 && len(body) < mediaChunkSize {
+			return nil
+		}
+		if len(body) == 0 {
+			return fmt.Errorf("DownloadMedia: empty range at byte %d", written)
+		}
+	}
+	return nil
+}
+
+// fetchMediaRange requests the chunk of mediaURL starting at offset. Proxy
+// failures and 5xx/429 responses are retried on another account.
+func (c *Client) fetchMediaRange(ctx context.Context, mediaURL string, offset int64) ([]byte, map[string]string, int, error) {
+	policy := c.retryPolicy("DownloadMedia")
+	var tried []*Account
+	var lastErr error
+	for attempt := range policy.MaxAttempts {
+		if attempt > 0 {
+			if err := policy.wait(ctx, attempt); err != nil {
+				return nil, nil, 0, err
+			}
+		}
+		acc := c.mediaAccount(tried)
+		bc, ua := c.client, ""
+		if acc != nil {
+			tried = append(tried, acc)
+			bc, ua = c.clientForAccount(acc), acc.UserAgent
+		}
+		headers := mediaHeaders(ua)
+		c.cfg.Domain.setOrigin(headers)
+		headers["range"] = fmt.Sprintf("bytes=%d-%d", offset, offset+mediaChunkSize-1)
+
+		body, respHeaders, status, err := c.execute(ctx, bc, "GET", mediaURL, headers, nil)
+		switch {
+		case isContextError(err):
+			return nil, nil, 0, err
+		case err != nil:
+			if acc != nil && isProxyError(err) {
+				c.markProxyDown(acc)
+			}
+			lastErr = err
+		case status == 429 || status >= 500:
+			lastErr = fmt.Errorf("status %d", status)
+		default:
+			return body, respHeaders, status, nil
+		}
+		slog.Debug("media download attempt failed", slog.Int("attempt", attempt+1), slog.Any("error", lastErr))
+	}
+	return nil, nil, 0, lastErr
+}
+
+// mediaAccount picks an account whose proxy is not in backoff, preferring
+// ones not yet tried. Returns nil when the pool is empty, so the shared
+// client is used.
+func (c *Client) mediaAccount(tried []*Account) *Account {
+	if c.pool == nil {
+		return nil
+	}
+	candidates := c.eligibleAccounts("")
+	fresh := slices.DeleteFunc(slices.Clone(candidates), func(a *Account) bool { return slices.Contains(tried, a) })
+	if len(fresh) > 0 {
+		candidates = fresh
+	}
+	return pickAccount(StrategyRandom, candidates)
+}
+
+// contentRangeTotal returns the complete length from a Content-Range header
+// ("bytes 0-99/1234"), or -1 if it is absent or unknown.
+func contentRangeTotal(h string) int64 {
+	_, total, ok := strings.Cut(h, "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(total), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mediaTransport serves an asset for range requests, failing the first
+// fails attempts with a 503.
+type mediaTransport struct {
+	asset  []byte
+	fails  int
+	ranges []string
+}
+
+func (m *mediaTransport) Do(_ context.Context, _, _ string, headers map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	m.ranges = append(m.ranges, headers["range"])
+	if m.fails > 0 {
+		m.fails--
+		return nil, nil, 503, nil
+	}
+	return m.asset, map[string]string{"content-range": "bytes 0-9/10"}, 206, nil
+}
+
+func TestDownloadMedia(t *testing.T) {
+	tr := &mediaTransport{asset: []byte("0123456789"), fails: 1}
+	c := &Client{
+		cfg:  ClientConfig{Transport: tr, Retry: &RetryPolicy{MaxAttempts: 2, Backoff: stealth.BackoffConfig{InitialWait: time.Millisecond, MaxWait: time.Millisecond, Multiplier: 1}}},
+		pool: pool.New([]*Account{}, pool.Config{}),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, c.DownloadMedia(context.Background(), "https://video.twimg.com/ext_tw_video/1/vid/720x1280/a.mp4", &buf))
+	assert.Equal(t, "0123456789", buf.String())
+	assert.Equal(t, []string{"bytes=0-8388607", "bytes=0-8388607"}, tr.ranges)
+}
+
+func TestDownloadMedia_RejectsOtherHosts(t *testing.T) {
+	c := &Client{cfg: ClientConfig{Transport: &mediaTransport{}}}
+	err := c.DownloadMedia(context.Background(), "https://example.com/a.jpg", io.Discard)
+	assert.ErrorContains(t, err, "not a twimg.com URL")
+}
+
+func TestContentRangeTotal(t *testing.T) {
+	assert.Equal(t, int64(1234), contentRangeTotal("bytes 0-99/1234"))
+	assert.Equal(t, int64(-1), contentRangeTotal("bytes 0-99/*"))
+	assert.Equal(t, int64(-1), contentRangeTotal(""))
+}
+
+func TestParseMedia(t *testing.T) {
+	var r tweetResult
+	require.NoError(t, json.Unmarshal([]byte(`{"rest_id":"1","legacy":{"extended_entities":{"media":[
+		{"type":"photo","media_url_https":"https://pbs.twimg.com/media/a.jpg"},
+		{"type":"video","media_url_https":"https://pbs.twimg.com/thumb.jpg","video_info":{"duration_millis":12500,"variants":[
+			{"content_type":"application/x-mpegURL","url":"https://video.twimg.com/pl.m3u8"},
+			{"bitrate":832000,"content_type":"video/mp4","url":"https://video.twimg.com/480.mp4"},
+			{"bitrate":2176000,"content_type":"video/mp4","url":"https://video.twimg.com/vid/avc1/720x1280/a.mp4"}]}}]}}}`), &r))
+	tw, err := parseTweetResult(r, "")
+	require.NoError(t, err)
+	require.Len(t, tw.Media, 2)
+
+	assert.Equal(t, "photo", tw.Media[0].Type)
+	_, ok := tw.Media[0].BestVariant()
+	assert.False(t, ok)
+
+	best, ok := tw.Media[1].BestVariant()
+	require.True(t, ok)
+	assert.Equal(t, "https://video.twimg.com/vid/avc1/720x1280/a.mp4", best.URL)
+	assert.Equal(t, [2]int{720, 1280}, [2]int{best.Width, best.Height})
+	assert.Equal(t, 12500*time.Millisecond, tw.Media[1].Duration)
+	assert.Equal(t, 2176000, best.Bitrate)
+}
@@ -0,0 +1,243 @@
+package twitter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics accumulates client-wide counters and renders them, together with
+// per-account health gauges, in the Prometheus text exposition format.
+// It has no dependency on the Prometheus client library; scrape it through
+// Client.MetricsHandler or write it out with Client.WriteMetrics.
+type Metrics struct {
+	mu             sync.Mutex
+	requests       map[requestKey]uint64
+	rateLimitHits  map[string]uint64
+	captchaSolves  map[string]uint64 // result → count
+	relogins       map[string]uint64 // result → count
+	guestFallbacks map[guestKey]uint64
+}
+
+type requestKey struct {
+	endpoint string
+	status   int
+	outcome  string
+}
+
+type guestKey struct {
+	endpoint string
+	result   string
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{
+		requests:       make(map[requestKey]uint64),
+		rateLimitHits:  make(map[string]uint64),
+		captchaSolves:  make(map[string]uint64),
+		relogins:       make(map[string]uint64),
+		guestFallbacks: make(map[guestKey]uint64),
+	}
+}
+
+// observeRequest counts one API call outcome; status is its HTTP status, 0
+// for calls that had none (mirror lookups). Like the other observe* methods
+// it is a no-op on a nil *Metrics.
+func (m *Metrics) observeRequest(endpoint string, status int, success, rateLimited bool) {
+	if m == nil {
+		return
+	}
+	outcome := "error"
+	switch {
+	case success:
+		outcome = "success"
+	case rateLimited:
+		outcome = "rate_limited"
+	}
+	m.mu.Lock()
+	m.requests[requestKey{endpoint, status, outcome}]++
+	if rateLimited {
+		m.rateLimitHits[endpoint]++
+	}
+	m.mu.Unlock()
+}
+
+func (m *Metrics) observeCaptcha(err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.captchaSolves[resultLabel(err)]++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) observeRelogin(err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.relogins[resultLabel(err)]++
+	m.mu.Unlock()
+}
+
+// observeGuestFallback counts a guest-path request once its outcome is known.
+func (m *Metrics) observeGuestFallback(endpoint string, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.guestFallbacks[guestKey{endpoint, resultLabel(err)}]++
+	m.mu.Unlock()
+}
+
+func resultLabel(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// write renders the counters in Prometheus text format.
+func (m *Metrics) write(w io.Writer) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeHeader(w, "twitter_requests_total", "counter", "API requests by endpoint, HTTP status (0 if none) and outcome (success, error, rate_limited).")
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		if keys[i].status != keys[j].status {
+			return keys[i].status < keys[j].status
+		}
+		return keys[i].outcome < keys[j].outcome
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "twitter_requests_total{endpoint=\"%s\",status=\"%d\",outcome=\"%s\"} %d\n",
+			escapeLabel(k.endpoint), k.status, k.outcome, m.requests[k])
+	}
+
+	writeCounterMap(w, "twitter_rate_limit_hits_total", "HTTP 429 responses by endpoint.", "endpoint", m.rateLimitHits)
+	writeCounterMap(w, "twitter_captcha_solves_total", "CAPTCHA solve attempts during login by result.", "result", m.captchaSolves)
+	writeCounterMap(w, "twitter_relogins_total", "Automatic re-login attempts by result.", "result", m.relogins)
+
+	writeHeader(w, "twitter_guest_fallbacks_total", "counter", "Requests sent through the guest-token fallback path by endpoint and result.")
+	guests := make([]guestKey, 0, len(m.guestFallbacks))
+	for k := range m.guestFallbacks {
+		guests = append(guests, k)
+	}
+	sort.Slice(guests, func(i, j int) bool {
+		if guests[i].endpoint != guests[j].endpoint {
+			return guests[i].endpoint < guests[j].endpoint
+		}
+		return guests[i].result < guests[j].result
+	})
+	for _, k := range guests {
+		fmt.Fprintf(w, "twitter_guest_fallbacks_total{endpoint=\"%s\",result=\"%s\"} %d\n", escapeLabel(k.endpoint), k.result, m.guestFallbacks[k])
+	}
+}
+
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeCounterMap(w io.Writer, name, help, label string, values map[string]uint64) {
+	writeHeader(w, name, "counter", help)
+	for _, k := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, escapeLabel(k), values[k])
+	}
+}
+
+// labelEscaper escapes label values as the Prometheus text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Metrics returns the client's metrics registry.
+func (c *Client) Metrics() *Metrics {
+	return c.metrics
+}
+
+// WriteMetrics writes all client counters and per-account health gauges to w
+// in the Prometheus text exposition format.
+func (c *Client) WriteMetrics(w io.Writer) {
+	c.metrics.write(w)
+
+	stats := c.Stats()
+	gauges := []struct {
+		name, help string
+		value      func(AccountStats) float64
+	}{
+		{"twitter_account_active", "1 if the account is active in the pool.", func(s AccountStats) float64 { return boolFloat(s.Active) }},
+		{"twitter_account_requests", "Requests recorded by the account health tracker.", func(s AccountStats) float64 { return float64(s.Total) }},
+		{"twitter_account_failures", "Failures recorded by the account health tracker.", func(s AccountStats) float64 { return float64(s.Failed) }},
+		{"twitter_account_consecutive_failures", "Current consecutive failure streak.", func(s AccountStats) float64 { return float64(s.ConsecFails) }},
+		{"twitter_account_rate_limited_endpoints", "Endpoints currently rate-limited for the account.", func(s AccountStats) float64 { return float64(len(s.RateLimited)) }},
+		{"twitter_account_proxy_consecutive_failures", "Consecutive proxy connection failures.", func(s AccountStats) float64 { return float64(s.ProxyConsecFails) }},
+	}
+	for _, g := range gauges {
+		writeHeader(w, g.name, "gauge", g.help)
+		for _, s := range stats.Accounts {
+			fmt.Fprintf(w, "%s{account=\"%s\"} %g\n", g.name, escapeLabel(s.Username), g.value(s))
+		}
+	}
+	writeHeader(w, "twitter_guest_token_available", "gauge", "1 if a cached guest token is usable.")
+	fmt.Fprintf(w, "twitter_guest_token_available %g\n", boolFloat(stats.GuestTokenAvailable))
+
+	xs := stats.XTID
+	writeHeader(w, "twitter_xtid_generated_total", "counter", "x-client-transaction-id values generated.")
+	fmt.Fprintf(w, "twitter_xtid_generated_total %d\n", xs.Generated)
+	writeHeader(w, "twitter_xtid_generate_failures_total", "counter", "Requests sent without x-client-transaction-id because no keys were available.")
+	fmt.Fprintf(w, "twitter_xtid_generate_failures_total %d\n", xs.GenerateFailures)
+	writeHeader(w, "twitter_xtid_refresh_failures_total", "counter", "Failed fetches of the x-client-transaction-id keys.")
+	fmt.Fprintf(w, "twitter_xtid_refresh_failures_total %d\n", xs.RefreshFailures)
+	writeHeader(w, "twitter_xtid_last_refresh_timestamp_seconds", "gauge", "Unix time the x-client-transaction-id keys in use were fetched; 0 if none.")
+	fmt.Fprintf(w, "twitter_xtid_last_refresh_timestamp_seconds %d\n", unixOrZero(xs.LastRefresh))
+}
+
+// MetricsHandler returns an http.Handler serving WriteMetrics, suitable for
+// mounting at /metrics.
+func (c *Client) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var b strings.Builder
+		c.WriteMetrics(&b)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = io.WriteString(w, b.String())
+	})
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package twitter
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	acc := &Account{Username: "alice", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	c := &Client{
+		pool:    pool.New([]*Account{acc}, pool.Config{}),
+		metrics: newMetrics(),
+	}
+
+	c.recordAPICall("UserTweets", 200, true, false)
+	c.recordAPICall("UserTweets", 200, true, false)
+	c.recordAPICall("UserTweets", 429, false, true)
+	c.recordAPICall("Followers", 503, false, false)
+	c.recordAPICall("Followers", 200, false, false)
+	c.metrics.observeRelogin(nil)
+	c.metrics.observeRelogin(errors.New("boom"))
+	c.metrics.observeCaptcha(nil)
+	c.metrics.observeGuestFallback("UserByRestId", nil)
+	c.metrics.observeGuestFallback("UserByRestId", errors.New("guest HTTP 403"))
+
+	var b strings.Builder
+	c.WriteMetrics(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE twitter_requests_total counter",
+		`twitter_requests_total{endpoint="Followers",status="200",outcome="error"} 1`,
+		`twitter_requests_total{endpoint="Followers",status="503",outcome="error"} 1`,
+		`twitter_requests_total{endpoint="UserTweets",status="429",outcome="rate_limited"} 1`,
+		`twitter_requests_total{endpoint="UserTweets",status="200",outcome="success"} 2`,
+		`twitter_rate_limit_hits_total{endpoint="UserTweets"} 1`,
+		`twitter_relogins_total{result="failure"} 1`,
+		`twitter_relogins_total{result="success"} 1`,
+		`twitter_captcha_solves_total{result="success"} 1`,
+		`twitter_guest_fallbacks_total{endpoint="UserByRestId",result="failure"} 1`,
+		`twitter_guest_fallbacks_total{endpoint="UserByRestId",result="success"} 1`,
+		`twitter_account_active{account="alice"} 1`,
+		"twitter_guest_token_available 0",
+	} {
+		assert.Contains(t, out, want)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	c := &Client{pool: pool.New([]*Account{}, pool.Config{}), metrics: newMetrics()}
+	c.recordAPICall("TweetDetail", 200, true, false)
+
+	rec := httptest.NewRecorder()
+	c.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	require.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `twitter_requests_total{endpoint="TweetDetail",status="200",outcome="success"} 1`)
+}
+
+func TestMetrics_NilSafe(t *testing.T) {
+	var m *Metrics
+	m.observeRequest("x", 200, true, false)
+	m.observeRelogin(nil)
+	m.observeCaptcha(nil)
+	m.observeGuestFallback("x", nil)
+	m.write(io.Discard)
+}
+
+func TestEscapeLabel(t *testing.T) {
+	assert.Equal(t, `a\\b \"c\" \n é`, escapeLabel("a\\b \"c\" \n é"))
+
+	m := newMetrics()
+	m.observeRequest("v2/tweets \"x\"", 200, true, false)
+	var b strings.Builder
+	m.write(&b)
+	assert.Contains(t, b.String(), `twitter_requests_total{endpoint="v2/tweets \"x\"",status="200",outcome="success"} 1`)
+}
@@ -0,0 +1,223 @@
+package twitter
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Mirror is a read-only fallback data source, such as a Nitter instance. It
+// serves GetUserByScreenName and GetUserTweets when neither the pool nor the
+// guest token can (see ClientConfig.Mirror). Mirrors see less than the API,
+// so missing fields are left zero.
+type Mirror interface {
+	UserByScreenName(ctx context.Context, handle string) (*TwitterUser, error)
+	UserTweets(ctx context.Context, userID string, count int) ([]*Tweet, error)
+}
+
+// mirrorFallback reports whether a call that failed with err should be
+// retried on the configured mirror.
+func (c *Client) mirrorFallback(ctx context.Context, err error) bool {
+	return c.cfg.Mirror != nil && ctx.Err() == nil &&
+		requestOptionsFrom(ctx).Account == "" && errors.Is(err, ErrPoolExhausted)
+}
+
+// mirrorUserByScreenName serves a profile lookup from the mirror.
+func (c *Client) mirrorUserByScreenName(ctx context.Context, handle string) (*TwitterUser, error) {
+	slog.Info("serving from mirror", slog.String("endpoint", "UserByScreenName"))
+	requestInfoFrom(ctx).served(SourceMirror, "")
+	u, err := c.cfg.Mirror.UserByScreenName(ctx, handle)
+	c.recordAPICall("mirror/UserByScreenName", 0, err == nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("UserByScreenName (mirror): %w", err)
+	}
+	return u, nil
+}
+
+// mirrorUserTweets serves a user timeline from the mirror.
+func (c *Client) mirrorUserTweets(ctx context.Context, userID string, count int) ([]*Tweet, error) {
+	slog.Info("serving from mirror", slog.String("endpoint", "UserTweets"))
+	requestInfoFrom(ctx).served(SourceMirror, "")
+	tweets, err := c.cfg.Mirror.UserTweets(ctx, userID, count)
+	c.recordAPICall("mirror/UserTweets", 0, err == nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("UserTweets (mirror): %w", err)
+	}
+	return c.enrichTweets(tweets, nil)
+}
+
+// NitterMirror reads profiles and timelines from a Nitter instance: profile
+// pages for users and RSS feeds for tweets. Nitter pages carry no user ID or
+// engagement counts, so TwitterUser.ID and tweet metrics are left zero.
+type NitterMirror struct {
+	// BaseURL is the instance root, e.g. "https://nitter.example.com".
+	BaseURL string
+
+	// HTTP overrides the HTTP client. Default: 30s timeout client.
+	HTTP *http.Client
+}
+
+var (
+	nitterFullnameRe = regexp.MustCompile(`class="profile-card-fullname"[^>]*title="([^"]*)"`)
+	nitterUsernameRe = regexp.MustCompile(`class="profile-card-username"[^>]*title="@?([^"]*)"`)
+	nitterBioRe      = regexp.MustCompile(`(?s)<div class="profile-bio"><p[^>]*>(.*?)</p>`)
+	nitterStatRe     = regexp.MustCompile(`(?s)<li class="(posts|tweets|following|followers)">.*?<span class="profile-stat-num">([\d,.KMB]+)</span>`)
+	nitterJoinedRe   = regexp.MustCompile(`class="profile-joindate"><span title="([^"]+)"`)
+	nitterAvatarRe   = regexp.MustCompile(`class="profile-card-avatar"[^>]*href="([^"]+)"`)
+	nitterStatusRe   = regexp.MustCompile(`/status/(\d+)`)
+	htmlTagRe        = regexp.MustCompile(`<[^>]+>`)
+)
+
+// UserByScreenName scrapes the profile page of handle.
+func (n *NitterMirror) UserByScreenName(ctx context.Context, handle string) (*TwitterUser, error) {
+	page, _, err := n.get(ctx, "/"+url.PathEscape(handle))
+	if err != nil {
+		return nil, err
+	}
+	u, err := parseNitterProfile(string(page))
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(u.AvatarURL, "/") {
+		u.AvatarURL = strings.TrimRight(n.BaseURL, "/") + u.AvatarURL
+	}
+	return u, nil
+}
+
+// UserTweets reads the RSS feed of userID. Nitter redirects /i/user/<id> to
+// the profile, which yields the handle for the feed URL.
+func (n *NitterMirror) UserTweets(ctx context.Context, userID string, count int) ([]*Tweet, error) {
+	_, final, err := n.get(ctx, "/i/user/"+url.PathEscape(userID))
+	if err != nil {
+		return nil, err
+	}
+	handle := path.Base(final.Path)
+	if handle == "" || handle == "/" || handle == userID {
+		return nil, fmt.Errorf("nitter: cannot resolve user %s", userID)
+	}
+	feed, _, err := n.get(ctx, "/"+url.PathEscape(handle)+"/rss")
+	if err != nil {
+		return nil, err
+	}
+	tweets, err := parseNitterRSS(feed, userID, handle)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && len(tweets) > count {
+		tweets = tweets[:count]
+	}
+	return tweets, nil
+}
+
+// get fetches p from the instance, returning the body and the final URL
+// after redirects.
+func (n *NitterMirror) get(ctx context.Context, p string) ([]byte, *url.URL, error) {
+	hc := n.HTTP
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(n.BaseURL, "/")+p, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("nitter: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("nitter %s: HTTP %d", p, resp.StatusCode)
+	}
+	body, err := readLimited(resp.Body, defaultMaxResponseBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("nitter: read body: %w", err)
+	}
+	return body, resp.Request.URL, nil
+}
+
+// parseNitterProfile extracts a user from a Nitter profile page.
+func parseNitterProfile(page string) (*TwitterUser, error) {
+	m := nitterUsernameRe.FindStringSubmatch(page)
+	if m == nil {
+		return nil, fmt.Errorf("nitter: profile not found")
+	}
+	u := &TwitterUser{Handle: html.UnescapeString(m[1])}
+	if m := nitterFullnameRe.FindStringSubmatch(page); m != nil {
+		u.DisplayName = html.UnescapeString(m[1])
+	}
+	if m := nitterBioRe.FindStringSubmatch(page); m != nil {
+		u.Bio = strings.TrimSpace(html.UnescapeString(htmlTagRe.ReplaceAllString(m[1], "")))
+	}
+	for _, m := range nitterStatRe.FindAllStringSubmatch(page, -1) {
+		n := parseCompactCount(m[2])
+		switch m[1] {
+		case "posts", "tweets":
+			u.TweetCount = n
+		case "following":
+			u.Following = n
+		case "followers":
+			u.Followers = n
+		}
+	}
+	if m := nitterJoinedRe.FindStringSubmatch(page); m != nil {
+		if t, err := time.Parse("3:04 PM - 2 Jan 2006", m[1]); err == nil {
+			u.CreatedAt = t
+		}
+	}
+	if m := nitterAvatarRe.FindStringSubmatch(page); m != nil {
+		u.HasAvatar = !strings.Contains(m[1], "default_profile")
+		u.AvatarURL = html.UnescapeString(m[1])
+	}
+	u.IsVerified = strings.Contains(page, `class="verified-icon`)
+	u.HasBio = u.Bio != ""
+	return u, nil
+}
+
+type nitterFeed struct {
+	Items []struct {
+		Title   string `xml:"title"`
+		Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		PubDate string `xml:"pubDate"`
+		Link    string `xml:"link"`
+	} `xml:"channel>item"`
+}
+
+// parseNitterRSS converts a Nitter RSS feed into tweets authored by handle;
+// retweets of other accounts are skipped.
+func parseNitterRSS(feed []byte, userID, handle string) ([]*Tweet, error) {
+	var f nitterFeed
+	if err := xml.Unmarshal(feed, &f); err != nil {
+		return nil, fmt.Errorf("nitter: parse rss: %w", err)
+	}
+	tweets := make([]*Tweet, 0, len(f.Items))
+	for _, it := range f.Items {
+		if !strings.EqualFold(strings.TrimPrefix(it.Creator, "@"), handle) {
+			continue
+		}
+		m := nitterStatusRe.FindStringSubmatch(it.Link)
+		if m == nil {
+			continue
+		}
+		created, _ := time.Parse(time.RFC1123, it.PubDate)
+		text := html.UnescapeString(it.Title)
+		tweets = append(tweets, &Tweet{
+			ID:            m[1],
+			AuthorID:      userID,
+			AuthorHandle:  handle,
+			Text:          text,
+			CreatedAt:     created,
+			TokenMentions: extractTokenMentions(text),
+		})
+	}
+	return tweets, nil
+}
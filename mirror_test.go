@@ -0,0 +1,101 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nitterProfilePage = `<div class="profile-card">
+<a class="profile-card-avatar" href="/pic/pbs.twimg.com%2Fprofile_images%2F1%2Fa.jpg"></a>
+<a class="profile-card-fullname" href="/jack" title="jack &amp; co">jack</a><span class="verified-icon"></span>
+<a class="profile-card-username" href="/jack" title="@jack">@jack</a>
+<div class="profile-bio"><p dir="auto">no <a href="/x">state</a> is the best state</p></div>
+<div class="profile-joindate"><span title="8:50 PM - 21 Mar 2006">Joined March 2006</span></div>
+<ul class="profile-statlist">
+<li class="posts"><span class="profile-stat-header">Posts</span>
+<span class="profile-stat-num">29,497</span></li>
+<li class="following"><span class="profile-stat-header">Following</span>
+<span class="profile-stat-num">4</span></li>
+<li class="followers"><span class="profile-stat-header">Followers</span>
+<span class="profile-stat-num">6,312,004</span></li>
+</ul></div>`
+
+const nitterFeedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss xmlns:dc="http://purl.org/dc/elements/1.1/" version="2.0"><channel>
+<item><title>just setting up my twttr $btc</title><dc:creator>@jack</dc:creator>
+<pubDate>Tue, 21 Mar 2006 20:50:14 GMT</pubDate><link>%[1]s/jack/status/20#m</link></item>
+<item><title>RT by @jack: other</title><dc:creator>@biz</dc:creator>
+<pubDate>Tue, 21 Mar 2006 21:00:00 GMT</pubDate><link>%[1]s/biz/status/21#m</link></item>
+</channel></rss>`
+
+func newNitterServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jack":
+			fmt.Fprint(w, nitterProfilePage)
+		case "/i/user/12":
+			http.Redirect(w, r, "/jack", http.StatusFound)
+		case "/jack/rss":
+			fmt.Fprintf(w, nitterFeedXML, srv.URL)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNitterMirror(t *testing.T) {
+	srv := newNitterServer(t)
+	m := &NitterMirror{BaseURL: srv.URL}
+
+	u, err := m.UserByScreenName(context.Background(), "jack")
+	require.NoError(t, err)
+	assert.Equal(t, "jack", u.Handle)
+	assert.Equal(t, "jack & co", u.DisplayName)
+	assert.Equal(t, "no state is the best state", u.Bio)
+	assert.Equal(t, int64(29497), u.TweetCount)
+	assert.Equal(t, int64(4), u.Following)
+	assert.Equal(t, int64(6312004), u.Followers)
+	assert.Equal(t, time.Date(2006, 3, 21, 20, 50, 0, 0, time.UTC), u.CreatedAt)
+	assert.True(t, u.IsVerified)
+	assert.True(t, u.HasAvatar)
+	assert.Equal(t, srv.URL+"/pic/pbs.twimg.com%2Fprofile_images%2F1%2Fa.jpg", u.AvatarURL)
+
+	tweets, err := m.UserTweets(context.Background(), "12", 10)
+	require.NoError(t, err)
+	require.Len(t, tweets, 1, "retweets of other accounts are skipped")
+	assert.Equal(t, "20", tweets[0].ID)
+	assert.Equal(t, "12", tweets[0].AuthorID)
+	assert.Equal(t, []string{"BTC"}, tweets[0].TokenMentions)
+	assert.Equal(t, 2006, tweets[0].CreatedAt.Year())
+}
+
+func TestMirrorFallback(t *testing.T) {
+	srv := newNitterServer(t)
+	c := &Client{
+		pool: pool.New([]*Account{}, pool.Config{}),
+		cfg:  ClientConfig{Mirror: &NitterMirror{BaseURL: srv.URL}},
+	}
+	info := &RequestInfo{}
+	ctx := WithRequestInfo(context.Background(), info)
+
+	tweets, err := c.GetUserTweets(ctx, "12", 5)
+	require.NoError(t, err)
+	require.Len(t, tweets, 1)
+	assert.Equal(t, SourceMirror, info.Source())
+
+	u, err := c.GetUserByScreenName(ctx, "jack")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6312004), u.Followers)
+}
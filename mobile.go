@@ -0,0 +1,122 @@
+package twitter
+
+import (
+	"net/url"
+	"strings"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+)
+
+// ClientMode selects which official client an account's requests imitate.
+// Mobile modes send the app's bearer token, headers and User-Agent to the
+// API host, keeping cookie auth. The app's TLS fingerprint needs the
+// account's own client, which it gets with a Proxy; without one it shares
+// the default client.
+type ClientMode string
+
+const (
+	ModeWeb     ClientMode = ""        // x.com web app (default)
+	ModeAndroid ClientMode = "android" // Twitter for Android
+	ModeIOS     ClientMode = "ios"     // Twitter for iPhone
+)
+
+// mobileApp describes an official mobile client: its bearer token, the
+// headers identifying it, and the TLS fingerprint closest to its network
+// stack. Mobile-token traffic has its own rate limits, which keep some reads
+// working while the web limits are spent.
+type mobileApp struct {
+	bearer    string
+	client    string // x-twitter-client
+	version   string // x-twitter-client-version
+	userAgent string
+	profile   stealth.BrowserProfile
+}
+
+var mobileApps = map[ClientMode]mobileApp{
+	ModeAndroid: {
+		bearer:    "AAAAAAAAAAAAAAAAAAAAAFXzAwAAAAAAMHCxpeSDG1gLNLghVe8d74hl6k4%3DRUMF4xAQLsbeBhTSRrCiQpJtxoGWeyHrDb5te2jpGskWDFW82F",
+		client:    "TwitterAndroid",
+		version:   "10.21.0-release.0",
+		userAgent: "TwitterAndroid/10.21.0-release.0 (310210000-r-0) Pixel+8/14 (Google;Pixel+8;google;shiba;0;;1;2023)",
+		profile:   stealth.BrowserProfile{TLSProfile: stealth.ProfileChrome131, Browser: "chrome", OS: "android", Mobile: true},
+	},
+	ModeIOS: {
+		bearer:    "AAAAAAAAAAAAAAAAAAAAAAj4AQAAAAAAPraK64zCZ9CSzdLesbE7LB%2Bw4uE%3DVJQREvQNCZJNiz3rHO7lOXlkVOQkzzdsgu6wWgcazdMUaGoUGm",
+		client:    "Twitter-iPhone",
+		version:   "10.68",
+		userAgent: "Twitter-iPhone/10.68 iOS/17.5 (Apple;iPhone15,2;;;;;1;2022)",
+		profile:   stealth.BrowserProfile{TLSProfile: stealth.ProfileSafariIOS18, Browser: "safari", OS: "ios", Mobile: true},
+	},
+}
+
+// mobileProfile returns the browser profile for an account in mode: the
+// app's User-Agent with the matching TLS fingerprint. ok is false for web.
+func mobileProfile(mode ClientMode) (p stealth.BrowserProfile, ok bool) {
+	app, ok := mobileApps[mode]
+	if !ok {
+		return p, false
+	}
+	p = app.profile
+	p.UserAgent = app.userAgent
+	return p, true
+}
+
+// mobileHeaders returns the headers the mobile app sends with cookie auth.
+// deviceID identifies the install; userAgent overrides the app's default.
+func mobileHeaders(app mobileApp, authToken, ct0, userAgent, deviceID string) map[string]string {
+	if userAgent == "" {
+		userAgent = app.userAgent
+	}
+	h := map[string]string{
+		"authorization":             "Bearer " + app.bearer,
+		"x-csrf-token":              ct0,
+		"x-twitter-auth-type":       "OAuth2Session",
+		"x-twitter-active-user":     "yes",
+		"x-twitter-api-version":     "5",
+		"x-twitter-client":          app.client,
+		"x-twitter-client-version":  app.version,
+		"x-twitter-client-language": "en-US",
+		"content-type":              "application/json",
+		"cookie":                    "auth_token=" + authToken + "; ct0=" + ct0,
+		"user-agent":                userAgent,
+		"accept":                    "application/json",
+		"accept-language":           "en-US",
+		"accept-encoding":           "gzip, deflate",
+	}
+	if deviceID != "" {
+		h["x-twitter-client-deviceid"] = deviceID
+	}
+	return h
+}
+
+// isMobileRequest reports whether headers were built by mobileHeaders.
+func isMobileRequest(headers map[string]string) bool {
+	return headers["x-twitter-client"] != ""
+}
+
+// mobileRoutes maps web API path prefixes to the API-host paths the mobile
+// apps call. Operations keep their web query IDs.
+var mobileRoutes = []struct{ web, mobile string }{
+	{"/i/api/graphql/", "/graphql/"},
+	{"/i/api/1.1/", "/1.1/"},
+	{"/i/api/2/", "/2/"},
+}
+
+// mobileURL moves a web API request onto the API host the mobile apps use,
+// e.g. https://x.com/i/api/graphql/ID/Op → https://api.x.com/graphql/ID/Op.
+// Other URLs are returned unchanged.
+func mobileURL(urlStr string) string {
+	u, err := url.Parse(urlStr)
+	if err != nil || (u.Host != "x.com" && u.Host != "twitter.com") {
+		return urlStr
+	}
+	for _, r := range mobileRoutes {
+		if rest, ok := strings.CutPrefix(u.Path, r.web); ok {
+			u.Host = "api." + u.Host
+			u.Path = r.mobile + rest
+			u.RawPath = ""
+			return u.String()
+		}
+	}
+	return urlStr
+}
@@ -0,0 +1,57 @@
+package twitter
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMobileURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://x.com/i/api/graphql/QID/UserTweets?variables=%7B%7D": "https://api.x.com/graphql/QID/UserTweets?variables=%7B%7D",
+		"https://twitter.com/i/api/1.1/dm/inbox_initial_state.json":   "https://api.twitter.com/1.1/dm/inbox_initial_state.json",
+		"https://api.x.com/1.1/friendships/show.json":                 "https://api.x.com/1.1/friendships/show.json",
+		"https://pro.x.com/i/api/graphql/QID/SearchTimeline":          "https://pro.x.com/i/api/graphql/QID/SearchTimeline",
+	} {
+		assert.Equal(t, want, mobileURL(in), in)
+	}
+}
+
+// headerTransport records the URL and headers of each request.
+type headerTransport struct {
+	urls    []string
+	headers []map[string]string
+}
+
+func (h *headerTransport) Do(_ context.Context, _, rawURL string, headers map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	h.urls = append(h.urls, rawURL)
+	h.headers = append(h.headers, headers)
+	return []byte(`{}`), nil, 200, nil
+}
+
+func TestMobileRequest(t *testing.T) {
+	tr := &headerTransport{}
+	c := &Client{cfg: ClientConfig{Transport: tr}}
+	acc := &Account{Username: "alice", Mode: ModeAndroid}
+	resolveProfile(acc, nil)
+	require.True(t, acc.Profile.Mobile)
+
+	authTok, ct0, ua := acc.Credentials()
+	_, _, _, err := c.doRequest(context.Background(), nil, "GET", "https://x.com/i/api/graphql/QID/UserByScreenName", acc.apiHeaders(authTok, ct0, ua))
+	require.NoError(t, err)
+
+	require.Len(t, tr.urls, 1)
+	assert.Equal(t, "https://api.x.com/graphql/QID/UserByScreenName", tr.urls[0])
+	h := tr.headers[0]
+	assert.Equal(t, "Bearer "+mobileApps[ModeAndroid].bearer, h["authorization"])
+	assert.Equal(t, "TwitterAndroid", h["x-twitter-client"])
+	assert.True(t, strings.HasPrefix(h["user-agent"], "TwitterAndroid/"))
+	assert.NotEmpty(t, h["x-twitter-client-deviceid"])
+	for _, web := range []string{"x-client-transaction-id", "x-xp-forwarded-for", "origin", "sec-ch-ua"} {
+		assert.NotContains(t, h, web)
+	}
+}
@@ -0,0 +1,551 @@
+package twitter
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// MonitorEventType names the kind of a MonitorEvent.
+type MonitorEventType string
+
+const (
+	EventNewTweet      MonitorEventType = "new_tweet"      // a tweet not seen before
+	EventDeletedTweet  MonitorEventType = "deleted_tweet"  // a tracked user's tweet vanished from their timeline
+	EventProfileChange MonitorEventType = "profile_change" // a tracked user's profile changed (see Changes)
+)
+
+// MonitorEvent is one item of a Monitor's event stream.
+type MonitorEvent struct {
+	Type   MonitorEventType
+	Target string // key returned by TrackSearch or TrackUser
+	At     time.Time
+
+	// Tweet is the new tweet, or the last seen copy of a deleted one.
+	Tweet *Tweet
+
+	// User and Previous are the current and previous profile snapshots of a
+	// profile change, and Changes what changed between them.
+	User     *TwitterUser
+	Previous *TwitterUser
+	Changes  []ProfileChange
+}
+
+// MonitorConfig configures a Monitor. Zero values get defaults.
+type MonitorConfig struct {
+	// Interval is how often a quiet target is polled. Default: 5m.
+	Interval time.Duration
+
+	// HotInterval is how often a hot target is polled. A target turns hot
+	// when a poll finds new tweets and cools back to Interval, doubling its
+	// interval after each quiet poll. Default: 30s.
+	HotInterval time.Duration
+
+	// ProfileInterval is how often the profiles of tracked users are
+	// fetched (in UsersByRestIds batches) to detect changes. Default: Interval.
+	ProfileInterval time.Duration
+
+	// FollowerThresholds are follower counts whose crossing by a tracked
+	// user is reported as a FollowersCrossedThreshold change. Follower
+	// counts moving without crossing one emit nothing.
+	FollowerThresholds []int
+
+	// FollowerDelta and FollowingDelta report a tracked user's follower or
+	// following count moving by at least that much between two profile
+	// polls, as FollowersDelta and FollowingDelta changes. Off by default.
+	FollowerDelta, FollowingDelta DeltaThreshold
+
+	// Count is the number of tweets requested per poll. Default: 20.
+	Count int
+
+	// Budget is the fraction of the pool's rate-limit capacity per endpoint
+	// the monitor may spend, leaving the rest for other callers. Default: 0.5.
+	Budget float64
+
+	// Concurrency caps polls in flight. Default: 4.
+	Concurrency int
+
+	// Webhooks receive every event as a JSON POST, each in the background
+	// and in order. A webhook more than 256 events behind drops new ones;
+	// dropped new tweets are emitted again, to every consumer, on the next
+	// poll, and receivers drop the repeats by MonitorEventPayload.ID. When
+	// Run stops, queued events get 10s to be delivered.
+	Webhooks []*MonitorWebhook
+
+	// WebhooksOnly sends events to Webhooks only, so the Events stream
+	// stays empty and need not be read.
+	WebhooksOnly bool
+
+	// OnError receives poll failures and events a webhook did not take,
+	// which are also logged. Polling goes on.
+	OnError func(target string, err error)
+}
+
+func (cfg *MonitorConfig) defaults() {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	if cfg.HotInterval <= 0 {
+		cfg.HotInterval = 30 * time.Second
+	}
+	if cfg.HotInterval > cfg.Interval {
+		cfg.HotInterval = cfg.Interval
+	}
+	if cfg.ProfileInterval <= 0 {
+		cfg.ProfileInterval = cfg.Interval
+	}
+	if cfg.Count <= 0 {
+		cfg.Count = defaultPollCount
+	}
+	if cfg.Budget <= 0 || cfg.Budget > 1 {
+		cfg.Budget = 0.5
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 4
+	}
+}
+
+// monitorSource is the part of *Client a Monitor polls.
+type monitorSource interface {
+	SearchTimeline(ctx context.Context, query string, count int) ([]*Tweet, error)
+	GetUserTweets(ctx context.Context, userID string, count int) ([]*Tweet, error)
+	GetUsersByIDs(ctx context.Context, ids []string, concurrency int, opts ...BulkOption) []UserResult
+}
+
+// Monitor tracks many searches and users through one scheduler and emits a
+// unified stream of events. Polls are paced per endpoint to a share of the
+// pool's rate-limit capacity, and due hot targets go before quiet ones.
+// Targets can be added and removed while it runs. Seen tweets are persisted
+// the same way as PollSearch and PollUserTweets, keyed by target.
+type Monitor struct {
+	c        *Client
+	src      monitorSource
+	cfg      MonitorConfig
+	events   chan MonitorEvent
+	wake     chan struct{}
+	profiles *ProfileWatcher
+	hooks    []*webhookQueue
+
+	mu       sync.Mutex
+	targets  map[string]*monitorTarget
+	limiters map[string]*rate.Limiter
+}
+
+// monitorTarget is one tracked search or user.
+type monitorTarget struct {
+	key      string
+	op       string // SearchTimeline or UserTweets
+	arg      string // query or user ID
+	pinned   bool
+	interval time.Duration
+	next     time.Time
+	running  bool
+	seen     *seenSet
+
+	last map[string]*Tweet // user targets: previous page, for deletions
+}
+
+// hot reports whether t is polled faster than the quiet interval.
+func (t *monitorTarget) hot(cfg *MonitorConfig) bool {
+	return t.pinned || t.interval < cfg.Interval
+}
+
+// TrackOption configures a tracked target.
+type TrackOption func(*monitorTarget)
+
+// WithHot keeps a target at HotInterval regardless of activity.
+func WithHot() TrackOption {
+	return func(t *monitorTarget) { t.pinned = true }
+}
+
+// NewMonitor returns a Monitor polling through c. Call Run to start it.
+func (c *Client) NewMonitor(cfg MonitorConfig) *Monitor {
+	cfg.defaults()
+	m := &Monitor{
+		c:      c,
+		src:    c,
+		cfg:    cfg,
+		events: make(chan MonitorEvent, 256),
+		wake:   make(chan struct{}, 1),
+		profiles: &ProfileWatcher{
+			Thresholds:     slices.Clone(cfg.FollowerThresholds),
+			FollowerDelta:  cfg.FollowerDelta,
+			FollowingDelta: cfg.FollowingDelta,
+		},
+		targets:  make(map[string]*monitorTarget),
+		limiters: make(map[string]*rate.Limiter),
+	}
+	for _, h := range cfg.Webhooks {
+		m.hooks = append(m.hooks, &webhookQueue{hook: h, events: make(chan MonitorEvent, webhookQueueSize)})
+	}
+	return m
+}
+
+// Events returns the event stream. It is closed when Run returns.
+func (m *Monitor) Events() <-chan MonitorEvent {
+	return m.events
+}
+
+// TrackSearch starts tracking query (Latest tab) and returns its target key.
+func (m *Monitor) TrackSearch(query string, opts ...TrackOption) string {
+	return m.track("search:"+query, "SearchTimeline", query, opts)
+}
+
+// TrackUser starts tracking the tweets and profile of userID and returns its
+// target key.
+func (m *Monitor) TrackUser(userID string, opts ...TrackOption) string {
+	return m.track("user:"+userID, "UserTweets", userID, opts)
+}
+
+// Untrack stops tracking the target with key. A poll already in flight
+// still delivers its events.
+func (m *Monitor) Untrack(key string) {
+	m.mu.Lock()
+	t, ok := m.targets[key]
+	delete(m.targets, key)
+	m.mu.Unlock()
+	if ok && t.op == "UserTweets" {
+		m.profiles.Forget(t.arg)
+	}
+}
+
+// Targets returns the keys of the tracked targets, sorted.
+func (m *Monitor) Targets() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.targets))
+	for k := range m.targets {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (m *Monitor) track(key, op, arg string, opts []TrackOption) string {
+	t := &monitorTarget{key: key, op: op, arg: arg, interval: m.cfg.Interval}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.seen = newSeenSet(defaultSeenSize, sessionDirFile(m.c.cfg.SessionDir, seenFileName("monitor", key)))
+	if err := t.seen.load(); err != nil {
+		slog.Warn("load seen-set failed", slog.String("target", key), slog.Any("error", err))
+	}
+
+	m.mu.Lock()
+	if _, ok := m.targets[key]; !ok {
+		m.targets[key] = t
+	}
+	m.mu.Unlock()
+	m.notify()
+	return key
+}
+
+// notify wakes the scheduler to re-check due targets.
+func (m *Monitor) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run schedules polls until ctx is done, then waits for polls in flight and
+// queued webhook deliveries and closes the event stream. It returns
+// ctx.Err().
+func (m *Monitor) Run(ctx context.Context) error {
+	defer close(m.events)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.runProfiles(ctx)
+	}()
+	for _, q := range m.hooks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.runWebhook(ctx, q)
+		}()
+	}
+
+	sem := make(chan struct{}, m.cfg.Concurrency)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		t, wait := m.nextDue(time.Now())
+		if t == nil {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-m.wake:
+				timer.Stop()
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			m.release(t)
+			return ctx.Err()
+		}
+		if err := m.limiter(t.op).Wait(ctx); err != nil {
+			<-sem
+			m.release(t)
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			m.poll(ctx, t)
+		}()
+	}
+}
+
+// nextDue claims the most urgent due target: hot before quiet, then most
+// overdue. With none due, it returns how long until the next one is.
+func (m *Monitor) nextDue(now time.Time) (*monitorTarget, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var best *monitorTarget
+	wait := m.cfg.HotInterval
+	for _, t := range m.targets {
+		if t.running {
+			continue
+		}
+		if d := t.next.Sub(now); d > 0 {
+			wait = min(wait, d)
+			continue
+		}
+		if best == nil || m.before(t, best) {
+			best = t
+		}
+	}
+	if best != nil {
+		best.running = true
+	}
+	return best, wait
+}
+
+// before orders due targets by priority.
+func (m *Monitor) before(a, b *monitorTarget) bool {
+	if ah, bh := a.hot(&m.cfg), b.hot(&m.cfg); ah != bh {
+		return ah
+	}
+	return a.next.Before(b.next)
+}
+
+// release returns a claimed target to the schedule without polling it.
+func (m *Monitor) release(t *monitorTarget) {
+	m.mu.Lock()
+	t.running = false
+	m.mu.Unlock()
+}
+
+// limiter returns the pacing limiter for op, refreshed to the pool's current
+// capacity.
+func (m *Monitor) limiter(op string) *rate.Limiter {
+	limit := m.rateFor(op)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.limiters[op]
+	if !ok {
+		l = rate.NewLimiter(limit, m.cfg.Concurrency)
+		m.limiters[op] = l
+	} else if l.Limit() != limit {
+		l.SetLimit(limit)
+	}
+	return l
+}
+
+// rateFor returns Budget of the per-second capacity of the active pool
+// accounts (inside their ActiveHours) for op. With no active account it assumes one, which is what the
+// guest fallback or a recovering account provides.
+func (m *Monitor) rateFor(op string) rate.Limit {
+	lc := m.c.cfg.rateLimitFor(op)
+	if lc.RequestsPerWindow <= 0 || lc.WindowDuration <= 0 {
+		return rate.Inf
+	}
+	active := 0
+	if m.c.pool != nil {
+		now := time.Now()
+		for _, acc := range m.c.pool.Items() {
+			if acc.IsActive() && !acc.offHours(now) {
+				active++
+			}
+		}
+	}
+	perAccount := float64(lc.RequestsPerWindow) / lc.WindowDuration.Seconds()
+	return rate.Limit(float64(max(active, 1)) * perAccount * m.cfg.Budget)
+}
+
+// poll fetches one target, emits its events and reschedules it.
+func (m *Monitor) poll(ctx context.Context, t *monitorTarget) {
+	var tweets []*Tweet
+	var err error
+	if t.op == "UserTweets" {
+		tweets, err = m.src.GetUserTweets(ctx, t.arg, m.cfg.Count)
+	} else {
+		tweets, err = m.src.SearchTimeline(ctx, t.arg, m.cfg.Count)
+	}
+	if err != nil && ctx.Err() == nil {
+		m.fail(t.key, err)
+	}
+
+	now := time.Now()
+	fresh := t.seen.unseen(tweets)
+	var deleted []*Tweet
+	if t.op == "UserTweets" && err == nil {
+		deleted = deletedTweets(t.last, tweets)
+		t.last = make(map[string]*Tweet, len(tweets))
+		for _, tw := range tweets {
+			t.last[tw.ID] = tw
+		}
+	}
+
+	m.mu.Lock()
+	switch {
+	case t.pinned || len(fresh) > 0:
+		t.interval = m.cfg.HotInterval
+	default:
+		t.interval = min(t.interval*2, m.cfg.Interval)
+	}
+	t.next = now.Add(t.interval)
+	t.running = false
+	m.mu.Unlock()
+	m.notify()
+
+	delivered := false
+	for _, tw := range fresh {
+		if !m.emit(ctx, MonitorEvent{Type: EventNewTweet, Target: t.key, At: now, Tweet: tw}) {
+			break
+		}
+		t.seen.mark(tw.ID)
+		delivered = true
+	}
+	if delivered {
+		if err := t.seen.save(); err != nil {
+			slog.Warn("save seen-set failed", slog.String("target", t.key), slog.Any("error", err))
+		}
+	}
+	for _, tw := range deleted {
+		m.emit(ctx, MonitorEvent{Type: EventDeletedTweet, Target: t.key, At: now, Tweet: tw})
+	}
+}
+
+// deletedTweets returns the tweets of the previous page that are missing from
+// the current one although they are newer than its oldest tweet, and so
+// cannot have just scrolled off the end. Oldest first.
+func deletedTweets(prev map[string]*Tweet, cur []*Tweet) []*Tweet {
+	if len(prev) == 0 || len(cur) == 0 {
+		return nil
+	}
+	present := make(map[string]bool, len(cur))
+	oldest := cur[0].ID
+	for _, tw := range cur {
+		present[tw.ID] = true
+		if compareIDs(tw.ID, oldest) < 0 {
+			oldest = tw.ID
+		}
+	}
+	var gone []*Tweet
+	for id, tw := range prev {
+		if !present[id] && compareIDs(id, oldest) > 0 {
+			gone = append(gone, tw)
+		}
+	}
+	slices.SortFunc(gone, func(a, b *Tweet) int { return compareIDs(a.ID, b.ID) })
+	return gone
+}
+
+// runProfiles fetches the profiles of tracked users every ProfileInterval
+// and emits their changes as seen by a ProfileWatcher. The first snapshot
+// of a user is the baseline.
+func (m *Monitor) runProfiles(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.ProfileInterval)
+	defer ticker.Stop()
+	for {
+		m.pollProfiles(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Monitor) pollProfiles(ctx context.Context) {
+	m.mu.Lock()
+	var users []*monitorTarget
+	for _, t := range m.targets {
+		if t.op == "UserTweets" {
+			users = append(users, t)
+		}
+	}
+	m.mu.Unlock()
+
+	for chunk := range slices.Chunk(users, maxUsersByRestIDsBatch) {
+		if err := m.limiter("UsersByRestIds").Wait(ctx); err != nil {
+			return
+		}
+		ids := make([]string, len(chunk))
+		for i, t := range chunk {
+			ids[i] = t.arg
+		}
+		results := m.src.GetUsersByIDs(ctx, ids, 1)
+		now := time.Now()
+		for i, r := range results {
+			t := chunk[i]
+			if r.Err != nil {
+				if ctx.Err() == nil {
+					m.fail(t.key, r.Err)
+				}
+				continue
+			}
+			if changes := m.profiles.Observe(r.User); len(changes) > 0 {
+				m.emit(ctx, MonitorEvent{
+					Type: EventProfileChange, Target: t.key, At: now,
+					User: r.User, Previous: changes[0].Previous, Changes: changes,
+				})
+			}
+		}
+	}
+}
+
+// fail logs a poll failure and reports it to OnError.
+func (m *Monitor) fail(target string, err error) {
+	slog.Warn("monitor poll failed", slog.String("target", target), slog.Any("error", err))
+	if m.cfg.OnError != nil {
+		m.cfg.OnError(target, err)
+	}
+}
+
+// emit delivers ev on the event stream, unless ctx is done first, then
+// queues it for the webhooks. It reports whether every consumer took ev; a
+// new tweet that one missed is emitted again on the next poll, so consumers
+// may see it twice.
+func (m *Monitor) emit(ctx context.Context, ev MonitorEvent) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if !m.cfg.WebhooksOnly || len(m.hooks) == 0 {
+		select {
+		case m.events <- ev:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	ok := true
+	for _, q := range m.hooks {
+		ok = m.enqueue(q, ev) && ok
+	}
+	return ok
+}
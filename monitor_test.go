@@ -0,0 +1,144 @@
+package twitter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMonitorSource serves scripted pages: each call to a target returns the
+// next page, repeating the last one.
+type fakeMonitorSource struct {
+	mu       sync.Mutex
+	pages    map[string][][]*Tweet
+	profiles map[string][]*TwitterUser
+	calls    map[string]int
+}
+
+func (f *fakeMonitorSource) next(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	i := f.calls[key]
+	f.calls[key]++
+	return i
+}
+
+func (f *fakeMonitorSource) page(key string) []*Tweet {
+	pages := f.pages[key]
+	return pages[min(f.next(key), len(pages)-1)]
+}
+
+func (f *fakeMonitorSource) SearchTimeline(_ context.Context, query string, _ int) ([]*Tweet, error) {
+	return f.page("search:" + query), nil
+}
+
+func (f *fakeMonitorSource) GetUserTweets(_ context.Context, userID string, _ int) ([]*Tweet, error) {
+	return f.page("user:" + userID), nil
+}
+
+func (f *fakeMonitorSource) GetUsersByIDs(_ context.Context, ids []string, _ int, _ ...BulkOption) []UserResult {
+	results := make([]UserResult, len(ids))
+	for i, id := range ids {
+		snaps := f.profiles[id]
+		results[i] = UserResult{Input: id, User: snaps[min(f.next("profile:"+id), len(snaps)-1)]}
+	}
+	return results
+}
+
+func newTestMonitor(t *testing.T, src monitorSource) *Monitor {
+	t.Helper()
+	fast := ratelimit.Config{RequestsPerWindow: 1000, WindowDuration: time.Second}
+	c := &Client{cfg: ClientConfig{
+		SessionDir:     t.TempDir(),
+		EndpointLimits: map[string]ratelimit.Config{"SearchTimeline": fast, "UserTweets": fast, "UsersByRestIds": fast},
+	}}
+	m := c.NewMonitor(MonitorConfig{Interval: 20 * time.Millisecond, HotInterval: 5 * time.Millisecond})
+	m.src = src
+	return m
+}
+
+func TestMonitor_Events(t *testing.T) {
+	src := &fakeMonitorSource{
+		pages: map[string][][]*Tweet{
+			"search:$BTC": {tweetsWithIDs("1"), tweetsWithIDs("2", "1")},
+			"user:12":     {tweetsWithIDs("30", "20", "10"), tweetsWithIDs("30", "10")},
+		},
+		profiles: map[string][]*TwitterUser{
+			"12": {{ID: "12", Bio: "old"}, {ID: "12", Bio: "new"}},
+		},
+	}
+	m := newTestMonitor(t, src)
+	m.TrackSearch("$BTC", WithHot())
+	m.TrackUser("12")
+	assert.Equal(t, []string{"search:$BTC", "user:12"}, m.Targets())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	var newIDs []string
+	var deleted, profile *MonitorEvent
+	timeout := time.After(2 * time.Second)
+	for len(newIDs) < 5 || deleted == nil || profile == nil {
+		select {
+		case ev := <-m.Events():
+			switch ev.Type {
+			case EventNewTweet:
+				newIDs = append(newIDs, ev.Tweet.ID)
+			case EventDeletedTweet:
+				deleted = &ev
+			case EventProfileChange:
+				profile = &ev
+			}
+		case <-timeout:
+			t.Fatalf("timed out: new=%v deleted=%v profile=%v", newIDs, deleted, profile)
+		}
+	}
+	assert.ElementsMatch(t, []string{"1", "2", "10", "20", "30"}, newIDs)
+	assert.Equal(t, "20", deleted.Tweet.ID)
+	assert.Equal(t, "user:12", deleted.Target)
+	assert.Equal(t, "old", profile.Previous.Bio)
+	assert.Equal(t, "new", profile.User.Bio)
+	require.Len(t, profile.Changes, 1)
+	assert.Equal(t, BioChanged, profile.Changes[0].Kind)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	for range m.Events() {
+	}
+}
+
+func TestMonitor_PrioritizesHotTargets(t *testing.T) {
+	m := newTestMonitor(t, &fakeMonitorSource{})
+	m.TrackSearch("quiet")
+	m.TrackSearch("hot", WithHot())
+
+	first, _ := m.nextDue(time.Now())
+	require.NotNil(t, first)
+	assert.Equal(t, "search:hot", first.key)
+	second, _ := m.nextDue(time.Now())
+	require.NotNil(t, second)
+	assert.Equal(t, "search:quiet", second.key)
+	none, wait := m.nextDue(time.Now())
+	assert.Nil(t, none, "running targets are not handed out twice")
+	assert.Positive(t, wait)
+}
+
+func TestDeletedTweets(t *testing.T) {
+	prev := map[string]*Tweet{}
+	for _, tw := range tweetsWithIDs("5", "4", "3", "2") {
+		prev[tw.ID] = tw
+	}
+	gone := deletedTweets(prev, tweetsWithIDs("6", "5", "3"))
+	require.Len(t, gone, 1, "2 scrolled off the page, 4 was deleted")
+	assert.Equal(t, "4", gone[0].ID)
+	assert.Nil(t, deletedTweets(nil, tweetsWithIDs("1")))
+}
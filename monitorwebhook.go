@@ -0,0 +1,292 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ErrWebhookSignature is returned by VerifyMonitorWebhook for a request
+// that is unsigned, signed with another secret, or signed too long ago.
+var ErrWebhookSignature = errors.New("invalid webhook signature")
+
+// Headers of a MonitorWebhook POST.
+const (
+	WebhookIDHeader        = "X-Webhook-Id"        // event ID, the same on every retry
+	WebhookEventHeader     = "X-Webhook-Event"     // MonitorEventType
+	WebhookTimestampHeader = "X-Webhook-Timestamp" // Unix seconds the POST was signed at
+	WebhookSignatureHeader = "X-Webhook-Signature" // "sha256=" + hex HMAC, see MonitorWebhook.Secret
+)
+
+const (
+	defaultWebhookAttempts   = 5
+	defaultWebhookRetryDelay = time.Second
+	maxWebhookRetryDelay     = time.Minute
+	webhookQueueSize         = 256
+	webhookDrainTimeout      = 10 * time.Second
+)
+
+// MonitorWebhook posts a Monitor's events as JSON (MonitorEventPayload)
+// to URL. Add it to MonitorConfig.Webhooks.
+type MonitorWebhook struct {
+	URL string
+
+	// Secret signs each POST: WebhookSignatureHeader carries "sha256=" and
+	// the hex HMAC-SHA256, keyed by Secret, of the WebhookTimestampHeader
+	// value, a dot and the body. Receivers check it with
+	// VerifyMonitorWebhook. Empty: unsigned.
+	Secret string
+
+	// Types limits the events sent. Default: all.
+	Types []MonitorEventType
+
+	// MaxAttempts bounds the POSTs of one event. Network errors, 429s and
+	// 5xx responses are retried; other responses are final. Default: 5.
+	MaxAttempts int
+
+	// RetryDelay is the wait before the first retry, doubling for each
+	// next one up to a minute; a longer Retry-After wins. Default: 1s.
+	RetryDelay time.Duration
+
+	// HTTPClient sends the requests. Default: a client with a 10s timeout.
+	HTTPClient *http.Client
+}
+
+// MonitorEventPayload is the JSON form of a MonitorEvent: the body of a
+// MonitorWebhook POST, and of the messages the sink package publishes.
+type MonitorEventPayload struct {
+	// ID identifies the event; receivers drop redelivered events by it.
+	ID     string           `json:"id"`
+	Type   MonitorEventType `json:"type"`
+	Target string           `json:"target"`
+	At     time.Time        `json:"at"`
+
+	Tweet    *Tweet        `json:"tweet,omitempty"`
+	User     *TwitterUser  `json:"user,omitempty"`
+	Previous *TwitterUser  `json:"previous,omitempty"`
+	Changes  []ProfileDiff `json:"changes,omitempty"`
+}
+
+// ProfileDiff is a ProfileChange without its profile snapshots, which a
+// MonitorEventPayload carries once as User and Previous.
+type ProfileDiff struct {
+	Kind      ProfileChangeKind `json:"kind"`
+	Old       string            `json:"old,omitempty"`
+	New       string            `json:"new,omitempty"`
+	Threshold int               `json:"threshold,omitempty"`
+	Delta     int64             `json:"delta,omitempty"`
+}
+
+// ID identifies ev: the same for every delivery of the event, and for the
+// new-tweet events of the same tweet from the same target.
+func (ev MonitorEvent) ID() string {
+	if ev.Tweet != nil {
+		return fmt.Sprintf("%s:%s:%s", ev.Type, ev.Target, ev.Tweet.ID)
+	}
+	return fmt.Sprintf("%s:%s:%d", ev.Type, ev.Target, ev.At.UnixNano())
+}
+
+// Payload returns ev in its JSON form.
+func (ev MonitorEvent) Payload() MonitorEventPayload {
+	p := MonitorEventPayload{
+		ID: ev.ID(), Type: ev.Type, Target: ev.Target, At: ev.At,
+		Tweet: ev.Tweet, User: ev.User, Previous: ev.Previous,
+	}
+	for _, ch := range ev.Changes {
+		p.Changes = append(p.Changes, ProfileDiff{
+			Kind: ch.Kind, Old: ch.Old, New: ch.New, Threshold: ch.Threshold, Delta: ch.Delta,
+		})
+	}
+	return p
+}
+
+// Send posts ev, retrying as configured, and returns the last error.
+func (h *MonitorWebhook) Send(ctx context.Context, ev MonitorEvent) error {
+	p := ev.Payload()
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+	attempts := h.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultWebhookAttempts
+	}
+	delay := h.RetryDelay
+	if delay <= 0 {
+		delay = defaultWebhookRetryDelay
+	}
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := h.post(ctx, p, body)
+		if err == nil || retryAfter < 0 || attempt >= attempts {
+			return err
+		}
+		slog.Debug("monitor webhook failed, retrying", slog.String("url", h.URL),
+			slog.String("id", p.ID), slog.Int("attempt", attempt), slog.Any("error", err))
+		select {
+		case <-time.After(max(delay, retryAfter)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay = min(delay*2, maxWebhookRetryDelay)
+	}
+}
+
+// post sends body once. On failure it returns how long the server asked
+// to wait (0 if it did not say), or -1 if the POST must not be retried.
+func (h *MonitorWebhook) post(ctx context.Context, p MonitorEventPayload, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, p.ID)
+	req.Header.Set(WebhookEventHeader, string(p.Type))
+	if h.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, ts)
+		req.Header.Set(WebhookSignatureHeader, signWebhook(h.Secret, ts, body))
+	}
+	hc := h.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return 0, fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode/100 == 2:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return time.Duration(max(secs, 0)) * time.Second, fmt.Errorf("webhook returned %d", resp.StatusCode)
+	default:
+		return -1, fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+}
+
+// signWebhook returns the WebhookSignatureHeader value for body signed at
+// ts.
+func signWebhook(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyMonitorWebhook checks that a POST with header and body was signed
+// by a MonitorWebhook with secret, and, if maxAge is positive, signed at
+// most maxAge ago, which bounds replays of a captured request.
+func VerifyMonitorWebhook(secret string, header http.Header, body []byte, maxAge time.Duration) error {
+	ts := header.Get(WebhookTimestampHeader)
+	sig := header.Get(WebhookSignatureHeader)
+	if ts == "" || sig == "" {
+		return fmt.Errorf("%w: missing headers", ErrWebhookSignature)
+	}
+	if !hmac.Equal([]byte(sig), []byte(signWebhook(secret, ts, body))) {
+		return ErrWebhookSignature
+	}
+	if maxAge > 0 {
+		unix, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad timestamp %q", ErrWebhookSignature, ts)
+		}
+		if age := time.Since(time.Unix(unix, 0)); age > maxAge || age < -maxAge {
+			return fmt.Errorf("%w: signed %s ago", ErrWebhookSignature, age.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// webhookQueue feeds one webhook its events, in order, in the background.
+type webhookQueue struct {
+	hook   *MonitorWebhook
+	events chan MonitorEvent
+}
+
+// enqueue queues ev for the webhook unless it filters it out, and reports
+// whether the webhook took ev. An event the queue has no room for is
+// dropped and reported.
+func (m *Monitor) enqueue(q *webhookQueue, ev MonitorEvent) bool {
+	if len(q.hook.Types) > 0 && !slices.Contains(q.hook.Types, ev.Type) {
+		return true
+	}
+	select {
+	case q.events <- ev:
+		return true
+	default:
+		m.webhookFailed(q.hook, ev, errors.New("webhook queue full, event dropped"))
+		return false
+	}
+}
+
+// runWebhook delivers q's events until ctx is done, then spends up to
+// webhookDrainTimeout delivering the events still queued, starting with one
+// the stop interrupted.
+func (m *Monitor) runWebhook(ctx context.Context, q *webhookQueue) {
+	for {
+		select {
+		case ev := <-q.events:
+			err := q.hook.Send(ctx, ev)
+			switch {
+			case err == nil:
+			case ctx.Err() == nil:
+				m.webhookFailed(q.hook, ev, err)
+			default:
+				m.drainWebhook(context.WithoutCancel(ctx), q, ev)
+				return
+			}
+		case <-ctx.Done():
+			m.drainWebhook(context.WithoutCancel(ctx), q)
+			return
+		}
+	}
+}
+
+// drainWebhook delivers pending and then the events left in q when the
+// monitor stops, and reports those it could not deliver in time.
+func (m *Monitor) drainWebhook(ctx context.Context, q *webhookQueue, pending ...MonitorEvent) {
+	ctx, cancel := context.WithTimeout(ctx, webhookDrainTimeout)
+	defer cancel()
+	send := func(ev MonitorEvent) {
+		if err := q.hook.Send(ctx, ev); err != nil {
+			m.webhookFailed(q.hook, ev, err)
+		}
+	}
+	for _, ev := range pending {
+		send(ev)
+	}
+	for {
+		select {
+		case ev := <-q.events:
+			send(ev)
+		default:
+			return
+		}
+	}
+}
+
+// webhookFailed logs an event the webhook did not take and reports it to
+// OnError.
+func (m *Monitor) webhookFailed(h *MonitorWebhook, ev MonitorEvent, err error) {
+	slog.Warn("monitor webhook failed", slog.String("url", h.URL), slog.String("target", ev.Target),
+		slog.String("type", string(ev.Type)), slog.Any("error", err))
+	if m.cfg.OnError != nil {
+		m.cfg.OnError(ev.Target, fmt.Errorf("webhook %s: %w", h.URL, err))
+	}
+}
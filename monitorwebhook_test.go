@@ -0,0 +1,182 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records the POSTs it accepts and fails the first
+// failures of them with status.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	failures int
+	calls    int
+	headers  []http.Header
+	bodies   [][]byte
+	got      chan MonitorEventPayload
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	rcv.calls++
+	if rcv.calls <= rcv.failures {
+		rcv.mu.Unlock()
+		w.WriteHeader(rcv.status)
+		return
+	}
+	rcv.headers = append(rcv.headers, r.Header.Clone())
+	rcv.bodies = append(rcv.bodies, body)
+	rcv.mu.Unlock()
+	if rcv.got != nil {
+		var p MonitorEventPayload
+		_ = json.Unmarshal(body, &p)
+		rcv.got <- p
+	}
+}
+
+func TestMonitorWebhook_SignsPayload(t *testing.T) {
+	rcv := &webhookReceiver{}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	h := &MonitorWebhook{URL: srv.URL, Secret: "s3cret"}
+
+	ev := MonitorEvent{Type: EventNewTweet, Target: "search:go", At: time.Now(), Tweet: &Tweet{ID: "7", Text: "hi"}}
+	require.NoError(t, h.Send(context.Background(), ev))
+
+	require.Len(t, rcv.bodies, 1)
+	hdr, body := rcv.headers[0], rcv.bodies[0]
+	assert.Equal(t, "new_tweet:search:go:7", hdr.Get(WebhookIDHeader))
+	assert.Equal(t, "new_tweet", hdr.Get(WebhookEventHeader))
+	assert.NoError(t, VerifyMonitorWebhook("s3cret", hdr, body, time.Minute))
+	assert.ErrorIs(t, VerifyMonitorWebhook("other", hdr, body, time.Minute), ErrWebhookSignature)
+	assert.ErrorIs(t, VerifyMonitorWebhook("s3cret", hdr, append(body, ' '), time.Minute), ErrWebhookSignature)
+	assert.ErrorIs(t, VerifyMonitorWebhook("s3cret", http.Header{}, body, 0), ErrWebhookSignature)
+
+	var p MonitorEventPayload
+	require.NoError(t, json.Unmarshal(body, &p))
+	assert.Equal(t, EventNewTweet, p.Type)
+	assert.Equal(t, "hi", p.Tweet.Text)
+}
+
+func TestVerifyMonitorWebhook_RejectsStale(t *testing.T) {
+	body := []byte(`{}`)
+	hdr := http.Header{}
+	ts := "1000000000"
+	hdr.Set(WebhookTimestampHeader, ts)
+	hdr.Set(WebhookSignatureHeader, signWebhook("k", ts, body))
+
+	assert.ErrorIs(t, VerifyMonitorWebhook("k", hdr, body, time.Hour), ErrWebhookSignature)
+	assert.NoError(t, VerifyMonitorWebhook("k", hdr, body, 0), "maxAge 0 skips the age check")
+}
+
+func TestMonitorWebhook_Retries(t *testing.T) {
+	rcv := &webhookReceiver{status: http.StatusServiceUnavailable, failures: 2}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	h := &MonitorWebhook{URL: srv.URL, RetryDelay: time.Millisecond}
+
+	require.NoError(t, h.Send(context.Background(), MonitorEvent{Type: EventNewTweet, Tweet: &Tweet{ID: "1"}}))
+	assert.Equal(t, 3, rcv.calls)
+	assert.Len(t, rcv.bodies, 1)
+}
+
+func TestMonitorWebhook_GivesUp(t *testing.T) {
+	rcv := &webhookReceiver{status: http.StatusInternalServerError, failures: 100}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	h := &MonitorWebhook{URL: srv.URL, MaxAttempts: 3, RetryDelay: time.Millisecond}
+	require.Error(t, h.Send(context.Background(), MonitorEvent{Type: EventNewTweet, Tweet: &Tweet{ID: "1"}}))
+	assert.Equal(t, 3, rcv.calls)
+
+	rcv.status, rcv.calls = http.StatusBadRequest, 0
+	require.Error(t, h.Send(context.Background(), MonitorEvent{Type: EventNewTweet, Tweet: &Tweet{ID: "1"}}))
+	assert.Equal(t, 1, rcv.calls, "4xx responses are not retried")
+}
+
+func TestMonitor_Webhooks(t *testing.T) {
+	rcv := &webhookReceiver{got: make(chan MonitorEventPayload, 16)}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	src := &fakeMonitorSource{pages: map[string][][]*Tweet{
+		"search:$BTC": {tweetsWithIDs("1"), tweetsWithIDs("2", "1")},
+	}}
+	m := newTestMonitor(t, src)
+	m.cfg.WebhooksOnly = true
+	m.hooks = []*webhookQueue{{
+		hook:   &MonitorWebhook{URL: srv.URL, Types: []MonitorEventType{EventNewTweet}},
+		events: make(chan MonitorEvent, webhookQueueSize),
+	}}
+	m.TrackSearch("$BTC", WithHot())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	var ids []string
+	timeout := time.After(2 * time.Second)
+	for len(ids) < 2 {
+		select {
+		case p := <-rcv.got:
+			assert.Equal(t, "search:$BTC", p.Target)
+			ids = append(ids, p.Tweet.ID)
+		case <-timeout:
+			t.Fatalf("timed out: got %v", ids)
+		}
+	}
+	assert.Equal(t, []string{"1", "2"}, ids, "events arrive in order")
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	_, open := <-m.Events()
+	assert.False(t, open, "WebhooksOnly leaves the event stream empty")
+}
+
+func TestMonitor_EmitReportsFullWebhookQueue(t *testing.T) {
+	m := newTestMonitor(t, &fakeMonitorSource{})
+	m.cfg.WebhooksOnly = true
+	full := &webhookQueue{hook: &MonitorWebhook{URL: "http://unused"}, events: make(chan MonitorEvent, 1)}
+	m.hooks = []*webhookQueue{full}
+	ev := MonitorEvent{Type: EventNewTweet, Target: "search:$BTC", Tweet: &Tweet{ID: "1"}}
+
+	assert.True(t, m.emit(context.Background(), ev))
+	assert.False(t, m.emit(context.Background(), ev), "the queue dropped it")
+
+	full.hook.Types = []MonitorEventType{EventDeletedTweet}
+	assert.True(t, m.emit(context.Background(), ev), "filtered out, not dropped")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	<-full.events
+	assert.False(t, m.emit(ctx, ev), "nothing is queued once the monitor stops")
+	assert.Empty(t, full.events)
+}
+
+func TestMonitor_WebhookDrainsOnShutdown(t *testing.T) {
+	rcv := &webhookReceiver{}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	m := newTestMonitor(t, &fakeMonitorSource{})
+	q := &webhookQueue{hook: &MonitorWebhook{URL: srv.URL}, events: make(chan MonitorEvent, 4)}
+	q.events <- MonitorEvent{Type: EventNewTweet, Tweet: &Tweet{ID: "1"}}
+	q.events <- MonitorEvent{Type: EventNewTweet, Tweet: &Tweet{ID: "2"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.runWebhook(ctx, q)
+	assert.Len(t, rcv.bodies, 2, "queued events are delivered before Run returns")
+	assert.Empty(t, q.events)
+}
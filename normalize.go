@@ -0,0 +1,65 @@
+package twitter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxHandleLen is Twitter's maximum screen_name length.
+const maxHandleLen = 15
+
+// maxUserIDLen is the number of digits in the largest uint64 user ID.
+const maxUserIDLen = 20
+
+// ValidationError is returned when user-supplied input is rejected before a
+// request is issued.
+type ValidationError struct {
+	Field  string // "handle", "user_id", or the offending field name
+	Value  string // the original input
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// NormalizeHandle strips a leading @ and surrounding whitespace, lowercases the
+// result, and validates it against Twitter's screen_name rules (1-15 chars of
+// [a-z0-9_]). Returns a *ValidationError for malformed input.
+func NormalizeHandle(s string) (string, error) {
+	h := strings.TrimPrefix(strings.TrimSpace(s), "@")
+	if h == "" {
+		return "", &ValidationError{Field: "handle", Value: s, Reason: "empty"}
+	}
+	if len(h) > maxHandleLen {
+		return "", &ValidationError{Field: "handle", Value: s, Reason: fmt.Sprintf("longer than %d characters", maxHandleLen)}
+	}
+	h = strings.ToLower(h)
+	for _, ch := range h {
+		if !(ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '_') {
+			return "", &ValidationError{Field: "handle", Value: s, Reason: fmt.Sprintf("invalid character %q", ch)}
+		}
+	}
+	return h, nil
+}
+
+// IsValidUserID reports whether s looks like a Twitter numeric user ID (rest_id).
+func IsValidUserID(s string) bool {
+	if s == "" || len(s) > maxUserIDLen || s[0] == '0' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validateUserID returns a *ValidationError if s is not a valid user ID.
+func validateUserID(s string) error {
+	if !IsValidUserID(s) {
+		return &ValidationError{Field: "user_id", Value: s, Reason: "must be a non-zero numeric ID"}
+	}
+	return nil
+}
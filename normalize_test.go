@@ -0,0 +1,57 @@
+package twitter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeHandle(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"elonmusk", "elonmusk"},
+		{"@ElonMusk", "elonmusk"},
+		{"  @jack_  ", "jack_"},
+		{"A1234567890_xyz", "a1234567890_xyz"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeHandle(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestNormalizeHandle_Invalid(t *testing.T) {
+	for _, in := range []string{"", "@", "   ", "this_is_too_long_x", "bad-handle", "bad handle", "héllo", "@@double"} {
+		_, err := NormalizeHandle(in)
+		var ve *ValidationError
+		require.True(t, errors.As(err, &ve), "expected ValidationError for %q, got %v", in, err)
+		assert.Equal(t, "handle", ve.Field)
+		assert.Equal(t, in, ve.Value)
+	}
+}
+
+func TestIsValidUserID(t *testing.T) {
+	valid := []string{"1", "12345", "44196397", "18446744073709551615"}
+	for _, s := range valid {
+		assert.True(t, IsValidUserID(s), s)
+	}
+	invalid := []string{"", "0", "0123", "abc", "12a4", "-5", " 123", "123456789012345678901"}
+	for _, s := range invalid {
+		assert.False(t, IsValidUserID(s), s)
+	}
+}
+
+func TestValidateUserID(t *testing.T) {
+	require.NoError(t, validateUserID("12345"))
+
+	err := validateUserID("elonmusk")
+	var ve *ValidationError
+	require.True(t, errors.As(err, &ve))
+	assert.Equal(t, "user_id", ve.Field)
+	assert.ErrorContains(t, err, `invalid user_id "elonmusk"`)
+}
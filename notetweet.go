@@ -0,0 +1,50 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+// RichTextType is a formatting style of a long-form post.
+type RichTextType string
+
+const (
+	RichTextBold          RichTextType = "Bold"
+	RichTextItalic        RichTextType = "Italic"
+	RichTextStrikethrough RichTextType = "Strikethrough"
+)
+
+// RichTextTag applies styles to the characters [From, To) of a note tweet's
+// text. Offsets count characters (runes), not bytes.
+type RichTextTag struct {
+	From, To int
+	Types    []RichTextType
+}
+
+// maxNoteTweetLength is X's limit for long-form posts by premium accounts.
+const maxNoteTweetLength = 25000
+
+// CreateNoteTweet posts a long-form tweet (up to 25,000 characters, premium
+// accounts only) from acc, with optional rich text formatting. Returns the
+// tweet ID on success. Non-premium accounts get an API error.
+func (c *Client) CreateNoteTweet(ctx context.Context, acc *Account, text string, richtext ...RichTextTag) (string, error) {
+	n := utf8.RuneCountInString(text)
+	if n > maxNoteTweetLength {
+		return "", fmt.Errorf("note tweet is %d characters, limit is %d", n, maxNoteTweetLength)
+	}
+	tags := make([]map[string]any, 0, len(richtext))
+	for _, t := range richtext {
+		if t.From < 0 || t.From >= t.To || t.To > n || len(t.Types) == 0 {
+			return "", fmt.Errorf("invalid rich text tag [%d, %d) %v for %d characters", t.From, t.To, t.Types, n)
+		}
+		tags = append(tags, map[string]any{
+			"from_index":     t.From,
+			"to_index":       t.To,
+			"richtext_types": t.Types,
+		})
+	}
+	variables := createTweetVariables(text)
+	variables["richtext_options"] = map[string]any{"richtext_tags": tags}
+	return c.postTweet(ctx, acc, "CreateNoteTweet", variables)
+}
@@ -0,0 +1,41 @@
+package twitter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateTweet_NoteTweet(t *testing.T) {
+	id, err := parseCreateTweet("CreateNoteTweet", []byte(`{"data":{"notetweet_create":{"tweet_results":{"result":{"rest_id":"42"}}}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "42", id)
+
+	id, err = parseCreateTweet("CreateTweet", []byte(`{"data":{"create_tweet":{"tweet_results":{"result":{"rest_id":"7"}}}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "7", id)
+
+	_, err = parseCreateTweet("CreateNoteTweet", []byte(`{"errors":[{"message":"Not premium"}]}`))
+	assert.ErrorContains(t, err, "CreateNoteTweet API error: Not premium")
+}
+
+func TestCreateNoteTweet_Validation(t *testing.T) {
+	c := &Client{}
+	acc := &Account{Username: "a"}
+	ctx := context.Background()
+
+	_, err := c.CreateNoteTweet(ctx, acc, strings.Repeat("x", maxNoteTweetLength+1))
+	assert.ErrorContains(t, err, "limit is 25000")
+
+	for _, tag := range []RichTextTag{
+		{From: 0, To: 6, Types: []RichTextType{RichTextBold}},
+		{From: 3, To: 3, Types: []RichTextType{RichTextItalic}},
+		{From: 0, To: 2},
+	} {
+		_, err = c.CreateNoteTweet(ctx, acc, "héllo", tag)
+		assert.ErrorContains(t, err, "invalid rich text tag")
+	}
+}
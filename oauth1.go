@@ -0,0 +1,159 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OAuth1Credentials sign REST v1.1 requests with OAuth 1.0a (HMAC-SHA1), the
+// way the official apps authenticate, instead of with cookies. Consumer is
+// usually one of the official apps' keys (AndroidConsumer, IPhoneConsumer);
+// Token and TokenSecret are the account's access token for it.
+type OAuth1Credentials struct {
+	Consumer    OAuth1Consumer
+	Token       string
+	TokenSecret string
+}
+
+// OAuth1Consumer is an app's consumer key and secret.
+type OAuth1Consumer struct {
+	Key    string
+	Secret string
+}
+
+// Consumer keys of the official apps.
+var (
+	AndroidConsumer = OAuth1Consumer{Key: "3nVuSoBZnx6U4vzUxf5w", Secret: "Bcs59EFbbsdF6Sl9Ng71smgStWEGwXXKSjYvPVt7qys"}
+	IPhoneConsumer  = OAuth1Consumer{Key: "IQKbtAYlXLripLGPWd0HUA", Secret: "GgDYlkSvaPxGxC4X8liwpUoqKwwr3lCADbz8A7ADU"}
+)
+
+// authorization returns the Authorization header for method and rawURL.
+// form holds the parameters of a form-encoded body, which are signed too.
+func (o *OAuth1Credentials) authorization(method, rawURL string, form url.Values, nonce string, timestamp int64) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	oauth := map[string]string{
+		"oauth_consumer_key":     o.Consumer.Key,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(timestamp, 10),
+		"oauth_token":            o.Token,
+		"oauth_version":          "1.0",
+	}
+
+	var params []string
+	add := func(k, v string) { params = append(params, oauthEscape(k)+"="+oauthEscape(v)) }
+	for k, v := range oauth {
+		add(k, v)
+	}
+	for k, vs := range u.Query() {
+		for _, v := range vs {
+			add(k, v)
+		}
+	}
+	for k, vs := range form {
+		for _, v := range vs {
+			add(k, v)
+		}
+	}
+	sort.Strings(params)
+
+	base := *u
+	base.RawQuery, base.Fragment = "", ""
+	base.Scheme, base.Host = strings.ToLower(base.Scheme), strings.ToLower(base.Host)
+	baseString := strings.ToUpper(method) + "&" + oauthEscape(base.String()) + "&" + oauthEscape(strings.Join(params, "&"))
+
+	mac := hmac.New(sha1.New, []byte(oauthEscape(o.Consumer.Secret)+"&"+oauthEscape(o.TokenSecret)))
+	mac.Write([]byte(baseString))
+	oauth["oauth_signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	keys := make([]string, 0, len(oauth))
+	for k := range oauth {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = oauthEscape(k) + `="` + oauthEscape(oauth[k]) + `"`
+	}
+	return "OAuth " + strings.Join(parts, ", "), nil
+}
+
+// oauthEscape percent-encodes s per RFC 5849: everything but unreserved
+// characters, with spaces as %20.
+func oauthEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func oauthNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type oauth1Key struct{}
+
+// authContext returns ctx carrying acc's OAuth1 credentials, if it has any,
+// for doRequestWithBody to sign v1.1 requests with.
+func (a *Account) authContext(ctx context.Context) context.Context {
+	if a.OAuth1 == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth1Key{}, a.OAuth1)
+}
+
+func oauth1From(ctx context.Context) *OAuth1Credentials {
+	o, _ := ctx.Value(oauth1Key{}).(*OAuth1Credentials)
+	return o
+}
+
+// isRESTv11 reports whether urlStr is a v1.1 REST endpoint on the web or API
+// host.
+func isRESTv11(urlStr string) bool {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(u.Path, "/1.1/") || strings.HasPrefix(u.Path, "/i/api/1.1/")
+}
+
+// signOAuth1 turns cookie-authenticated headers into an OAuth1-signed
+// request to the API host: it drops the cookie and CSRF headers, moves
+// /i/api/1.1/ paths to /1.1/ and signs the URL and any form body. It returns
+// the URL and body to send.
+func signOAuth1(o *OAuth1Credentials, method, urlStr string, headers map[string]string, body io.Reader) (string, io.Reader, error) {
+	urlStr = mobileURL(urlStr)
+	var form url.Values
+	if body != nil && strings.HasPrefix(headers["content-type"], "application/x-www-form-urlencoded") {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return "", nil, err
+		}
+		if form, err = url.ParseQuery(string(b)); err != nil {
+			return "", nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	auth, err := o.authorization(method, urlStr, form, oauthNonce(), time.Now().Unix())
+	if err != nil {
+		return "", nil, err
+	}
+	for _, h := range []string{"cookie", "x-csrf-token", "x-twitter-auth-type", "origin", "referer"} {
+		delete(headers, h)
+	}
+	headers["authorization"] = auth
+	return urlStr, body, nil
+}
@@ -0,0 +1,43 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The example from Twitter's "Creating a signature" guide.
+func TestOAuth1Authorization(t *testing.T) {
+	o := &OAuth1Credentials{
+		Consumer:    OAuth1Consumer{Key: "xvz1evFS4wEEPTGEFPHBog", Secret: "kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw"},
+		Token:       "370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb",
+		TokenSecret: "LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE",
+	}
+	form := url.Values{"status": {"Hello Ladies + Gentlemen, a signed OAuth request!"}}
+	auth, err := o.authorization("POST", "https://api.twitter.com/1.1/statuses/update.json?include_entities=true", form,
+		"kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg", 1318622958)
+	require.NoError(t, err)
+	assert.Contains(t, auth, `oauth_signature="hCtSmYh%2BiHYCEqBWrE7C7hYmtUk%3D"`)
+	assert.True(t, strings.HasPrefix(auth, `OAuth oauth_consumer_key="xvz1evFS4wEEPTGEFPHBog", oauth_nonce=`))
+}
+
+func TestOAuth1Request(t *testing.T) {
+	tr := &headerTransport{}
+	c := &Client{cfg: ClientConfig{Transport: tr}}
+	acc := &Account{Username: "alice", AuthToken: "tok", CT0: "csrf", OAuth1: &OAuth1Credentials{Consumer: AndroidConsumer, Token: "t", TokenSecret: "s"}}
+	ctx := acc.authContext(context.Background())
+
+	h := acc.apiHeaders("tok", "csrf", "")
+	h["content-type"] = "application/x-www-form-urlencoded"
+	_, _, _, err := c.doRequestWithBody(ctx, nil, "POST", "https://x.com/i/api/1.1/account/update_profile.json", h, bytes.NewReader([]byte("name=Alice")))
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.x.com/1.1/account/update_profile.json", tr.urls[0])
+	assert.True(t, strings.HasPrefix(tr.headers[0]["authorization"], "OAuth "))
+	assert.NotContains(t, tr.headers[0], "cookie")
+	assert.NotContains(t, tr.headers[0], "x-csrf-token")
+}
@@ -0,0 +1,127 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// PagedResult is the outcome of a paginated list fetch. When pagination
+// stops on an error, Items holds every page fetched before it and PartialErr
+// says why; NextCursor then points at the failed page, so passing it back
+// resumes where the fetch stopped.
+type PagedResult[T any] struct {
+	Items      []T
+	NextCursor string // next page to fetch; "" once the list is exhausted
+	Pages      int    // pages fetched successfully
+	PartialErr error  // nil if pagination ended normally
+}
+
+// Complete reports whether the whole list was fetched.
+func (r PagedResult[T]) Complete() bool {
+	return r.PartialErr == nil && r.NextCursor == ""
+}
+
+// listPage describes one paginated GraphQL list operation for paginate.
+type listPage[T any] struct {
+	operation string
+	pageSize  int
+	variables func(count int) map[string]any
+	parse     func(body []byte) ([]T, string, error)
+
+	// fetch requests one page; nil GETs the operation's GraphQL URL.
+	fetch func(ctx context.Context, variables map[string]any) ([]byte, error)
+	// filter says whether to keep an item and whether its page is the last
+	// one; nil keeps everything. Timelines are only roughly ordered (pinned
+	// tweets, threads), so the rest of a last page is still filtered.
+	filter func(T) (keep, last bool)
+}
+
+// page requests one page of p.
+func (p listPage[T]) page(ctx context.Context, c *Client, variables map[string]any) ([]byte, error) {
+	if p.fetch != nil {
+		return p.fetch(ctx, variables)
+	}
+	url, err := EndpointURL(p.operation)
+	if err != nil {
+		return nil, err
+	}
+	body, _, err := c.doGET(ctx, p.operation, addGraphQLParams(url, variables, c.features(p.operation)))
+	return body, err
+}
+
+// apply runs p.filter over batch and reports whether it is the last page.
+func (p listPage[T]) apply(batch []T) ([]T, bool) {
+	if p.filter == nil {
+		return batch, false
+	}
+	kept := batch[:0]
+	stop := false
+	for _, item := range batch {
+		keep, last := p.filter(item)
+		if keep {
+			kept = append(kept, item)
+		}
+		stop = stop || last
+	}
+	return kept, stop
+}
+
+// paginate fetches pages of p from cursor ("" = first page) until maxCount
+// items are collected, the list ends, p.filter stops it, or a request
+// fails. A failed or truncated page makes it halve the page size (see
+// pageSizes); a failed one is retried at the smaller size.
+func paginate[T any](ctx context.Context, c *Client, p listPage[T], cursor string, maxCount int) PagedResult[T] {
+	res := PagedResult[T]{NextCursor: cursor}
+	if p.fetch == nil {
+		if _, err := EndpointURL(p.operation); err != nil {
+			res.PartialErr = err
+			return res
+		}
+	}
+	for len(res.Items) < maxCount {
+		if err := ctx.Err(); err != nil {
+			res.PartialErr = err
+			return res
+		}
+
+		count := min(c.pageSizes.get(p.operation, p.pageSize), maxCount-len(res.Items))
+		variables := p.variables(count)
+		if res.NextCursor != "" {
+			variables["cursor"] = res.NextCursor
+		}
+
+		body, err := p.page(ctx, c, variables)
+		if err != nil {
+			if ctx.Err() == nil && c.pageSizes.shrink(p.operation, count) {
+				slog.Warn("page failed, retrying smaller", slog.String("operation", p.operation),
+					slog.Int("count", count), slog.Any("error", err))
+				continue
+			}
+			res.PartialErr = fmt.Errorf("%s: %w", p.operation, err)
+			return res
+		}
+		batch, next, err := p.parse(body)
+		if err != nil {
+			res.PartialErr = fmt.Errorf("parse %s: %w", p.operation, err)
+			return res
+		}
+		n := len(batch)
+		batch, stopped := p.apply(batch)
+		res.Items = append(res.Items, batch...)
+		res.Pages++
+		// Pages that passed their ResponseValidator may still be empty at
+		// the end of the list.
+		if stopped || n == 0 || isEndCursor(next) {
+			res.NextCursor = ""
+			break
+		}
+		if n < count/2 {
+			c.pageSizes.shrink(p.operation, count)
+		} else {
+			c.pageSizes.ok(p.operation, p.pageSize)
+		}
+		res.NextCursor = next
+	}
+	return res
+}
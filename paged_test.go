@@ -0,0 +1,92 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedTransport serves Followers pages keyed by cursor and fails on the
+// cursors in fail.
+type pagedTransport struct {
+	pages map[string]string // cursor → body
+	fail  map[string]bool
+}
+
+func (p *pagedTransport) Do(_ context.Context, _, rawURL string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	u, _ := url.Parse(rawURL)
+	var vars struct {
+		Cursor string `json:"cursor"`
+	}
+	_ = json.Unmarshal([]byte(u.Query().Get("variables")), &vars)
+	if p.fail[vars.Cursor] {
+		return nil, nil, 0, errors.New("connection reset")
+	}
+	return []byte(p.pages[vars.Cursor]), nil, 200, nil
+}
+
+func followersPage(next string, ids ...string) string {
+	var entries []string
+	for _, id := range ids {
+		entries = append(entries, fmt.Sprintf(`{"entryId":"user-%s","content":{"entryType":"TimelineTimelineItem","itemContent":{"__typename":"TimelineUser","user_results":{"result":{"rest_id":"%s","legacy":{"screen_name":"u%s"}}}}}}`, id, id, id))
+	}
+	if next != "" {
+		entries = append(entries, `{"entryId":"cursor-bottom-1","content":{"entryType":"TimelineTimelineCursor","cursorType":"Bottom","value":"`+next+`"}}`)
+	}
+	return `{"data":{"user":{"result":{"timeline":{"timeline":{"instructions":[{"type":"TimelineAddEntries","entries":[` + strings.Join(entries, ",") + `]}]}}}}}}`
+}
+
+func TestGetFollowersPaged_PartialAndResume(t *testing.T) {
+	tr := &pagedTransport{
+		pages: map[string]string{
+			"":   followersPage("c2", "1", "2"),
+			"c2": followersPage("c3", "3"),
+			"c3": followersPage("", "4"),
+		},
+		fail: map[string]bool{"c2": true},
+	}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+		Retry:                &RetryPolicy{MaxAttempts: 1},
+	})
+	require.NoError(t, err)
+
+	r := c.GetFollowersPaged(context.Background(), "42", "", 10)
+	require.Error(t, r.PartialErr)
+	assert.Len(t, r.Items, 2)
+	assert.Equal(t, 1, r.Pages)
+	assert.Equal(t, "c2", r.NextCursor, "points at the failed page")
+	assert.False(t, r.Complete())
+
+	users, err := c.GetFollowers(context.Background(), "42", 10)
+	assert.Error(t, err)
+	assert.Len(t, users, 2, "partial data is still returned")
+
+	delete(tr.fail, "c2")
+	r = c.GetFollowersPaged(context.Background(), "42", r.NextCursor, 10)
+	require.NoError(t, r.PartialErr)
+	assert.Len(t, r.Items, 2)
+	assert.Equal(t, 2, r.Pages)
+	assert.True(t, r.Complete())
+
+	// maxCount stops early and leaves the cursor to continue from.
+	r = c.GetFollowersPaged(context.Background(), "42", "", 2)
+	require.NoError(t, r.PartialErr)
+	assert.Equal(t, "c2", r.NextCursor)
+	assert.False(t, r.Complete())
+
+	r = c.GetFollowersPaged(context.Background(), "not-an-id", "", 10)
+	assert.Error(t, r.PartialErr)
+	assert.Zero(t, r.Pages)
+}
@@ -0,0 +1,80 @@
+package twitter
+
+import (
+	"log/slog"
+	"sync"
+)
+
+const (
+	// minPageSize is the smallest page size paging adapts down to.
+	minPageSize = 5
+
+	// pageGrowAfter is the number of good pages at a reduced size after
+	// which the size is doubled again, back towards the default.
+	pageGrowAfter = 10
+)
+
+// pageSizes remembers the working page size of each paginated operation.
+// X rejects or silently truncates oversized count values on some
+// operations and accounts; paginate halves the size when that happens, and
+// later pages start from the remembered size instead of failing again.
+type pageSizes struct {
+	mu  sync.Mutex
+	ops map[string]*pageSize
+}
+
+type pageSize struct {
+	size int // current size, below the operation's default
+	good int // good pages in a row at size
+}
+
+// get returns the page size to request for operation, def when it has not
+// been reduced.
+func (p *pageSizes) get(operation string, def int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok := p.ops[operation]; ok {
+		return s.size
+	}
+	return def
+}
+
+// shrink halves the page size of operation below failed, the count of a
+// request that failed or came back truncated. It reports false when failed
+// is already as small as paging goes.
+func (p *pageSizes) shrink(operation string, failed int) bool {
+	size := failed / 2
+	if size < minPageSize {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ops == nil {
+		p.ops = make(map[string]*pageSize)
+	}
+	if s, ok := p.ops[operation]; ok && s.size <= size {
+		return true // a concurrent fetch already went lower
+	}
+	p.ops[operation] = &pageSize{size: size}
+	slog.Info("reducing page size", slog.String("operation", operation), slog.Int("size", size))
+	return true
+}
+
+// ok records a good page of operation, doubling a reduced size after
+// pageGrowAfter of them, up to def.
+func (p *pageSizes) ok(operation string, def int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.ops[operation]
+	if !ok {
+		return
+	}
+	if s.good++; s.good < pageGrowAfter {
+		return
+	}
+	if s.size*2 >= def {
+		delete(p.ops, operation)
+		return
+	}
+	s.size, s.good = s.size*2, 0
+}
@@ -0,0 +1,87 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countLimitTransport serves Followers pages keyed by cursor, rejecting
+// requests for more than limit users as X does for oversized counts.
+type countLimitTransport struct {
+	limit int
+	pages map[string]string
+
+	mu     sync.Mutex
+	counts []int
+}
+
+func (p *countLimitTransport) Do(_ context.Context, _, rawURL string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	u, _ := url.Parse(rawURL)
+	var vars struct {
+		Cursor string `json:"cursor"`
+		Count  int    `json:"count"`
+	}
+	_ = json.Unmarshal([]byte(u.Query().Get("variables")), &vars)
+	p.mu.Lock()
+	p.counts = append(p.counts, vars.Count)
+	p.mu.Unlock()
+	if vars.Count > p.limit {
+		return []byte(`{"errors":[{"message":"Bad request"}]}`), nil, 400, nil
+	}
+	return []byte(p.pages[vars.Cursor]), nil, 200, nil
+}
+
+func TestPaginate_AdaptsPageSize(t *testing.T) {
+	var first []string
+	for i := range 20 {
+		first = append(first, strconv.Itoa(i+1))
+	}
+	tr := &countLimitTransport{limit: 30, pages: map[string]string{
+		"":   followersPage("c2", first...),
+		"c2": followersPage("", "21"),
+	}}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}, {Username: "v", AuthToken: "b", CT0: "d"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	r := c.GetFollowersPaged(context.Background(), "42", "", 1000)
+	require.NoError(t, r.PartialErr)
+	assert.Len(t, r.Items, 21)
+	assert.Equal(t, 25, c.pageSizes.get("Followers", 100), "the working size is remembered")
+	assert.Equal(t, 100, c.pageSizes.get("Following", 100))
+
+	tr.counts = nil
+	r = c.GetFollowersPaged(context.Background(), "42", "", 1000)
+	require.NoError(t, r.PartialErr)
+	assert.Equal(t, []int{25, 25}, tr.counts, "later fetches start at the working size")
+}
+
+func TestPageSizes(t *testing.T) {
+	var p pageSizes
+	assert.True(t, p.shrink("Op", 100))
+	assert.Equal(t, 50, p.get("Op", 100))
+	assert.False(t, p.shrink("Op", 8), "not below minPageSize")
+	assert.True(t, p.shrink("Op", 40), "a truncated page halves again")
+	assert.Equal(t, 20, p.get("Op", 100))
+
+	for range pageGrowAfter {
+		p.ok("Op", 100)
+	}
+	assert.Equal(t, 40, p.get("Op", 100), "good pages double the size again")
+	for range 2 * pageGrowAfter {
+		p.ok("Op", 100)
+	}
+	assert.Equal(t, 100, p.get("Op", 100), "up to the default")
+}
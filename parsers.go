@@ -1,9 +1,12 @@
 package twitter
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -33,6 +36,37 @@ func parseUserByScreenName(body []byte) (*TwitterUser, error) {
 	return parseUserResult(raw.Data.User.Result)
 }
 
+// parseUsersByRestIDs parses the UsersByRestIds GraphQL response. Unavailable
+// (suspended, deactivated) users are skipped rather than failing the batch.
+func parseUsersByRestIDs(body []byte) ([]*TwitterUser, error) {
+	var raw struct {
+		Data struct {
+			Users []struct {
+				Result userResult `json:"result"`
+			} `json:"users"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal UsersByRestIds: %w", err)
+	}
+	if len(raw.Data.Users) == 0 && len(raw.Errors) > 0 {
+		return nil, fmt.Errorf("twitter API error: %s", raw.Errors[0].Message)
+	}
+	users := make([]*TwitterUser, 0, len(raw.Data.Users))
+	for _, u := range raw.Data.Users {
+		user, err := parseUserResult(u.Result)
+		if err != nil {
+			slog.Debug("skip user parse error", slog.Any("error", err))
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
 // parseUserList parses Followers/Following response.
 func parseUserList(body []byte) ([]*TwitterUser, string, error) {
 	var raw struct {
@@ -72,47 +106,37 @@ func parseRetweeterList(body []byte) ([]*TwitterUser, string, error) {
 }
 
 // parseTweetDetail parses TweetDetail GraphQL response.
-// The response wraps tweets in a threaded conversation timeline.
-func parseTweetDetail(body []byte) ([]*Tweet, error) {
-	type conversationData struct {
-		Instructions []struct {
-			Entries []struct {
-				Content struct {
-					ItemContent json.RawMessage `json:"itemContent"`
-				} `json:"content"`
-			} `json:"entries"`
-		} `json:"instructions"`
-	}
+// The response wraps tweets in a threaded conversation timeline: ancestors
+// and the focal tweet as single items, replies as conversation modules.
+func parseTweetDetail(body []byte) ([]*Tweet, []*TweetUnavailableError, error) {
 	var raw struct {
 		Data struct {
 			// Twitter uses both keys depending on the endpoint version
-			V2 conversationData `json:"threaded_conversation_with_injections_v2"`
-			V1 conversationData `json:"threaded_conversation_with_injections"`
+			V2 timelineObj `json:"threaded_conversation_with_injections_v2"`
+			V1 timelineObj `json:"threaded_conversation_with_injections"`
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(body, &raw); err != nil {
-		return nil, fmt.Errorf("unmarshal TweetDetail: %w", err)
+		return nil, nil, fmt.Errorf("unmarshal TweetDetail: %w", err)
 	}
 	// Use v2 if it has instructions, otherwise fall back to v1
-	conv := raw.Data.V2
-	if len(conv.Instructions) == 0 {
-		conv = raw.Data.V1
-	}
-	tl := timelineObj{Instructions: make([]timelineInstruction, 0)}
-	for _, instr := range conv.Instructions {
-		entries := make([]timelineEntry, 0, len(instr.Entries))
-		for _, e := range instr.Entries {
-			entries = append(entries, timelineEntry{
-				Content: timelineContent{ItemContent: e.Content.ItemContent},
-			})
-		}
-		tl.Instructions = append(tl.Instructions, timelineInstruction{Entries: entries})
+	tl := raw.Data.V2
+	if len(tl.Instructions) == 0 {
+		tl = raw.Data.V1
 	}
-	return extractTweetsFromTimeline(tl, "")
+	tweets, unavailable := extractTimeline(tl, "")
+	return tweets, unavailable, nil
 }
 
 // parseTweetTimeline parses UserTweets timeline response.
 func parseTweetTimeline(body []byte, authorID string) ([]*Tweet, error) {
+	tweets, _, err := parseTweetTimelinePage(body, authorID)
+	return tweets, err
+}
+
+// parseTweetTimelinePage is parseTweetTimeline that also returns the bottom
+// cursor.
+func parseTweetTimelinePage(body []byte, authorID string) ([]*Tweet, string, error) {
 	var raw struct {
 		Data struct {
 			User struct {
@@ -128,17 +152,25 @@ func parseTweetTimeline(body []byte, authorID string) ([]*Tweet, error) {
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(body, &raw); err != nil {
-		return nil, fmt.Errorf("unmarshal tweet timeline: %w", err)
+		return nil, "", fmt.Errorf("unmarshal tweet timeline: %w", err)
 	}
 	tl := raw.Data.User.Result.Timeline.Timeline
 	if len(tl.Instructions) == 0 {
 		tl = raw.Data.User.Result.TimelineV2.Timeline
 	}
-	return extractTweetsFromTimeline(tl, authorID)
+	tweets, _ := extractTimeline(tl, authorID)
+	return tweets, bottomCursor(tl), nil
 }
 
 // parseSearchTimeline parses SearchTimeline response.
 func parseSearchTimeline(body []byte) ([]*Tweet, error) {
+	tweets, _, err := parseSearchTimelinePage(body)
+	return tweets, err
+}
+
+// parseSearchTimelinePage is parseSearchTimeline that also returns the
+// bottom cursor.
+func parseSearchTimelinePage(body []byte) ([]*Tweet, string, error) {
 	var raw struct {
 		Data struct {
 			SearchByRawQuery struct {
@@ -149,9 +181,28 @@ func parseSearchTimeline(body []byte) ([]*Tweet, error) {
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(body, &raw); err != nil {
-		return nil, fmt.Errorf("unmarshal search timeline: %w", err)
+		return nil, "", fmt.Errorf("unmarshal search timeline: %w", err)
+	}
+	tl := raw.Data.SearchByRawQuery.SearchTimeline.Timeline
+	tweets, _ := extractTimeline(tl, "")
+	return tweets, bottomCursor(tl), nil
+}
+
+// parseListTimeline parses ListLatestTweetsTimeline response.
+func parseListTimeline(body []byte) ([]*Tweet, error) {
+	var raw struct {
+		Data struct {
+			List struct {
+				TweetsTimeline struct {
+					Timeline timelineObj `json:"timeline"`
+				} `json:"tweets_timeline"`
+			} `json:"list"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal list timeline: %w", err)
 	}
-	return extractTweetsFromTimeline(raw.Data.SearchByRawQuery.SearchTimeline.Timeline, "")
+	return extractTweetsFromTimeline(raw.Data.List.TweetsTimeline.Timeline, "")
 }
 
 // --- Timeline types ---
@@ -161,9 +212,10 @@ type timelineObj struct {
 }
 
 type timelineInstruction struct {
-	Type    string          `json:"type"`
-	Entries []timelineEntry `json:"entries"`
-	Entry   *timelineEntry  `json:"entry"`
+	Type        string          `json:"type"`
+	Entries     []timelineEntry `json:"entries"`
+	Entry       *timelineEntry  `json:"entry"`
+	ModuleItems []moduleItem    `json:"moduleItems"` // TimelineAddToModule
 }
 
 type timelineEntry struct {
@@ -178,6 +230,65 @@ type timelineContent struct {
 	ItemContent json.RawMessage `json:"itemContent"`
 	Value       string          `json:"value"`
 	CursorType  string          `json:"cursorType"`
+	Items       []moduleItem    `json:"items"` // TimelineTimelineModule
+}
+
+// moduleItem is one item of a TimelineTimelineModule entry.
+type moduleItem struct {
+	EntryID string `json:"entryId"`
+	Item    struct {
+		ItemContent json.RawMessage `json:"itemContent"`
+	} `json:"item"`
+}
+
+// timelineItem is the content of one timeline item and the ID of the entry
+// (or module item) holding it.
+type timelineItem struct {
+	EntryID string
+	Content json.RawMessage
+}
+
+// bottomCursor returns the cursor of the next (older) page of tl. Later
+// pages carry it in a TimelineReplaceEntry instruction.
+func bottomCursor(tl timelineObj) string {
+	for _, instruction := range tl.Instructions {
+		entries := instruction.Entries
+		if instruction.Entry != nil {
+			entries = append(entries, *instruction.Entry)
+		}
+		for _, entry := range entries {
+			if entry.Content.CursorType == "Bottom" || strings.Contains(entry.EntryID, "cursor-bottom") {
+				return entry.Content.Value
+			}
+		}
+	}
+	return ""
+}
+
+// timelineItems returns the items of tl in order: single items, the items of
+// TimelineTimelineModule entries (conversation threads in search and profile
+// timelines, who-to-follow carousels) and items appended to modules by
+// TimelineAddToModule.
+func timelineItems(tl timelineObj) []timelineItem {
+	var items []timelineItem
+	for _, instruction := range tl.Instructions {
+		for _, entry := range instruction.Entries {
+			if entry.Content.ItemContent != nil {
+				items = append(items, timelineItem{entry.EntryID, entry.Content.ItemContent})
+			}
+			for _, it := range entry.Content.Items {
+				if it.Item.ItemContent != nil {
+					items = append(items, timelineItem{it.EntryID, it.Item.ItemContent})
+				}
+			}
+		}
+		for _, it := range instruction.ModuleItems {
+			if it.Item.ItemContent != nil {
+				items = append(items, timelineItem{it.EntryID, it.Item.ItemContent})
+			}
+		}
+	}
+	return items
 }
 
 type userResult struct {
@@ -187,16 +298,39 @@ type userResult struct {
 	Legacy   struct {
 		Name            string `json:"name"`
 		ScreenName      string `json:"screen_name"`
-		FollowersCount  int    `json:"followers_count"`
-		FriendsCount    int    `json:"friends_count"`
-		StatusesCount   int    `json:"statuses_count"`
-		ListedCount     int    `json:"listed_count"`
+		FollowersCount  count  `json:"followers_count"`
+		FriendsCount    count  `json:"friends_count"`
+		StatusesCount   count  `json:"statuses_count"`
+		ListedCount     count  `json:"listed_count"`
 		CreatedAt       string `json:"created_at"`
 		Verified        bool   `json:"verified"`
 		Description     string `json:"description"`
 		ProfileImageURL string `json:"profile_image_url_https"`
+
+		Location     string   `json:"location"`
+		URL          string   `json:"url"`
+		PinnedTweets []string `json:"pinned_tweet_ids_str"`
+		Protected    bool     `json:"protected"`
+		CanDM        bool     `json:"can_dm"`
+		Entities     struct {
+			URL struct {
+				URLs []struct {
+					ExpandedURL string `json:"expanded_url"`
+				} `json:"urls"`
+			} `json:"url"`
+		} `json:"entities"`
 	} `json:"legacy"`
 	IsBlueVerified bool `json:"is_blue_verified"`
+	Professional   struct {
+		Category []struct {
+			Name string `json:"name"`
+		} `json:"category"`
+	} `json:"professional"`
+	ExtendedProfile struct {
+		Birthdate struct {
+			Visibility string `json:"visibility"`
+		} `json:"birthdate"`
+	} `json:"legacy_extended_profile"`
 }
 
 type tweetResult struct {
@@ -208,17 +342,116 @@ type tweetResult struct {
 		} `json:"user_results"`
 	} `json:"core"`
 	Legacy struct {
-		FullText      string `json:"full_text"`
-		CreatedAt     string `json:"created_at"`
-		FavoriteCount int    `json:"favorite_count"`
-		RetweetCount  int    `json:"retweet_count"`
-		QuoteCount    int    `json:"quote_count"`
-		ReplyCount    int    `json:"reply_count"`
-		UserIDStr     string `json:"user_id_str"`
+		FullText             string `json:"full_text"`
+		CreatedAt            string `json:"created_at"`
+		FavoriteCount        count  `json:"favorite_count"`
+		RetweetCount         count  `json:"retweet_count"`
+		QuoteCount           count  `json:"quote_count"`
+		ReplyCount           count  `json:"reply_count"`
+		UserIDStr            string `json:"user_id_str"`
+		ConversationIDStr    string `json:"conversation_id_str"`
+		InReplyToStatusIDStr string `json:"in_reply_to_status_id_str"`
+		InReplyToUserIDStr   string `json:"in_reply_to_user_id_str"`
+		Entities             struct {
+			Media []mediaEntity `json:"media"`
+		} `json:"extended_entities"`
+		Lang        string        `json:"lang"`
+		Place       *placeResult  `json:"place"`
+		Coordinates *geoJSONPoint `json:"coordinates"`
 	} `json:"legacy"`
 	Views struct {
-		Count string `json:"count"`
+		Count count `json:"count"` // a string, e.g. "1234"; absent when hidden
 	} `json:"views"`
+	EditControl editControl `json:"edit_control"`
+
+	// TweetWithVisibilityResults wraps the tweet itself.
+	Tweet *tweetResult `json:"tweet"`
+	// TweetTombstone and TweetUnavailable say why there is no tweet.
+	Tombstone tombstoneInfo `json:"tombstone"`
+	Reason    string        `json:"reason"`
+}
+
+// unwrap returns the tweet inside a TweetWithVisibilityResults wrapper
+// (limited-visibility tweets), or r itself.
+func (r tweetResult) unwrap() tweetResult {
+	if r.TypeName == "TweetWithVisibilityResults" && r.Tweet != nil {
+		return *r.Tweet
+	}
+	return r
+}
+
+// tombstoneInfo holds the notice shown in place of an unavailable tweet.
+type tombstoneInfo struct {
+	Text struct {
+		Text string `json:"text"`
+	} `json:"text"`
+	RichText struct {
+		Text string `json:"text"`
+	} `json:"richText"`
+}
+
+func (t tombstoneInfo) text() string {
+	if t.RichText.Text != "" {
+		return t.RichText.Text
+	}
+	return t.Text.Text
+}
+
+// mediaEntity is a legacy.extended_entities.media item.
+type mediaEntity struct {
+	Type          string `json:"type"`
+	MediaURLHTTPS string `json:"media_url_https"`
+	VideoInfo     struct {
+		DurationMillis int `json:"duration_millis"`
+		Variants       []struct {
+			Bitrate     int    `json:"bitrate"`
+			ContentType string `json:"content_type"`
+			URL         string `json:"url"`
+		} `json:"variants"`
+	} `json:"video_info"`
+}
+
+// variantSizeRe matches the WxH path segment of a video variant URL, e.g.
+// ".../vid/avc1/720x1280/abc.mp4".
+var variantSizeRe = regexp.MustCompile(`/(\d+)x(\d+)/`)
+
+// parseMedia converts a tweet's media entities.
+func parseMedia(entities []mediaEntity) []Media {
+	var out []Media
+	for _, e := range entities {
+		m := Media{
+			Type:     e.Type,
+			URL:      e.MediaURLHTTPS,
+			Duration: time.Duration(e.VideoInfo.DurationMillis) * time.Millisecond,
+		}
+		for _, v := range e.VideoInfo.Variants {
+			mv := MediaVariant{URL: v.URL, ContentType: v.ContentType, Bitrate: v.Bitrate}
+			if dims := variantSizeRe.FindStringSubmatch(v.URL); dims != nil {
+				mv.Width, _ = strconv.Atoi(dims[1])
+				mv.Height, _ = strconv.Atoi(dims[2])
+			}
+			m.Variants = append(m.Variants, mv)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// editControl lists a tweet's revision IDs. The latest revision carries them
+// directly; older revisions nest them under edit_control_initial.
+type editControl struct {
+	EditTweetIDs []string `json:"edit_tweet_ids"`
+	Initial      *struct {
+		EditTweetIDs []string `json:"edit_tweet_ids"`
+	} `json:"edit_control_initial"`
+}
+
+// ids returns all revision IDs, oldest first.
+func (e editControl) ids() []string {
+	if len(e.EditTweetIDs) == 0 && e.Initial != nil {
+		return e.Initial.EditTweetIDs
+	}
+	return e.EditTweetIDs
 }
 
 // --- Extraction helpers ---
@@ -266,39 +499,71 @@ func extractUsersFromTimeline(tl timelineObj) ([]*TwitterUser, string, error) {
 }
 
 func extractTweetsFromTimeline(tl timelineObj, defaultAuthorID string) ([]*Tweet, error) {
+	tweets, _ := extractTimeline(tl, defaultAuthorID)
+	return tweets, nil
+}
+
+// extractTimeline returns the tweets of tl and the tombstones standing in
+// for tweets that cannot be shown.
+func extractTimeline(tl timelineObj, defaultAuthorID string) ([]*Tweet, []*TweetUnavailableError) {
 	var tweets []*Tweet
+	var unavailable []*TweetUnavailableError
+	seen := make(map[string]bool)
 
-	for _, instruction := range tl.Instructions {
-		for _, entry := range instruction.Entries {
-			if entry.Content.ItemContent == nil {
-				continue
-			}
-			var item struct {
-				TypeName     string `json:"__typename"`
-				TweetResults struct {
-					Result tweetResult `json:"result"`
-				} `json:"tweet_results"`
-			}
-			if err := json.Unmarshal(entry.Content.ItemContent, &item); err != nil {
-				continue
-			}
-			if item.TypeName != "TimelineTweet" {
-				continue
-			}
-			t, err := parseTweetResult(item.TweetResults.Result, defaultAuthorID)
-			if err != nil {
-				slog.Debug("skip tweet parse error", slog.Any("error", err))
-				continue
-			}
-			tweets = append(tweets, t)
+	for _, it := range timelineItems(tl) {
+		var item struct {
+			TypeName     string `json:"__typename"`
+			TweetResults struct {
+				Result tweetResult `json:"result"`
+			} `json:"tweet_results"`
+			TombstoneInfo tombstoneInfo `json:"tombstoneInfo"`
+		}
+		if err := json.Unmarshal(it.Content, &item); err != nil {
+			continue
+		}
+		switch item.TypeName {
+		case "TimelineTweet":
+		case "TimelineTombstone":
+			unavailable = append(unavailable, newTweetUnavailable(entryTweetID(it.EntryID), item.TombstoneInfo.text(), ""))
+			continue
+		default:
+			continue
 		}
+		r := item.TweetResults.Result.unwrap()
+		switch r.TypeName {
+		case "TweetTombstone", "TweetUnavailable":
+			unavailable = append(unavailable, newTweetUnavailable(entryTweetID(it.EntryID), r.Tombstone.text(), r.Reason))
+			continue
+		}
+		t, err := parseTweetResult(r, defaultAuthorID)
+		if err != nil {
+			slog.Debug("skip tweet parse error", slog.Any("error", err))
+			continue
+		}
+		// A tweet can show both on its own and inside a conversation module.
+		if seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		tweets = append(tweets, t)
 	}
-	return tweets, nil
+	return tweets, unavailable
+}
+
+// entryTweetIDRe matches the tweet ID at the end of an entry ID, e.g.
+// "tweet-123" or "conversationthread-1-tweet-123".
+var entryTweetIDRe = regexp.MustCompile(`(?:tweet|tombstone)-(\d+)$`)
+
+func entryTweetID(entryID string) string {
+	if m := entryTweetIDRe.FindStringSubmatch(entryID); m != nil {
+		return m[1]
+	}
+	return ""
 }
 
 func parseUserResult(r userResult) (*TwitterUser, error) {
 	if r.TypeName == "UserUnavailable" {
-		return nil, fmt.Errorf("user unavailable (suspended or restricted)")
+		return nil, fmt.Errorf("%w (suspended or restricted)", ErrUserUnavailable)
 	}
 	if r.RestID == "" {
 		return nil, fmt.Errorf("empty user rest_id (typename=%s)", r.TypeName)
@@ -311,23 +576,41 @@ func parseUserResult(r userResult) (*TwitterUser, error) {
 		}
 	}
 	bio := strings.TrimSpace(r.Legacy.Description)
-	return &TwitterUser{
+	u := &TwitterUser{
 		ID:          r.RestID,
 		Handle:      r.Legacy.ScreenName,
 		DisplayName: r.Legacy.Name,
 		Bio:         bio,
-		Followers:   r.Legacy.FollowersCount,
-		Following:   r.Legacy.FriendsCount,
-		TweetCount:  r.Legacy.StatusesCount,
-		ListedCount: r.Legacy.ListedCount,
+		Followers:   int64(r.Legacy.FollowersCount),
+		Following:   int64(r.Legacy.FriendsCount),
+		TweetCount:  int64(r.Legacy.StatusesCount),
+		ListedCount: int64(r.Legacy.ListedCount),
 		CreatedAt:   createdAt,
 		IsVerified:  r.Legacy.Verified || r.IsBlueVerified,
 		HasAvatar:   r.Legacy.ProfileImageURL != "" && !strings.Contains(r.Legacy.ProfileImageURL, "default_profile"),
 		HasBio:      bio != "",
-	}, nil
+		AvatarURL:   r.Legacy.ProfileImageURL,
+
+		Location:            strings.TrimSpace(r.Legacy.Location),
+		URL:                 r.Legacy.URL,
+		BirthdateVisibility: strings.ToLower(r.ExtendedProfile.Birthdate.Visibility),
+		Protected:           r.Legacy.Protected,
+		CanDM:               r.Legacy.CanDM,
+	}
+	if urls := r.Legacy.Entities.URL.URLs; len(urls) > 0 && urls[0].ExpandedURL != "" {
+		u.URL = urls[0].ExpandedURL
+	}
+	if len(r.Legacy.PinnedTweets) > 0 {
+		u.PinnedTweetID = r.Legacy.PinnedTweets[0]
+	}
+	if cats := r.Professional.Category; len(cats) > 0 {
+		u.ProfessionalCategory = cats[0].Name
+	}
+	return u, nil
 }
 
 func parseTweetResult(r tweetResult, defaultAuthorID string) (*Tweet, error) {
+	r = r.unwrap()
 	if r.RestID == "" {
 		return nil, fmt.Errorf("empty tweet rest_id")
 	}
@@ -345,59 +628,251 @@ func parseTweetResult(r tweetResult, defaultAuthorID string) (*Tweet, error) {
 		}
 	}
 
-	views := 0
-	if r.Views.Count != "" {
-		views, _ = strconv.Atoi(r.Views.Count)
-	}
-
 	text := r.Legacy.FullText
 	mentions := extractTokenMentions(text)
 
+	var editIDs []string
+	if ids := r.EditControl.ids(); len(ids) > 1 {
+		editIDs = ids
+	}
+
 	return &Tweet{
-		ID:            r.RestID,
-		AuthorID:      authorID,
-		AuthorHandle:  r.Core.UserResults.Result.Legacy.ScreenName,
-		AuthorName:    r.Core.UserResults.Result.Legacy.Name,
-		Text:          text,
-		CreatedAt:     createdAt,
-		Views:         views,
-		Likes:         r.Legacy.FavoriteCount,
-		Retweets:      r.Legacy.RetweetCount,
-		Quotes:        r.Legacy.QuoteCount,
-		ReplyCount:    r.Legacy.ReplyCount,
-		TokenMentions: mentions,
+		ID:              r.RestID,
+		AuthorID:        authorID,
+		AuthorHandle:    r.Core.UserResults.Result.Legacy.ScreenName,
+		AuthorName:      r.Core.UserResults.Result.Legacy.Name,
+		Text:            text,
+		CreatedAt:       createdAt,
+		Views:           int64(r.Views.Count),
+		Likes:           int64(r.Legacy.FavoriteCount),
+		Retweets:        int64(r.Legacy.RetweetCount),
+		Quotes:          int64(r.Legacy.QuoteCount),
+		ReplyCount:      int64(r.Legacy.ReplyCount),
+		TokenMentions:   mentions,
+		EditIDs:         editIDs,
+		ConversationID:  r.Legacy.ConversationIDStr,
+		InReplyToID:     r.Legacy.InReplyToStatusIDStr,
+		InReplyToUserID: r.Legacy.InReplyToUserIDStr,
+		Media:           parseMedia(r.Legacy.Entities.Media),
+		Lang:            r.Legacy.Lang,
+		Place:           r.Legacy.Place.toPlace(),
+		Coordinates:     r.Legacy.Coordinates.toGeoPoint(),
 	}, nil
 }
 
-// parseCreateTweet extracts the tweet ID from a CreateTweet mutation response.
-func parseCreateTweet(body []byte) (string, error) {
+// placeResult is the place a tweet is tagged with.
+type placeResult struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	PlaceType   string `json:"place_type"`
+	Country     string `json:"country"`
+	CountryCode string `json:"country_code"`
+}
+
+func (p *placeResult) toPlace() *Place {
+	if p == nil || p.ID == "" {
+		return nil
+	}
+	return &Place{
+		ID:          p.ID,
+		Name:        p.Name,
+		FullName:    p.FullName,
+		Type:        p.PlaceType,
+		Country:     p.Country,
+		CountryCode: p.CountryCode,
+	}
+}
+
+// geoJSONPoint is a GeoJSON point; its coordinates are longitude first.
+type geoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func (g *geoJSONPoint) toGeoPoint() *GeoPoint {
+	if g == nil || g.Type != "Point" || len(g.Coordinates) != 2 {
+		return nil
+	}
+	return &GeoPoint{Lat: g.Coordinates[1], Lon: g.Coordinates[0]}
+}
+
+// parseCreateTweet extracts the tweet ID from a CreateTweet or
+// CreateNoteTweet mutation response.
+func parseCreateTweet(operation string, body []byte) (string, error) {
+	type created struct {
+		TweetResults struct {
+			Result struct {
+				RestID string `json:"rest_id"`
+			} `json:"result"`
+		} `json:"tweet_results"`
+	}
 	var raw struct {
 		Data struct {
-			CreateTweet struct {
-				TweetResults struct {
-					Result struct {
-						RestID string `json:"rest_id"`
-					} `json:"result"`
-				} `json:"tweet_results"`
-			} `json:"create_tweet"`
+			CreateTweet     created `json:"create_tweet"`
+			NoteTweetCreate created `json:"notetweet_create"`
 		} `json:"data"`
 		Errors []struct {
 			Message string `json:"message"`
 		} `json:"errors"`
 	}
 	if err := json.Unmarshal(body, &raw); err != nil {
-		return "", fmt.Errorf("unmarshal CreateTweet: %w", err)
+		return "", fmt.Errorf("unmarshal %s: %w", operation, err)
 	}
 	if len(raw.Errors) > 0 {
-		return "", fmt.Errorf("CreateTweet API error: %s", raw.Errors[0].Message)
+		return "", fmt.Errorf("%s API error: %s", operation, raw.Errors[0].Message)
 	}
-	tweetID := raw.Data.CreateTweet.TweetResults.Result.RestID
+	tweetID := cmp.Or(raw.Data.CreateTweet.TweetResults.Result.RestID, raw.Data.NoteTweetCreate.TweetResults.Result.RestID)
 	if tweetID == "" {
-		return "", fmt.Errorf("CreateTweet returned empty tweet ID: %s", truncateBytes(body, 300))
+		return "", fmt.Errorf("%s returned empty tweet ID: %s", operation, truncateBytes(body, 300))
 	}
 	return tweetID, nil
 }
 
+// parseTrends parses the Explore trending timeline (GenericTimelineById).
+// Trends arrive either as top-level items or grouped inside modules.
+func parseTrends(body []byte) ([]*Trend, error) {
+	var raw struct {
+		Data struct {
+			Timeline struct {
+				Timeline timelineObj `json:"timeline"`
+			} `json:"timeline"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal trends: %w", err)
+	}
+	if len(raw.Errors) > 0 && len(raw.Data.Timeline.Timeline.Instructions) == 0 {
+		return nil, fmt.Errorf("GenericTimelineById API error: %s", raw.Errors[0].Message)
+	}
+
+	var trends []*Trend
+	for _, it := range timelineItems(raw.Data.Timeline.Timeline) {
+		if t := parseTrendItem(it.Content); t != nil {
+			trends = append(trends, t)
+		}
+	}
+	return trends, nil
+}
+
+// parseTrendItem converts a TimelineTrend item, or returns nil for other items.
+func parseTrendItem(itemContent json.RawMessage) *Trend {
+	var item struct {
+		TypeName string `json:"__typename"`
+		Name     string `json:"name"`
+		Rank     string `json:"rank"`
+		TrendURL struct {
+			URL string `json:"url"`
+		} `json:"trend_url"`
+		TrendMetadata struct {
+			DomainContext   string `json:"domain_context"`
+			MetaDescription string `json:"meta_description"`
+		} `json:"trend_metadata"`
+		Description   string `json:"description"`
+		IsAITrend     bool   `json:"is_ai_trend"`
+		GroupedTrends []struct {
+			Name string `json:"name"`
+		} `json:"grouped_trends"`
+		AssociatedEvents []struct {
+			Title string `json:"title"`
+		} `json:"associated_events"`
+	}
+	if err := json.Unmarshal(itemContent, &item); err != nil || item.TypeName != "TimelineTrend" {
+		return nil
+	}
+	t := &Trend{
+		Name:      item.Name,
+		Query:     trendQuery(item.TrendURL.URL),
+		PostCount: parseCompactCount(item.TrendMetadata.MetaDescription),
+		Context:   item.TrendMetadata.DomainContext,
+		Genre:     trendGenre(item.TrendMetadata.DomainContext),
+		Summary:   item.Description,
+		IsAI:      item.IsAITrend,
+	}
+	t.Rank, _ = strconv.Atoi(item.Rank)
+	for _, g := range item.GroupedTrends {
+		if g.Name != "" {
+			t.Events = append(t.Events, g.Name)
+		}
+	}
+	for _, e := range item.AssociatedEvents {
+		if e.Title != "" {
+			t.Events = append(t.Events, e.Title)
+		}
+	}
+	return t
+}
+
+// trendQuery extracts the search query from a trend link such as
+// "twitter://search/?query=%23Go&src=trend_click".
+func trendQuery(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("query")
+}
+
+// trendGenre returns the category part of a domain context label:
+// "Sports · Trending" → "Sports". Generic labels ("Trending in Germany",
+// "Only on X") have no genre.
+func trendGenre(label string) string {
+	genre, _, _ := strings.Cut(label, "·")
+	genre = strings.TrimSpace(genre)
+	if strings.HasPrefix(genre, "Trending") || strings.HasPrefix(genre, "Only on") {
+		return ""
+	}
+	return genre
+}
+
+// parseCompactCount parses counts like "12.3K posts", "1,204 posts" or
+// "2M posts". Returns 0 if s holds no number; values past int64 saturate.
+func parseCompactCount(s string) int64 {
+	num, _, _ := strings.Cut(strings.TrimSpace(s), " ")
+	num = strings.ReplaceAll(num, ",", "")
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(num, "K"), strings.HasSuffix(num, "k"):
+		mult = 1e3
+	case strings.HasSuffix(num, "M"), strings.HasSuffix(num, "m"):
+		mult = 1e6
+	case strings.HasSuffix(num, "B"), strings.HasSuffix(num, "b"):
+		mult = 1e9
+	}
+	if mult > 1 {
+		num = num[:len(num)-1]
+	}
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil && mult == 1 {
+		return max(n, 0)
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 || math.IsNaN(f) {
+		return 0
+	}
+	if v := f*mult + 0.5; v < math.MaxInt64 {
+		return int64(v)
+	}
+	return math.MaxInt64
+}
+
+// count is a counter in a GraphQL payload. X sends most counters as JSON
+// numbers but some, like views.count, as strings, and occasionally as null
+// or compact text ("1.2M"); anything unparseable decodes as 0.
+type count int64
+
+func (c *count) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "null" {
+		*c = 0
+		return nil
+	}
+	*c = count(parseCompactCount(s))
+	return nil
+}
+
 func extractTokenMentions(text string) []string {
 	matches := tokenMentionRe.FindAllStringSubmatch(strings.ToUpper(text), -1)
 	seen := make(map[string]bool)
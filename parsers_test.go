@@ -1,6 +1,10 @@
 package twitter
 
-import "testing"
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
 
 func TestParseUserByScreenName(t *testing.T) {
 	body := `{
@@ -171,3 +175,230 @@ func TestCT0(t *testing.T) {
 		t.Fatal("expected different ct0 values")
 	}
 }
+
+func TestParseUsersByRestIDs(t *testing.T) {
+	body := `{
+		"data": {
+			"users": [
+				{"result": {"__typename": "User", "rest_id": "1", "legacy": {"screen_name": "one", "followers_count": 10}}},
+				{"result": {"__typename": "UserUnavailable", "rest_id": ""}},
+				{"result": {"__typename": "User", "rest_id": "2", "legacy": {"screen_name": "two"}}}
+			]
+		}
+	}`
+
+	users, err := parseUsersByRestIDs([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	if users[0].ID != "1" || users[0].Handle != "one" || users[0].Followers != 10 {
+		t.Fatalf("unexpected first user: %+v", users[0])
+	}
+	if users[1].ID != "2" {
+		t.Fatalf("expected second user ID 2, got %s", users[1].ID)
+	}
+}
+
+func TestParseUsersByRestIDs_Error(t *testing.T) {
+	_, err := parseUsersByRestIDs([]byte(`{"errors":[{"message":"Bad request"}]}`))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParseTrends(t *testing.T) {
+	body := `{"data":{"timeline":{"timeline":{"instructions":[{
+		"type":"TimelineAddEntries",
+		"entries":[
+			{"entryId":"trend-1","content":{"entryType":"TimelineTimelineItem","itemContent":{
+				"__typename":"TimelineTrend","name":"#GoLang","rank":"1",
+				"trend_url":{"url":"twitter://search/?query=%23GoLang&src=trend_click"},
+				"trend_metadata":{"domain_context":"Technology · Trending","meta_description":"12.3K posts"},
+				"grouped_trends":[{"name":"Gophers"}]
+			}}},
+			{"entryId":"trending-module","content":{"entryType":"TimelineTimelineModule","items":[
+				{"item":{"itemContent":{
+					"__typename":"TimelineTrend","name":"Election",
+					"trend_metadata":{"domain_context":"Trending in United States","meta_description":"1,204 posts"},
+					"description":"Grok summary of the debate.","is_ai_trend":true,
+					"associated_events":[{"title":"Presidential debate"}]
+				}}},
+				{"item":{"itemContent":{"__typename":"TimelineTweet"}}}
+			]}}
+		]
+	}]}}}}`
+
+	trends, err := parseTrends([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends) != 2 {
+		t.Fatalf("expected 2 trends, got %d", len(trends))
+	}
+	first := trends[0]
+	if first.Query != "#GoLang" || first.Rank != 1 || first.PostCount != 12300 {
+		t.Fatalf("unexpected first trend: %+v", first)
+	}
+	if first.Genre != "Technology" || len(first.Events) != 1 || first.Events[0] != "Gophers" {
+		t.Fatalf("unexpected first trend metadata: %+v", first)
+	}
+	second := trends[1]
+	if second.Genre != "" || second.PostCount != 1204 || !second.IsAI {
+		t.Fatalf("unexpected second trend: %+v", second)
+	}
+	if second.Summary != "Grok summary of the debate." || len(second.Events) != 1 || second.Events[0] != "Presidential debate" {
+		t.Fatalf("unexpected second trend metadata: %+v", second)
+	}
+}
+
+func TestParseCompactCount(t *testing.T) {
+	cases := map[string]int64{
+		"12.3K posts":         12300,
+		"1,204 posts":         1204,
+		"2M posts":            2000000,
+		"1.5b":                1500000000,
+		"4294967296":          4294967296,
+		"9223372036854775807": 9223372036854775807,
+		"1e30":                9223372036854775807,
+		"-5":                  0,
+		"":                    0,
+		"Trending":            0,
+	}
+	for in, want := range cases {
+		if got := parseCompactCount(in); got != want {
+			t.Errorf("parseCompactCount(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestParseTweetResult_Counters(t *testing.T) {
+	cases := map[string]int64{
+		`"count":"3500000000"`: 3500000000,
+		`"count":"1.2M"`:       1200000,
+		`"count":12`:           12,
+		`"count":null`:         0,
+		`"state":"Enabled"`:    0,
+	}
+	for views, want := range cases {
+		var r tweetResult
+		body := `{"rest_id":"1","legacy":{"full_text":"hi","favorite_count":2500000000,"retweet_count":"7"},"views":{` + views + `},` +
+			`"core":{"user_results":{"result":{"rest_id":"9","legacy":{"followers_count":3000000000,"friends_count":null}}}}}`
+		if err := json.Unmarshal([]byte(body), &r); err != nil {
+			t.Fatalf("%s: %v", views, err)
+		}
+		tw, err := parseTweetResult(r, "")
+		if err != nil {
+			t.Fatalf("%s: %v", views, err)
+		}
+		if tw.Views != want || tw.Likes != 2500000000 || tw.Retweets != 7 {
+			t.Errorf("%s: got %+v, want views %d", views, tw, want)
+		}
+		u, err := parseUserResult(r.Core.UserResults.Result)
+		if err != nil {
+			t.Fatalf("%s: %v", views, err)
+		}
+		if u.Followers != 3000000000 || u.Following != 0 {
+			t.Errorf("%s: got user %+v", views, u)
+		}
+	}
+}
+
+func TestParseSearchTimeline_Modules(t *testing.T) {
+	tweet := func(id string) string {
+		return `{"__typename":"TimelineTweet","tweet_results":{"result":{"__typename":"Tweet","rest_id":"` + id + `","legacy":{"full_text":"t` + id + `"}}}}`
+	}
+	body := `{"data":{"search_by_raw_query":{"search_timeline":{"timeline":{"instructions":[
+		{"type":"TimelineAddEntries","entries":[
+			{"entryId":"tweet-1","content":{"entryType":"TimelineTimelineItem","itemContent":` + tweet("1") + `}},
+			{"entryId":"conversationthread-2","content":{"entryType":"TimelineTimelineModule","items":[
+				{"entryId":"conversationthread-2-tweet-2","item":{"itemContent":` + tweet("2") + `}},
+				{"entryId":"conversationthread-2-tweet-3","item":{"itemContent":` + tweet("3") + `}},
+				{"entryId":"conversationthread-2-tweet-1","item":{"itemContent":` + tweet("1") + `}}
+			]}},
+			{"entryId":"who-to-follow-4","content":{"entryType":"TimelineTimelineModule","items":[
+				{"item":{"itemContent":{"__typename":"TimelineUser","user_results":{"result":{"rest_id":"9"}}}}}
+			]}}
+		]},
+		{"type":"TimelineAddToModule","moduleEntryId":"conversationthread-2","moduleItems":[
+			{"entryId":"conversationthread-2-tweet-4","item":{"itemContent":` + tweet("4") + `}}
+		]}
+	]}}}}}`
+
+	tweets, err := parseSearchTimeline([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, tw := range tweets {
+		ids = append(ids, tw.ID)
+	}
+	if got := strings.Join(ids, ","); got != "1,2,3,4" {
+		t.Fatalf("expected tweets 1,2,3,4, got %s", got)
+	}
+}
+
+func TestParseUserResult_ExtendedFields(t *testing.T) {
+	body := `{"data":{"user":{"result":{
+		"__typename":"User","rest_id":"12345",
+		"legacy":{"screen_name":"testuser","location":" Berlin ","url":"https://t.co/abc",
+			"entities":{"url":{"urls":[{"expanded_url":"https://example.com"}]}},
+			"pinned_tweet_ids_str":["777"],"protected":true,"can_dm":true},
+		"professional":{"professional_type":"Business","category":[{"id":580,"name":"Media & News"}]},
+		"legacy_extended_profile":{"birthdate":{"day":1,"month":2,"visibility":"Self"}}
+	}}}}`
+
+	user, err := parseUserByScreenName([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TwitterUser{
+		ID: "12345", Handle: "testuser",
+		Location: "Berlin", URL: "https://example.com", PinnedTweetID: "777",
+		ProfessionalCategory: "Media & News", BirthdateVisibility: "self",
+		Protected: true, CanDM: true,
+	}
+	if *user != want {
+		t.Fatalf("got %+v, want %+v", *user, want)
+	}
+
+	c := &Client{cfg: ClientConfig{MinimalUsers: true}}
+	user, _ = c.shapeUser(user, nil)
+	if *user != (TwitterUser{ID: "12345", Handle: "testuser"}) {
+		t.Fatalf("MinimalUsers kept extended fields: %+v", *user)
+	}
+}
+
+func TestParseTweetResult_LangAndPlace(t *testing.T) {
+	var r tweetResult
+	body := `{"rest_id":"1","legacy":{"full_text":"hola","lang":"es",
+		"place":{"id":"0a3e119020705b64","name":"Madrid","full_name":"Madrid, España","place_type":"city","country":"España","country_code":"ES"},
+		"coordinates":{"type":"Point","coordinates":[-3.7038,40.4168]}}}`
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatal(err)
+	}
+	tw, err := parseTweetResult(r, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tw.Lang != "es" {
+		t.Errorf("Lang = %q, want es", tw.Lang)
+	}
+	want := Place{ID: "0a3e119020705b64", Name: "Madrid", FullName: "Madrid, España", Type: "city", Country: "España", CountryCode: "ES"}
+	if tw.Place == nil || *tw.Place != want {
+		t.Errorf("Place = %+v, want %+v", tw.Place, want)
+	}
+	if tw.Coordinates == nil || *tw.Coordinates != (GeoPoint{Lat: 40.4168, Lon: -3.7038}) {
+		t.Errorf("Coordinates = %+v", tw.Coordinates)
+	}
+
+	r = tweetResult{}
+	if err := json.Unmarshal([]byte(`{"rest_id":"2","legacy":{"full_text":"x","place":null,"coordinates":null}}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if tw, _ = parseTweetResult(r, ""); tw.Place != nil || tw.Coordinates != nil {
+		t.Errorf("untagged tweet: got place %+v, coordinates %+v", tw.Place, tw.Coordinates)
+	}
+}
@@ -0,0 +1,90 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Poll is the state of a tweet's poll card.
+type Poll struct {
+	Choices  []PollChoice
+	Selected int // 1-based choice of the account that voted; 0 if none
+	EndsAt   time.Time
+	Final    bool // voting has closed and the counts will not change
+}
+
+// PollChoice is one option of a Poll.
+type PollChoice struct {
+	Label string
+	Votes int64
+}
+
+// maxPollChoices is the most options a poll card can have.
+const maxPollChoices = 4
+
+// VotePoll casts acc's vote for choice (1-based) in the poll of tweetID,
+// whose card is cardURI (e.g. "card://1234567890"), and returns the updated
+// poll. X resolves the poll from cardURI; the card name sent along only has
+// to admit the choice.
+func (c *Client) VotePoll(ctx context.Context, acc *Account, cardURI, tweetID string, choice int) (*Poll, error) {
+	if choice < 1 || choice > maxPollChoices {
+		return nil, &ValidationError{Field: "choice", Value: strconv.Itoa(choice), Reason: "must be between 1 and 4"}
+	}
+	if !strings.HasPrefix(cardURI, "card://") {
+		return nil, &ValidationError{Field: "card_uri", Value: cardURI, Reason: `must start with "card://"`}
+	}
+	if !IsValidUserID(tweetID) {
+		return nil, &ValidationError{Field: "tweet_id", Value: tweetID, Reason: "must be a non-zero numeric ID"}
+	}
+	form := url.Values{
+		"twitter:string:card_uri":           {cardURI},
+		"twitter:long:original_tweet_id":    {tweetID},
+		"twitter:string:response_card_name": {fmt.Sprintf("poll%dchoice_text_only", max(choice, 2))},
+		"twitter:string:cards_platform":     {"Web-12"},
+		"twitter:string:selected_choice":    {strconv.Itoa(choice)},
+	}
+	body, err := c.doFormPOST(ctx, acc, "VotePoll", capiPassthroughURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("VotePoll: %w", err)
+	}
+	return parsePollCard(body)
+}
+
+// parsePollCard parses the card a poll vote returns. Card values are typed
+// binding values keyed "choice1_label", "choice1_count" and so on.
+func parsePollCard(body []byte) (*Poll, error) {
+	var raw struct {
+		Card struct {
+			BindingValues map[string]struct {
+				StringValue  string `json:"string_value"`
+				BooleanValue bool   `json:"boolean_value"`
+			} `json:"binding_values"`
+		} `json:"card"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal poll card: %w", err)
+	}
+	values := raw.Card.BindingValues
+	p := &Poll{Final: values["counts_are_final"].BooleanValue}
+	for i := 1; i <= maxPollChoices; i++ {
+		label, ok := values[fmt.Sprintf("choice%d_label", i)]
+		if !ok {
+			break
+		}
+		votes, _ := strconv.ParseInt(values[fmt.Sprintf("choice%d_count", i)].StringValue, 10, 64)
+		p.Choices = append(p.Choices, PollChoice{Label: label.StringValue, Votes: votes})
+	}
+	if len(p.Choices) == 0 {
+		return nil, fmt.Errorf("poll card has no choices: %s", truncateBytes(body, 300))
+	}
+	p.Selected, _ = strconv.Atoi(values["selected_choice"].StringValue)
+	if end := values["end_datetime_utc"].StringValue; end != "" {
+		p.EndsAt, _ = time.Parse(time.RFC3339, end)
+	}
+	return p, nil
+}
@@ -0,0 +1,43 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePollCard(t *testing.T) {
+	p, err := parsePollCard([]byte(`{"card":{"name":"poll3choice_text_only","binding_values":{
+		"choice1_label":{"type":"STRING","string_value":"Yes"},"choice1_count":{"type":"STRING","string_value":"12"},
+		"choice2_label":{"type":"STRING","string_value":"No"},"choice2_count":{"type":"STRING","string_value":"3"},
+		"choice3_label":{"type":"STRING","string_value":"Maybe"},"choice3_count":{"type":"STRING","string_value":"0"},
+		"selected_choice":{"type":"STRING","string_value":"2"},
+		"counts_are_final":{"type":"BOOLEAN","boolean_value":false},
+		"end_datetime_utc":{"type":"STRING","string_value":"2025-03-19T12:00:00Z"}}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []PollChoice{{"Yes", 12}, {"No", 3}, {"Maybe", 0}}, p.Choices)
+	assert.Equal(t, 2, p.Selected)
+	assert.False(t, p.Final)
+	assert.Equal(t, time.Date(2025, 3, 19, 12, 0, 0, 0, time.UTC), p.EndsAt)
+
+	_, err = parsePollCard([]byte(`{"card":{"binding_values":{}}}`))
+	assert.Error(t, err)
+}
+
+func TestVotePoll_Validation(t *testing.T) {
+	c := &Client{}
+	acc := &Account{Username: "a"}
+	var verr *ValidationError
+	_, err := c.VotePoll(context.Background(), acc, "card://1", "20", 5)
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "choice", verr.Field)
+	_, err = c.VotePoll(context.Background(), acc, "https://x.com", "20", 1)
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "card_uri", verr.Field)
+	_, err = c.VotePoll(context.Background(), acc, "card://1", "abc", 1)
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "tweet_id", verr.Field)
+}
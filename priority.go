@@ -0,0 +1,143 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Priority orders requests that wait for a pool account. When an endpoint's
+// accounts are all busy or rate-limited, waiting requests of a higher
+// priority get the next free account before lower ones, so interactive
+// lookups are not starved by background pagination.
+type Priority int
+
+const (
+	PriorityNormal Priority = iota // default
+	PriorityHigh                   // interactive lookups
+	PriorityLow                    // background bulk work and pagination
+
+	numPriorities = 3
+)
+
+// rank orders priorities from lowest (0) to highest.
+func (p Priority) rank() int {
+	switch p {
+	case PriorityHigh:
+		return 2
+	case PriorityLow:
+		return 0
+	}
+	return 1
+}
+
+type priorityKeyType struct{}
+
+// WithPriority returns a context whose requests queue for a pool account at
+// priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKeyType{}, p)
+}
+
+// priorityFrom returns the priority set by WithPriority, or PriorityNormal.
+func priorityFrom(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKeyType{}).(Priority)
+	return p
+}
+
+// queueSlice bounds each pool wait of a queued request, so a lower-priority
+// waiter yields within queueSlice once a higher-priority one arrives.
+const queueSlice = 250 * time.Millisecond
+
+// errOutranked is returned when a request's wait ran out while
+// higher-priority requests kept the endpoint's accounts busy.
+var errOutranked = errors.New("no account available: higher-priority requests waiting")
+
+// requestQueue lets requests that wait for an account on the same endpoint
+// take turns by priority: a request only asks the pool for an account while
+// no higher-priority request waits on that endpoint. Within a priority the
+// pool's own order applies. The zero value is ready to use.
+type requestQueue struct {
+	mu      sync.Mutex
+	waiting map[string]*[numPriorities]int // endpoint → waiters per rank
+	changed chan struct{}                  // closed and replaced when waiting changes
+}
+
+// acquire returns an account for endpoint from try, waiting up to wait in
+// slices of at most queueSlice. try is only called while no higher-priority
+// request waits on endpoint. An error from try that arrives before its slice
+// is up is final (the pool has nothing that could free up).
+func (q *requestQueue) acquire(ctx context.Context, endpoint string, p Priority, wait time.Duration, try func(wait time.Duration) (*Account, error)) (*Account, error) {
+	r := p.rank()
+	q.update(endpoint, r, 1)
+	defer q.update(endpoint, r, -1)
+
+	deadline := time.Now().Add(wait)
+	var lastErr error
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if lastErr == nil {
+				lastErr = errOutranked
+			}
+			return nil, lastErr
+		}
+		if outranked, changed := q.outranked(endpoint, r); outranked {
+			select {
+			case <-changed:
+			case <-time.After(remaining):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		slice := min(remaining, queueSlice)
+		start := time.Now()
+		acc, err := try(slice)
+		if err == nil || ctx.Err() != nil || time.Since(start) < slice {
+			return acc, err
+		}
+		lastErr = err
+	}
+}
+
+// update adds delta waiters of rank r on endpoint and wakes outranked ones.
+func (q *requestQueue) update(endpoint string, r, delta int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.waiting == nil {
+		q.waiting = make(map[string]*[numPriorities]int)
+	}
+	w := q.waiting[endpoint]
+	if w == nil {
+		w = new([numPriorities]int)
+		q.waiting[endpoint] = w
+	}
+	w[r] += delta
+	if *w == [numPriorities]int{} {
+		delete(q.waiting, endpoint)
+	}
+	if q.changed != nil {
+		close(q.changed)
+		q.changed = nil
+	}
+}
+
+// outranked reports whether a higher-rank request waits on endpoint, and
+// returns a channel closed at the next change.
+func (q *requestQueue) outranked(endpoint string, r int) (bool, <-chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.changed == nil {
+		q.changed = make(chan struct{})
+	}
+	if w := q.waiting[endpoint]; w != nil {
+		for higher := r + 1; higher < numPriorities; higher++ {
+			if w[higher] > 0 {
+				return true, q.changed
+			}
+		}
+	}
+	return false, q.changed
+}
@@ -0,0 +1,65 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPriority(t *testing.T) {
+	assert.Equal(t, PriorityNormal, priorityFrom(context.Background()))
+	assert.Equal(t, PriorityLow, priorityFrom(WithPriority(context.Background(), PriorityLow)))
+}
+
+func TestRequestQueue_LowYieldsToHigh(t *testing.T) {
+	var q requestQueue
+	q.update("SearchTimeline", PriorityHigh.rank(), 1) // an interactive request is waiting
+
+	var tries atomic.Int32
+	acc := &Account{Username: "a"}
+	got := make(chan *Account)
+	go func() {
+		a, _ := q.acquire(context.Background(), "SearchTimeline", PriorityLow, 2*time.Second, func(time.Duration) (*Account, error) {
+			tries.Add(1)
+			return acc, nil
+		})
+		got <- a
+	}()
+
+	// Other endpoints are unaffected.
+	a, err := q.acquire(context.Background(), "UserTweets", PriorityLow, time.Second, func(time.Duration) (*Account, error) { return acc, nil })
+	require.NoError(t, err)
+	assert.Same(t, acc, a)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, tries.Load(), "low priority waits while high priority is queued")
+
+	q.update("SearchTimeline", PriorityHigh.rank(), -1)
+	select {
+	case a := <-got:
+		assert.Same(t, acc, a)
+	case <-time.After(time.Second):
+		t.Fatal("low-priority request not admitted after the high one left")
+	}
+	assert.Empty(t, q.waiting)
+}
+
+func TestRequestQueue_Errors(t *testing.T) {
+	var q requestQueue
+	noAccounts := errors.New("no available items")
+	_, err := q.acquire(context.Background(), "E", PriorityNormal, time.Minute, func(time.Duration) (*Account, error) {
+		return nil, noAccounts
+	})
+	assert.ErrorIs(t, err, noAccounts, "an immediate pool error is final")
+
+	q.update("E", PriorityHigh.rank(), 1)
+	_, err = q.acquire(context.Background(), "E", PriorityNormal, 20*time.Millisecond, func(time.Duration) (*Account, error) {
+		return nil, noAccounts
+	})
+	assert.ErrorIs(t, err, errOutranked)
+}
@@ -0,0 +1,120 @@
+package twitter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"unicode/utf8"
+)
+
+const formContentType = "application/x-www-form-urlencoded"
+
+// Profile image size limits enforced by Twitter.
+const (
+	maxAvatarBytes = 700 << 10
+	maxBannerBytes = 5 << 20
+)
+
+// ProfileUpdate holds the profile fields to change. Empty fields are left
+// as they are.
+type ProfileUpdate struct {
+	Name     string // display name, up to 50 characters
+	Bio      string // up to 160 characters
+	Location string // up to 30 characters
+	URL      string // website, up to 100 characters
+}
+
+// form validates u and encodes it for account/update_profile.
+func (u ProfileUpdate) form() (url.Values, error) {
+	form := url.Values{}
+	for _, f := range []struct {
+		param, field, value string
+		max                 int
+	}{
+		{"name", "name", u.Name, 50},
+		{"description", "bio", u.Bio, 160},
+		{"location", "location", u.Location, 30},
+		{"url", "url", u.URL, 100},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if utf8.RuneCountInString(f.value) > f.max {
+			return nil, &ValidationError{Field: f.field, Value: f.value, Reason: "longer than " + strconv.Itoa(f.max) + " characters"}
+		}
+		form.Set(f.param, f.value)
+	}
+	if len(form) == 0 {
+		return nil, fmt.Errorf("profile update has no fields set")
+	}
+	return form, nil
+}
+
+// UpdateProfile changes the profile of acc and returns the updated profile.
+func (c *Client) UpdateProfile(ctx context.Context, acc *Account, update ProfileUpdate) (*TwitterUser, error) {
+	form, err := update.form()
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.doFormPOST(ctx, acc, "UpdateProfile", updateProfileURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateProfile: %w", err)
+	}
+	return c.shapeUser(parseRESTUser(body))
+}
+
+// UpdateAvatar replaces the profile image of acc with image (JPEG, PNG or
+// GIF, at most 700 KB).
+func (c *Client) UpdateAvatar(ctx context.Context, acc *Account, image io.Reader) error {
+	return c.uploadProfileImage(ctx, acc, "UpdateProfileImage", updateProfileImageURL, "image", image, maxAvatarBytes)
+}
+
+// UpdateBanner replaces the profile banner of acc with image (JPEG, PNG or
+// GIF, at most 5 MB; 1500x500 recommended).
+func (c *Client) UpdateBanner(ctx context.Context, acc *Account, image io.Reader) error {
+	return c.uploadProfileImage(ctx, acc, "UpdateProfileBanner", updateProfileBannerURL, "banner", image, maxBannerBytes)
+}
+
+// uploadProfileImage sends image base64-encoded in param, which the profile
+// image endpoints accept in place of a separate media upload.
+func (c *Client) uploadProfileImage(ctx context.Context, acc *Account, endpoint, urlStr, param string, image io.Reader, maxBytes int64) error {
+	data, err := io.ReadAll(io.LimitReader(image, maxBytes+1))
+	if err != nil {
+		return fmt.Errorf("%s: read image: %w", endpoint, err)
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("%s: empty image", endpoint)
+	}
+	if int64(len(data)) > maxBytes {
+		return fmt.Errorf("%s: image larger than %d bytes", endpoint, maxBytes)
+	}
+	form := url.Values{param: {base64.StdEncoding.EncodeToString(data)}}
+	if _, err := c.doFormPOST(ctx, acc, endpoint, urlStr, form); err != nil {
+		return fmt.Errorf("%s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// doFormPOST is doPOST for form-encoded REST endpoints.
+func (c *Client) doFormPOST(ctx context.Context, acc *Account, endpoint, urlStr string, form url.Values) ([]byte, error) {
+	return c.doPOSTAs(ctx, acc, endpoint, urlStr, formContentType, []byte(form.Encode()))
+}
+
+// parseRESTUser parses a REST (v1.1) user object, whose fields match the
+// GraphQL legacy block.
+func parseRESTUser(body []byte) (*TwitterUser, error) {
+	var r userResult
+	if err := json.Unmarshal(body, &r.Legacy); err != nil {
+		return nil, fmt.Errorf("unmarshal user: %w", err)
+	}
+	var id struct {
+		IDStr string `json:"id_str"`
+	}
+	_ = json.Unmarshal(body, &id)
+	r.RestID = id.IDStr
+	return parseUserResult(r)
+}
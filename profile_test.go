@@ -0,0 +1,45 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileUpdate_Form(t *testing.T) {
+	form, err := ProfileUpdate{Name: "Jack", Bio: "gm ☀️"}.form()
+	require.NoError(t, err)
+	assert.Equal(t, "description=gm+%E2%98%80%EF%B8%8F&name=Jack", form.Encode())
+
+	_, err = ProfileUpdate{Location: strings.Repeat("x", 31)}.form()
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "location", verr.Field)
+
+	_, err = ProfileUpdate{}.form()
+	assert.Error(t, err)
+}
+
+func TestParseRESTUser(t *testing.T) {
+	u, err := parseRESTUser([]byte(`{"id":12,"id_str":"12","name":"jack","screen_name":"jack",
+		"description":"just setting up","followers_count":5,"profile_image_url_https":"https://pbs.twimg.com/a.jpg",
+		"created_at":"Tue Mar 21 20:50:14 +0000 2006"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "12", u.ID)
+	assert.Equal(t, "just setting up", u.Bio)
+	assert.Equal(t, int64(5), u.Followers)
+	assert.Equal(t, "https://pbs.twimg.com/a.jpg", u.AvatarURL)
+	assert.Equal(t, 2006, u.CreatedAt.Year())
+}
+
+func TestUpdateAvatar_RejectsOversizedImage(t *testing.T) {
+	c := &Client{}
+	err := c.UpdateAvatar(context.Background(), &Account{Username: "a"}, bytes.NewReader(make([]byte, maxAvatarBytes+1)))
+	assert.ErrorContains(t, err, "larger than")
+	err = c.UpdateBanner(context.Background(), &Account{Username: "a"}, bytes.NewReader(nil))
+	assert.ErrorContains(t, err, "empty image")
+}
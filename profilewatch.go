@@ -0,0 +1,217 @@
+package twitter
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProfileChangeKind names what changed in a profile.
+type ProfileChangeKind string
+
+const (
+	BioChanged                ProfileChangeKind = "bio_changed"
+	NameChanged               ProfileChangeKind = "name_changed"
+	HandleChanged             ProfileChangeKind = "handle_changed"
+	AvatarChanged             ProfileChangeKind = "avatar_changed"
+	FollowersCrossedThreshold ProfileChangeKind = "followers_crossed_threshold"
+	FollowersDelta            ProfileChangeKind = "followers_delta"
+	FollowingDelta            ProfileChangeKind = "following_delta"
+)
+
+// ProfileChange is one typed difference between two snapshots of a user.
+type ProfileChange struct {
+	Kind   ProfileChangeKind
+	UserID string
+
+	// Old and New hold the bio, display name, handle or avatar URL before
+	// and after the change.
+	Old, New string
+
+	// Threshold is the follower count crossed, in either direction, for
+	// FollowersCrossedThreshold; compare Previous and Current followers to
+	// tell which.
+	Threshold int
+
+	// Delta is the follower or following count change for FollowersDelta
+	// and FollowingDelta; negative for a loss.
+	Delta int64
+
+	Previous, Current *TwitterUser
+}
+
+// DiffProfiles returns the changes from prev to cur. A follower count that
+// crosses several thresholds at once yields one change per threshold.
+func DiffProfiles(prev, cur *TwitterUser, thresholds []int) []ProfileChange {
+	if prev == nil || cur == nil {
+		return nil
+	}
+	var changes []ProfileChange
+	add := func(kind ProfileChangeKind, before, after string) {
+		changes = append(changes, ProfileChange{
+			Kind: kind, UserID: cur.ID, Old: before, New: after, Previous: prev, Current: cur,
+		})
+	}
+	if prev.Bio != cur.Bio {
+		add(BioChanged, prev.Bio, cur.Bio)
+	}
+	if prev.DisplayName != cur.DisplayName {
+		add(NameChanged, prev.DisplayName, cur.DisplayName)
+	}
+	if !strings.EqualFold(prev.Handle, cur.Handle) {
+		add(HandleChanged, prev.Handle, cur.Handle)
+	}
+	if avatarChanged(prev, cur) {
+		add(AvatarChanged, prev.AvatarURL, cur.AvatarURL)
+	}
+	lo, hi := min(prev.Followers, cur.Followers), max(prev.Followers, cur.Followers)
+	for _, th := range thresholds {
+		if lo < int64(th) && int64(th) <= hi {
+			changes = append(changes, ProfileChange{
+				Kind: FollowersCrossedThreshold, UserID: cur.ID, Threshold: th, Previous: prev, Current: cur,
+			})
+		}
+	}
+	return changes
+}
+
+// DeltaThreshold sets how large a follower or following count change between
+// two snapshots must be to be reported: sudden unfollow dumps, purges, or
+// waves of bot follows. A change is reported when it reaches every threshold
+// set; the zero value reports nothing.
+type DeltaThreshold struct {
+	Count   int64   // absolute change in either direction
+	Percent float64 // change relative to the previous count, e.g. 10 for 10%
+}
+
+// reached reports whether the change from prev to cur reaches d.
+func (d DeltaThreshold) reached(prev, cur int64) bool {
+	if d.Count <= 0 && d.Percent <= 0 {
+		return false
+	}
+	delta := cur - prev
+	if delta == 0 {
+		return false
+	}
+	if d.Count > 0 && max(delta, -delta) < d.Count {
+		return false
+	}
+	// Any change from zero counts as reaching a percentage.
+	if d.Percent > 0 && prev > 0 && float64(max(delta, -delta))*100 < d.Percent*float64(prev) {
+		return false
+	}
+	return true
+}
+
+// DiffCounts returns FollowersDelta and FollowingDelta changes from prev to
+// cur for count changes reaching the given thresholds.
+func DiffCounts(prev, cur *TwitterUser, followers, following DeltaThreshold) []ProfileChange {
+	if prev == nil || cur == nil {
+		return nil
+	}
+	var changes []ProfileChange
+	if followers.reached(prev.Followers, cur.Followers) {
+		changes = append(changes, ProfileChange{
+			Kind: FollowersDelta, UserID: cur.ID, Delta: cur.Followers - prev.Followers, Previous: prev, Current: cur,
+		})
+	}
+	if following.reached(prev.Following, cur.Following) {
+		changes = append(changes, ProfileChange{
+			Kind: FollowingDelta, UserID: cur.ID, Delta: cur.Following - prev.Following, Previous: prev, Current: cur,
+		})
+	}
+	return changes
+}
+
+// avatarChanged compares avatar URLs when both snapshots have one, and
+// otherwise only whether an avatar is set, so a snapshot from a source that
+// lacks URLs is not mistaken for a change.
+func avatarChanged(prev, cur *TwitterUser) bool {
+	if prev.AvatarURL != "" && cur.AvatarURL != "" {
+		return prev.AvatarURL != cur.AvatarURL
+	}
+	return prev.HasAvatar != cur.HasAvatar
+}
+
+// ProfileWatcher keeps the last snapshot of each user it observes and reports
+// what changed since. Safe for concurrent use.
+type ProfileWatcher struct {
+	// Thresholds are follower counts whose crossing is reported.
+	Thresholds []int
+
+	// FollowerDelta and FollowingDelta report count changes between two
+	// snapshots as FollowersDelta and FollowingDelta.
+	FollowerDelta, FollowingDelta DeltaThreshold
+
+	mu    sync.Mutex
+	snaps map[string]*TwitterUser
+}
+
+// Observe records u as the user's latest snapshot and returns the changes
+// since the previous one. The first snapshot of a user is the baseline and
+// yields nothing.
+func (w *ProfileWatcher) Observe(u *TwitterUser) []ProfileChange {
+	if u == nil || u.ID == "" {
+		return nil
+	}
+	w.mu.Lock()
+	if w.snaps == nil {
+		w.snaps = make(map[string]*TwitterUser)
+	}
+	prev := w.snaps[u.ID]
+	w.snaps[u.ID] = u
+	w.mu.Unlock()
+	changes := DiffProfiles(prev, u, w.Thresholds)
+	return append(changes, DiffCounts(prev, u, w.FollowerDelta, w.FollowingDelta)...)
+}
+
+// Forget drops the snapshot of userID.
+func (w *ProfileWatcher) Forget(userID string) {
+	w.mu.Lock()
+	delete(w.snaps, userID)
+	w.mu.Unlock()
+}
+
+// WatchProfiles fetches the profiles of userIDs every interval (default 1m)
+// in UsersByRestIds batches and delivers their changes, with follower counts
+// crossing any of thresholds reported as FollowersCrossedThreshold. The
+// channel is closed when ctx is done.
+func (c *Client) WatchProfiles(ctx context.Context, userIDs []string, interval time.Duration, thresholds ...int) <-chan ProfileChange {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	w := &ProfileWatcher{Thresholds: slices.Clone(thresholds)}
+	ids := slices.Clone(userIDs)
+	out := make(chan ProfileChange, 16)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, r := range c.GetUsersByIDs(ctx, ids, 1) {
+				if r.Err != nil {
+					if ctx.Err() == nil {
+						slog.Warn("profile watch failed", slog.String("user_id", r.Input), slog.Any("error", r.Err))
+					}
+					continue
+				}
+				for _, ch := range w.Observe(r.User) {
+					select {
+					case out <- ch:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
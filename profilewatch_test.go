@@ -0,0 +1,82 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProfiles(t *testing.T) {
+	prev := &TwitterUser{ID: "1", Handle: "old", DisplayName: "Old", Bio: "gm", Followers: 900,
+		HasAvatar: true, AvatarURL: "https://pbs.twimg.com/a.jpg"}
+	cur := &TwitterUser{ID: "1", Handle: "new", DisplayName: "Old", Bio: "gn", Followers: 10500,
+		HasAvatar: true, AvatarURL: "https://pbs.twimg.com/b.jpg"}
+
+	changes := DiffProfiles(prev, cur, []int{1000, 5000, 100000})
+	var kinds []ProfileChangeKind
+	var crossed []int
+	for _, ch := range changes {
+		kinds = append(kinds, ch.Kind)
+		if ch.Kind == FollowersCrossedThreshold {
+			crossed = append(crossed, ch.Threshold)
+		}
+		assert.Equal(t, "1", ch.UserID)
+	}
+	assert.ElementsMatch(t, []ProfileChangeKind{BioChanged, HandleChanged, AvatarChanged,
+		FollowersCrossedThreshold, FollowersCrossedThreshold}, kinds)
+	assert.Equal(t, []int{1000, 5000}, crossed)
+	assert.Equal(t, "gm", changes[0].Old)
+	assert.Equal(t, "gn", changes[0].New)
+
+	// Falling back below a threshold counts as crossing it.
+	down := DiffProfiles(cur, &TwitterUser{ID: "1", Handle: "NEW", DisplayName: "Old", Bio: "gn", Followers: 4000,
+		HasAvatar: true, AvatarURL: "https://pbs.twimg.com/b.jpg"}, []int{5000})
+	require.Len(t, down, 1, "handle case changes are not renames")
+	assert.Equal(t, 5000, down[0].Threshold)
+}
+
+func TestDiffProfiles_AvatarWithoutURL(t *testing.T) {
+	withURL := &TwitterUser{ID: "1", HasAvatar: true, AvatarURL: "https://pbs.twimg.com/a.jpg"}
+	noURL := &TwitterUser{ID: "1", HasAvatar: true}
+	assert.Empty(t, DiffProfiles(withURL, noURL, nil))
+	assert.Len(t, DiffProfiles(noURL, &TwitterUser{ID: "1"}, nil), 1)
+}
+
+func TestProfileWatcher(t *testing.T) {
+	w := &ProfileWatcher{}
+	assert.Nil(t, w.Observe(&TwitterUser{ID: "1", Bio: "a"}), "first snapshot is the baseline")
+	changes := w.Observe(&TwitterUser{ID: "1", Bio: "b"})
+	require.Len(t, changes, 1)
+	assert.Equal(t, BioChanged, changes[0].Kind)
+
+	w.Forget("1")
+	assert.Nil(t, w.Observe(&TwitterUser{ID: "1", Bio: "c"}))
+}
+
+func TestDiffCounts(t *testing.T) {
+	prev := &TwitterUser{ID: "1", Followers: 10000, Following: 100}
+	dump := &TwitterUser{ID: "1", Followers: 8500, Following: 2100}
+
+	changes := DiffCounts(prev, dump, DeltaThreshold{Count: 1000, Percent: 10}, DeltaThreshold{Count: 500})
+	require.Len(t, changes, 2)
+	assert.Equal(t, FollowersDelta, changes[0].Kind)
+	assert.Equal(t, int64(-1500), changes[0].Delta)
+	assert.Same(t, prev, changes[0].Previous)
+	assert.Same(t, dump, changes[0].Current)
+	assert.Equal(t, FollowingDelta, changes[1].Kind)
+	assert.Equal(t, int64(2000), changes[1].Delta)
+
+	assert.Empty(t, DiffCounts(prev, dump, DeltaThreshold{Count: 1000, Percent: 20}, DeltaThreshold{}),
+		"every threshold set must be reached; the zero value is off")
+	assert.Len(t, DiffCounts(&TwitterUser{ID: "1"}, &TwitterUser{ID: "1", Followers: 3}, DeltaThreshold{Percent: 50}, DeltaThreshold{}), 1)
+}
+
+func TestProfileWatcher_Deltas(t *testing.T) {
+	w := &ProfileWatcher{FollowerDelta: DeltaThreshold{Count: 100}}
+	assert.Nil(t, w.Observe(&TwitterUser{ID: "1", Followers: 500}))
+	assert.Empty(t, w.Observe(&TwitterUser{ID: "1", Followers: 550}))
+	changes := w.Observe(&TwitterUser{ID: "1", Followers: 900})
+	require.Len(t, changes, 1)
+	assert.Equal(t, int64(350), changes[0].Delta)
+}
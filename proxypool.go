@@ -0,0 +1,325 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/anatolykoptev/go-twitter/proxyprovider"
+)
+
+// ProxyPoolConfig tunes a ProxyPool.
+type ProxyPoolConfig struct {
+	// CheckURL is fetched through each proxy by health checks.
+	// Default: https://x.com/robots.txt
+	CheckURL string
+
+	// CheckInterval is the time between background health sweeps in Run.
+	// Default: 5m.
+	CheckInterval time.Duration
+
+	// Cooldown is how long a proxy reported down stays out of rotation
+	// before a health check may bring it back. Default: 10m.
+	Cooldown time.Duration
+
+	// MaxAccountsPerProxy caps how many accounts share one proxy.
+	// Default: 0 (unlimited).
+	MaxAccountsPerProxy int
+
+	// Checker overrides the health check. It must return nil if the proxy
+	// is usable. Default: GET CheckURL through the proxy, expecting HTTP 200.
+	Checker func(ctx context.Context, proxy string) error
+}
+
+// ProxyPool is a set of proxies shared by the account pool. Each account is
+// pinned to one proxy (sticky, so residential exit IPs stay stable per
+// account) until that proxy fails; it is then moved to the least-loaded
+// healthy proxy instead of just backing off. Safe for concurrent use.
+type ProxyPool struct {
+	cfg ProxyPoolConfig
+
+	mu       sync.Mutex
+	proxies  []*pooledProxy
+	assigned map[string]*pooledProxy // account username → proxy
+}
+
+type pooledProxy struct {
+	url       string
+	downUntil time.Time
+	fails     int
+	accounts  int
+}
+
+func (p *pooledProxy) healthy(now time.Time) bool { return !now.Before(p.downUntil) }
+
+// ProxyStatus is a snapshot of one pool proxy.
+type ProxyStatus struct {
+	URL       string // password redacted
+	Healthy   bool
+	DownUntil time.Time
+	Fails     int // consecutive failures
+	Accounts  int // accounts currently assigned
+}
+
+// NewProxyPool creates a pool over the given proxy URLs.
+func NewProxyPool(proxies []string, cfg ProxyPoolConfig) *ProxyPool {
+	if cfg.CheckURL == "" {
+		cfg.CheckURL = "https://x.com/robots.txt"
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 5 * time.Minute
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 10 * time.Minute
+	}
+	if cfg.Checker == nil {
+		cfg.Checker = checkProxy(cfg.CheckURL)
+	}
+	p := &ProxyPool{cfg: cfg, assigned: make(map[string]*pooledProxy)}
+	p.Add(proxies...)
+	return p
+}
+
+// Add inserts proxies not already in the pool.
+func (p *ProxyPool) Add(proxies ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, u := range proxies {
+		if u != "" && p.findLocked(u) == nil {
+			p.proxies = append(p.proxies, &pooledProxy{url: u})
+		}
+	}
+}
+
+// Remove drops proxies from the pool. Accounts pinned to them are moved on
+// their next Assign.
+func (p *ProxyPool) Remove(proxies ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.proxies = slices.DeleteFunc(p.proxies, func(pp *pooledProxy) bool {
+		return slices.Contains(proxies, pp.url)
+	})
+	for acc, pp := range p.assigned {
+		if slices.Contains(proxies, pp.url) {
+			delete(p.assigned, acc)
+		}
+	}
+}
+
+// Sync makes the pool hold exactly proxies: new ones are added and proxies
+// no longer listed are removed. Health state of kept proxies is preserved.
+func (p *ProxyPool) Sync(proxies []string) {
+	p.mu.Lock()
+	var stale []string
+	for _, pp := range p.proxies {
+		if !slices.Contains(proxies, pp.url) {
+			stale = append(stale, pp.url)
+		}
+	}
+	p.mu.Unlock()
+	p.Remove(stale...)
+	p.Add(proxies...)
+}
+
+// Refresh replaces the pool contents with the proxies listed by provider. An
+// empty list is treated as an error and leaves the pool untouched.
+func (p *ProxyPool) Refresh(ctx context.Context, provider proxyprovider.Provider) error {
+	proxies, err := provider.Proxies(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch proxies: %w", err)
+	}
+	if len(proxies) == 0 {
+		return errors.New("fetch proxies: provider returned no proxies")
+	}
+	p.Sync(proxies)
+	return nil
+}
+
+// RunProvider refreshes the pool from provider every interval (default 1h)
+// until ctx is done. Failed refreshes are logged and the previous proxies
+// kept.
+func (p *ProxyPool) RunProvider(ctx context.Context, provider proxyprovider.Provider, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Refresh(ctx, provider); err != nil && ctx.Err() == nil {
+			slog.Warn("proxy provider refresh failed", slog.Any("error", err))
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Assign returns the proxy pinned to account, pinning it to the least-loaded
+// healthy proxy first if it has none or its proxy is down. Returns false if
+// no healthy proxy has room.
+func (p *ProxyPool) Assign(account string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if pp, ok := p.assigned[account]; ok && pp.healthy(now) {
+		return pp.url, true
+	}
+	return p.reassignLocked(account, now)
+}
+
+// ReportDown marks the account's proxy as failed and moves the account to
+// another healthy proxy. Returns the new proxy, or false if none is available
+// (the account then keeps its old proxy).
+func (p *ProxyPool) ReportDown(account string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if pp, ok := p.assigned[account]; ok {
+		pp.fails++
+		pp.downUntil = now.Add(p.cfg.Cooldown)
+	}
+	return p.reassignLocked(account, now)
+}
+
+// reassignLocked pins account to the healthy proxy with the fewest accounts.
+func (p *ProxyPool) reassignLocked(account string, now time.Time) (string, bool) {
+	var best *pooledProxy
+	for _, pp := range p.proxies {
+		if !pp.healthy(now) {
+			continue
+		}
+		if p.cfg.MaxAccountsPerProxy > 0 && pp.accounts >= p.cfg.MaxAccountsPerProxy {
+			continue
+		}
+		if best == nil || pp.accounts < best.accounts {
+			best = pp
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	if old, ok := p.assigned[account]; ok {
+		old.accounts--
+	}
+	best.accounts++
+	p.assigned[account] = best
+	return best.url, true
+}
+
+// Check health-checks every proxy once, concurrently. Proxies that pass are
+// returned to rotation; proxies that fail are benched for Cooldown.
+func (p *ProxyPool) Check(ctx context.Context) {
+	p.mu.Lock()
+	urls := make([]string, len(p.proxies))
+	for i, pp := range p.proxies {
+		urls[i] = pp.url
+	}
+	p.mu.Unlock()
+
+	errs := make([]error, len(urls))
+	runBounded(ctx, len(urls), defaultBulkConcurrency, func(i int) {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			return
+		}
+		errs[i] = p.cfg.Checker(ctx, urls[i])
+	})
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, u := range urls {
+		pp := p.findLocked(u)
+		if pp == nil {
+			continue // removed during the sweep
+		}
+		if errs[i] != nil {
+			pp.fails++
+			pp.downUntil = now.Add(p.cfg.Cooldown)
+			slog.Warn("proxy health check failed",
+				slog.String("proxy", stealth.MaskProxy(u)), slog.Any("error", errs[i]))
+			continue
+		}
+		pp.fails = 0
+		pp.downUntil = time.Time{}
+	}
+}
+
+// Run health-checks the pool every CheckInterval until ctx is done.
+func (p *ProxyPool) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		p.Check(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Status returns a snapshot of every proxy in the pool.
+func (p *ProxyPool) Status() []ProxyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	out := make([]ProxyStatus, len(p.proxies))
+	for i, pp := range p.proxies {
+		out[i] = ProxyStatus{
+			URL:       redactProxy(pp.url),
+			Healthy:   pp.healthy(now),
+			DownUntil: pp.downUntil,
+			Fails:     pp.fails,
+			Accounts:  pp.accounts,
+		}
+	}
+	return out
+}
+
+func (p *ProxyPool) findLocked(u string) *pooledProxy {
+	for _, pp := range p.proxies {
+		if pp.url == u {
+			return pp
+		}
+	}
+	return nil
+}
+
+// proxyCheckTimeout bounds one default health check. go-stealth cannot
+// abort a request in flight, so the transport timeout is what ends a check
+// whose context was cancelled.
+const proxyCheckTimeout = 15 * time.Second
+
+// checkProxy returns the default health check: GET checkURL through the proxy.
+func checkProxy(checkURL string) func(ctx context.Context, proxy string) error {
+	return func(ctx context.Context, proxy string) error {
+		timeout := proxyCheckTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = min(timeout, time.Until(deadline))
+		}
+		bc, err := stealth.NewClient(stealth.WithProxy(proxy), stealth.WithHeaderOrder(twitterHeaderOrder),
+			stealth.WithTimeout(max(int(timeout.Round(time.Second)/time.Second), 1)))
+		if err != nil {
+			return err
+		}
+		_, _, status, err := bc.DoWithHeaderOrderCtx(ctx, "GET", checkURL, map[string]string{}, nil, twitterHeaderOrder)
+		if err != nil {
+			return err
+		}
+		if status != 200 {
+			return fmt.Errorf("health check HTTP %d", status)
+		}
+		return nil
+	}
+}
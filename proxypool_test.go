@@ -0,0 +1,119 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyPool_AssignSticky(t *testing.T) {
+	p := NewProxyPool([]string{"http://p1", "http://p2"}, ProxyPoolConfig{})
+
+	a1, ok := p.Assign("alice")
+	require.True(t, ok)
+	b1, ok := p.Assign("bob")
+	require.True(t, ok)
+	assert.NotEqual(t, a1, b1, "accounts spread over the least-loaded proxies")
+
+	a2, _ := p.Assign("alice")
+	assert.Equal(t, a1, a2, "assignment is sticky")
+}
+
+func TestProxyPool_ReportDown(t *testing.T) {
+	p := NewProxyPool([]string{"http://p1", "http://p2"}, ProxyPoolConfig{})
+	first, _ := p.Assign("alice")
+
+	next, ok := p.ReportDown("alice")
+	require.True(t, ok)
+	assert.NotEqual(t, first, next)
+
+	// Both proxies down: nothing left to move to.
+	_, ok = p.ReportDown("alice")
+	assert.False(t, ok)
+
+	var healthy int
+	for _, s := range p.Status() {
+		if s.Healthy {
+			healthy++
+		}
+	}
+	assert.Zero(t, healthy)
+}
+
+func TestProxyPool_MaxAccountsPerProxy(t *testing.T) {
+	p := NewProxyPool([]string{"http://p1"}, ProxyPoolConfig{MaxAccountsPerProxy: 1})
+	_, ok := p.Assign("alice")
+	require.True(t, ok)
+	_, ok = p.Assign("bob")
+	assert.False(t, ok)
+}
+
+func TestProxyPool_Check(t *testing.T) {
+	p := NewProxyPool([]string{"http://good", "http://bad"}, ProxyPoolConfig{
+		Cooldown: time.Hour,
+		Checker: func(_ context.Context, proxy string) error {
+			if proxy == "http://bad" {
+				return errors.New("refused")
+			}
+			return nil
+		},
+	})
+	p.ReportDown("nobody") // no-op for unassigned accounts
+	p.Check(context.Background())
+
+	st := p.Status()
+	require.Len(t, st, 2)
+	assert.True(t, st[0].Healthy)
+	assert.False(t, st[1].Healthy)
+	assert.Equal(t, 1, st[1].Fails)
+
+	got, ok := p.Assign("alice")
+	require.True(t, ok)
+	assert.Equal(t, "http://good", got)
+}
+
+func TestMarkProxyDown_SwapsPooledProxy(t *testing.T) {
+	pp := NewProxyPool([]string{"http://p1", "http://p2"}, ProxyPoolConfig{})
+	acc := &Account{Username: "alice", pooledProxy: true}
+	acc.Proxy, _ = pp.Assign("alice")
+	old := acc.Proxy
+
+	c := &Client{cfg: ClientConfig{ProxyPool: pp, ProxyBackoffInitial: time.Minute, ProxyBackoffMax: time.Hour}}
+	c.markProxyDown(acc)
+
+	assert.NotEqual(t, old, acc.Proxy)
+	assert.True(t, acc.proxyBackoff.IsZero(), "swapped accounts are not backed off")
+	assert.NotNil(t, acc.client)
+
+	// With every proxy down the account falls back to backoff.
+	c.markProxyDown(acc)
+	assert.True(t, acc.proxyBackoff.After(time.Now()))
+}
+
+type staticProvider struct {
+	proxies []string
+	err     error
+}
+
+func (s staticProvider) Proxies(context.Context) ([]string, error) { return s.proxies, s.err }
+
+func TestProxyPool_Refresh(t *testing.T) {
+	p := NewProxyPool([]string{"http://p1", "http://p2"}, ProxyPoolConfig{})
+	p.Assign("alice")
+	p.ReportDown("alice") // benches p1 and moves alice to p2
+
+	require.NoError(t, p.Refresh(context.Background(), staticProvider{proxies: []string{"http://p1", "http://p3"}}))
+	st := p.Status()
+	require.Len(t, st, 2)
+	assert.Equal(t, "http://p1", st[0].URL)
+	assert.False(t, st[0].Healthy, "kept proxies keep their health state")
+	assert.Equal(t, "http://p3", st[1].URL)
+
+	assert.Error(t, p.Refresh(context.Background(), staticProvider{}))
+	assert.Error(t, p.Refresh(context.Background(), staticProvider{err: errors.New("boom")}))
+	assert.Len(t, p.Status(), 2, "failed refresh leaves the pool untouched")
+}
@@ -0,0 +1,96 @@
+package proxyprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	brightDataBaseURL = "https://api.brightdata.com"
+	brightDataGateway = "brd.superproxy.io"
+	brightDataPort    = 33335
+
+	// sessionPlaceholder is the session placeholder understood by the client.
+	sessionPlaceholder = "{session}"
+)
+
+// BrightData builds proxies for a Bright Data zone. For zones with allocated
+// IPs (datacenter, ISP) it lists them through the API and returns one proxy
+// pinned to each IP; for zones without (residential) it returns a single
+// gateway proxy with a {session} placeholder that the client fills per
+// account.
+type BrightData struct {
+	// APIToken authorizes the zone IP listing.
+	APIToken string
+
+	// Customer and Zone form the proxy username; Password is the zone password.
+	Customer string
+	Zone     string
+	Password string
+
+	// BaseURL overrides the API host. Default: https://api.brightdata.com.
+	BaseURL string
+
+	// Gateway overrides the super proxy host:port. Default: brd.superproxy.io:33335.
+	Gateway string
+
+	// HTTP overrides the HTTP client. Default: 30s timeout client.
+	HTTP *http.Client
+}
+
+// Proxies returns one proxy per allocated zone IP, or the session-templated
+// gateway proxy for zones without allocated IPs.
+func (b *BrightData) Proxies(ctx context.Context) ([]string, error) {
+	ips, err := b.routeIPs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user := fmt.Sprintf("brd-customer-%s-zone-%s", b.Customer, b.Zone)
+	host, port := b.gateway()
+
+	var out []string
+	for _, ip := range ips {
+		out = append(out, proxyURL(user+"-ip-"+ip, b.Password, host, port))
+	}
+	if len(out) == 0 {
+		// Userinfo escaping turns the placeholder braces into %7B/%7D.
+		tmpl := proxyURL(user+"-session-"+sessionPlaceholder, b.Password, host, port)
+		out = append(out, strings.Replace(tmpl, url.PathEscape(sessionPlaceholder), sessionPlaceholder, 1))
+	}
+	return out, nil
+}
+
+// routeIPs lists the IPs allocated to the zone. The endpoint answers with
+// either a JSON array or one IP per line, depending on the zone type.
+func (b *BrightData) routeIPs(ctx context.Context) ([]string, error) {
+	base := strings.TrimRight(b.BaseURL, "/")
+	if base == "" {
+		base = brightDataBaseURL
+	}
+	endpoint := base + "/zone/route_ips?" + url.Values{"zone": {b.Zone}}.Encode()
+	body, err := get(ctx, httpClient(b.HTTP), "brightdata", endpoint, "Authorization", "Bearer "+b.APIToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+	if err := json.Unmarshal(body, &ips); err != nil {
+		ips = strings.Fields(string(body))
+	}
+	return ips, nil
+}
+
+func (b *BrightData) gateway() (string, int) {
+	if b.Gateway == "" {
+		return brightDataGateway, brightDataPort
+	}
+	host, port, err := splitHostPort(b.Gateway)
+	if err != nil {
+		return brightDataGateway, brightDataPort
+	}
+	return host, port
+}
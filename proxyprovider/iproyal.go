@@ -0,0 +1,64 @@
+package proxyprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const iproyalBaseURL = "https://apid.iproyal.com"
+
+// IPRoyal lists the proxies of an IPRoyal ISP or datacenter order through the
+// reseller API.
+type IPRoyal struct {
+	// APIToken is the reseller API access token.
+	APIToken string
+
+	// OrderID selects the order whose proxies are returned.
+	OrderID string
+
+	// BaseURL overrides the API host. Default: https://apid.iproyal.com.
+	BaseURL string
+
+	// HTTP overrides the HTTP client. Default: 30s timeout client.
+	HTTP *http.Client
+}
+
+type iproyalOrder struct {
+	ProxyData struct {
+		Ports   map[string]int `json:"ports"`
+		Proxies []struct {
+			IP       string `json:"ip"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"proxies"`
+	} `json:"proxy_data"`
+}
+
+// Proxies returns the HTTP proxies of the order.
+func (r *IPRoyal) Proxies(ctx context.Context) ([]string, error) {
+	base := strings.TrimRight(r.BaseURL, "/")
+	if base == "" {
+		base = iproyalBaseURL
+	}
+	var order iproyalOrder
+	err := getJSON(ctx, httpClient(r.HTTP), "iproyal", base+"/v1/reseller/orders/"+r.OrderID,
+		"X-Access-Token", "Bearer "+r.APIToken, &order)
+	if err != nil {
+		return nil, err
+	}
+
+	port := order.ProxyData.Ports["http|https"]
+	if port == 0 {
+		port = order.ProxyData.Ports["http"]
+	}
+	if port == 0 {
+		return nil, fmt.Errorf("iproyal order %s has no HTTP port", r.OrderID)
+	}
+	out := make([]string, 0, len(order.ProxyData.Proxies))
+	for _, p := range order.ProxyData.Proxies {
+		out = append(out, proxyURL(p.Username, p.Password, p.IP, port))
+	}
+	return out, nil
+}
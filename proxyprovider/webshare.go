@@ -0,0 +1,64 @@
+package proxyprovider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const webshareBaseURL = "https://proxy.webshare.io"
+
+// Webshare lists the proxies of a Webshare account through its v2 API.
+type Webshare struct {
+	APIKey string
+
+	// BaseURL overrides the API host. Default: https://proxy.webshare.io.
+	BaseURL string
+
+	// HTTP overrides the HTTP client. Default: 30s timeout client.
+	HTTP *http.Client
+}
+
+type webshareList struct {
+	Next    *string `json:"next"`
+	Results []struct {
+		Username     string `json:"username"`
+		Password     string `json:"password"`
+		ProxyAddress string `json:"proxy_address"`
+		Port         int    `json:"port"`
+		Valid        bool   `json:"valid"`
+	} `json:"results"`
+}
+
+// Proxies returns every valid direct-mode proxy on the account, following
+// pagination.
+func (w *Webshare) Proxies(ctx context.Context) ([]string, error) {
+	base := strings.TrimRight(w.BaseURL, "/")
+	if base == "" {
+		base = webshareBaseURL
+	}
+	next := base + "/api/v2/proxy/list/?" + url.Values{
+		"mode":      {"direct"},
+		"page":      {"1"},
+		"page_size": {"100"},
+	}.Encode()
+
+	var out []string
+	for next != "" {
+		var page webshareList
+		if err := getJSON(ctx, httpClient(w.HTTP), "webshare", next, "Authorization", "Token "+w.APIKey, &page); err != nil {
+			return nil, err
+		}
+		for _, p := range page.Results {
+			if p.Valid {
+				out = append(out, proxyURL(p.Username, p.Password, p.ProxyAddress, p.Port))
+			}
+		}
+		next = ""
+		if page.Next != nil {
+			next = *page.Next
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,48 @@
+package twitter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderProxy(t *testing.T) {
+	got := renderProxy("http://user-{account}-session-{rand}:pw@gate:7777", "abc", "alice")
+	assert.Equal(t, "http://user-alice-session-abc:pw@gate:7777", got)
+	assert.True(t, isProxyTemplate("http://u-{session}:p@h:1"))
+	assert.False(t, isProxyTemplate("http://u:p@h:1"))
+}
+
+func TestRotateProxySession(t *testing.T) {
+	acc := &Account{Username: "alice", Proxy: "http://u-{session}:p@gate:1", ProxySessionRequests: 2}
+	first := acc.renderedProxy(acc.Proxy)
+	assert.NotContains(t, first, "{session}")
+	assert.Equal(t, first, acc.renderedProxy(acc.Proxy), "session is stable between requests")
+
+	acc.rotateProxySession(false) // request 1
+	acc.rotateProxySession(false) // request 2
+	assert.Equal(t, first, acc.renderedProxy(acc.Proxy))
+
+	acc.rotateProxySession(false) // request 3 starts a new session
+	second := acc.renderedProxy(acc.Proxy)
+	assert.NotEqual(t, first, second)
+	assert.NotNil(t, acc.client)
+
+	acc.rotateProxySession(true) // login
+	assert.NotEqual(t, second, acc.renderedProxy(acc.Proxy))
+}
+
+func TestRotateProxySession_PerLoginOnly(t *testing.T) {
+	acc := &Account{Username: "bob", Proxy: "http://u-{session}:p@gate:1"}
+	first := acc.renderedProxy(acc.Proxy)
+	for range 100 {
+		acc.rotateProxySession(false)
+	}
+	assert.Equal(t, first, acc.renderedProxy(acc.Proxy))
+
+	plain := &Account{Username: "carol", Proxy: "http://u:p@gate:1"}
+	plain.rotateProxySession(true)
+	assert.Nil(t, plain.client, "plain proxies are never rebuilt")
+	assert.True(t, strings.HasPrefix(plain.renderedProxy(plain.Proxy), "http://u:p@"))
+}
@@ -0,0 +1,113 @@
+package twitter
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// ActionClass groups write operations that share a daily cap.
+type ActionClass string
+
+const (
+	ActionTweet   ActionClass = "tweet" // tweets, replies, retweets
+	ActionFollow  ActionClass = "follow"
+	ActionLike    ActionClass = "like"
+	ActionDM      ActionClass = "dm"
+	ActionProfile ActionClass = "profile" // profile, avatar and banner edits
+)
+
+// actionClasses maps write endpoints to the class whose quota they draw on.
+// Endpoints not listed are not capped.
+var actionClasses = map[string]ActionClass{
+	"CreateTweet":         ActionTweet,
+	"CreateNoteTweet":     ActionTweet,
+	"CreateRetweet":       ActionTweet,
+	"FavoriteTweet":       ActionLike,
+	"UpdateProfile":       ActionProfile,
+	"UpdateProfileImage":  ActionProfile,
+	"UpdateProfileBanner": ActionProfile,
+}
+
+// ErrQuotaExceeded is returned by write operations once the account has used
+// its daily quota for the action class (see ClientConfig.ActionQuotas).
+var ErrQuotaExceeded = errors.New("daily action quota exceeded")
+
+// quotaDay is the day counters are kept for: the UTC date.
+func quotaDay(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// reserveAction counts one class action against limit for today, or returns
+// ErrQuotaExceeded if the day's quota is used up.
+func (a *Account) reserveAction(class ActionClass, limit int, now time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rollActionsLocked(now)
+	if a.actions[class] >= limit {
+		return fmt.Errorf("%s: %w (%d %s actions per day)", a.Username, ErrQuotaExceeded, limit, class)
+	}
+	if a.actions == nil {
+		a.actions = make(map[ActionClass]int)
+	}
+	a.actions[class]++
+	return nil
+}
+
+// releaseAction returns a reservation for an action that was not carried out.
+func (a *Account) releaseAction(class ActionClass, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.actionsDay == quotaDay(now) && a.actions[class] > 0 {
+		a.actions[class]--
+	}
+}
+
+// rollActionsLocked starts a fresh count when the day has changed.
+func (a *Account) rollActionsLocked(now time.Time) {
+	if day := quotaDay(now); a.actionsDay != day {
+		a.actionsDay = day
+		a.actions = nil
+	}
+}
+
+// actionsLeft returns how many class actions the account has left today
+// under limit.
+func (a *Account) actionsLeft(class ActionClass, limit int, now time.Time) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rollActionsLocked(now)
+	return limit - a.actions[class]
+}
+
+// actionsToday returns today's action counts; nil if there are none.
+func (a *Account) actionsToday(now time.Time) map[ActionClass]int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.actionsDay != quotaDay(now) || len(a.actions) == 0 {
+		return nil
+	}
+	return maps.Clone(a.actions)
+}
+
+// withQuota runs write, which performs one endpoint action for acc, under the
+// configured daily quota of the endpoint's class. A failed write gives its
+// reservation back; counts are persisted with account health.
+func (c *Client) withQuota(acc *Account, endpoint string, write func() ([]byte, error)) ([]byte, error) {
+	class, ok := actionClasses[endpoint]
+	limit := c.cfg.ActionQuotas[class]
+	if !ok || limit <= 0 {
+		return write()
+	}
+	if err := acc.reserveAction(class, limit, time.Now()); err != nil {
+		return nil, fmt.Errorf("%s: %w", endpoint, err)
+	}
+	body, err := write()
+	if err != nil {
+		acc.releaseAction(class, time.Now())
+		return nil, err
+	}
+	c.health.schedule(c)
+	return body, nil
+}
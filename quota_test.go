@@ -0,0 +1,83 @@
+package twitter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithQuota(t *testing.T) {
+	c := &Client{cfg: ClientConfig{ActionQuotas: map[ActionClass]int{ActionTweet: 2}}}
+	acc := &Account{Username: "alice"}
+	ok := func() ([]byte, error) { return []byte("{}"), nil }
+	fail := func() ([]byte, error) { return nil, errors.New("boom") }
+
+	_, err := c.withQuota(acc, "CreateTweet", ok)
+	require.NoError(t, err)
+	_, err = c.withQuota(acc, "CreateTweet", fail)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrQuotaExceeded, "a failed write gives its reservation back")
+	_, err = c.withQuota(acc, "CreateTweet", ok)
+	require.NoError(t, err)
+
+	_, err = c.withQuota(acc, "CreateTweet", ok)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.Equal(t, map[ActionClass]int{ActionTweet: 2}, acc.actionsToday(time.Now()))
+
+	// Uncapped classes and endpoints pass.
+	_, err = c.withQuota(acc, "UpdateProfile", ok)
+	assert.NoError(t, err)
+	_, err = c.withQuota(acc, "SomethingElse", ok)
+	assert.NoError(t, err)
+}
+
+func TestActionQuota_DayRollover(t *testing.T) {
+	acc := &Account{Username: "alice"}
+	day1 := time.Date(2025, 3, 18, 23, 59, 0, 0, time.UTC)
+	require.NoError(t, acc.reserveAction(ActionLike, 1, day1))
+	assert.ErrorIs(t, acc.reserveAction(ActionLike, 1, day1), ErrQuotaExceeded)
+	assert.NoError(t, acc.reserveAction(ActionLike, 1, day1.Add(2*time.Minute)))
+}
+
+func TestActionQuota_Persisted(t *testing.T) {
+	now := time.Now()
+	acc := &Account{Username: "alice"}
+	require.NoError(t, acc.reserveAction(ActionFollow, 10, now))
+	s := acc.snapshotHealth(now)
+
+	restored := &Account{Username: "alice"}
+	restored.restoreHealth(s, now)
+	assert.Equal(t, map[ActionClass]int{ActionFollow: 1}, restored.actionsToday(now))
+
+	stale := &Account{Username: "alice"}
+	stale.restoreHealth(s, now.Add(48*time.Hour))
+	assert.Nil(t, stale.actionsToday(now.Add(48*time.Hour)))
+}
+
+func TestActionQuota_PersistedWithoutPersistHealth(t *testing.T) {
+	dir := t.TempDir()
+	newClient := func() *Client {
+		c, err := NewClient(ClientConfig{
+			Accounts:     []*Account{{Username: "alice", AuthToken: "tok-alice", CT0: "c"}},
+			SessionDir:   dir,
+			Transport:    &tokenTransport{},
+			ActionQuotas: map[ActionClass]int{ActionFollow: 10},
+		})
+		require.NoError(t, err)
+		return c
+	}
+	c := newClient()
+	require.NotNil(t, c.health, "quotas alone enable saving")
+	acc := c.pool.Items()[0]
+	require.NoError(t, acc.reserveAction(ActionFollow, 10, time.Now()))
+	acc.RecordFailure()
+	require.NoError(t, c.SaveHealth())
+
+	acc = newClient().pool.Items()[0]
+	assert.Equal(t, map[ActionClass]int{ActionFollow: 1}, acc.actionsToday(time.Now()))
+	_, failed, _ := acc.Stats()
+	assert.Zero(t, failed, "the rest of account health is not restored without PersistHealth")
+}
@@ -0,0 +1,140 @@
+// Package redispool shares a go-twitter account pool between processes
+// through Redis. Give every Client the same accounts and a Coordinator on
+// the same Redis and prefix:
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	client, err := twitter.NewClient(twitter.ClientConfig{
+//		Accounts:    accounts,
+//		Coordinator: redispool.New(rdb, redispool.Options{}),
+//	})
+//
+// Leases are keys set with NX and an expiry, holding a token unique to the
+// lease, so a crashed process holds its accounts only until the lease runs
+// out and two requests of one process never share an account. Budgets are fixed-window
+// counters per account and endpoint. Keys are hash-tagged by account, so a
+// redis.ClusterClient works as well.
+package redispool
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+	"github.com/redis/go-redis/v9"
+)
+
+// Options configures a Coordinator.
+type Options struct {
+	// Prefix namespaces the keys, so that several account inventories can
+	// share one Redis. Default: "twitter:pool:".
+	Prefix string
+	// Owner prefixes this process's lease tokens, telling which process
+	// holds an account. Default: random.
+	Owner string
+}
+
+// Coordinator implements twitter.Coordinator on Redis.
+type Coordinator struct {
+	rdb    redis.UniversalClient
+	prefix string
+	owner  string
+}
+
+var _ twitter.Coordinator = (*Coordinator)(nil)
+
+// New returns a Coordinator keeping its state in rdb.
+func New(rdb redis.UniversalClient, opts Options) *Coordinator {
+	if opts.Prefix == "" {
+		opts.Prefix = "twitter:pool:"
+	}
+	if opts.Owner == "" {
+		opts.Owner = randomHex()
+	}
+	return &Coordinator{rdb: rdb, prefix: opts.Prefix, owner: opts.Owner}
+}
+
+// The keys of an account carry its username as a hash tag, so that a
+// script touching several of them runs on one Redis Cluster slot.
+
+func (c *Coordinator) leaseKey(username string) string {
+	return c.prefix + "{" + username + "}:lease"
+}
+
+func (c *Coordinator) limitedKey(username, endpoint string) string {
+	return c.prefix + "{" + username + "}:limited:" + endpoint
+}
+
+func (c *Coordinator) spentKey(username, endpoint string, bucket int64) string {
+	return c.prefix + "{" + username + "}:spent:" + endpoint + ":" + strconv.FormatInt(bucket, 10)
+}
+
+// Lease claims username for ttl unless it is leased already, to this
+// process or another.
+func (c *Coordinator) Lease(ctx context.Context, username string, ttl time.Duration) (string, error) {
+	token := c.owner + ":" + randomHex()
+	ok, err := c.rdb.SetNX(ctx, c.leaseKey(username), token, ttl).Result()
+	if err != nil || !ok {
+		return "", err
+	}
+	return token, nil
+}
+
+// Unlease releases username if token still holds it.
+func (c *Coordinator) Unlease(ctx context.Context, username, token string) error {
+	return unleaseScript.Run(ctx, c.rdb, []string{c.leaseKey(username)}, token).Err()
+}
+
+// Spend counts a request in the current window of username's endpoint.
+func (c *Coordinator) Spend(ctx context.Context, username, endpoint string, limit int, window time.Duration) (bool, error) {
+	w := window.Milliseconds()
+	if w <= 0 || limit <= 0 {
+		return true, nil
+	}
+	bucket := time.Now().UnixMilli() / w
+	keys := []string{c.limitedKey(username, endpoint), c.spentKey(username, endpoint, bucket)}
+	return spendScript.Run(ctx, c.rdb, keys, limit, w).Bool()
+}
+
+// MarkRateLimited blocks username's endpoint for every client until until.
+func (c *Coordinator) MarkRateLimited(ctx context.Context, username, endpoint string, until time.Time) error {
+	d := time.Until(until)
+	if d <= 0 {
+		return nil
+	}
+	return c.rdb.Set(ctx, c.limitedKey(username, endpoint), 1, d).Err()
+}
+
+// unleaseScript deletes KEYS[1] if lease token ARGV[1] holds it.
+var unleaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// spendScript counts one request in counter KEYS[2], which expires with
+// its ARGV[2] ms window, unless KEYS[1] marks a rate limit or the count
+// would exceed ARGV[1].
+var spendScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local n = tonumber(redis.call("GET", KEYS[2]) or "0")
+if n >= tonumber(ARGV[1]) then
+	return 0
+end
+if redis.call("INCR", KEYS[2]) == 1 then
+	redis.call("PEXPIRE", KEYS[2], ARGV[2])
+end
+return 1
+`)
+
+// randomHex returns 16 random hex digits.
+func randomHex() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
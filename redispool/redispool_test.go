@@ -0,0 +1,90 @@
+package redispool
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPair(t *testing.T) (*miniredis.Miniredis, *Coordinator, *Coordinator) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return mr, New(rdb, Options{Owner: "a"}), New(rdb, Options{Owner: "b"})
+}
+
+func TestLease(t *testing.T) {
+	mr, a, b := newPair(t)
+	ctx := context.Background()
+
+	tok, err := a.Lease(ctx, "alice", time.Minute)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tok)
+	other, err := b.Lease(ctx, "alice", time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, other, "held by another process")
+	again, err := a.Lease(ctx, "alice", time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again, "held by another request of the same process")
+
+	require.NoError(t, b.Unlease(ctx, "alice", "b:stolen"))
+	require.NoError(t, a.Unlease(ctx, "alice", "a:stale"))
+	other, _ = b.Lease(ctx, "alice", time.Minute)
+	assert.Empty(t, other, "only the lease's own token releases it")
+
+	require.NoError(t, a.Unlease(ctx, "alice", tok))
+	other, _ = b.Lease(ctx, "alice", time.Minute)
+	assert.NotEmpty(t, other)
+
+	mr.FastForward(2 * time.Minute)
+	tok, _ = a.Lease(ctx, "alice", time.Minute)
+	assert.NotEmpty(t, tok, "an expired lease is free")
+}
+
+func TestSpendAndRateLimit(t *testing.T) {
+	mr, a, b := newPair(t)
+	ctx := context.Background()
+
+	for i, c := range []*Coordinator{a, b, a} {
+		ok, err := c.Spend(ctx, "alice", "Followers", 3, time.Hour)
+		require.NoError(t, err)
+		assert.True(t, ok, "request %d", i)
+	}
+	ok, err := b.Spend(ctx, "alice", "Followers", 3, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, ok, "the budget is shared")
+	ok, _ = b.Spend(ctx, "bob", "Followers", 3, time.Hour)
+	assert.True(t, ok, "budgets are per account")
+
+	require.NoError(t, a.MarkRateLimited(ctx, "bob", "Followers", time.Now().Add(time.Minute)))
+	ok, _ = b.Spend(ctx, "bob", "Followers", 3, time.Hour)
+	assert.False(t, ok, "a 429 seen by one client rests the account for all")
+	mr.FastForward(2 * time.Minute)
+	ok, _ = b.Spend(ctx, "bob", "Followers", 3, time.Hour)
+	assert.True(t, ok)
+}
+
+// hashTag returns the part of key Redis Cluster hashes to pick its slot.
+func hashTag(key string) string {
+	if open := strings.IndexByte(key, '{'); open >= 0 {
+		if n := strings.IndexByte(key[open+1:], '}'); n > 0 {
+			return key[open+1 : open+1+n]
+		}
+	}
+	return key
+}
+
+func TestKeysShareSlot(t *testing.T) {
+	c := New(nil, Options{})
+	limited := c.limitedKey("alice", "Followers")
+	assert.Equal(t, "alice", hashTag(limited))
+	assert.Equal(t, hashTag(limited), hashTag(c.spentKey("alice", "Followers", 42)), "spendScript keys")
+	assert.Equal(t, hashTag(limited), hashTag(c.leaseKey("alice")))
+}
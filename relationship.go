@@ -0,0 +1,88 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Relationship is the friendship status between two users, as seen from
+// the source user.
+type Relationship struct {
+	SourceID     string
+	SourceHandle string
+	TargetID     string
+	TargetHandle string
+
+	Following  bool // source follows target
+	FollowedBy bool // target follows source
+
+	// Blocking, BlockedBy, Muting and CanDM are only reported when the
+	// source is the pool account that served the request; otherwise they
+	// are false.
+	Blocking  bool
+	BlockedBy bool
+	Muting    bool
+	CanDM     bool
+}
+
+// GetRelationship returns the friendship status between sourceID and
+// targetID via friendships/show.
+func (c *Client) GetRelationship(ctx context.Context, sourceID, targetID string) (*Relationship, error) {
+	if err := validateUserID(sourceID); err != nil {
+		return nil, err
+	}
+	if err := validateUserID(targetID); err != nil {
+		return nil, err
+	}
+	q := url.Values{"source_id": {sourceID}, "target_id": {targetID}}
+	body, _, err := c.doGET(ctx, "FriendshipsShow", friendshipsShowURL+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("FriendshipsShow: %w", err)
+	}
+	return parseRelationship(body)
+}
+
+// parseRelationship parses a friendships/show response. Flags the API
+// leaves null are read as false.
+func parseRelationship(body []byte) (*Relationship, error) {
+	var raw struct {
+		Relationship *struct {
+			Source struct {
+				IDStr      string `json:"id_str"`
+				ScreenName string `json:"screen_name"`
+				Following  bool   `json:"following"`
+				FollowedBy bool   `json:"followed_by"`
+				Blocking   *bool  `json:"blocking"`
+				BlockedBy  *bool  `json:"blocked_by"`
+				Muting     *bool  `json:"muting"`
+				CanDM      *bool  `json:"can_dm"`
+			} `json:"source"`
+			Target struct {
+				IDStr      string `json:"id_str"`
+				ScreenName string `json:"screen_name"`
+			} `json:"target"`
+		} `json:"relationship"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal relationship: %w", err)
+	}
+	if raw.Relationship == nil {
+		return nil, fmt.Errorf("relationship missing from response: %s", truncateBytes(body, 200))
+	}
+	src, tgt := raw.Relationship.Source, raw.Relationship.Target
+	flag := func(b *bool) bool { return b != nil && *b }
+	return &Relationship{
+		SourceID:     src.IDStr,
+		SourceHandle: src.ScreenName,
+		TargetID:     tgt.IDStr,
+		TargetHandle: tgt.ScreenName,
+		Following:    src.Following,
+		FollowedBy:   src.FollowedBy,
+		Blocking:     flag(src.Blocking),
+		BlockedBy:    flag(src.BlockedBy),
+		Muting:       flag(src.Muting),
+		CanDM:        flag(src.CanDM),
+	}, nil
+}
@@ -0,0 +1,33 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelationship(t *testing.T) {
+	body := []byte(`{"relationship":{
+		"source":{"id_str":"12","screen_name":"jack","following":true,"followed_by":false,
+			"blocking":null,"blocked_by":null,"muting":true,"can_dm":false},
+		"target":{"id_str":"13","screen_name":"biz","following":false,"followed_by":true}}}`)
+	r, err := parseRelationship(body)
+	require.NoError(t, err)
+	assert.Equal(t, &Relationship{
+		SourceID: "12", SourceHandle: "jack", TargetID: "13", TargetHandle: "biz",
+		Following: true, Muting: true,
+	}, r)
+
+	_, err = parseRelationship([]byte(`{"errors":[{"code":163,"message":"You must specify either a source"}]}`))
+	assert.ErrorContains(t, err, "relationship missing")
+}
+
+func TestGetRelationship_ValidatesIDs(t *testing.T) {
+	c := &Client{}
+	_, err := c.GetRelationship(context.Background(), "jack", "13")
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "user_id", verr.Field)
+}
@@ -0,0 +1,131 @@
+package twitter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ReportFormat selects the output encoding of ExportPoolReport.
+type ReportFormat string
+
+const (
+	ReportCSV  ReportFormat = "csv"
+	ReportJSON ReportFormat = "json"
+)
+
+// PoolReportRow is one account line of a pool report.
+type PoolReportRow struct {
+	Username             string    `json:"username"`
+	Status               string    `json:"status"` // active, cooldown, deactivated
+	ReactivateAt         time.Time `json:"reactivate_at,omitzero"`
+	LastSuccessAt        time.Time `json:"last_success_at,omitzero"`
+	LastError            string    `json:"last_error,omitempty"`
+	LastErrorAt          time.Time `json:"last_error_at,omitzero"`
+	Requests             int       `json:"requests"`
+	Failures             int       `json:"failures"`
+	ConsecFailures       int       `json:"consec_failures"`
+	Relogins             int       `json:"relogins"`
+	CaptchaSolves        int       `json:"captcha_solves"`
+	Proxy                string    `json:"proxy,omitempty"`
+	ProxyBackoffUntil    time.Time `json:"proxy_backoff_until,omitzero"`
+	RateLimitedEndpoints int       `json:"rate_limited_endpoints"`
+	// RateLimitUtilization is the share of known endpoints currently
+	// rate-limited for the account, from 0 to 1.
+	RateLimitUtilization float64 `json:"rate_limit_utilization"`
+}
+
+// PoolReport builds one report row per account from Stats, sorted by username.
+func (c *Client) PoolReport() []PoolReportRow {
+	stats := c.Stats()
+	rows := make([]PoolReportRow, 0, len(stats.Accounts))
+	for _, s := range stats.Accounts {
+		rows = append(rows, reportRow(s, time.Now()))
+	}
+	return rows
+}
+
+// ExportPoolReport writes a per-account credential health report in the given
+// format, for periodic operational reviews. Proxy passwords are redacted.
+func (c *Client) ExportPoolReport(w io.Writer, format ReportFormat) error {
+	rows := c.PoolReport()
+	switch format {
+	case ReportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case ReportCSV:
+		return writeReportCSV(w, rows)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+func reportRow(s AccountStats, now time.Time) PoolReportRow {
+	status := "active"
+	if !s.Active {
+		status = "deactivated"
+		if s.ReactivateAt.After(now) {
+			status = "cooldown"
+		}
+	}
+	row := PoolReportRow{
+		Username:             s.Username,
+		Status:               status,
+		ReactivateAt:         s.ReactivateAt,
+		LastSuccessAt:        s.LastSuccessAt,
+		LastError:            s.LastError,
+		LastErrorAt:          s.LastErrorAt,
+		Requests:             s.Total,
+		Failures:             s.Failed,
+		ConsecFailures:       s.ConsecFails,
+		Relogins:             s.Relogins,
+		CaptchaSolves:        s.CaptchaSolves,
+		Proxy:                s.Proxy,
+		ProxyBackoffUntil:    s.ProxyBackoffUntil,
+		RateLimitedEndpoints: len(s.RateLimited),
+	}
+	if n := len(endpointNames()); n > 0 {
+		row.RateLimitUtilization = float64(len(s.RateLimited)) / float64(n)
+	}
+	return row
+}
+
+var reportCSVHeader = []string{
+	"username", "status", "reactivate_at", "last_success_at", "last_error", "last_error_at",
+	"requests", "failures", "consec_failures", "relogins", "captcha_solves",
+	"proxy", "proxy_backoff_until", "rate_limited_endpoints", "rate_limit_utilization",
+}
+
+func writeReportCSV(w io.Writer, rows []PoolReportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reportCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		rec := []string{
+			r.Username, r.Status, csvTime(r.ReactivateAt), csvTime(r.LastSuccessAt),
+			r.LastError, csvTime(r.LastErrorAt),
+			strconv.Itoa(r.Requests), strconv.Itoa(r.Failures), strconv.Itoa(r.ConsecFailures),
+			strconv.Itoa(r.Relogins), strconv.Itoa(r.CaptchaSolves),
+			r.Proxy, csvTime(r.ProxyBackoffUntil),
+			strconv.Itoa(r.RateLimitedEndpoints), strconv.FormatFloat(r.RateLimitUtilization, 'f', 3, 64),
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvTime formats t as RFC 3339, or empty for the zero time.
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
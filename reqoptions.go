@@ -0,0 +1,76 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrGuestFallbackDisabled is returned, wrapped with ErrPoolExhausted, when
+// no account could serve a call and guest tokens are ruled out by
+// ClientConfig.DisableGuestFallback or RequestOptions.NoGuestFallback.
+// Guest responses miss fields and cover shorter windows, so callers that
+// need authenticated data get this error instead.
+var ErrGuestFallbackDisabled = errors.New("guest fallback disabled")
+
+// RequestOptions adjusts how the calls made with a context are served; see
+// WithRequestOptions. The zero value changes nothing.
+type RequestOptions struct {
+	// NoGuestFallback fails a call the pool cannot serve instead of
+	// retrying it on a guest token, as ClientConfig.DisableGuestFallback
+	// does for every call.
+	NoGuestFallback bool
+	// Account pins the calls to the pool account with this username: they
+	// wait for it, skip the cache and never fall back to another account,
+	// a guest token, API v2 or a mirror.
+	Account string
+	// Priority queues the calls for a pool account, as WithPriority does.
+	// PriorityNormal leaves the context's priority as is.
+	Priority Priority
+	// MaxAttempts overrides the retry policy's attempts per call; 0 keeps
+	// ClientConfig.Retry.
+	MaxAttempts int
+	// NoCache skips ClientConfig.Cache: responses are neither read from nor
+	// stored in it.
+	NoCache bool
+}
+
+type requestOptionsKeyType struct{}
+
+// WithRequestOptions returns a context whose calls are served per opts,
+// e.g. a fresh lookup on a given account without guest fallback:
+//
+//	ctx = twitter.WithRequestOptions(ctx, twitter.RequestOptions{Account: "alice", NoCache: true})
+func WithRequestOptions(ctx context.Context, opts RequestOptions) context.Context {
+	if opts.Priority != PriorityNormal {
+		ctx = WithPriority(ctx, opts.Priority)
+	}
+	return context.WithValue(ctx, requestOptionsKeyType{}, opts)
+}
+
+// WithoutGuestFallback returns a context whose calls fail with
+// ErrGuestFallbackDisabled rather than fall back to a guest token, keeping
+// any other RequestOptions already set on ctx.
+func WithoutGuestFallback(ctx context.Context) context.Context {
+	o := requestOptionsFrom(ctx)
+	o.NoGuestFallback = true
+	return context.WithValue(ctx, requestOptionsKeyType{}, o)
+}
+
+// requestOptionsFrom returns the options set by WithRequestOptions, or the
+// zero value.
+func requestOptionsFrom(ctx context.Context) RequestOptions {
+	o, _ := ctx.Value(requestOptionsKeyType{}).(RequestOptions)
+	return o
+}
+
+// allows reports whether acc may serve a call made with o.
+func (o RequestOptions) allows(acc *Account) bool {
+	return o.Account == "" || strings.EqualFold(acc.Username, o.Account)
+}
+
+// guestAllowed reports whether a call made with o may fall back to a guest
+// token.
+func (o RequestOptions) guestAllowed() bool {
+	return !o.NoGuestFallback && o.Account == ""
+}
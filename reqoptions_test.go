@@ -0,0 +1,161 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenTransport records the auth_token of each request and fails them all
+// while fail is set. Each response is held back by delay.
+type tokenTransport struct {
+	mu     sync.Mutex
+	tokens []string
+	fail   bool
+	delay  time.Duration
+}
+
+func (tr *tokenTransport) Do(_ context.Context, _, _ string, headers map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	time.Sleep(tr.delay)
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tok, _, _ := strings.Cut(strings.TrimPrefix(headers["cookie"], "auth_token="), ";")
+	tr.tokens = append(tr.tokens, tok)
+	if tr.fail {
+		return nil, nil, 0, errors.New("connection reset")
+	}
+	return []byte(`{"data":{}}`), nil, 200, nil
+}
+
+func newOptionsClient(t *testing.T, tr *tokenTransport) *Client {
+	t.Helper()
+	c, err := NewClient(ClientConfig{
+		Accounts: []*Account{
+			{Username: "alice", AuthToken: "tok-alice", CT0: "c"},
+			{Username: "bob", AuthToken: "tok-bob", CT0: "c"},
+		},
+		SessionDir: t.TempDir(),
+		Transport:  tr,
+		Cache:      NewMemoryCache(),
+		Retry:      &RetryPolicy{MaxAttempts: 3},
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestRequestOptions_AccountAndCache(t *testing.T) {
+	tr := &tokenTransport{}
+	c := newOptionsClient(t, tr)
+	url := addGraphQLParams(Endpoints["UserByScreenName"].URL(), map[string]any{"screen_name": "jack"}, nil)
+
+	ctx := WithRequestOptions(context.Background(), RequestOptions{Account: "BOB"})
+	for range 4 {
+		_, _, err := c.doGET(ctx, "UserByScreenName", url)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"tok-bob", "tok-bob", "tok-bob", "tok-bob"}, tr.tokens, "pinned, and pinned calls skip the cache")
+
+	_, _, err := c.doGET(context.Background(), "UserByScreenName", url)
+	require.NoError(t, err)
+	_, _, err = c.doGET(context.Background(), "UserByScreenName", url)
+	require.NoError(t, err)
+	assert.Len(t, tr.tokens, 5, "the second default call is served from the cache")
+	_, _, err = c.doGET(WithRequestOptions(context.Background(), RequestOptions{NoCache: true}), "UserByScreenName", url)
+	require.NoError(t, err)
+	assert.Len(t, tr.tokens, 6, "NoCache skips the cached entry")
+
+	ctx = WithRequestOptions(context.Background(), RequestOptions{Account: "carol"})
+	_, _, err = c.doGET(ctx, "UserByScreenName", url)
+	assert.ErrorIs(t, err, ErrPoolExhausted)
+	assert.ErrorContains(t, err, `"carol"`)
+}
+
+func TestRequestOptions_MaxAttemptsAndGuest(t *testing.T) {
+	tr := &tokenTransport{fail: true}
+	c := newOptionsClient(t, tr)
+	url := addGraphQLParams(Endpoints["UserByScreenName"].URL(), map[string]any{"screen_name": "jack"}, nil)
+
+	ctx := WithRequestOptions(context.Background(), RequestOptions{MaxAttempts: 1, NoGuestFallback: true})
+	_, _, err := c.doGET(ctx, "UserByRestId", url)
+	require.ErrorIs(t, err, ErrPoolExhausted)
+	assert.ErrorIs(t, err, ErrGuestFallbackDisabled)
+	assert.Len(t, tr.tokens, 1, "one attempt and no guest token request")
+}
+
+func TestWithoutGuestFallback(t *testing.T) {
+	tr := &tokenTransport{}
+	c, err := NewClient(ClientConfig{
+		SessionDir: t.TempDir(),
+		Transport:  tr,
+	})
+	require.NoError(t, err)
+	url := addGraphQLParams(Endpoints["UserByScreenName"].URL(), map[string]any{"screen_name": "jack"}, nil)
+
+	ctx := WithRequestOptions(context.Background(), RequestOptions{MaxAttempts: 1})
+	ctx = WithoutGuestFallback(ctx)
+	assert.Equal(t, RequestOptions{MaxAttempts: 1, NoGuestFallback: true}, requestOptionsFrom(ctx))
+
+	_, _, err = c.doGET(ctx, "UserByRestId", url)
+	require.ErrorIs(t, err, ErrGuestFallbackDisabled)
+	assert.ErrorIs(t, err, ErrPoolExhausted)
+	assert.Empty(t, tr.tokens, "no guest token was requested")
+}
+
+func TestWithoutGuestFallback_DoesNotShareGuestResponses(t *testing.T) {
+	tr := &tokenTransport{delay: 100 * time.Millisecond}
+	c, err := NewClient(ClientConfig{
+		SessionDir: t.TempDir(),
+		Transport:  tr,
+		Cache:      NewMemoryCache(),
+	})
+	require.NoError(t, err)
+	c.setGuestToken("gt")
+	url := addGraphQLParams(Endpoints["UserByRestId"].URL(), map[string]any{"userId": "12"}, nil)
+	noGuest := WithoutGuestFallback(context.Background())
+
+	guestDone := make(chan error, 1)
+	go func() {
+		_, _, err := c.doGET(context.Background(), "UserByRestId", url)
+		guestDone <- err
+	}()
+	time.Sleep(20 * time.Millisecond) // the guest-allowed call is in flight
+	_, _, err = c.doGET(noGuest, "UserByRestId", url)
+	assert.ErrorIs(t, err, ErrGuestFallbackDisabled, "did not join the guest-allowed flight")
+	require.NoError(t, <-guestDone)
+
+	_, _, err = c.doGET(noGuest, "UserByRestId", url)
+	assert.ErrorIs(t, err, ErrGuestFallbackDisabled, "the guest-served body was not cached")
+	assert.Equal(t, []string{""}, tr.tokens, "one guest request")
+}
+
+func TestRequestOptions_SeparateFlights(t *testing.T) {
+	for name, opts := range map[string]RequestOptions{
+		"max attempts": {MaxAttempts: 1},
+		"priority":     {Priority: PriorityHigh},
+		"no cache":     {NoCache: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tr := &tokenTransport{delay: 100 * time.Millisecond}
+			c := newOptionsClient(t, tr)
+			url := addGraphQLParams(Endpoints["UserByRestId"].URL(), map[string]any{"userId": "12"}, nil)
+
+			done := make(chan error, 1)
+			go func() {
+				_, _, err := c.doGET(context.Background(), "UserByRestId", url)
+				done <- err
+			}()
+			time.Sleep(20 * time.Millisecond) // the default call is in flight
+			_, _, err := c.doGET(WithRequestOptions(context.Background(), opts), "UserByRestId", url)
+			require.NoError(t, err)
+			require.NoError(t, <-done)
+			assert.Len(t, tr.tokens, 2, "the call did not join the default flight")
+		})
+	}
+}
@@ -4,20 +4,81 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
 	stealth "github.com/anatolykoptev/go-stealth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-const maxRetries = 3
-
 // doGET executes a GET request with multi-account retry, ct0 rotation, relogin,
-// and guest-token fallback.
+// and guest-token fallback. Responses are served from and stored in
+// ClientConfig.Cache when the endpoint has a CacheTTL, and concurrent calls
+// for the same URL and RequestOptions share a single upstream request.
 func (c *Client) doGET(ctx context.Context, endpoint, url string) ([]byte, map[string]string, error) {
-	return c.doPoolRequest(ctx, "GET", endpoint, url, nil)
+	opts := requestOptionsFrom(ctx)
+	key, ttl := c.cacheLookup(endpoint, url)
+	if opts.NoCache || opts.Account != "" {
+		ttl = 0 // a pinned call is answered by its account, not the cache
+	}
+	if ttl > 0 {
+		if body, ok := c.cfg.Cache.Get(key); ok {
+			requestInfoFrom(ctx).cached()
+			return body, nil, nil
+		}
+	}
+	fetch := func(ctx context.Context) ([]byte, map[string]string, error) {
+		info := &RequestInfo{}
+		body, headers, err := c.doPoolRequest(WithRequestInfo(ctx, info), "GET", endpoint, url, nil)
+		if info.Attempts() > 0 {
+			requestInfoFrom(ctx).adopt(info, info.Attempts())
+		}
+		// Guest-served bodies are never cached: a later caller may refuse
+		// the guest fallback.
+		if err == nil && ttl > 0 && info.Source() != SourceGuest {
+			c.cfg.Cache.Set(key, body, ttl)
+		}
+		return body, headers, err
+	}
+	if opts.Account != "" {
+		// A call pinned to one account must not share another's response.
+		return fetch(ctx)
+	}
+
+	// Only calls served alike share a flight: one that refuses the guest
+	// fallback, caps its attempts or queues at another priority runs its own.
+	flightKey := fmt.Sprintf("%t %d %d %t %s", opts.guestAllowed(), opts.MaxAttempts, priorityFrom(ctx), opts.NoCache, url)
+	ch := c.flight.DoChan(flightKey, func() (any, error) {
+		body, headers, err := fetch(ctx)
+		return sharedResponse{body, headers}, err
+	})
+	select {
+	case r := <-ch:
+		if r.Err != nil && r.Shared && ctx.Err() == nil && isContextError(r.Err) {
+			// The caller that started the shared request gave up; ours is still live.
+			return fetch(ctx)
+		}
+		resp, _ := r.Val.(sharedResponse)
+		return resp.body, resp.headers, r.Err
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// sharedResponse carries a doGET result through the singleflight group.
+// Callers must treat body and headers as read-only.
+type sharedResponse struct {
+	body    []byte
+	headers map[string]string
+}
+
+// isContextError reports whether err stems from a cancelled or expired context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
 
 // doPoolPOST executes a POST request with the same pool-rotation, retry, and
@@ -29,59 +90,110 @@ func (c *Client) doPoolPOST(ctx context.Context, endpoint, url string, payload [
 // doPoolRequest executes a pool-rotated request (GET or POST) with retry, ct0 rotation,
 // relogin, and guest-token fallback.
 func (c *Client) doPoolRequest(ctx context.Context, method, endpoint, url string, payload []byte) ([]byte, map[string]string, error) {
+	ctx, span := c.startSpan(ctx, "twitter."+endpoint,
+		attribute.String("twitter.endpoint", endpoint),
+		attribute.String("http.method", method))
+	body, respHdrs, err := c.hedgedPoolRequest(ctx, span, method, endpoint, url, payload)
+	endSpan(span, err)
+	return body, respHdrs, err
+}
+
+// poolRequest implements doPoolRequest, reporting attempts and responses on span.
+func (c *Client) poolRequest(ctx context.Context, span trace.Span, method, endpoint, url string, payload []byte) ([]byte, map[string]string, error) {
 	// Anti-fingerprint jitter
-	if err := stealth.DefaultJitter.Sleep(ctx); err != nil {
+	if err := c.fingerprintJitter(ctx); err != nil {
+		return nil, nil, err
+	}
+	if err := c.adaptive.jitter(ctx); err != nil {
 		return nil, nil, err
 	}
 
 	var lastErr error
-	for attempt := range maxRetries {
+	downgrade := false
+	hedge := hedgeBranchFrom(ctx)
+	featuresRetried := false       // missing-feature recovery is tried once
+	var rejected map[*Account]bool // accounts whose response failed validation
+	labels := labelsFrom(ctx)
+	opts := requestOptionsFrom(ctx)
+	policy := c.retryPolicy(endpoint)
+	if opts.MaxAttempts > 0 {
+		policy.MaxAttempts = opts.MaxAttempts
+	}
+	if opts.Account != "" && c.AccountByUsername(opts.Account) == nil {
+		return nil, nil, fmt.Errorf("%s: account %q is not in the pool: %w", endpoint, opts.Account, ErrPoolExhausted)
+	}
+	release := func() {} // gives back the attempt's shared lease
+	defer func() { release() }()
+attempts:
+	for attempt := range policy.MaxAttempts {
+		release()
+		release = func() {}
 		if attempt > 0 {
-			delay := stealth.DefaultBackoff.Duration(attempt)
-			select {
-			case <-time.After(delay):
-			case <-ctx.Done():
-				return nil, nil, ctx.Err()
+			if err := policy.wait(ctx, attempt); err != nil {
+				return nil, nil, err
 			}
 		}
 
-		var acc *Account
-		var accErr error
-
-		filter := func(a *Account) bool {
-			return a.AllowRequest(endpoint) && time.Now().After(a.proxyBackoff)
+		eligible := func(a *Account) bool {
+			now := time.Now()
+			return !hedge.excludes(a) && !rejected[a] && opts.allows(a) && a.hasLabels(labels) && !a.offHours(now) &&
+				now.After(a.proxyBackoff)
 		}
 
-		if requiresAuth(endpoint) {
-			acc, accErr = c.pool.NextWithWait(ctx, filter, 5*time.Minute)
-		} else {
-			acc, accErr = c.pool.Next(filter)
+		var wait time.Duration
+		if requiresAuth(endpoint) || opts.Account != "" {
+			wait = c.accountWait(ctx, endpoint)
 		}
+		acc, leased, accErr := c.nextSharedAccount(ctx, endpoint, eligible, wait)
+		release = leased
 		if accErr != nil {
-			lastErr = accErr
+			if len(rejected) == 0 {
+				lastErr = accErr // otherwise the rejected responses are the cause
+			}
+			if c.canDowngradeToGuest(ctx, endpoint) && ctx.Err() == nil {
+				slog.Info("no account before deadline, downgrading to guest",
+					slog.String("endpoint", endpoint), slog.Any("error", accErr))
+				downgrade = true
+			}
 			break
 		}
+		hedge.claim(acc)
+		// The in-flight slot is taken once an account is picked, so
+		// requests queued for an account hold none.
+		if err := c.adaptive.acquire(ctx); err != nil {
+			return nil, nil, err
+		}
+		release = func() { c.adaptive.release(); leased() }
 
 		// Proactive ct0 rotation
 		if acc.CT0Age() > ct0MaxAge {
 			_, oldCT0, _ := acc.Credentials()
 			acc.RotateCT0()
 			slog.Info("ct0 rotated (proactive)", slog.String("user", acc.Username), slog.String("old_prefix", oldCT0[:min(8, len(oldCT0))]))
-			authTok2, ct02, _ := acc.Credentials()
-			_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
+			_ = saveSession(c.cfg.SessionDir, acc)
 		}
 
+		if err := c.waitGlobal(ctx); err != nil {
+			return nil, nil, err
+		}
+		acc.rotateProxySession(false)
 		bc := c.clientForAccount(acc)
+		traceAttempt(span, attempt, acc)
+		requestInfoFrom(ctx).served(SourceAccount, acc.Username)
 
 		authTok, ct0, ua := acc.Credentials()
-		body, respHdrs, status, err := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok, ct0, ua))
+		body, respHdrs, status, err := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok, ct0, ua))
 		if err != nil {
-			if acc.Proxy != "" && isProxyError(err) {
+			if ctx.Err() != nil {
+				// Cancelled, e.g. a hedge branch that lost: not the account's fault.
+				return nil, nil, ctx.Err()
+			}
+			if acc.currentProxy() != "" && isProxyError(err) {
 				c.markProxyDown(acc)
 			} else {
 				acc.RecordFailure()
 			}
-			lastErr = err
+			lastErr = acc.recordError(err)
 			continue
 		}
 
@@ -89,33 +201,39 @@ func (c *Client) doPoolRequest(ctx context.Context, method, endpoint, url string
 		acc.mu.Lock()
 		acc.proxyConsecFails = 0
 		acc.mu.Unlock()
+		traceResponse(span, status, body)
+		c.adaptive.observe(status)
 
 		// Handle HTTP status
 		switch {
 		case status == 429:
-			c.recordAPICall(endpoint, false, true)
-			acc.MarkEndpointRateLimited(endpoint, parseRateLimitReset(respHdrs["x-rate-limit-reset"]))
-			lastErr = fmt.Errorf("429 rate limited")
+			c.recordAPICall(endpoint, status, false, true)
+			reset := parseRateLimitReset(respHdrs["x-rate-limit-reset"])
+			acc.MarkEndpointRateLimited(endpoint, reset)
+			c.shareRateLimit(ctx, acc, endpoint, reset)
+			lastErr = acc.recordError(fmt.Errorf("429 rate limited"))
+			if policy.NoRetryOn429 {
+				break attempts
+			}
 			continue
 
 		case status == 401 || status == 403:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			errClass := classifyError(body, respHdrs)
 			switch errClass {
 			case errCSRF:
 				slog.Warn("CSRF error 353, rotating ct0", slog.String("user", acc.Username))
 				acc.RotateCT0()
 				authTok2, ct02, ua2 := acc.Credentials()
-				_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
-				body2, respHdrs2, status2, err2 := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok2, ct02, ua2))
+				_ = saveSession(c.cfg.SessionDir, acc)
+				body2, respHdrs2, status2, err2 := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok2, ct02, ua2))
 				if err2 == nil && status2 == 200 {
 					if newCT0 := extractCT0FromHeaders(respHdrs2); newCT0 != "" {
 						acc.SetCT0(newCT0)
-						authTok3, ct03, _ := acc.Credentials()
-						_ = saveSession(c.cfg.SessionDir, acc.Username, authTok3, ct03)
+						_ = saveSession(c.cfg.SessionDir, acc)
 					}
-					c.recordAPICall(endpoint, true, false)
-					acc.RecordSuccess()
+					c.recordAPICall(endpoint, status2, true, false)
+					acc.recordSuccess()
 					return body2, respHdrs2, nil
 				}
 				acc.RecordFailure()
@@ -123,47 +241,58 @@ func (c *Client) doPoolRequest(ctx context.Context, method, endpoint, url string
 				slog.Warn("CSRF retry failed, attempting relogin", slog.String("user", acc.Username))
 				if reErr := c.relogin(acc); reErr != nil {
 					slog.Warn("relogin after CSRF failed", slog.String("user", acc.Username), slog.Any("error", reErr))
-					c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-					lastErr = reErr
+					lastErr = acc.recordError(reErr)
+					c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 					continue
 				}
 				// Retry with fresh credentials after relogin
 				authTok3, ct03, ua3 := acc.Credentials()
-				body3, respHdrs3, status3, err3 := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok3, ct03, ua3))
+				body3, respHdrs3, status3, err3 := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok3, ct03, ua3))
 				if err3 == nil && status3 == 200 {
-					c.recordAPICall(endpoint, true, false)
-					acc.RecordSuccess()
+					c.recordAPICall(endpoint, status3, true, false)
+					acc.recordSuccess()
 					return body3, respHdrs3, nil
 				}
-				c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-				lastErr = fmt.Errorf("post-relogin CSRF request failed")
+				lastErr = acc.recordError(fmt.Errorf("post-relogin CSRF request failed"))
+				c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 				continue
 			case errAuthExpired:
 				slog.Warn("auth expired (code 32), attempting relogin", slog.String("user", acc.Username))
 				if reErr := c.relogin(acc); reErr != nil {
 					slog.Warn("relogin failed", slog.String("user", acc.Username), slog.Any("error", reErr))
-					c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-					lastErr = reErr
+					lastErr = acc.recordError(reErr)
+					c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 					continue
 				}
 				authTok2, ct02, ua2 := acc.Credentials()
-				body2, respHdrs2, status2, err2 := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok2, ct02, ua2))
+				body2, respHdrs2, status2, err2 := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok2, ct02, ua2))
 				if err2 == nil && status2 == 200 {
-					c.recordAPICall(endpoint, true, false)
-					acc.RecordSuccess()
+					c.recordAPICall(endpoint, status2, true, false)
+					acc.recordSuccess()
 					return body2, respHdrs2, nil
 				}
-				c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-				lastErr = fmt.Errorf("post-relogin request failed")
+				lastErr = acc.recordError(fmt.Errorf("post-relogin request failed"))
+				c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 				continue
 			default:
+				if status == 403 {
+					c.noteForbidden()
+				}
 				acc.RecordFailure()
-				lastErr = fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200))
+				lastErr = acc.recordError(fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200)))
 				continue
 			}
 
 		case status != 200:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
+			if !featuresRetried {
+				if u, p, ok := c.recoverFeatures(endpoint, url, payload, body); ok {
+					featuresRetried = true
+					url, payload = u, p
+					lastErr = fmt.Errorf("%s HTTP %d: missing features", endpoint, status)
+					continue
+				}
+			}
 			slog.Warn("doGET non-200", slog.String("endpoint", endpoint), slog.Int("status", status), slog.String("body", truncateBytes(body, 500)))
 			if shouldDeactivate := acc.RecordFailure(); shouldDeactivate {
 				total, failed, consec := acc.Stats()
@@ -172,9 +301,9 @@ func (c *Client) doPoolRequest(ctx context.Context, method, endpoint, url string
 					slog.Int("total", total),
 					slog.Int("failed", failed),
 					slog.Int("consec", consec))
-				c.pool.DeactivateItem(acc)
+				c.deactivate(acc, AccountEventDeactivated, fmt.Errorf("%s HTTP %d", endpoint, status))
 			}
-			return nil, nil, fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200))
+			return nil, nil, acc.recordError(fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200)))
 		}
 
 		// HTTP 200 — check for error codes in response body
@@ -183,108 +312,117 @@ func (c *Client) doPoolRequest(ctx context.Context, method, endpoint, url string
 		case errNone:
 			if newCT0 := extractCT0FromHeaders(respHdrs); newCT0 != "" && newCT0 != ct0 {
 				acc.SetCT0(newCT0)
-				authTok2, ct02, _ := acc.Credentials()
-				_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
+				_ = saveSession(c.cfg.SessionDir, acc)
+			}
+			if vErr := c.validateResponse(endpoint, body); vErr != nil {
+				c.recordAPICall(endpoint, status, false, false)
+				slog.Warn("response failed validation, retrying on another account",
+					slog.String("user", acc.Username), slog.String("endpoint", endpoint), slog.Any("error", vErr))
+				acc.RecordFailure()
+				lastErr = acc.recordError(vErr)
+				if rejected == nil {
+					rejected = make(map[*Account]bool)
+				}
+				rejected[acc] = true
+				continue
 			}
-			c.recordAPICall(endpoint, true, false)
-			acc.RecordSuccess()
+			c.recordAPICall(endpoint, status, true, false)
+			acc.recordSuccess()
 			return body, respHdrs, nil
 
 		case errCSRF:
 			slog.Warn("CSRF error 353, rotating ct0", slog.String("user", acc.Username))
 			acc.RotateCT0()
 			authTok2, ct02, ua2 := acc.Credentials()
-			_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
-			body2, respHdrs2, status2, err2 := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok2, ct02, ua2))
+			_ = saveSession(c.cfg.SessionDir, acc)
+			body2, respHdrs2, status2, err2 := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok2, ct02, ua2))
 			if err2 == nil && status2 == 200 && classifyError(body2, respHdrs2) == errNone {
 				if newCT0 := extractCT0FromHeaders(respHdrs2); newCT0 != "" {
 					acc.SetCT0(newCT0)
-					authTok3, ct03, _ := acc.Credentials()
-					_ = saveSession(c.cfg.SessionDir, acc.Username, authTok3, ct03)
+					_ = saveSession(c.cfg.SessionDir, acc)
 				}
-				c.recordAPICall(endpoint, true, false)
-				acc.RecordSuccess()
+				c.recordAPICall(endpoint, status2, true, false)
+				acc.recordSuccess()
 				return body2, respHdrs2, nil
 			}
 			// CSRF retry failed — attempt relogin
 			slog.Warn("CSRF retry failed, attempting relogin", slog.String("user", acc.Username))
 			if reErr := c.relogin(acc); reErr != nil {
 				slog.Warn("relogin after CSRF failed", slog.String("user", acc.Username), slog.Any("error", reErr))
-				c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-				lastErr = reErr
+				lastErr = acc.recordError(reErr)
+				c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 				continue
 			}
 			authTok3, ct03, ua3 := acc.Credentials()
-			body3, respHdrs3, status3, err3 := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok3, ct03, ua3))
+			body3, respHdrs3, status3, err3 := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok3, ct03, ua3))
 			if err3 == nil && status3 == 200 {
-				c.recordAPICall(endpoint, true, false)
-				acc.RecordSuccess()
+				c.recordAPICall(endpoint, status3, true, false)
+				acc.recordSuccess()
 				return body3, respHdrs3, nil
 			}
-			c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-			lastErr = fmt.Errorf("post-relogin CSRF request failed")
+			lastErr = acc.recordError(fmt.Errorf("post-relogin CSRF request failed"))
+			c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 			continue
 
 		case errAuthExpired:
 			slog.Warn("auth expired (code 32), attempting relogin", slog.String("user", acc.Username))
 			if reErr := c.relogin(acc); reErr != nil {
 				slog.Warn("relogin failed, soft-deactivating", slog.String("user", acc.Username), slog.Any("error", reErr))
-				c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-				lastErr = reErr
+				lastErr = acc.recordError(reErr)
+				c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 				continue
 			}
 			authTok2, ct02, ua2 := acc.Credentials()
-			body2, respHdrs2, status2, err2 := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok2, ct02, ua2))
+			body2, respHdrs2, status2, err2 := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok2, ct02, ua2))
 			if err2 == nil && status2 == 200 {
-				c.recordAPICall(endpoint, true, false)
-				acc.RecordSuccess()
+				c.recordAPICall(endpoint, status2, true, false)
+				acc.recordSuccess()
 				return body2, respHdrs2, nil
 			}
-			c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-			lastErr = fmt.Errorf("post-relogin request failed")
+			lastErr = acc.recordError(fmt.Errorf("post-relogin request failed"))
+			c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 			continue
 
 		case errInternal:
 			if hasResponseData(body) {
 				if newCT0 := extractCT0FromHeaders(respHdrs); newCT0 != "" && newCT0 != ct0 {
 					acc.SetCT0(newCT0)
-					authTok2, ct02, _ := acc.Credentials()
-					_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
+					_ = saveSession(c.cfg.SessionDir, acc)
 				}
-				c.recordAPICall(endpoint, true, false)
-				acc.RecordSuccess()
+				c.recordAPICall(endpoint, status, true, false)
+				acc.recordSuccess()
 				slog.Debug("error 131 with usable data, treating as success", slog.String("endpoint", endpoint))
 				return body, respHdrs, nil
 			}
 			slog.Warn("error 131 without data, retrying", slog.String("user", acc.Username), slog.String("endpoint", endpoint))
-			lastErr = fmt.Errorf("Twitter internal error (131)")
+			lastErr = acc.recordError(fmt.Errorf("Twitter internal error (131)"))
 			continue
 
 		case errBanned:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			slog.Warn("account banned (code 88)", slog.String("user", acc.Username))
-			c.pool.SoftDeactivate(acc, c.cfg.BanCooldown)
-			lastErr = fmt.Errorf("account banned")
+			lastErr = acc.recordError(fmt.Errorf("account banned"))
+			c.softDeactivate(acc, c.cfg.BanCooldown, AccountEventSoftDeactivated, lastErr)
 			continue
 
 		case errSuspended:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			slog.Warn("account suspended (code 64), permanently deactivating", slog.String("user", acc.Username))
-			c.pool.DeactivateItem(acc)
-			lastErr = fmt.Errorf("account suspended")
+			lastErr = acc.recordError(fmt.Errorf("account suspended"))
+			c.deactivate(acc, AccountEventSuspended, lastErr)
 			continue
 
 		case errLocked:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			slog.Warn("account locked (code 326, captcha needed)", slog.String("user", acc.Username))
 			if c.cfg.CaptchaSolver != nil {
 				slog.Info("attempting CAPTCHA unlock via relogin", slog.String("user", acc.Username))
 				if reErr := c.relogin(acc); reErr == nil {
 					authTok2, ct02, ua2 := acc.Credentials()
-					body2, respHdrs2, status2, err2 := c.doPoolReq(bc, method, url, payload, twitterHeaders(authTok2, ct02, ua2))
+					body2, respHdrs2, status2, err2 := c.doPoolReq(acc.authContext(ctx), bc, method, url, payload, acc.apiHeaders(authTok2, ct02, ua2))
 					if err2 == nil && status2 == 200 {
-						c.recordAPICall(endpoint, true, false)
-						acc.RecordSuccess()
+						c.recordAPICall(endpoint, status2, true, false)
+						acc.recordSuccess()
 						slog.Info("CAPTCHA unlock succeeded", slog.String("user", acc.Username))
 						return body2, respHdrs2, nil
 					}
@@ -293,124 +431,196 @@ func (c *Client) doPoolRequest(ctx context.Context, method, endpoint, url string
 					slog.Warn("CAPTCHA unlock failed", slog.String("user", acc.Username), slog.Any("error", reErr))
 				}
 			}
-			c.pool.SoftDeactivate(acc, c.cfg.BanCooldown)
-			lastErr = fmt.Errorf("account locked")
+			lastErr = acc.recordError(fmt.Errorf("account locked"))
+			c.softDeactivate(acc, c.cfg.BanCooldown, AccountEventLocked, lastErr)
 			continue
 
 		default: // errBlocked, errNotAuthorized
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			slog.Warn("account error", slog.String("user", acc.Username), slog.Int("class", int(errClass)))
-			c.pool.SoftDeactivate(acc, c.cfg.AuthCooldown)
-			lastErr = fmt.Errorf("account error class %d", errClass)
+			lastErr = acc.recordError(fmt.Errorf("account error class %d", errClass))
+			c.softDeactivate(acc, c.cfg.AuthCooldown, AccountEventSoftDeactivated, lastErr)
 			continue
 		}
 	}
 
+	release()
+	release = func() {}
+
 	// --- Guest token fallback ---
-	if requiresAuth(endpoint) {
+	if requiresAuth(endpoint) && !downgrade {
 		if lastErr != nil {
-			return nil, nil, fmt.Errorf("pool exhausted for %s (requires auth): %w", endpoint, lastErr)
+			return nil, nil, fmt.Errorf("%w for %s (requires auth): %w", ErrPoolExhausted, endpoint, lastErr)
 		}
-		return nil, nil, fmt.Errorf("%s requires authenticated account", endpoint)
+		return nil, nil, fmt.Errorf("%s requires authenticated account: %w", endpoint, ErrPoolExhausted)
 	}
 
 	// Global guest fallback kill-switch. In production, guest tokens from
 	// datacenter IPs are unreliable (persistent 403 Bad guest token). Enabling
 	// this flag forces all endpoints to require an authenticated account.
-	if c.cfg.DisableGuestFallback {
+	if c.cfg.DisableGuestFallback || !opts.guestAllowed() {
 		if lastErr != nil {
-			return nil, nil, fmt.Errorf("pool exhausted for %s (guest fallback disabled): %w", endpoint, lastErr)
+			return nil, nil, fmt.Errorf("%w for %s: %w: %w", ErrPoolExhausted, endpoint, ErrGuestFallbackDisabled, lastErr)
 		}
-		return nil, nil, fmt.Errorf("%s: no authenticated account and guest fallback disabled", endpoint)
+		return nil, nil, fmt.Errorf("%s: no authenticated account and %w: %w", endpoint, ErrGuestFallbackDisabled, ErrPoolExhausted)
 	}
 
+	span.AddEvent("guest_fallback")
+	body, respHdrs, err := c.guestRequest(ctx, endpoint, url, lastErr)
+	c.metrics.observeGuestFallback(endpoint, err)
+	return body, respHdrs, err
+}
+
+// guestRequest sends a pool request that found no account with a guest
+// token. lastErr is the account path's failure, reported if no guest token
+// can be had.
+func (c *Client) guestRequest(ctx context.Context, endpoint, url string, lastErr error) ([]byte, map[string]string, error) {
 	gt, ok := c.getGuestTokenCached()
 	if !ok {
 		token, err := c.acquireGuestToken(ctx, c.client)
 		if err != nil {
 			if lastErr != nil {
-				return nil, nil, fmt.Errorf("pool exhausted for %s: %w", endpoint, lastErr)
+				return nil, nil, fmt.Errorf("%w for %s: %w", ErrPoolExhausted, endpoint, lastErr)
 			}
-			return nil, nil, fmt.Errorf("guest token unavailable for %s: %w", endpoint, err)
+			return nil, nil, fmt.Errorf("guest token unavailable for %s: %w: %w", endpoint, ErrPoolExhausted, err)
 		}
 		c.setGuestToken(token)
 		gt = token
 		slog.Info("guest token acquired as fallback", slog.String("endpoint", endpoint))
 	}
 
-	body, respHdrs, status, err := c.doRequest(c.client, "GET", url, guestHeaders(gt))
+	if err := c.adaptive.acquire(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer c.adaptive.release()
+	if err := c.waitGlobal(ctx); err != nil {
+		return nil, nil, err
+	}
+	requestInfoFrom(ctx).served(SourceGuest, "")
+	body, respHdrs, status, err := c.doRequest(ctx, c.client, "GET", url, guestHeaders(gt))
 	if err != nil {
 		return nil, nil, err
 	}
 	if status == 429 {
-		c.recordAPICall(endpoint, false, true)
+		c.recordAPICall(endpoint, status, false, true)
 		c.markGuestTokenRateLimited(parseRateLimitReset(respHdrs["x-rate-limit-reset"]))
-		return nil, nil, fmt.Errorf("guest token rate-limited for %s", endpoint)
+		return nil, nil, fmt.Errorf("guest token rate-limited for %s: %w", endpoint, ErrPoolExhausted)
 	}
 	if status == 401 || status == 403 {
 		slog.Warn("guest token expired, reacquiring", slog.String("endpoint", endpoint), slog.Int("status", status))
 		c.setGuestToken("")
 		newGT, gtErr := c.acquireGuestToken(ctx, c.client)
 		if gtErr != nil {
-			c.recordAPICall(endpoint, false, false)
-			return nil, nil, fmt.Errorf("guest token reacquisition failed for %s: %w", endpoint, gtErr)
+			c.recordAPICall(endpoint, status, false, false)
+			return nil, nil, fmt.Errorf("guest token reacquisition failed for %s: %w: %w", endpoint, ErrPoolExhausted, gtErr)
 		}
 		c.setGuestToken(newGT)
-		body, respHdrs, status, err = c.doRequest(c.client, "GET", url, guestHeaders(newGT))
+		body, respHdrs, status, err = c.doRequest(ctx, c.client, "GET", url, guestHeaders(newGT))
 		if err != nil {
 			return nil, nil, err
 		}
 		if status != 200 {
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			return nil, nil, fmt.Errorf("%s (guest retry) HTTP %d: %s", endpoint, status, truncateBytes(body, 200))
 		}
-		c.recordAPICall(endpoint, true, false)
+		c.recordAPICall(endpoint, status, true, false)
 		return body, respHdrs, nil
 	}
 	if status != 200 {
-		c.recordAPICall(endpoint, false, false)
+		c.recordAPICall(endpoint, status, false, false)
 		return nil, nil, fmt.Errorf("%s (guest) HTTP %d: %s", endpoint, status, truncateBytes(body, 200))
 	}
-	c.recordAPICall(endpoint, true, false)
+	c.recordAPICall(endpoint, status, true, false)
 	return body, respHdrs, nil
 }
 
 // doPOST executes a POST mutation with a specific account.
 // Unlike doGET, it does not rotate accounts from the pool — the caller provides the account.
-// Handles CSRF rotation, auth expiry, and retries on transient errors.
+// Handles CSRF rotation, auth expiry, and retries on transient errors. An
+// attempt cut off by RequestTimeout is final, as the write may have landed.
 func (c *Client) doPOST(ctx context.Context, acc *Account, endpoint, url string, payload []byte) ([]byte, error) {
-	if err := stealth.DefaultJitter.Sleep(ctx); err != nil {
+	return c.doPOSTAs(ctx, acc, endpoint, url, "", payload)
+}
+
+// doPOSTAs is doPOST with the request content type overridden; an empty
+// contentType keeps the JSON default.
+func (c *Client) doPOSTAs(ctx context.Context, acc *Account, endpoint, url, contentType string, payload []byte) ([]byte, error) {
+	ctx, span := c.startSpan(ctx, "twitter."+endpoint,
+		attribute.String("twitter.endpoint", endpoint),
+		attribute.String("http.method", "POST"),
+		attribute.String("twitter.account", hashAccount(acc.Username)))
+	body, err := c.withQuota(acc, endpoint, func() ([]byte, error) {
+		return c.accountPOST(ctx, span, acc, endpoint, url, contentType, payload)
+	})
+	endSpan(span, err)
+	return body, err
+}
+
+// accountPOST implements doPOST, reporting attempts and responses on span.
+func (c *Client) accountPOST(ctx context.Context, span trace.Span, acc *Account, endpoint, url, contentType string, payload []byte) ([]byte, error) {
+	headers := func(authTok, ct0, ua string) map[string]string {
+		h := acc.apiHeaders(authTok, ct0, ua)
+		if contentType != "" {
+			h["content-type"] = contentType
+		}
+		return h
+	}
+	if err := c.fingerprintJitter(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.adaptive.jitter(ctx); err != nil {
 		return nil, err
 	}
 
 	var lastErr error
-	for attempt := range maxRetries {
+	featuresRetried := false // missing-feature recovery is tried once
+	policy := c.retryPolicy(endpoint)
+	release := func() {} // gives back the attempt's shared lease
+	defer func() { release() }()
+	for attempt := range policy.MaxAttempts {
+		release()
+		release = func() {}
 		if attempt > 0 {
-			delay := stealth.DefaultBackoff.Duration(attempt)
-			select {
-			case <-time.After(delay):
-			case <-ctx.Done():
-				return nil, ctx.Err()
+			if err := policy.wait(ctx, attempt); err != nil {
+				return nil, err
 			}
 		}
+		leased, err := c.leaseAccount(ctx, acc, endpoint, c.accountWait(ctx, endpoint))
+		if err != nil {
+			return nil, err
+		}
+		release = leased
+		if err := c.adaptive.acquire(ctx); err != nil {
+			return nil, err
+		}
+		release = func() { c.adaptive.release(); leased() }
 
 		// Proactive ct0 rotation
 		if acc.CT0Age() > ct0MaxAge {
 			acc.RotateCT0()
-			authTok, ct0, _ := acc.Credentials()
-			_ = saveSession(c.cfg.SessionDir, acc.Username, authTok, ct0)
+			_ = saveSession(c.cfg.SessionDir, acc)
 		}
 
+		if err := c.waitGlobal(ctx); err != nil {
+			return nil, err
+		}
+		acc.rotateProxySession(false)
 		bc := c.clientForAccount(acc)
+		traceAttempt(span, attempt, acc)
+		requestInfoFrom(ctx).served(SourceAccount, acc.Username)
 		authTok, ct0, ua := acc.Credentials()
-		body, respHdrs, status, err := c.doRequestWithBody(bc, "POST", url, twitterHeaders(authTok, ct0, ua), bytes.NewReader(payload))
+		body, respHdrs, status, err := c.doRequestWithBody(acc.authContext(ctx), bc, "POST", url, headers(authTok, ct0, ua), bytes.NewReader(payload))
 		if err != nil {
-			if acc.Proxy != "" && isProxyError(err) {
+			if acc.currentProxy() != "" && isProxyError(err) {
 				c.markProxyDown(acc)
 			} else {
 				acc.RecordFailure()
 			}
-			lastErr = err
+			lastErr = acc.recordError(err)
+			if errors.Is(err, errAttemptTimeout) {
+				// The write may have landed; resending it could apply it twice.
+				return nil, lastErr
+			}
 			continue
 		}
 
@@ -418,56 +628,80 @@ func (c *Client) doPOST(ctx context.Context, acc *Account, endpoint, url string,
 		acc.mu.Lock()
 		acc.proxyConsecFails = 0
 		acc.mu.Unlock()
+		traceResponse(span, status, body)
+		c.adaptive.observe(status)
 
 		switch {
 		case status == 429:
-			c.recordAPICall(endpoint, false, true)
-			acc.MarkEndpointRateLimited(endpoint, parseRateLimitReset(respHdrs["x-rate-limit-reset"]))
-			lastErr = fmt.Errorf("429 rate limited")
+			c.recordAPICall(endpoint, status, false, true)
+			reset := parseRateLimitReset(respHdrs["x-rate-limit-reset"])
+			acc.MarkEndpointRateLimited(endpoint, reset)
+			c.shareRateLimit(ctx, acc, endpoint, reset)
+			lastErr = acc.recordError(fmt.Errorf("429 rate limited"))
+			if policy.NoRetryOn429 {
+				return nil, fmt.Errorf("%s: %w", endpoint, lastErr)
+			}
 			continue
 
 		case status == 401 || status == 403:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			errClass := classifyError(body, respHdrs)
 			switch errClass {
 			case errCSRF:
 				slog.Warn("doPOST: CSRF error 353, rotating ct0", slog.String("user", acc.Username))
 				acc.RotateCT0()
 				authTok2, ct02, ua2 := acc.Credentials()
-				_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
-				body2, _, status2, err2 := c.doRequestWithBody(bc, "POST", url, twitterHeaders(authTok2, ct02, ua2), bytes.NewReader(payload))
+				_ = saveSession(c.cfg.SessionDir, acc)
+				body2, _, status2, err2 := c.doRequestWithBody(acc.authContext(ctx), bc, "POST", url, headers(authTok2, ct02, ua2), bytes.NewReader(payload))
+				if errors.Is(err2, errAttemptTimeout) {
+					return nil, acc.recordError(err2)
+				}
 				if err2 == nil && (status2 == 200 || status2 == 201) {
-					c.recordAPICall(endpoint, true, false)
-					acc.RecordSuccess()
+					c.recordAPICall(endpoint, status2, true, false)
+					acc.recordSuccess()
 					return body2, nil
 				}
 				acc.RecordFailure()
-				lastErr = fmt.Errorf("CSRF retry failed")
+				lastErr = acc.recordError(fmt.Errorf("CSRF retry failed"))
 				continue
 			case errAuthExpired:
 				slog.Warn("doPOST: auth expired, attempting relogin", slog.String("user", acc.Username))
 				if reErr := c.relogin(acc); reErr != nil {
-					lastErr = fmt.Errorf("relogin failed: %w", reErr)
+					lastErr = acc.recordError(fmt.Errorf("relogin failed: %w", reErr))
 					continue
 				}
 				authTok2, ct02, ua2 := acc.Credentials()
-				body2, _, status2, err2 := c.doRequestWithBody(bc, "POST", url, twitterHeaders(authTok2, ct02, ua2), bytes.NewReader(payload))
+				body2, _, status2, err2 := c.doRequestWithBody(acc.authContext(ctx), bc, "POST", url, headers(authTok2, ct02, ua2), bytes.NewReader(payload))
+				if errors.Is(err2, errAttemptTimeout) {
+					return nil, acc.recordError(err2)
+				}
 				if err2 == nil && (status2 == 200 || status2 == 201) {
-					c.recordAPICall(endpoint, true, false)
-					acc.RecordSuccess()
+					c.recordAPICall(endpoint, status2, true, false)
+					acc.recordSuccess()
 					return body2, nil
 				}
-				lastErr = fmt.Errorf("post-relogin request failed")
+				lastErr = acc.recordError(fmt.Errorf("post-relogin request failed"))
 				continue
 			default:
+				if status == 403 {
+					c.noteForbidden()
+				}
 				acc.RecordFailure()
-				return nil, fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200))
+				return nil, acc.recordError(fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200)))
 			}
 
 		case status != 200:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
+			if !featuresRetried {
+				if u, p, ok := c.recoverFeatures(endpoint, url, payload, body); ok {
+					featuresRetried = true
+					url, payload = u, p
+					lastErr = fmt.Errorf("%s HTTP %d: missing features", endpoint, status)
+					continue
+				}
+			}
 			acc.RecordFailure()
-			return nil, fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200))
+			return nil, acc.recordError(fmt.Errorf("%s HTTP %d: %s", endpoint, status, truncateBytes(body, 200)))
 		}
 
 		// HTTP 200 — check for error codes in response body
@@ -476,55 +710,105 @@ func (c *Client) doPOST(ctx context.Context, acc *Account, endpoint, url string,
 		case errNone:
 			if newCT0 := extractCT0FromHeaders(respHdrs); newCT0 != "" && newCT0 != ct0 {
 				acc.SetCT0(newCT0)
-				authTok2, ct02, _ := acc.Credentials()
-				_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
+				_ = saveSession(c.cfg.SessionDir, acc)
 			}
-			c.recordAPICall(endpoint, true, false)
-			acc.RecordSuccess()
+			c.recordAPICall(endpoint, status, true, false)
+			acc.recordSuccess()
 			return body, nil
 		case errCSRF:
 			slog.Warn("doPOST: CSRF in 200, rotating ct0", slog.String("user", acc.Username))
 			acc.RotateCT0()
 			authTok2, ct02, ua2 := acc.Credentials()
-			_ = saveSession(c.cfg.SessionDir, acc.Username, authTok2, ct02)
-			body2, _, status2, err2 := c.doRequestWithBody(bc, "POST", url, twitterHeaders(authTok2, ct02, ua2), bytes.NewReader(payload))
+			_ = saveSession(c.cfg.SessionDir, acc)
+			body2, _, status2, err2 := c.doRequestWithBody(acc.authContext(ctx), bc, "POST", url, headers(authTok2, ct02, ua2), bytes.NewReader(payload))
+			if errors.Is(err2, errAttemptTimeout) {
+				return nil, acc.recordError(err2)
+			}
 			if err2 == nil && (status2 == 200 || status2 == 201) && classifyError(body2, nil) == errNone {
-				c.recordAPICall(endpoint, true, false)
-				acc.RecordSuccess()
+				c.recordAPICall(endpoint, status2, true, false)
+				acc.recordSuccess()
 				return body2, nil
 			}
-			lastErr = fmt.Errorf("CSRF retry failed")
+			lastErr = acc.recordError(fmt.Errorf("CSRF retry failed"))
 			continue
 		default:
-			c.recordAPICall(endpoint, false, false)
+			c.recordAPICall(endpoint, status, false, false)
 			acc.RecordFailure()
-			return nil, fmt.Errorf("%s error class %d: %s", endpoint, errClass, truncateBytes(body, 200))
+			return nil, acc.recordError(fmt.Errorf("%s error class %d: %s", endpoint, errClass, truncateBytes(body, 200)))
 		}
 	}
 
 	if lastErr != nil {
-		return nil, fmt.Errorf("%s failed after %d attempts: %w", endpoint, maxRetries, lastErr)
+		return nil, fmt.Errorf("%s failed after %d attempts: %w", endpoint, policy.MaxAttempts, lastErr)
 	}
-	return nil, fmt.Errorf("%s failed after %d attempts", endpoint, maxRetries)
+	return nil, fmt.Errorf("%s failed after %d attempts", endpoint, policy.MaxAttempts)
 }
 
 // requiresAuth returns true for endpoints that need a real authenticated account.
 // UserByScreenName/UserTweets were added to prevent silent guest-token fallback,
 // which is unreliable in production and hides authentication errors.
 func requiresAuth(endpoint string) bool {
+	if strings.HasPrefix(endpoint, tweetdeckPrefix) {
+		return true // X Pro has no guest access
+	}
 	switch endpoint {
 	case "TweetDetail", "SearchTimeline", "Following", "Followers", "Retweeters",
-		"CreateTweet", "UserByScreenName", "UserTweets":
+		"CreateTweet", "UserByScreenName", "UserTweets", "FriendshipsShow":
 		return true
 	}
 	return false
 }
 
-// isProxyError returns true if the error looks like a proxy connectivity failure.
+// guestCapable returns true for auth-required endpoints that Twitter still
+// serves to guest tokens. They are only downgraded to the guest path under
+// GuestDowngradeOnDeadline.
+func guestCapable(endpoint string) bool {
+	switch endpoint {
+	case "UserByScreenName", "UserTweets", "TweetDetail":
+		return true
+	}
+	return false
+}
+
+// canDowngradeToGuest reports whether endpoint may fall back to the guest path
+// when no account is available before ctx's deadline.
+func (c *Client) canDowngradeToGuest(ctx context.Context, endpoint string) bool {
+	if !c.cfg.GuestDowngradeOnDeadline || c.cfg.DisableGuestFallback ||
+		!requestOptionsFrom(ctx).guestAllowed() || !guestCapable(endpoint) {
+		return false
+	}
+	_, ok := ctx.Deadline()
+	return ok
+}
+
+// maxAccountWait is how long an auth-required request blocks for a free account.
+const maxAccountWait = 5 * time.Minute
+
+// accountWait returns how long to block waiting for an account. For
+// downgradable endpoints it is capped so that GuestDowngradeReserve of the
+// caller's deadline is left for the guest request; 0 means don't wait.
+func (c *Client) accountWait(ctx context.Context, endpoint string) time.Duration {
+	if !c.canDowngradeToGuest(ctx, endpoint) {
+		return maxAccountWait
+	}
+	deadline, _ := ctx.Deadline()
+	wait := time.Until(deadline) - c.cfg.GuestDowngradeReserve
+	if wait <= 0 {
+		return 0
+	}
+	return min(wait, maxAccountWait)
+}
+
+// isProxyError returns true if the error looks like a proxy connectivity
+// failure. Attempts cut off by RequestTimeout count, since a hung proxy is
+// the usual cause.
 func isProxyError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, errAttemptTimeout) {
+		return true
+	}
 	msg := err.Error()
 	return strings.Contains(msg, "proxy") ||
 		strings.Contains(msg, "SOCKS") ||
@@ -533,8 +817,16 @@ func isProxyError(err error) bool {
 		strings.Contains(msg, "no such host")
 }
 
-// markProxyDown applies exponential backoff for proxy failures.
+// markProxyDown applies exponential backoff for proxy failures. Accounts whose
+// proxy came from ClientConfig.ProxyPool are moved to another healthy proxy
+// instead, when one is available. Either way an AccountEventProxyDown is
+// emitted.
 func (c *Client) markProxyDown(acc *Account) {
+	if c.swapProxy(acc) {
+		c.emitAccountEvent(AccountEventProxyDown, acc, time.Time{}, nil)
+		return
+	}
+
 	acc.mu.Lock()
 	acc.proxyConsecFails++
 	fails := acc.proxyConsecFails
@@ -547,17 +839,56 @@ func (c *Client) markProxyDown(acc *Account) {
 		JitterPct:   0.3,
 	}.Duration(fails - 1)
 
+	until := time.Now().Add(duration)
 	acc.mu.Lock()
-	acc.proxyBackoff = time.Now().Add(duration)
+	acc.proxyBackoff = until
 	acc.mu.Unlock()
+	c.emitAccountEvent(AccountEventProxyDown, acc, until, nil)
 
 	slog.Warn("proxy down, backing off",
 		slog.String("user", acc.Username),
-		slog.String("proxy", stealth.MaskProxy(acc.Proxy)),
+		slog.String("proxy", stealth.MaskProxy(acc.renderedProxy(acc.currentProxy()))),
 		slog.Int("consec_fails", fails),
 		slog.Duration("backoff", duration))
 }
 
+// swapProxy reports the account's pooled proxy as down and rebuilds its client
+// on a replacement. Returns false if the account has no pooled proxy or no
+// replacement is available.
+func (c *Client) swapProxy(acc *Account) bool {
+	if c.cfg.ProxyPool == nil {
+		return false
+	}
+	acc.mu.Lock()
+	pooled, old := acc.pooledProxy, acc.Proxy
+	acc.mu.Unlock()
+	if !pooled {
+		return false
+	}
+	proxy, ok := c.cfg.ProxyPool.ReportDown(acc.Username)
+	if !ok || proxy == old {
+		return false
+	}
+	bc, err := newAccountClient(acc, proxy)
+	if err != nil {
+		slog.Warn("proxy swap: client build failed", slog.String("user", acc.Username), slog.Any("error", err))
+		return false
+	}
+
+	acc.mu.Lock()
+	acc.Proxy = proxy
+	acc.client = bc
+	acc.proxyConsecFails = 0
+	acc.proxyBackoff = time.Time{}
+	acc.mu.Unlock()
+
+	slog.Warn("proxy down, swapped",
+		slog.String("user", acc.Username),
+		slog.String("old", stealth.MaskProxy(old)),
+		slog.String("new", stealth.MaskProxy(proxy)))
+	return true
+}
+
 func truncateBytes(b []byte, n int) string {
 	if len(b) <= n {
 		return string(b)
@@ -0,0 +1,86 @@
+package twitter
+
+import (
+	"context"
+	"sync"
+)
+
+// RequestSource names the backend that served a request.
+type RequestSource string
+
+const (
+	SourceAccount RequestSource = "account" // a pool account
+	SourceGuest   RequestSource = "guest"   // the guest-token fallback
+	SourceCache   RequestSource = "cache"   // ClientConfig.Cache
+	SourceAPIv2   RequestSource = "api_v2"  // the official API v2 backend
+	SourceMirror  RequestSource = "mirror"  // ClientConfig.Mirror (e.g. Nitter)
+)
+
+// RequestInfo describes how a call was served. Attach one to the context with
+// WithRequestInfo and read it after the call returns. When several calls
+// share the context, it describes the last upstream request; callers whose
+// GET was deduplicated onto another caller's in-flight request see nothing.
+type RequestInfo struct {
+	mu       sync.Mutex
+	source   RequestSource
+	account  string
+	attempts int
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a context that records into info how requests
+// made with it were served.
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// requestInfoFrom returns the RequestInfo attached to ctx, or nil.
+func requestInfoFrom(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// Source returns the backend that served the last request.
+func (r *RequestInfo) Source() RequestSource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.source
+}
+
+// Account returns the username of the pool account used, if any.
+func (r *RequestInfo) Account() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.account
+}
+
+// Attempts returns the number of upstream attempts made.
+func (r *RequestInfo) Attempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.attempts
+}
+
+// served records an upstream attempt by source. Safe on nil.
+func (r *RequestInfo) served(source RequestSource, account string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.source = source
+	r.account = account
+	r.attempts++
+	r.mu.Unlock()
+}
+
+// cached records a cache hit. Safe on nil.
+func (r *RequestInfo) cached() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.source = SourceCache
+	r.account = ""
+	r.mu.Unlock()
+}
@@ -0,0 +1,177 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/anatolykoptev/go-twitter/xtid"
+)
+
+var (
+	// bundleURLRegex matches the web app bundles that carry GraphQL operation
+	// definitions in the x.com home page HTML.
+	bundleURLRegex = regexp.MustCompile(`https://abs\.twimg\.com/responsive-web/client-web(?:-legacy)?/(?:main|api)\.[\w.]+\.js`)
+
+	// operationRegex matches one operation definition in a bundle:
+	// {queryId:"…",operationName:"…",operationType:"…",metadata:{featureSwitches:[…]
+	operationRegex = regexp.MustCompile(`queryId:"([\w-]+)",operationName:"(\w+)",operationType:"\w+",metadata:\{featureSwitches:\[([^\]]*)\]`)
+
+	quotedNameRegex = regexp.MustCompile(`"(\w+)"`)
+)
+
+// bundleFetcher fetches x.com pages; *xtid.Manager implements it.
+type bundleFetcher interface {
+	FetchHomeHTML(ctx context.Context) (string, error)
+	FetchURL(ctx context.Context, url string) (string, error)
+}
+
+// discoveredOperation is one operation definition found in a bundle.
+type discoveredOperation struct {
+	ID       string
+	Features []string
+}
+
+// EndpointResolver discovers current GraphQL query IDs and feature flags
+// from the x.com web bundles and hot-updates Endpoints, so stale IDs don't
+// require a release whenever Twitter ships a new bundle. Operations pinned by
+// a TWITTER_QID_* env var are left alone.
+type EndpointResolver struct {
+	fetcher bundleFetcher
+}
+
+// NewEndpointResolver creates a resolver that fetches pages through m.
+func NewEndpointResolver(m *xtid.Manager) *EndpointResolver {
+	return &EndpointResolver{fetcher: m}
+}
+
+// EndpointResolver returns a resolver sharing the client's x.com fetcher.
+func (c *Client) EndpointResolver() *EndpointResolver {
+	return NewEndpointResolver(c.xtidMgr)
+}
+
+// Resolve fetches the bundles once and updates every known x.com operation
+// found in them. Returns the names of updated Endpoints entries.
+func (r *EndpointResolver) Resolve(ctx context.Context) ([]string, error) {
+	html, err := r.fetcher.FetchHomeHTML(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch x.com: %w", err)
+	}
+	bundles := bundleURLRegex.FindAllString(html, -1)
+	if len(bundles) == 0 {
+		return nil, fmt.Errorf("no web bundles found in x.com HTML")
+	}
+
+	ops := make(map[string]discoveredOperation)
+	for _, u := range bundles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		js, err := r.fetcher.FetchURL(ctx, u)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			slog.Warn("endpoint discovery: bundle fetch failed", slog.String("url", u), slog.Any("error", err))
+			continue
+		}
+		maps.Copy(ops, parseBundleOperations(js))
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("no GraphQL operations found in %d bundles", len(bundles))
+	}
+	return applyDiscoveredOperations(ops, html), nil
+}
+
+// Run resolves immediately and then every interval (default 6h) until ctx
+// is done. Failures are logged and the current Endpoints kept.
+func (r *EndpointResolver) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		updated, err := r.Resolve(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("endpoint discovery failed", slog.Any("error", err))
+		} else if len(updated) > 0 {
+			slog.Info("endpoint discovery updated operations", slog.Any("operations", updated))
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// parseBundleOperations extracts operation definitions keyed by operation name.
+func parseBundleOperations(js string) map[string]discoveredOperation {
+	ops := make(map[string]discoveredOperation)
+	for _, m := range operationRegex.FindAllStringSubmatch(js, -1) {
+		var features []string
+		for _, f := range quotedNameRegex.FindAllStringSubmatch(m[3], -1) {
+			features = append(features, f[1])
+		}
+		ops[m[2]] = discoveredOperation{ID: m[1], Features: features}
+	}
+	return ops
+}
+
+// applyDiscoveredOperations updates the x.com entries of Endpoints from ops.
+// A flag keeps its current value; new flags take their value from the page's
+// feature switch config in html, else false.
+func applyDiscoveredOperations(ops map[string]discoveredOperation, html string) []string {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+
+	var updated []string
+	for key, ep := range Endpoints {
+		if ep.Base != "" {
+			continue // other hosts ship their own bundles
+		}
+		if env, ok := envOverrides[key]; ok && os.Getenv(env) != "" {
+			continue
+		}
+		op, ok := ops[ep.Name]
+		if !ok {
+			continue
+		}
+		changed := op.ID != ep.ID
+		if len(op.Features) > 0 {
+			features := make(map[string]any, len(op.Features))
+			for _, name := range op.Features {
+				v, ok := ep.Features[name]
+				if !ok {
+					v = pageFeatureValue(html, name)
+					changed = true
+				}
+				features[name] = v
+			}
+			if len(features) != len(ep.Features) {
+				changed = true
+			}
+			ep.Features = features
+		}
+		if !changed {
+			continue
+		}
+		ep.ID = op.ID
+		Endpoints[key] = ep
+		updated = append(updated, key)
+	}
+	return updated
+}
+
+// pageFeatureValue reads a boolean feature switch from the page's initial
+// state ("name":{"value":true}). Unknown or non-boolean switches are false.
+func pageFeatureValue(html, name string) bool {
+	re := regexp.MustCompile(`"` + regexp.QuoteMeta(name) + `":\{"value":(true|false)`)
+	m := re.FindStringSubmatch(html)
+	return len(m) > 1 && m[1] == "true"
+}
@@ -0,0 +1,62 @@
+package twitter
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBundleFetcher struct {
+	html  string
+	files map[string]string
+}
+
+func (f fakeBundleFetcher) FetchHomeHTML(context.Context) (string, error) { return f.html, nil }
+func (f fakeBundleFetcher) FetchURL(_ context.Context, url string) (string, error) {
+	return f.files[url], nil
+}
+
+// saveEndpoints restores Endpoints after a test that updates it.
+func saveEndpoints(t *testing.T) {
+	saved := maps.Clone(Endpoints)
+	t.Cleanup(func() { Endpoints = saved })
+}
+
+func TestParseBundleOperations(t *testing.T) {
+	js := `e.exports={queryId:"NEWID_abc-1",operationName:"UserByScreenName",operationType:"query",` +
+		`metadata:{featureSwitches:["hidden_profile_subscriptions_enabled","brand_new_flag"],fieldToggles:[]}}`
+	ops := parseBundleOperations(js)
+	require.Contains(t, ops, "UserByScreenName")
+	assert.Equal(t, "NEWID_abc-1", ops["UserByScreenName"].ID)
+	assert.Equal(t, []string{"hidden_profile_subscriptions_enabled", "brand_new_flag"}, ops["UserByScreenName"].Features)
+}
+
+func TestEndpointResolver_Resolve(t *testing.T) {
+	saveEndpoints(t)
+	t.Setenv("TWITTER_QID_USER_TWEETS", "pinned")
+
+	const bundle = "https://abs.twimg.com/responsive-web/client-web/main.1a2b3c4da.js"
+	f := fakeBundleFetcher{
+		html: `<script src="` + bundle + `"></script>{"brand_new_flag":{"value":true}}`,
+		files: map[string]string{bundle: `{queryId:"NEWID",operationName:"UserByScreenName",operationType:"query",` +
+			`metadata:{featureSwitches:["articles_preview_enabled","brand_new_flag","other_new_flag"]}}` +
+			`{queryId:"TWEETS2",operationName:"UserTweets",operationType:"query",metadata:{featureSwitches:[]}}`},
+	}
+
+	updated, err := (&EndpointResolver{fetcher: f}).Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"UserByScreenName"}, updated, "env-pinned and X Pro entries are skipped")
+
+	ep := Endpoints["UserByScreenName"]
+	assert.Equal(t, "NEWID", ep.ID)
+	assert.Equal(t, map[string]any{"articles_preview_enabled": true, "brand_new_flag": true, "other_new_flag": false}, ep.Features)
+	assert.NotEqual(t, "TWEETS2", Endpoints["TweetDeck/UserTweets"].ID)
+}
+
+func TestEndpointResolver_NoBundles(t *testing.T) {
+	_, err := (&EndpointResolver{fetcher: fakeBundleFetcher{html: "<html></html>"}}).Resolve(context.Background())
+	assert.Error(t, err)
+}
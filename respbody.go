@@ -0,0 +1,133 @@
+package twitter
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ErrResponseTooLarge is returned for a response body, compressed or not,
+// larger than ClientConfig.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// defaultMaxResponseBytes bounds response bodies when
+// ClientConfig.MaxResponseBytes is unset. The largest timeline pages are a
+// few MB.
+const defaultMaxResponseBytes = 32 << 20
+
+// readLimited reads r up to limit bytes (no limit if limit <= 0).
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrResponseTooLarge, limit)
+	}
+	return b, nil
+}
+
+// decodeBody bounds a response body by limit and decodes it if it is still
+// compressed. The default browser client decodes gzip and deflate but
+// hands brotli back as is, although every header set advertises br; gzip
+// left encoded (e.g. by a proxy that rewrote content-encoding) is caught by
+// its magic bytes. Decoding stops at limit, so a compression bomb cannot
+// exhaust memory.
+func decodeBody(body []byte, headers map[string]string, limit int64) ([]byte, error) {
+	if limit > 0 && int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", ErrResponseTooLarge, len(body), limit)
+	}
+	if looksDecoded(body) {
+		return body, nil
+	}
+	var r io.Reader
+	switch {
+	case len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b:
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return body, nil
+		}
+		defer zr.Close()
+		r = zr
+	case strings.Contains(strings.ToLower(headers["content-encoding"]), "br"):
+		r = brotli.NewReader(bytes.NewReader(body))
+	case strings.Contains(strings.ToLower(headers["content-encoding"]), "deflate"):
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return body, nil
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return body, nil
+	}
+	out, err := readLimited(r, limit)
+	if errors.Is(err, ErrResponseTooLarge) {
+		return nil, fmt.Errorf("decompressed %w", err)
+	}
+	if err != nil {
+		// Not actually compressed; let the parser report what it is.
+		return body, nil
+	}
+	return out, nil
+}
+
+// readBody reads a response body and decodes it as it streams in, so that
+// neither the compressed nor the decoded body is held past limit. It
+// detects encodings as decodeBody does and reports whether it decoded.
+func readBody(r io.Reader, headers map[string]string, limit int64) ([]byte, bool, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(16)
+	if looksDecoded(head) {
+		b, err := readLimited(br, limit)
+		return b, false, err
+	}
+	enc := strings.ToLower(headers["content-encoding"])
+	var dec io.Reader
+	switch {
+	case len(head) >= 2 && head[0] == 0x1f && head[1] == 0x8b:
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, false, fmt.Errorf("gzip body: %w", err)
+		}
+		defer zr.Close()
+		dec = zr
+	case strings.Contains(enc, "br"):
+		dec = brotli.NewReader(br)
+	case strings.Contains(enc, "deflate"):
+		zr, err := zlib.NewReader(br)
+		if err != nil {
+			return nil, false, fmt.Errorf("deflate body: %w", err)
+		}
+		defer zr.Close()
+		dec = zr
+	default:
+		b, err := readLimited(br, limit)
+		return b, false, err
+	}
+	out, err := readLimited(dec, limit)
+	if errors.Is(err, ErrResponseTooLarge) {
+		return nil, false, fmt.Errorf("decompressed %w", err)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("decode body: %w", err)
+	}
+	return out, true, nil
+}
+
+// looksDecoded reports whether body starts like JSON, HTML or text that
+// needs no decoding.
+func looksDecoded(body []byte) bool {
+	b := bytes.TrimLeft(body, " \t\r\n")
+	return len(b) == 0 || b[0] == '{' || b[0] == '[' || b[0] == '<' || b[0] == '"'
+}
@@ -0,0 +1,83 @@
+package twitter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBody(t *testing.T) {
+	payload := []byte(`{"data":{"user":{}}}`)
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write(payload)
+	require.NoError(t, zw.Close())
+
+	var br bytes.Buffer
+	bw := brotli.NewWriter(&br)
+	_, _ = bw.Write(payload)
+	require.NoError(t, bw.Close())
+
+	for name, tc := range map[string]struct {
+		body     []byte
+		encoding string
+	}{
+		"plain":             {payload, ""},
+		"already decoded":   {payload, "br"},
+		"gzip left encoded": {gz.Bytes(), ""},
+		"brotli":            {br.Bytes(), "br"},
+	} {
+		got, err := decodeBody(tc.body, map[string]string{"content-encoding": tc.encoding}, 1<<10)
+		require.NoError(t, err, name)
+		assert.Equal(t, payload, got, name)
+	}
+}
+
+func TestDecodeBody_Limits(t *testing.T) {
+	_, err := decodeBody(bytes.Repeat([]byte("x"), 101), nil, 100)
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+
+	// A small gzip body that inflates past the limit.
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte(`{"a":"` + strings.Repeat("x", 10_000) + `"}`))
+	require.NoError(t, zw.Close())
+	require.Less(t, gz.Len(), 1000)
+	_, err = decodeBody(gz.Bytes(), nil, 1000)
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+
+	got, err := decodeBody(gz.Bytes(), nil, -1)
+	require.NoError(t, err)
+	assert.Len(t, got, 10_008)
+}
+
+func TestReadBody(t *testing.T) {
+	payload := []byte(`{"data":{"user":{}}}`)
+	var br bytes.Buffer
+	bw := brotli.NewWriter(&br)
+	_, _ = bw.Write(payload)
+	require.NoError(t, bw.Close())
+
+	got, decoded, err := readBody(bytes.NewReader(br.Bytes()), map[string]string{"content-encoding": "br"}, 1<<10)
+	require.NoError(t, err)
+	assert.True(t, decoded)
+	assert.Equal(t, payload, got)
+
+	got, decoded, err = readBody(bytes.NewReader(payload), map[string]string{"content-encoding": "br"}, 1<<10)
+	require.NoError(t, err)
+	assert.False(t, decoded, "a body a proxy already decoded is read as is")
+	assert.Equal(t, payload, got)
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte(`{"a":"` + strings.Repeat("x", 10_000) + `"}`))
+	require.NoError(t, zw.Close())
+	_, _, err = readBody(&gz, nil, 1000)
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
@@ -0,0 +1,60 @@
+package twitter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSuspectResponse is returned when every account's answer to a read
+// failed its ResponseValidator, e.g. empty pages from shadow-limited
+// accounts.
+var ErrSuspectResponse = errors.New("response failed validation")
+
+// ResponseValidator checks a successful (HTTP 200, no error code) response
+// body of one operation. A non-nil error makes the pool retry the request on
+// a different account, as for an account error.
+type ResponseValidator func(body []byte) error
+
+// NonEmptyUserList rejects a Followers, Following or Retweeters page that
+// holds no users although its cursor says the list goes on: what
+// rate-limited or shadow-limited accounts get instead of an error.
+func NonEmptyUserList(body []byte) error {
+	users, cursor, err := parseRetweeterList(body)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 && !isEndCursor(cursor) {
+		return errors.New("empty user page before the end of the list")
+	}
+	return nil
+}
+
+// isEndCursor reports whether a bottom cursor marks the end of a list: none
+// at all, or X's "0|..." terminal cursor.
+func isEndCursor(cursor string) bool {
+	return cursor == "" || strings.HasPrefix(cursor, "0|")
+}
+
+// defaultValidators are used for operations without an entry in
+// ClientConfig.ResponseValidators.
+var defaultValidators = map[string]ResponseValidator{
+	"Followers":  NonEmptyUserList,
+	"Following":  NonEmptyUserList,
+	"Retweeters": NonEmptyUserList,
+}
+
+// validateResponse runs endpoint's validator, if any, on body.
+func (c *Client) validateResponse(endpoint string, body []byte) error {
+	v, ok := c.cfg.ResponseValidators[endpoint]
+	if !ok {
+		v = defaultValidators[endpoint]
+	}
+	if v == nil {
+		return nil
+	}
+	if err := v(body); err != nil {
+		return fmt.Errorf("%w: %w", ErrSuspectResponse, err)
+	}
+	return nil
+}
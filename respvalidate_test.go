@@ -0,0 +1,69 @@
+package twitter
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFollowers_ShadowLimitedPageRetried(t *testing.T) {
+	tr := &accountPagesTransport{pages: map[string]string{
+		"a": followersPage("c2"),              // empty page, list goes on
+		"b": followersPage("0|123", "1", "2"), // last page
+	}}
+	c, err := NewClient(ClientConfig{
+		Accounts: []*Account{
+			{Username: "a", AuthToken: "a", CT0: "c"},
+			{Username: "b", AuthToken: "b", CT0: "c"},
+		},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+		Retry:                &RetryPolicy{MaxAttempts: 3},
+	})
+	require.NoError(t, err)
+
+	r := c.GetFollowersPaged(context.Background(), "42", "", 10)
+	require.NoError(t, r.PartialErr)
+	assert.Len(t, r.Items, 2)
+	assert.True(t, r.Complete(), "the terminal 0| cursor ends the list")
+	assert.Contains(t, tr.tokens, "a")
+	assert.Equal(t, "b", tr.tokens[len(tr.tokens)-1])
+
+	// With validation off the empty page is accepted as the end of the list.
+	c.cfg.ResponseValidators = map[string]ResponseValidator{"Followers": nil}
+	tr.pages["b"] = followersPage("c2")
+	r = c.GetFollowersPaged(context.Background(), "42", "", 10)
+	require.NoError(t, r.PartialErr)
+	assert.Empty(t, r.Items)
+
+	// Every account empty: the caller gets ErrSuspectResponse, not silence.
+	c.cfg.ResponseValidators = nil
+	r = c.GetFollowersPaged(context.Background(), "42", "", 10)
+	assert.ErrorIs(t, r.PartialErr, ErrSuspectResponse)
+	assert.ErrorIs(t, r.PartialErr, ErrPoolExhausted)
+}
+
+// accountPagesTransport answers with the page of the requesting account,
+// identified by its auth_token cookie.
+type accountPagesTransport struct {
+	pages  map[string]string // auth_token → body
+	tokens []string
+}
+
+func (p *accountPagesTransport) Do(_ context.Context, _, _ string, headers map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	tok := strings.TrimPrefix(strings.Split(headers["cookie"], ";")[0], "auth_token=")
+	p.tokens = append(p.tokens, tok)
+	return []byte(p.pages[tok]), nil, 200, nil
+}
+
+func TestNonEmptyUserList(t *testing.T) {
+	assert.NoError(t, NonEmptyUserList([]byte(followersPage("c2", "1"))))
+	assert.NoError(t, NonEmptyUserList([]byte(followersPage("0|1"))))
+	assert.NoError(t, NonEmptyUserList([]byte(followersPage(""))))
+	assert.Error(t, NonEmptyUserList([]byte(followersPage("c2"))))
+}
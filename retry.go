@@ -0,0 +1,67 @@
+package twitter
+
+import (
+	"context"
+	"time"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+)
+
+// RetryPolicy controls how often a failed request is retried and how long to
+// wait in between. Latency-sensitive callers can fail fast with a single
+// attempt; batch jobs can retry more aggressively.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts per request, the first one
+	// included. Default: 3.
+	MaxAttempts int
+
+	// Backoff sets the wait before each retry. Default: stealth.DefaultBackoff.
+	Backoff stealth.BackoffConfig
+
+	// NoRetryOn429 ends the account attempts at a 429 instead of retrying
+	// the request on another account (pool reads may still fall back to a
+	// guest token).
+	NoRetryOn429 bool
+
+	// PerEndpointOverrides replaces the policy for the named operations
+	// (Endpoints keys). An override's own PerEndpointOverrides is ignored.
+	PerEndpointOverrides map[string]RetryPolicy
+}
+
+// defaultMaxAttempts is the attempt count when ClientConfig.Retry is unset.
+const defaultMaxAttempts = 3
+
+func (p *RetryPolicy) defaults() {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.Backoff == (stealth.BackoffConfig{}) {
+		p.Backoff = stealth.DefaultBackoff
+	}
+}
+
+// retryPolicy returns the policy for endpoint. Without ClientConfig.Retry,
+// requests get three attempts with the default backoff and retry on 429.
+func (c *Client) retryPolicy(endpoint string) RetryPolicy {
+	if c.cfg.Retry == nil {
+		return RetryPolicy{MaxAttempts: defaultMaxAttempts, Backoff: stealth.DefaultBackoff}
+	}
+	p := *c.cfg.Retry
+	if o, ok := p.PerEndpointOverrides[endpoint]; ok {
+		p = o
+	}
+	p.PerEndpointOverrides = nil
+	p.defaults()
+	return p
+}
+
+// wait sleeps before retry number attempt (1 for the first retry); it
+// returns early with ctx's error.
+func (p RetryPolicy) wait(ctx context.Context, attempt int) error {
+	select {
+	case <-time.After(p.Backoff.Duration(attempt)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
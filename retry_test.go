@@ -0,0 +1,44 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Resolution(t *testing.T) {
+	c := &Client{}
+	p := c.retryPolicy("SearchTimeline")
+	assert.Equal(t, defaultMaxAttempts, p.MaxAttempts)
+	assert.Equal(t, stealth.DefaultBackoff, p.Backoff)
+	assert.False(t, p.NoRetryOn429, "unset policy keeps retrying 429s")
+
+	fast := stealth.BackoffConfig{InitialWait: time.Millisecond, MaxWait: time.Millisecond, Multiplier: 1}
+	c.cfg.Retry = &RetryPolicy{
+		MaxAttempts: 6,
+		Backoff:     fast,
+		PerEndpointOverrides: map[string]RetryPolicy{
+			"UserByScreenName": {MaxAttempts: 1, NoRetryOn429: true},
+		},
+	}
+	p = c.retryPolicy("SearchTimeline")
+	assert.Equal(t, 6, p.MaxAttempts)
+	assert.Equal(t, fast, p.Backoff)
+	assert.False(t, p.NoRetryOn429, "a policy that sets only attempts still retries 429s")
+
+	p = c.retryPolicy("UserByScreenName")
+	assert.Equal(t, 1, p.MaxAttempts)
+	assert.Equal(t, stealth.DefaultBackoff, p.Backoff, "overrides are complete policies")
+	assert.True(t, p.NoRetryOn429)
+	assert.Nil(t, p.PerEndpointOverrides)
+}
+
+func TestRetryPolicy_WaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := RetryPolicy{Backoff: stealth.BackoffConfig{InitialWait: time.Hour, MaxWait: time.Hour, Multiplier: 1}}
+	assert.ErrorIs(t, p.wait(ctx, 1), context.Canceled)
+}
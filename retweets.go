@@ -0,0 +1,56 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+)
+
+// recentRetweeters returns the users behind the latest retweets of tweetID
+// (at most 100, newest first) from the 1.1 statuses/retweets endpoint.
+func (c *Client) recentRetweeters(ctx context.Context, tweetID string) ([]*TwitterUser, error) {
+	ctx = c.withTargetAffinity(ctx, "tweet", tweetID)
+	params := url.Values{"count": {"100"}, "trim_user": {"false"}}
+	body, _, err := c.doGET(ctx, "Retweets", fmt.Sprintf(retweetsURL, url.PathEscape(tweetID))+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("Retweets: %w", err)
+	}
+	return c.shapeUsers(parseRetweets(body))
+}
+
+// parseRetweets parses the retweeting users of a statuses/retweets response.
+func parseRetweets(body []byte) ([]*TwitterUser, error) {
+	var raw []struct {
+		User json.RawMessage `json:"user"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal retweets: %w", err)
+	}
+	users := make([]*TwitterUser, 0, len(raw))
+	for _, rt := range raw {
+		u, err := parseRESTUser(rt.User)
+		if err != nil {
+			slog.Debug("skip retweet user", slog.Any("error", err))
+			continue
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// mergeUsers appends the users of extra not already in users, keeping order.
+func mergeUsers(users, extra []*TwitterUser) []*TwitterUser {
+	seen := make(map[string]bool, len(users)+len(extra))
+	out := make([]*TwitterUser, 0, len(users)+len(extra))
+	for _, list := range [][]*TwitterUser{users, extra} {
+		for _, u := range list {
+			if !seen[u.ID] {
+				seen[u.ID] = true
+				out = append(out, u)
+			}
+		}
+	}
+	return out
+}
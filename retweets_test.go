@@ -0,0 +1,35 @@
+package twitter
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRetweeters_MergesRecentRetweets(t *testing.T) {
+	graphqlURL, err := EndpointURL("Retweeters")
+	require.NoError(t, err)
+	u, _ := url.Parse(graphqlURL)
+	tr := pathTransport{
+		u.Path:                            followersPage("", "1", "2"),
+		"/1.1/statuses/retweets/100.json": `[{"id_str":"9","user":{"id_str":"2","screen_name":"u2"}},{"id_str":"8","user":{"id_str":"3","screen_name":"u3"}}]`,
+	}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	users, err := c.GetRetweeters(context.Background(), "100", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, userIDs(users))
+
+	users, err = c.GetRetweeters(context.Background(), "100", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, userIDs(users), "a full GraphQL list needs no fallback")
+}
@@ -0,0 +1,70 @@
+package twitter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchQuery builds a SearchTimeline query from filters, so that X does
+// the filtering by language and region instead of the caller. Its String
+// is the raw query.
+type SearchQuery struct {
+	Text string // free text and any other operators, kept as given
+	From string // author handle, with or without @
+
+	// Lang keeps tweets X detected in this language (BCP 47, e.g. "en").
+	Lang string
+	// Place keeps tweets tagged with this place ID (see Tweet.Place).
+	Place string
+	// PlaceCountry keeps tweets tagged with a place in this country (ISO
+	// 3166-1 alpha-2, e.g. "US").
+	PlaceCountry string
+	// Near keeps tweets geotagged, or posted by users whose profile
+	// location lies, within the radius.
+	Near *GeoRadius
+
+	Since, Until time.Time // whole UTC days; Until is exclusive
+}
+
+// GeoRadius is a circle on Earth.
+type GeoRadius struct {
+	Center   GeoPoint
+	RadiusKm float64
+}
+
+// String returns the query with its operators in a fixed order.
+func (q SearchQuery) String() string {
+	var parts []string
+	if t := strings.TrimSpace(q.Text); t != "" {
+		parts = append(parts, t)
+	}
+	if h := strings.TrimPrefix(strings.TrimSpace(q.From), "@"); h != "" {
+		parts = append(parts, "from:"+h)
+	}
+	if q.Lang != "" {
+		parts = append(parts, "lang:"+q.Lang)
+	}
+	if q.Place != "" {
+		parts = append(parts, "place:"+q.Place)
+	}
+	if q.PlaceCountry != "" {
+		parts = append(parts, "place_country:"+strings.ToUpper(q.PlaceCountry))
+	}
+	if q.Near != nil {
+		parts = append(parts, "geocode:"+formatDegrees(q.Near.Center.Lat)+","+
+			formatDegrees(q.Near.Center.Lon)+","+formatDegrees(q.Near.RadiusKm)+"km")
+	}
+	if !q.Since.IsZero() {
+		parts = append(parts, "since:"+q.Since.UTC().Format(time.DateOnly))
+	}
+	if !q.Until.IsZero() {
+		parts = append(parts, "until:"+q.Until.UTC().Format(time.DateOnly))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatDegrees formats f without trailing zeros.
+func formatDegrees(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,22 @@
+package twitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchQuery_String(t *testing.T) {
+	q := SearchQuery{
+		Text:         "earthquake -is:retweet",
+		From:         "@USGS",
+		Lang:         "en",
+		PlaceCountry: "us",
+		Near:         &GeoRadius{Center: GeoPoint{Lat: 37.7749, Lon: -122.4194}, RadiusKm: 50},
+		Since:        time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, "earthquake -is:retweet from:USGS lang:en place_country:US geocode:37.7749,-122.4194,50km since:2025-03-01", q.String())
+	assert.Equal(t, "place:96683cc9126741d1", SearchQuery{Place: "96683cc9126741d1"}.String())
+	assert.Empty(t, SearchQuery{}.String())
+}
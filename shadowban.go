@@ -0,0 +1,64 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBanCheckInconclusive is returned by CheckSearchBan when the user has no
+// visible tweets to look for, e.g. a new or protected account.
+var ErrBanCheckInconclusive = errors.New("ban check inconclusive")
+
+// CheckSearchBan reports whether handle's tweets are hidden from search: a
+// "from:handle" search in the Latest tab finds none of the tweets on the
+// user's own timeline. Lookup errors, including a suspended or missing user,
+// are returned as is.
+func (c *Client) CheckSearchBan(ctx context.Context, handle string) (bool, error) {
+	u, err := c.GetUserByScreenName(ctx, handle)
+	if err != nil {
+		return false, err
+	}
+	if u.TweetCount == 0 {
+		return false, fmt.Errorf("%s: %w: no tweets", u.Handle, ErrBanCheckInconclusive)
+	}
+	found, err := c.SearchTimeline(ctx, "from:"+u.Handle, 20)
+	if err != nil {
+		return false, fmt.Errorf("search ban check: %w", err)
+	}
+	for _, t := range found {
+		if t.AuthorID == u.ID || strings.EqualFold(t.AuthorHandle, u.Handle) {
+			return false, nil
+		}
+	}
+	// Nothing found: only a ban if the timeline does show tweets.
+	own, err := c.GetUserTweets(ctx, u.ID, 5)
+	if err != nil {
+		return false, fmt.Errorf("search ban check: %w", err)
+	}
+	if len(own) == 0 {
+		return false, fmt.Errorf("%s: %w: no visible tweets", u.Handle, ErrBanCheckInconclusive)
+	}
+	return true, nil
+}
+
+// CheckSuggestionBan reports whether handle is missing from the search box
+// suggestions for its own exact @handle. Lookup errors, including a
+// suspended or missing user, are returned as is.
+func (c *Client) CheckSuggestionBan(ctx context.Context, handle string) (bool, error) {
+	u, err := c.GetUserByScreenName(ctx, handle)
+	if err != nil {
+		return false, err
+	}
+	suggested, err := c.Typeahead(ctx, "@"+u.Handle)
+	if err != nil {
+		return false, fmt.Errorf("suggestion ban check: %w", err)
+	}
+	for _, s := range suggested {
+		if s.ID == u.ID || strings.EqualFold(s.Handle, u.Handle) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
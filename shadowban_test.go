@@ -0,0 +1,68 @@
+package twitter
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShadowbanChecks(t *testing.T) {
+	path := func(op string) string {
+		raw, err := EndpointURL(op)
+		require.NoError(t, err)
+		u, _ := url.Parse(raw)
+		return u.Path
+	}
+	const user = `{"data":{"user":{"result":{"__typename":"User","rest_id":"7","legacy":{"screen_name":"Target","statuses_count":12}}}}}`
+	tweetEntry := `{"entryId":"tweet-1","content":{"entryType":"TimelineTimelineItem","itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{"__typename":"Tweet","rest_id":"1","legacy":{"full_text":"hi","user_id_str":"7"}}}}}}`
+	timeline := func(entries string) string {
+		return `{"timeline":{"instructions":[{"type":"TimelineAddEntries","entries":[` + entries + `]}]}}`
+	}
+	search := func(entries string) string {
+		return `{"data":{"search_by_raw_query":{"search_timeline":` + timeline(entries) + `}}}`
+	}
+	userTweets := func(entries string) string {
+		return `{"data":{"user":{"result":{"timeline":` + timeline(entries) + `}}}}`
+	}
+
+	tr := pathTransport{
+		path("UserByScreenName"):     user,
+		path("SearchTimeline"):       search(""),
+		path("UserTweets"):           userTweets(tweetEntry),
+		"/1.1/search/typeahead.json": `{"users":[{"id_str":"8","screen_name":"TargetFan"}]}`,
+	}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	banned, err := c.CheckSearchBan(ctx, "target")
+	require.NoError(t, err)
+	assert.True(t, banned, "timeline has tweets search does not find")
+
+	tr[path("SearchTimeline")] = search(tweetEntry)
+	banned, err = c.CheckSearchBan(ctx, "target")
+	require.NoError(t, err)
+	assert.False(t, banned)
+
+	tr[path("SearchTimeline")] = search("")
+	tr[path("UserTweets")] = userTweets("")
+	_, err = c.CheckSearchBan(ctx, "target")
+	assert.ErrorIs(t, err, ErrBanCheckInconclusive)
+
+	banned, err = c.CheckSuggestionBan(ctx, "target")
+	require.NoError(t, err)
+	assert.True(t, banned)
+
+	tr["/1.1/search/typeahead.json"] = `{"users":[{"id_str":"8","screen_name":"TargetFan"},{"id_str":"7","screen_name":"Target"}]}`
+	banned, err = c.CheckSuggestionBan(ctx, "target")
+	require.NoError(t, err)
+	assert.False(t, banned)
+}
@@ -0,0 +1,49 @@
+// Package natssink publishes sink messages to NATS subjects, or to
+// JetStream streams with acknowledgement and deduplication:
+//
+//	s := &sink.Sink{Publisher: natssink.New(nc), Topic: "twitter.{type}"}
+//
+// NATS routes by subject only, so Message.Key is not sent; the headers
+// are.
+package natssink
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/anatolykoptev/go-twitter/sink"
+)
+
+// New returns a Publisher sending on nc. Core NATS is fire and forget:
+// messages are buffered by nc and lost if the connection drops before
+// they are flushed. Use NewJetStream when every event must arrive.
+func New(nc *nats.Conn) sink.Publisher {
+	return sink.PublisherFunc(func(_ context.Context, m sink.Message) error {
+		return nc.PublishMsg(natsMsg(m))
+	})
+}
+
+// NewJetStream returns a Publisher that waits for the stream to store
+// each message. The event ID is sent as Nats-Msg-Id, so the stream drops
+// an event published twice within its duplicate window.
+func NewJetStream(js jetstream.JetStream) sink.Publisher {
+	return sink.PublisherFunc(func(ctx context.Context, m sink.Message) error {
+		_, err := js.PublishMsg(ctx, natsMsg(m))
+		return err
+	})
+}
+
+// natsMsg converts m to a NATS message on subject m.Topic.
+func natsMsg(m sink.Message) *nats.Msg {
+	msg := nats.NewMsg(m.Topic)
+	msg.Data = m.Value
+	for k, v := range m.Headers {
+		msg.Header.Set(k, v)
+	}
+	if id := m.Headers[sink.HeaderEventID]; id != "" {
+		msg.Header.Set(nats.MsgIdHdr, id)
+	}
+	return msg
+}
@@ -0,0 +1,94 @@
+package natssink
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+	"github.com/anatolykoptev/go-twitter/sink"
+)
+
+// published is one HPUB received by fakeServer.
+type published struct {
+	subject string
+	header  textproto.MIMEHeader
+	data    []byte
+}
+
+// fakeServer speaks just enough of the NATS client protocol to accept a
+// connection and record the messages published on it.
+func fakeServer(t *testing.T) (string, <-chan published) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	got := make(chan published, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, `INFO {"server_id":"fake","version":"2.10.0","proto":1,"headers":true,"max_payload":1048576}`+"\r\n")
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			switch fields[0] {
+			case "PING":
+				io.WriteString(conn, "PONG\r\n")
+			case "HPUB":
+				hdrLen, _ := strconv.Atoi(fields[len(fields)-2])
+				total, _ := strconv.Atoi(fields[len(fields)-1])
+				buf := make([]byte, total+2)
+				if _, err := io.ReadFull(r, buf); err != nil {
+					return
+				}
+				tp := textproto.NewReader(bufio.NewReader(strings.NewReader(string(buf[:hdrLen]))))
+				tp.ReadLine() // NATS/1.0
+				hdr, _ := tp.ReadMIMEHeader()
+				got <- published{subject: fields[1], header: hdr, data: buf[hdrLen:total]}
+			}
+		}
+	}()
+	return "nats://" + ln.Addr().String(), got
+}
+
+func TestNew_PublishesWithHeaders(t *testing.T) {
+	url, got := fakeServer(t)
+	nc, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer nc.Close()
+
+	s := &sink.Sink{Publisher: New(nc), Topic: "twitter.{type}"}
+	ev := twitter.MonitorEvent{Type: twitter.EventNewTweet, Target: "search:go", Tweet: &twitter.Tweet{ID: "5", Text: "hi"}}
+	require.NoError(t, s.Publish(context.Background(), ev))
+	require.NoError(t, nc.Flush())
+
+	select {
+	case p := <-got:
+		assert.Equal(t, "twitter.new_tweet", p.subject)
+		assert.Equal(t, "new_tweet:search:go:5", p.header.Get(nats.MsgIdHdr))
+		assert.Equal(t, "application/json", p.header.Get(sink.HeaderContentType))
+		assert.Contains(t, string(p.data), `"Text":"hi"`)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message published")
+	}
+}
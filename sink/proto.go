@@ -0,0 +1,156 @@
+package sink
+
+import (
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+// marshalEvent encodes ev as the MonitorEvent message of event.proto. The
+// field numbers below are the schema's; keep the two in step.
+func marshalEvent(ev twitter.MonitorEvent) []byte {
+	var b []byte
+	b = appendString(b, 1, ev.ID())
+	b = appendString(b, 2, string(ev.Type))
+	b = appendString(b, 3, ev.Target)
+	b = appendInt(b, 4, unixMilli(ev.At))
+	if ev.Tweet != nil {
+		b = appendMessage(b, 5, marshalTweet(ev.Tweet))
+	}
+	if ev.User != nil {
+		b = appendMessage(b, 6, marshalUser(ev.User))
+	}
+	if ev.Previous != nil {
+		b = appendMessage(b, 7, marshalUser(ev.Previous))
+	}
+	for _, ch := range ev.Changes {
+		var c []byte
+		c = appendString(c, 1, string(ch.Kind))
+		c = appendString(c, 2, ch.Old)
+		c = appendString(c, 3, ch.New)
+		c = appendInt(c, 4, int64(ch.Threshold))
+		c = appendInt(c, 5, ch.Delta)
+		b = appendMessage(b, 8, c)
+	}
+	return b
+}
+
+func marshalTweet(t *twitter.Tweet) []byte {
+	var b []byte
+	b = appendString(b, 1, t.ID)
+	b = appendString(b, 2, t.AuthorID)
+	b = appendString(b, 3, t.AuthorHandle)
+	b = appendString(b, 4, t.AuthorName)
+	b = appendString(b, 5, t.Text)
+	b = appendInt(b, 6, unixMilli(t.CreatedAt))
+	b = appendInt(b, 7, t.Views)
+	b = appendInt(b, 8, t.Likes)
+	b = appendInt(b, 9, t.Retweets)
+	b = appendInt(b, 10, t.Quotes)
+	b = appendInt(b, 11, t.ReplyCount)
+	b = appendString(b, 12, t.ConversationID)
+	b = appendString(b, 13, t.InReplyToID)
+	b = appendString(b, 14, t.InReplyToUserID)
+	b = appendString(b, 15, t.Lang)
+	for _, m := range t.Media {
+		var mb []byte
+		mb = appendString(mb, 1, m.Type)
+		mb = appendString(mb, 2, m.URL)
+		if v, ok := m.BestVariant(); ok {
+			mb = appendString(mb, 3, v.URL)
+		}
+		b = appendMessage(b, 16, mb)
+	}
+	for _, sym := range t.TokenMentions {
+		b = protowire.AppendTag(b, 17, protowire.BytesType)
+		b = protowire.AppendString(b, sym)
+	}
+	if p := t.Place; p != nil {
+		var pb []byte
+		pb = appendString(pb, 1, p.ID)
+		pb = appendString(pb, 2, p.Name)
+		pb = appendString(pb, 3, p.FullName)
+		pb = appendString(pb, 4, p.Type)
+		pb = appendString(pb, 5, p.Country)
+		pb = appendString(pb, 6, p.CountryCode)
+		b = appendMessage(b, 18, pb)
+	}
+	if g := t.Coordinates; g != nil {
+		var gb []byte
+		gb = appendDouble(gb, 1, g.Lat)
+		gb = appendDouble(gb, 2, g.Lon)
+		b = appendMessage(b, 19, gb)
+	}
+	return b
+}
+
+func marshalUser(u *twitter.TwitterUser) []byte {
+	var b []byte
+	b = appendString(b, 1, u.ID)
+	b = appendString(b, 2, u.Handle)
+	b = appendString(b, 3, u.DisplayName)
+	b = appendString(b, 4, u.Bio)
+	b = appendInt(b, 5, u.Followers)
+	b = appendInt(b, 6, u.Following)
+	b = appendInt(b, 7, u.TweetCount)
+	b = appendInt(b, 8, u.ListedCount)
+	b = appendInt(b, 9, unixMilli(u.CreatedAt))
+	b = appendBool(b, 10, u.IsVerified)
+	b = appendString(b, 11, u.AvatarURL)
+	b = appendString(b, 12, u.Location)
+	b = appendString(b, 13, u.URL)
+	b = appendBool(b, 14, u.Protected)
+	return b
+}
+
+// The append helpers skip zero values, as proto3 encoders do.
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendInt(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func appendBool(b []byte, num protowire.Number, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, 1)
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+// appendMessage appends an embedded message, even an empty one, so that a
+// set field stays distinguishable from an unset one.
+func appendMessage(b []byte, num protowire.Number, m []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m)
+}
+
+// unixMilli is t in Unix milliseconds, 0 for the zero time.
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
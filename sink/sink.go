@@ -0,0 +1,214 @@
+// Package sink publishes a go-twitter Monitor's events to a message broker,
+// so that high-volume pipelines consume them from a topic instead of
+// through an intermediate service:
+//
+//	nc, err := nats.Connect(nats.DefaultURL)
+//	...
+//	s := &sink.Sink{Publisher: natssink.New(nc), Topic: "twitter.{type}", Encoding: sink.Protobuf}
+//	go s.Run(ctx, monitor.Events())
+//
+// Subscriptions that yield tweets rather than events, such as
+// Client.PollSearch, go through RunTweets:
+//
+//	go s.RunTweets(ctx, "search:$BTC", client.PollSearch(ctx, "$BTC", time.Minute))
+//
+// natssink publishes to NATS and JetStream; it is the only adapter shipped.
+// There is no Kafka adapter: wrap the Kafka client of your choice in a
+// PublisherFunc, e.g. for segmentio/kafka-go:
+//
+//	w := &kafka.Writer{Addr: kafka.TCP("localhost:9092")}
+//	pub := sink.PublisherFunc(func(ctx context.Context, m sink.Message) error {
+//		msg := kafka.Message{Topic: m.Topic, Key: m.Key, Value: m.Value}
+//		for k, v := range m.Headers {
+//			msg.Headers = append(msg.Headers, kafka.Header{Key: k, Value: []byte(v)})
+//		}
+//		return w.WriteMessages(ctx, msg)
+//	})
+//
+// Events are encoded as JSON (twitter.MonitorEventPayload) or as the
+// protobuf message MonitorEvent of event.proto.
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+// Message is one encoded event, ready for a broker.
+type Message struct {
+	Topic string
+	// Key is the tweet ID, or the user ID for profile changes, so that a
+	// partitioned broker keeps each tweet's or user's events in order.
+	Key   []byte
+	Value []byte
+	// Headers hold the event ID (for broker-side deduplication), its type
+	// and the content type of Value.
+	Headers map[string]string
+}
+
+// Message header names.
+const (
+	HeaderEventID     = "event-id"
+	HeaderEventType   = "event-type"
+	HeaderContentType = "content-type"
+)
+
+// Publisher sends messages to a broker.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// PublisherFunc adapts a function to Publisher.
+type PublisherFunc func(ctx context.Context, msg Message) error
+
+// Publish calls f.
+func (f PublisherFunc) Publish(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// Encoding selects how events are serialized.
+type Encoding string
+
+const (
+	JSON     Encoding = "json"     // twitter.MonitorEventPayload as JSON
+	Protobuf Encoding = "protobuf" // the MonitorEvent message of event.proto
+)
+
+// contentTypes are the HeaderContentType values of the encodings.
+var contentTypes = map[Encoding]string{
+	JSON:     "application/json",
+	Protobuf: "application/x-protobuf",
+}
+
+// Sink publishes Monitor events through a Publisher.
+type Sink struct {
+	Publisher Publisher
+
+	// Topic is the topic or subject events go to. "{type}" in it is
+	// replaced by the event type, e.g. "twitter.{type}" sends new tweets to
+	// "twitter.new_tweet".
+	Topic string
+
+	// Encoding selects the serialization. Default: JSON.
+	Encoding Encoding
+
+	// Types limits the events published. Default: all.
+	Types []twitter.MonitorEventType
+
+	// OnError receives events Run or RunTweets failed to publish, which
+	// are also logged. They go on with the next event.
+	OnError func(ev twitter.MonitorEvent, err error)
+}
+
+// Run publishes events until the channel is closed, which returns nil, or
+// ctx is done, which returns ctx.Err().
+func (s *Sink) Run(ctx context.Context, events <-chan twitter.MonitorEvent) error {
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			s.deliver(ctx, ev)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RunTweets publishes the tweets of a subscription such as
+// Client.PollSearch as EventNewTweet events of target, e.g. "search:$BTC",
+// until the channel is closed, which returns nil, or ctx is done, which
+// returns ctx.Err().
+func (s *Sink) RunTweets(ctx context.Context, target string, tweets <-chan *twitter.Tweet) error {
+	for {
+		select {
+		case tw, ok := <-tweets:
+			if !ok {
+				return nil
+			}
+			s.deliver(ctx, twitter.MonitorEvent{Type: twitter.EventNewTweet, Target: target, At: time.Now(), Tweet: tw})
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// deliver publishes ev, logging and reporting a failure to OnError.
+func (s *Sink) deliver(ctx context.Context, ev twitter.MonitorEvent) {
+	if err := s.Publish(ctx, ev); err != nil && ctx.Err() == nil {
+		slog.Warn("sink publish failed", slog.String("target", ev.Target),
+			slog.String("type", string(ev.Type)), slog.Any("error", err))
+		if s.OnError != nil {
+			s.OnError(ev, err)
+		}
+	}
+}
+
+// Publish encodes ev and publishes it, unless Types filters it out.
+func (s *Sink) Publish(ctx context.Context, ev twitter.MonitorEvent) error {
+	if s.Publisher == nil {
+		return errors.New("sink: nil Publisher")
+	}
+	if len(s.Types) > 0 && !slices.Contains(s.Types, ev.Type) {
+		return nil
+	}
+	msg, err := s.Message(ev)
+	if err != nil {
+		return err
+	}
+	if err := s.Publisher.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("publish %s to %s: %w", msg.Headers[HeaderEventID], msg.Topic, err)
+	}
+	return nil
+}
+
+// Message encodes ev as the Sink would publish it.
+func (s *Sink) Message(ev twitter.MonitorEvent) (Message, error) {
+	enc := s.Encoding
+	if enc == "" {
+		enc = JSON
+	}
+	var value []byte
+	var err error
+	switch enc {
+	case JSON:
+		value, err = json.Marshal(ev.Payload())
+	case Protobuf:
+		value = marshalEvent(ev)
+	default:
+		err = fmt.Errorf("unknown encoding %q", enc)
+	}
+	if err != nil {
+		return Message{}, fmt.Errorf("sink: encode %s: %w", ev.Type, err)
+	}
+	return Message{
+		Topic: strings.ReplaceAll(s.Topic, "{type}", string(ev.Type)),
+		Key:   []byte(eventKey(ev)),
+		Value: value,
+		Headers: map[string]string{
+			HeaderEventID:     ev.ID(),
+			HeaderEventType:   string(ev.Type),
+			HeaderContentType: contentTypes[enc],
+		},
+	}, nil
+}
+
+// eventKey is the partition key of ev.
+func eventKey(ev twitter.MonitorEvent) string {
+	switch {
+	case ev.Tweet != nil:
+		return ev.Tweet.ID
+	case ev.User != nil:
+		return ev.User.ID
+	}
+	return ev.Target
+}
@@ -0,0 +1,184 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+// recorder is a Publisher keeping what it is sent.
+type recorder struct {
+	msgs []Message
+	err  error
+}
+
+func (r *recorder) Publish(_ context.Context, m Message) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.msgs = append(r.msgs, m)
+	return nil
+}
+
+var at = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newTweetEvent() twitter.MonitorEvent {
+	return twitter.MonitorEvent{
+		Type: twitter.EventNewTweet, Target: "search:go", At: at,
+		Tweet: &twitter.Tweet{
+			ID: "99", AuthorHandle: "gopher", Text: "hello", Likes: 5, CreatedAt: at,
+			TokenMentions: []string{"BTC"},
+			Coordinates:   &twitter.GeoPoint{Lat: 52.5, Lon: 13.4},
+		},
+	}
+}
+
+func TestSink_JSON(t *testing.T) {
+	rec := &recorder{}
+	s := &Sink{Publisher: rec, Topic: "twitter.{type}"}
+
+	require.NoError(t, s.Publish(context.Background(), newTweetEvent()))
+	require.Len(t, rec.msgs, 1)
+	m := rec.msgs[0]
+	assert.Equal(t, "twitter.new_tweet", m.Topic)
+	assert.Equal(t, "99", string(m.Key))
+	assert.Equal(t, "new_tweet:search:go:99", m.Headers[HeaderEventID])
+	assert.Equal(t, "application/json", m.Headers[HeaderContentType])
+
+	var p twitter.MonitorEventPayload
+	require.NoError(t, json.Unmarshal(m.Value, &p))
+	assert.Equal(t, "hello", p.Tweet.Text)
+	assert.Equal(t, "search:go", p.Target)
+}
+
+func TestSink_Protobuf(t *testing.T) {
+	s := &Sink{Publisher: &recorder{}, Topic: "events", Encoding: Protobuf}
+
+	m, err := s.Message(newTweetEvent())
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-protobuf", m.Headers[HeaderContentType])
+
+	ev := decodeFields(t, m.Value)
+	assert.Equal(t, "new_tweet:search:go:99", string(ev[1][0]))
+	assert.Equal(t, "new_tweet", string(ev[2][0]))
+	assert.Equal(t, at.UnixMilli(), varint(t, ev[4][0]))
+
+	tw := decodeFields(t, ev[5][0])
+	assert.Equal(t, "99", string(tw[1][0]))
+	assert.Equal(t, "gopher", string(tw[3][0]))
+	assert.Equal(t, "hello", string(tw[5][0]))
+	assert.Equal(t, int64(5), varint(t, tw[8][0]))
+	assert.Equal(t, "BTC", string(tw[17][0]))
+	assert.Empty(t, tw[7], "zero views are not encoded")
+
+	geo := decodeFields(t, tw[19][0])
+	lat, _ := protowire.ConsumeFixed64(geo[1][0])
+	assert.Equal(t, 52.5, math.Float64frombits(lat))
+}
+
+func TestSink_ProfileChangeProtobuf(t *testing.T) {
+	s := &Sink{Encoding: Protobuf}
+	m, err := s.Message(twitter.MonitorEvent{
+		Type: twitter.EventProfileChange, Target: "user:12", At: at,
+		User:     &twitter.TwitterUser{ID: "12", Bio: "new", Followers: 10},
+		Previous: &twitter.TwitterUser{ID: "12", Bio: "old"},
+		Changes:  []twitter.ProfileChange{{Kind: twitter.BioChanged, Old: "old", New: "new"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "12", string(m.Key))
+
+	ev := decodeFields(t, m.Value)
+	assert.Equal(t, "new", string(decodeFields(t, ev[6][0])[4][0]))
+	assert.Equal(t, "old", string(decodeFields(t, ev[7][0])[4][0]))
+	diff := decodeFields(t, ev[8][0])
+	assert.Equal(t, string(twitter.BioChanged), string(diff[1][0]))
+	assert.Equal(t, "new", string(diff[3][0]))
+}
+
+func TestSink_Run(t *testing.T) {
+	rec := &recorder{}
+	var failed []twitter.MonitorEvent
+	s := &Sink{
+		Publisher: rec,
+		Types:     []twitter.MonitorEventType{twitter.EventNewTweet},
+		OnError:   func(ev twitter.MonitorEvent, _ error) { failed = append(failed, ev) },
+	}
+
+	events := make(chan twitter.MonitorEvent, 3)
+	events <- newTweetEvent()
+	events <- twitter.MonitorEvent{Type: twitter.EventDeletedTweet, Tweet: &twitter.Tweet{ID: "1"}}
+	close(events)
+	require.NoError(t, s.Run(context.Background(), events))
+	assert.Len(t, rec.msgs, 1, "filtered types are skipped")
+
+	rec.err = errors.New("broker down")
+	events = make(chan twitter.MonitorEvent, 1)
+	events <- newTweetEvent()
+	close(events)
+	require.NoError(t, s.Run(context.Background(), events))
+	assert.Len(t, failed, 1)
+}
+
+func TestSink_RunTweets(t *testing.T) {
+	rec := &recorder{}
+	s := &Sink{Publisher: rec, Topic: "twitter.{type}"}
+
+	tweets := make(chan *twitter.Tweet, 2)
+	tweets <- &twitter.Tweet{ID: "1", Text: "a"}
+	tweets <- &twitter.Tweet{ID: "2", Text: "b"}
+	close(tweets)
+	require.NoError(t, s.RunTweets(context.Background(), "search:$BTC", tweets))
+
+	require.Len(t, rec.msgs, 2)
+	assert.Equal(t, "twitter.new_tweet", rec.msgs[0].Topic)
+	assert.Equal(t, "new_tweet:search:$BTC:1", rec.msgs[0].Headers[HeaderEventID])
+	assert.Equal(t, "2", string(rec.msgs[1].Key))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, s.RunTweets(ctx, "search:go", make(chan *twitter.Tweet)), context.Canceled)
+}
+
+func TestSink_UnknownEncoding(t *testing.T) {
+	s := &Sink{Publisher: &recorder{}, Encoding: "avro"}
+	assert.Error(t, s.Publish(context.Background(), newTweetEvent()))
+}
+
+// decodeFields splits a protobuf message into the raw values of each field
+// number: bytes for length-delimited fields, the encoded value otherwise.
+func decodeFields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	t.Helper()
+	fields := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		var v []byte
+		if typ == protowire.BytesType {
+			v, n = protowire.ConsumeBytes(b)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			v = b[:max(n, 0)]
+		}
+		require.GreaterOrEqual(t, n, 0)
+		fields[num] = append(fields[num], v)
+		b = b[n:]
+	}
+	return fields
+}
+
+func varint(t *testing.T, b []byte) int64 {
+	t.Helper()
+	v, n := protowire.ConsumeVarint(b)
+	require.Positive(t, n)
+	return int64(v)
+}
@@ -0,0 +1,175 @@
+package twitter
+
+import (
+	"maps"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/anatolykoptev/go-twitter/xtid"
+)
+
+// AccountStats is a point-in-time snapshot of a single pool account.
+type AccountStats struct {
+	Username     string
+	Active       bool
+	ReactivateAt time.Time // zero unless soft-deactivated
+	OffHours     bool      // outside Account.ActiveHours; the pool skips it
+	Labels       map[string]string
+
+	Total       int
+	Failed      int
+	ConsecFails int
+
+	// RateLimited maps endpoint names to when their rate-limit window ends.
+	// Only endpoints that are currently blocked are included.
+	RateLimited map[string]time.Time
+
+	ProxyBackoffUntil time.Time
+	ProxyConsecFails  int
+
+	LastError     string
+	LastErrorAt   time.Time
+	LastSuccessAt time.Time
+
+	Relogins      int // relogin attempts since startup
+	CaptchaSolves int // CAPTCHA solve attempts since startup (each one is billed)
+
+	Proxy  string // account proxy with the password redacted; empty for DefaultProxy
+	CT0Age time.Duration
+
+	ActionsToday map[ActionClass]int // write actions counted against ClientConfig.ActionQuotas
+}
+
+// ClientStats is a point-in-time snapshot of the client's pool and guest-token state.
+type ClientStats struct {
+	Accounts []AccountStats
+
+	GuestTokenAvailable bool
+	GuestLimitedUntil   time.Time
+	GuestBlockedUntil   time.Time
+
+	// Adaptive concurrency state; all zero unless AdaptiveConcurrency is set.
+	ConcurrencyLimit int
+	InFlight         int
+	ExtraJitter      time.Duration
+
+	// XTID is the x-client-transaction-id key state and counters;
+	// GenerateFailures counts requests sent without the header.
+	XTID xtid.Stats
+
+	// Connections counts new and reused connections; zero unless
+	// ClientConfig.Connections or DNS is set.
+	Connections ConnStats
+}
+
+// Stats returns a snapshot of per-account state (activation, health counters,
+// rate-limit windows, proxy backoff, last error) and guest-token state.
+// Accounts are sorted by username.
+func (c *Client) Stats() ClientStats {
+	items := c.pool.Items()
+	stats := ClientStats{Accounts: make([]AccountStats, 0, len(items))}
+	for _, acc := range items {
+		stats.Accounts = append(stats.Accounts, acc.snapshot())
+	}
+	sort.Slice(stats.Accounts, func(i, j int) bool {
+		return stats.Accounts[i].Username < stats.Accounts[j].Username
+	})
+
+	_, stats.GuestTokenAvailable = c.getGuestTokenCached()
+	c.mu.Lock()
+	stats.GuestLimitedUntil = c.guestLimitedUntil
+	stats.GuestBlockedUntil = c.guestBlockedUntil
+	c.mu.Unlock()
+	stats.ConcurrencyLimit, stats.InFlight, stats.ExtraJitter = c.adaptive.state()
+	stats.XTID = c.xtidStats()
+	stats.Connections = c.conns.snapshot()
+	return stats
+}
+
+// snapshot collects the account's current state for Stats.
+func (a *Account) snapshot() AccountStats {
+	total, failed, consec := a.Stats()
+	s := AccountStats{
+		Username:     a.Username,
+		Active:       a.IsActive(),
+		ReactivateAt: a.ReactivateAt(),
+		OffHours:     a.offHours(time.Now()),
+		Labels:       maps.Clone(a.Labels),
+		Total:        total,
+		Failed:       failed,
+		ConsecFails:  consec,
+		CT0Age:       a.CT0Age(),
+		ActionsToday: a.actionsToday(time.Now()),
+	}
+
+	now := time.Now()
+	for _, name := range endpointNames() {
+		if until := a.EndpointAvailableAt(name); until.After(now) {
+			if s.RateLimited == nil {
+				s.RateLimited = make(map[string]time.Time)
+			}
+			s.RateLimited[name] = until
+		}
+	}
+
+	a.mu.Lock()
+	s.ProxyBackoffUntil = a.proxyBackoff
+	s.ProxyConsecFails = a.proxyConsecFails
+	s.LastError = a.lastError
+	s.LastErrorAt = a.lastErrorAt
+	s.LastSuccessAt = a.lastSuccessAt
+	s.Relogins = a.relogins
+	s.CaptchaSolves = a.captchaSolves
+	proxy := a.Proxy
+	a.mu.Unlock()
+	s.Proxy = redactProxy(a.renderedProxy(proxy))
+	return s
+}
+
+// recordSuccess records a successful request in the health tracker and
+// remembers when it happened.
+func (a *Account) recordSuccess() {
+	a.RecordSuccess()
+	a.mu.Lock()
+	a.lastSuccessAt = time.Now()
+	a.mu.Unlock()
+}
+
+// countRelogin and countCaptcha track per-account auth spend for Stats.
+func (a *Account) countRelogin() {
+	a.mu.Lock()
+	a.relogins++
+	a.mu.Unlock()
+}
+
+func (a *Account) countCaptcha() {
+	a.mu.Lock()
+	a.captchaSolves++
+	a.mu.Unlock()
+}
+
+// redactProxy hides the password in a proxy URL.
+func redactProxy(proxy string) string {
+	if proxy == "" {
+		return ""
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return "(invalid proxy URL)"
+	}
+	return u.Redacted()
+}
+
+// recordError remembers err as the account's most recent failure and returns it
+// unchanged, so call sites can write `lastErr = acc.recordError(err)`.
+func (a *Account) recordError(err error) error {
+	if err == nil {
+		return nil
+	}
+	a.mu.Lock()
+	a.lastError = err.Error()
+	a.lastErrorAt = time.Now()
+	a.mu.Unlock()
+	return err
+}
@@ -0,0 +1,49 @@
+package twitter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStats(t *testing.T) {
+	accB := &Account{Username: "bravo", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	accA := &Account{Username: "alpha", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	c := &Client{
+		pool: pool.New([]*Account{accB, accA}, pool.Config{}),
+		cfg:  ClientConfig{ProxyBackoffInitial: time.Minute, ProxyBackoffMax: time.Hour},
+	}
+
+	err := accB.recordError(errors.New("429 rate limited"))
+	assert.EqualError(t, err, "429 rate limited")
+	accB.Proxy = "http://proxy:8080"
+	c.markProxyDown(accB)
+
+	stats := c.Stats()
+	require.Len(t, stats.Accounts, 2)
+	assert.Equal(t, "alpha", stats.Accounts[0].Username)
+	assert.Equal(t, "bravo", stats.Accounts[1].Username)
+
+	a := stats.Accounts[0]
+	assert.True(t, a.Active)
+	assert.Empty(t, a.LastError)
+	assert.True(t, a.ProxyBackoffUntil.IsZero())
+
+	b := stats.Accounts[1]
+	assert.Equal(t, "429 rate limited", b.LastError)
+	assert.WithinDuration(t, time.Now(), b.LastErrorAt, time.Second)
+	assert.Equal(t, 1, b.ProxyConsecFails)
+	assert.True(t, b.ProxyBackoffUntil.After(time.Now()))
+
+	assert.False(t, stats.GuestTokenAvailable)
+}
+
+func TestRecordError_Nil(t *testing.T) {
+	acc := &Account{Username: "u"}
+	assert.NoError(t, acc.recordError(nil))
+	assert.Empty(t, acc.snapshot().LastError)
+}
@@ -0,0 +1,181 @@
+package twitter
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// PoolStrategy controls which eligible account serves the next request.
+type PoolStrategy string
+
+const (
+	// StrategyRoundRobin rotates through accounts in order (the pool's own
+	// behavior). Default.
+	StrategyRoundRobin PoolStrategy = "round_robin"
+	// StrategyLeastUsed picks the account with the fewest requests in the
+	// current 15-minute window, spreading wear evenly.
+	StrategyLeastUsed PoolStrategy = "least_used"
+	// StrategyRandom picks uniformly at random.
+	StrategyRandom PoolStrategy = "random"
+	// StrategyWeighted picks at random, weighted by each account's success rate.
+	StrategyWeighted PoolStrategy = "weighted"
+)
+
+// nextAccount selects an account for endpoint: the account bound to the
+// context's affinity key if it can serve the request, otherwise one chosen by
+// the configured PoolStrategy. eligible narrows the accounts without
+// consuming anything; endpoint's rate-limit budget is only spent on the
+// account taken. wait > 0 lets the pool block for an account to free up.
+func (c *Client) nextAccount(ctx context.Context, endpoint string, eligible func(*Account) bool, wait time.Duration) (*Account, error) {
+	key := affinityKey(ctx)
+	if !c.cfg.StickyAffinity {
+		key = ""
+	}
+	if key != "" {
+		if sticky := c.affinity.lookup(key, c.cfg.AffinityTTL); sticky != nil {
+			acc, err := c.pool.Next(func(a *Account) bool { return a == sticky && eligible(a) && a.AllowRequest(endpoint) })
+			if err == nil {
+				acc.noteUse()
+				c.affinity.bind(key, acc, c.cfg.AffinityTTL)
+				return acc, nil
+			}
+		}
+	}
+	acc, err := c.selectAccount(ctx, endpoint, eligible, wait)
+	if err == nil && key != "" {
+		c.affinity.bind(key, acc, c.cfg.AffinityTTL)
+	}
+	return acc, err
+}
+
+// selectAccount picks an account according to PoolStrategy. Requests that
+// may wait queue for the account by priority (see WithPriority).
+func (c *Client) selectAccount(ctx context.Context, endpoint string, eligible func(*Account) bool, wait time.Duration) (*Account, error) {
+	if wait <= 0 {
+		return c.takeAccount(ctx, endpoint, eligible, 0)
+	}
+	return c.queue.acquire(ctx, endpoint, priorityFrom(ctx), wait, func(slice time.Duration) (*Account, error) {
+		return c.takeAccount(ctx, endpoint, eligible, slice)
+	})
+}
+
+// takeAccount implements selectAccount for one pool wait of up to wait.
+func (c *Client) takeAccount(ctx context.Context, endpoint string, eligible func(*Account) bool, wait time.Duration) (*Account, error) {
+	filter := func(a *Account) bool { return eligible(a) && a.AllowRequest(endpoint) }
+	if s := c.cfg.PoolStrategy; s != "" && s != StrategyRoundRobin {
+		candidates := slices.DeleteFunc(c.eligibleAccounts(endpoint), func(a *Account) bool { return !eligible(a) })
+		if labels := labelsFrom(ctx); len(labels) > 0 {
+			candidates = slices.DeleteFunc(candidates, func(a *Account) bool { return !a.hasLabels(labels) })
+		}
+		for len(candidates) > 0 {
+			chosen := pickAccount(s, candidates)
+			acc, err := c.pool.Next(func(a *Account) bool { return a == chosen && filter(a) })
+			if err == nil {
+				acc.noteUse()
+				return acc, nil
+			}
+			// Out of budget, or taken by another request: pick again.
+			candidates = slices.DeleteFunc(candidates, func(a *Account) bool { return a == chosen })
+		}
+		// Nothing can serve right now: wait on the pool's own selection.
+	}
+
+	var acc *Account
+	var err error
+	if wait > 0 {
+		acc, err = c.pool.NextWithWait(ctx, filter, wait)
+	} else {
+		acc, err = c.pool.Next(filter)
+	}
+	if err == nil {
+		acc.noteUse()
+	}
+	return acc, err
+}
+
+// eligibleAccounts returns accounts in service (see inService) that are
+// inside their ActiveHours and neither rate-limited on endpoint nor in proxy
+// backoff. Unlike the pool filter it consumes no rate-limit budget.
+func (c *Client) eligibleAccounts(endpoint string) []*Account {
+	now := time.Now()
+	var out []*Account
+	for _, a := range c.pool.Items() {
+		if !a.inService(now) || a.offHours(now) || a.IsEndpointRateLimited(endpoint) {
+			continue
+		}
+		a.mu.Lock()
+		backoff := a.proxyBackoff
+		a.mu.Unlock()
+		if now.After(backoff) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// pickAccount applies strategy s to candidates. Returns nil if there are none.
+func pickAccount(s PoolStrategy, candidates []*Account) *Account {
+	if len(candidates) == 0 {
+		return nil
+	}
+	switch s {
+	case StrategyLeastUsed:
+		best := candidates[0]
+		bestUses := best.windowUses()
+		for _, a := range candidates[1:] {
+			if u := a.windowUses(); u < bestUses {
+				best, bestUses = a, u
+			}
+		}
+		return best
+	case StrategyWeighted:
+		weights := make([]float64, len(candidates))
+		var sum float64
+		for i, a := range candidates {
+			weights[i] = a.successWeight()
+			sum += weights[i]
+		}
+		r := rand.Float64() * sum
+		for i, w := range weights {
+			if r < w {
+				return candidates[i]
+			}
+			r -= w
+		}
+		return candidates[len(candidates)-1]
+	default: // StrategyRandom
+		return candidates[rand.IntN(len(candidates))]
+	}
+}
+
+// successWeight is the account's smoothed success rate, (ok+1)/(total+2), so
+// fresh accounts start at 0.5 and a failing account is never fully excluded.
+func (a *Account) successWeight() float64 {
+	total, failed, _ := a.Stats()
+	return float64(total-failed+1) / float64(total+2)
+}
+
+// noteUse counts one request against the account's current usage window.
+func (a *Account) noteUse() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rollUsageWindowLocked(time.Now())
+	a.uses++
+}
+
+// windowUses returns the number of requests in the current usage window.
+func (a *Account) windowUses() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rollUsageWindowLocked(time.Now())
+	return a.uses
+}
+
+func (a *Account) rollUsageWindowLocked(now time.Time) {
+	if now.Sub(a.usesSince) >= rateWindow {
+		a.usesSince = now
+		a.uses = 0
+	}
+}
@@ -0,0 +1,94 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickAccount_LeastUsed(t *testing.T) {
+	busy := &Account{Username: "busy", active: true}
+	idle := &Account{Username: "idle", active: true}
+	for range 3 {
+		busy.noteUse()
+	}
+	idle.noteUse()
+
+	assert.Same(t, idle, pickAccount(StrategyLeastUsed, []*Account{busy, idle}))
+	assert.Nil(t, pickAccount(StrategyLeastUsed, nil))
+}
+
+func TestEligibleAccounts_Cooldown(t *testing.T) {
+	rested := &Account{Username: "rested", reactivateAt: time.Now().Add(-time.Minute)}
+	resting := &Account{Username: "resting", reactivateAt: time.Now().Add(time.Hour)}
+	dead := &Account{Username: "dead"}
+	c := &Client{pool: pool.New([]*Account{rested, resting, dead}, pool.Config{})}
+
+	assert.Equal(t, []*Account{rested}, c.eligibleAccounts("UserTweets"), "an ended cooldown counts as active")
+}
+
+func TestPickAccount_Weighted(t *testing.T) {
+	good := &Account{Username: "good", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	bad := &Account{Username: "bad", active: true, HealthTracker: pool.DefaultHealthTracker()}
+	for range 50 {
+		good.RecordSuccess()
+		bad.RecordFailure()
+	}
+
+	picks := map[string]int{}
+	for range 1000 {
+		picks[pickAccount(StrategyWeighted, []*Account{good, bad}).Username]++
+	}
+	assert.Greater(t, picks["good"], 900)
+	assert.Greater(t, picks["bad"], 0, "failing accounts keep a small share")
+}
+
+func TestNextAccount_Strategy(t *testing.T) {
+	a := &Account{Username: "a", active: true}
+	b := &Account{Username: "b", active: true}
+	b.noteUse()
+	c := &Client{
+		pool: pool.New([]*Account{a, b}, pool.Config{}),
+		cfg:  ClientConfig{PoolStrategy: StrategyLeastUsed},
+	}
+	allow := func(*Account) bool { return true }
+
+	// Each pick bumps the chosen account's usage; ties go to pool order.
+	for _, want := range []*Account{a, a, b, a} {
+		got, err := c.nextAccount(context.Background(), "UserTweets", allow, 0)
+		require.NoError(t, err)
+		assert.Same(t, want, got)
+	}
+
+	// b is least used, but the strategy only picks among eligible accounts.
+	got, err := c.nextAccount(context.Background(), "UserTweets", func(x *Account) bool { return x == a }, 0)
+	require.NoError(t, err)
+	assert.Same(t, a, got)
+}
+
+func TestNextAccount_StrategyAfterRejection(t *testing.T) {
+	a := &Account{Username: "a", active: true}
+	busy := &Account{Username: "busy", active: true}
+	idle := &Account{Username: "idle", active: true}
+	for range 3 {
+		busy.noteUse()
+	}
+	idle.noteUse()
+	c := &Client{
+		pool: pool.New([]*Account{a, busy, idle}, pool.Config{}),
+		cfg:  ClientConfig{PoolStrategy: StrategyLeastUsed},
+	}
+
+	// A retry excludes the account that rejected the first attempt; the
+	// pick among the rest still follows the strategy, not pool order.
+	rejected := map[*Account]bool{a: true}
+	for range 2 {
+		got, err := c.nextAccount(context.Background(), "UserTweets", func(x *Account) bool { return !rejected[x] }, 0)
+		require.NoError(t, err)
+		assert.Same(t, idle, got)
+	}
+}
@@ -0,0 +1,255 @@
+package twitter
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	defaultPollCount    = 20
+	defaultSeenSize     = 2000
+)
+
+// PollOption configures PollSearch and PollUserTweets.
+type PollOption func(*pollOptions)
+
+type pollOptions struct {
+	count    int
+	seenFile string
+	seenSize int
+	onError  func(error)
+}
+
+// WithPollCount sets how many tweets each poll requests (default 20). It
+// should exceed the number of new tweets expected per interval.
+func WithPollCount(n int) PollOption {
+	return func(o *pollOptions) { o.count = n }
+}
+
+// WithSeenFile sets where the seen-set is persisted. By default it lives in
+// SessionDir, keyed by the subscription, so a restarted subscription does not
+// re-deliver tweets it already delivered. An empty path keeps it in memory.
+func WithSeenFile(path string) PollOption {
+	return func(o *pollOptions) { o.seenFile = path }
+}
+
+// WithSeenSize caps the number of remembered tweet IDs (default 2000); the
+// oldest are forgotten first.
+func WithSeenSize(n int) PollOption {
+	return func(o *pollOptions) { o.seenSize = n }
+}
+
+// WithPollErrors registers fn to receive poll failures. Failures are logged
+// and the subscription keeps polling either way.
+func WithPollErrors(fn func(error)) PollOption {
+	return func(o *pollOptions) { o.onError = fn }
+}
+
+// PollSearch polls SearchTimeline (Latest) for query every interval (default
+// 1m) and delivers tweets not seen before, oldest first. A tweet counts as
+// seen once it is received from the channel, which is closed when ctx is
+// done.
+func (c *Client) PollSearch(ctx context.Context, query string, interval time.Duration, opts ...PollOption) <-chan *Tweet {
+	return c.poll(ctx, "search:"+query, interval, opts, func(ctx context.Context, count int) ([]*Tweet, error) {
+		return c.SearchTimeline(ctx, query, count)
+	})
+}
+
+// PollUserTweets polls GetUserTweets for userID every interval (default 1m)
+// and delivers tweets not seen before, oldest first, as PollSearch does.
+func (c *Client) PollUserTweets(ctx context.Context, userID string, interval time.Duration, opts ...PollOption) <-chan *Tweet {
+	return c.poll(ctx, "user:"+userID, interval, opts, func(ctx context.Context, count int) ([]*Tweet, error) {
+		return c.GetUserTweets(ctx, userID, count)
+	})
+}
+
+// poll runs fetch immediately and then every interval, delivering unseen
+// tweets on the returned channel until ctx is done.
+func (c *Client) poll(ctx context.Context, key string, interval time.Duration, opts []PollOption,
+	fetch func(ctx context.Context, count int) ([]*Tweet, error)) <-chan *Tweet {
+	o := pollOptions{
+		count:    defaultPollCount,
+		seenFile: sessionDirFile(c.cfg.SessionDir, seenFileName("poll", key)),
+		seenSize: defaultSeenSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	seen := newSeenSet(o.seenSize, o.seenFile)
+	if err := seen.load(); err != nil {
+		slog.Warn("load seen-set failed", slog.String("subscription", key), slog.Any("error", err))
+	}
+
+	// Unbuffered, so that a tweet is marked seen only once it is received.
+	out := make(chan *Tweet)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			tweets, err := fetch(ctx, o.count)
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("subscription poll failed", slog.String("subscription", key), slog.Any("error", err))
+				if o.onError != nil {
+					o.onError(err)
+				}
+			}
+			delivered := false
+		deliver:
+			for _, tw := range seen.unseen(tweets) {
+				select {
+				case out <- tw:
+					seen.mark(tw.ID)
+					delivered = true
+				case <-ctx.Done():
+					break deliver
+				}
+			}
+			if delivered {
+				if err := seen.save(); err != nil {
+					slog.Warn("save seen-set failed", slog.String("subscription", key), slog.Any("error", err))
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// seenFileName is the file the seen-set of key is kept in inside
+// SessionDir; kind ("poll", "monitor") keeps a subscription and a Monitor
+// target with the same key apart.
+func seenFileName(kind, key string) string {
+	sum := sha1.Sum([]byte(key))
+	return ".seen-" + kind + "-" + hex.EncodeToString(sum[:8]) + ".json"
+}
+
+// seenSet remembers the most recent tweet IDs delivered by a subscription,
+// optionally persisted to a file.
+type seenSet struct {
+	mu    sync.Mutex
+	size  int
+	path  string
+	ids   map[string]struct{}
+	order []string // oldest first
+}
+
+func newSeenSet(size int, path string) *seenSet {
+	if size <= 0 {
+		size = defaultSeenSize
+	}
+	return &seenSet{size: size, path: path, ids: make(map[string]struct{})}
+}
+
+// unseen returns the tweets not seen before, oldest first, without
+// recording them: mark each once it is delivered.
+func (s *seenSet) unseen(tweets []*Tweet) []*Tweet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var fresh []*Tweet
+	batch := make(map[string]bool)
+	for _, tw := range tweets {
+		if tw == nil || tw.ID == "" || batch[tw.ID] {
+			continue
+		}
+		if _, ok := s.ids[tw.ID]; ok {
+			continue
+		}
+		batch[tw.ID] = true
+		fresh = append(fresh, tw)
+	}
+	slices.SortFunc(fresh, func(a, b *Tweet) int { return compareIDs(a.ID, b.ID) })
+	return fresh
+}
+
+// mark records id as seen.
+func (s *seenSet) mark(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[id]; !ok {
+		s.addLocked(id)
+	}
+}
+
+func (s *seenSet) addLocked(id string) {
+	s.ids[id] = struct{}{}
+	s.order = append(s.order, id)
+	for len(s.order) > s.size {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// load reads the persisted IDs; a missing file is not an error.
+func (s *seenSet) load() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return fmt.Errorf("parse seen-set: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := s.ids[id]; !ok {
+			s.addLocked(id)
+		}
+	}
+	return nil
+}
+
+// save writes the IDs to the file, replacing it atomically.
+func (s *seenSet) save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.mu.Lock()
+	data, err := json.Marshal(s.order)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("create seen-set dir: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// compareIDs orders snowflake IDs numerically without parsing them.
+func compareIDs(a, b string) int {
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a, b)
+}
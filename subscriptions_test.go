@@ -0,0 +1,115 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tweetsWithIDs(ids ...string) []*Tweet {
+	tweets := make([]*Tweet, len(ids))
+	for i, id := range ids {
+		tweets[i] = &Tweet{ID: id}
+	}
+	return tweets
+}
+
+func collectTweets(t *testing.T, ch <-chan *Tweet, n int) []string {
+	t.Helper()
+	var ids []string
+	for len(ids) < n {
+		select {
+		case tw, ok := <-ch:
+			require.True(t, ok, "channel closed early")
+			ids = append(ids, tw.ID)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %v", ids)
+		}
+	}
+	return ids
+}
+
+func TestPoll_DeliversOnlyNewTweets(t *testing.T) {
+	pages := [][]*Tweet{
+		tweetsWithIDs("9", "10", "8"),
+		nil, // failed poll
+		tweetsWithIDs("11", "10", "9"),
+	}
+	var calls atomic.Int32
+	var failures atomic.Int32
+	fetch := func(ctx context.Context, count int) ([]*Tweet, error) {
+		assert.Equal(t, 5, count)
+		i := int(calls.Add(1)) - 1
+		if i == 1 {
+			return nil, errors.New("boom")
+		}
+		return pages[min(i, len(pages)-1)], nil
+	}
+
+	c := &Client{}
+	file := filepath.Join(t.TempDir(), "seen.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.poll(ctx, "test", 10*time.Millisecond,
+		[]PollOption{WithPollCount(5), WithSeenFile(file), WithPollErrors(func(error) { failures.Add(1) })}, fetch)
+
+	assert.Equal(t, []string{"8", "9", "10", "11"}, collectTweets(t, ch, 4))
+	assert.GreaterOrEqual(t, failures.Load(), int32(1))
+	cancel()
+	for range ch {
+	}
+
+	// A restarted subscription remembers what it delivered.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	ch = c.poll(ctx, "test", 10*time.Millisecond, []PollOption{WithSeenFile(file)},
+		func(context.Context, int) ([]*Tweet, error) { return tweetsWithIDs("12", "11", "10"), nil })
+	assert.Equal(t, []string{"12"}, collectTweets(t, ch, 1))
+}
+
+func TestPoll_CancelKeepsUndelivered(t *testing.T) {
+	c := &Client{}
+	file := filepath.Join(t.TempDir(), "seen.json")
+	fetch := func(context.Context, int) ([]*Tweet, error) { return tweetsWithIDs("3", "2", "1"), nil }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.poll(ctx, "test", time.Hour, []PollOption{WithSeenFile(file)}, fetch)
+	assert.Equal(t, []string{"1"}, collectTweets(t, ch, 1))
+	cancel()
+	for range ch {
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	ch = c.poll(ctx, "test", time.Hour, []PollOption{WithSeenFile(file)}, fetch)
+	assert.Equal(t, []string{"2", "3"}, collectTweets(t, ch, 2), "tweets not received are delivered after a restart")
+	cancel()
+	for range ch { // let the last save finish before TempDir is removed
+	}
+}
+
+func TestSeenSet_Bounded(t *testing.T) {
+	s := newSeenSet(2, "")
+	fresh := s.unseen(tweetsWithIDs("1", "2", "3", "2"))
+	assert.Len(t, fresh, 3)
+	assert.Empty(t, s.order, "nothing is seen before it is marked")
+	for _, tw := range fresh {
+		s.mark(tw.ID)
+	}
+	assert.Equal(t, []string{"2", "3"}, s.order)
+	assert.Len(t, s.unseen(tweetsWithIDs("1")), 1, "evicted IDs are forgotten")
+}
+
+func TestSeenFileName_SeparatesKinds(t *testing.T) {
+	assert.NotEqual(t, seenFileName("poll", "search:go"), seenFileName("monitor", "search:go"))
+}
+
+func TestCompareIDs(t *testing.T) {
+	assert.Negative(t, compareIDs("999", "1000"))
+	assert.Positive(t, compareIDs("1001", "1000"))
+	assert.Zero(t, compareIDs("5", "5"))
+}
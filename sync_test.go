@@ -0,0 +1,49 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncUserTweets(t *testing.T) {
+	tr := &pagedTransport{pages: map[string]string{
+		"":   userTweetsPage("c2", "10", "9", "8"),
+		"c2": userTweetsPage("", "7", "6"),
+	}}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	state, err := c.LoadSyncState("scrape")
+	require.NoError(t, err)
+	state.advance("user:42", "7")
+	require.NoError(t, c.SaveSyncState("scrape", state))
+
+	state, err = c.LoadSyncState("scrape")
+	require.NoError(t, err)
+	assert.Equal(t, "7", state.Newest("user:42"))
+
+	tweets, err := c.SyncUserTweets(ctx, "42", state)
+	require.NoError(t, err)
+	require.Len(t, tweets, 3)
+	assert.Equal(t, "10", tweets[0].ID)
+	assert.Equal(t, "10", state.Newest("user:42"))
+
+	tweets, err = c.SyncUserTweets(ctx, "42", state)
+	require.NoError(t, err)
+	assert.Empty(t, tweets, "nothing new since the last sync")
+
+	fresh := &SyncState{}
+	tweets, err = c.SyncUserTweets(ctx, "42", fresh)
+	require.NoError(t, err)
+	assert.Len(t, tweets, 5, "the first sync takes the most recent tweets")
+	assert.Equal(t, "10", fresh.Newest("user:42"))
+}
@@ -0,0 +1,125 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// maxThreadFetches bounds the TweetDetail requests of one GetThread call.
+const maxThreadFetches = 25
+
+// GetThread returns the self-thread tweetID belongs to, oldest first: the
+// chain of tweets by its author in which each replies to the previous one.
+// The chain starts at the author's first tweet in it (which may itself
+// answer someone else) and continues through the author's earliest reply to
+// each tweet. Each step beyond the loaded conversation costs a TweetDetail
+// request; if one fails, the thread found so far is returned with the error.
+func (c *Client) GetThread(ctx context.Context, tweetID string) ([]*Tweet, error) {
+	tf := &threadFetcher{c: c, tweets: make(map[string]*Tweet), fetched: make(map[string]bool)}
+	if err := tf.fetch(ctx, tweetID); err != nil {
+		return nil, err
+	}
+	focal := tf.tweets[tweetID]
+	if focal == nil {
+		return nil, fmt.Errorf("tweet %s not found in response", tweetID)
+	}
+	author := focal.AuthorID
+	thread := []*Tweet{focal}
+
+	// Up to the first tweet of the chain.
+	for cur := focal; cur.InReplyToID != "" && (cur.InReplyToUserID == "" || cur.InReplyToUserID == author); {
+		parent, err := tf.get(ctx, cur.InReplyToID)
+		if err != nil {
+			slices.Reverse(thread)
+			return thread, fmt.Errorf("thread of %s incomplete: %w", tweetID, err)
+		}
+		if parent == nil || parent.AuthorID != author || slices.Contains(thread, parent) {
+			break
+		}
+		thread = append(thread, parent)
+		cur = parent
+	}
+	slices.Reverse(thread)
+
+	// Down through the author's replies.
+	for cur := thread[len(thread)-1]; ; {
+		next, err := tf.selfReply(ctx, cur, author)
+		if err != nil {
+			return thread, fmt.Errorf("thread of %s incomplete: %w", tweetID, err)
+		}
+		if next == nil || slices.Contains(thread, next) {
+			break
+		}
+		thread = append(thread, next)
+		cur = next
+	}
+	return thread, nil
+}
+
+// threadFetcher collects the tweets of the conversations GetThread loads.
+type threadFetcher struct {
+	c       *Client
+	tweets  map[string]*Tweet
+	fetched map[string]bool // focal IDs already requested
+}
+
+// fetch loads the conversation around id, unless it was loaded already or
+// the request budget is spent.
+func (tf *threadFetcher) fetch(ctx context.Context, id string) error {
+	if tf.fetched[id] {
+		return nil
+	}
+	if len(tf.fetched) >= maxThreadFetches {
+		slog.Warn("thread: request budget spent", slog.Int("requests", maxThreadFetches))
+		return nil
+	}
+	tf.fetched[id] = true
+	tweets, _, err := tf.c.tweetDetail(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, t := range tweets {
+		tf.tweets[t.ID] = t
+	}
+	return nil
+}
+
+// get returns tweet id, loading its conversation if needed; nil if it is
+// not available (deleted, protected, or the budget is spent).
+func (tf *threadFetcher) get(ctx context.Context, id string) (*Tweet, error) {
+	if t := tf.tweets[id]; t != nil {
+		return t, nil
+	}
+	if err := tf.fetch(ctx, id); err != nil {
+		return nil, err
+	}
+	return tf.tweets[id], nil
+}
+
+// selfReply returns author's earliest reply to t, loading t's conversation
+// if none is known yet.
+func (tf *threadFetcher) selfReply(ctx context.Context, t *Tweet, author string) (*Tweet, error) {
+	if r := tf.earliestReply(t.ID, author); r != nil {
+		return r, nil
+	}
+	if err := tf.fetch(ctx, t.ID); err != nil {
+		return nil, err
+	}
+	return tf.earliestReply(t.ID, author), nil
+}
+
+func (tf *threadFetcher) earliestReply(parentID, author string) *Tweet {
+	var best *Tweet
+	for _, t := range tf.tweets {
+		if t.InReplyToID != parentID || t.AuthorID != author {
+			continue
+		}
+		if best == nil || t.CreatedAt.Before(best.CreatedAt) ||
+			(t.CreatedAt.Equal(best.CreatedAt) && t.ID < best.ID) {
+			best = t
+		}
+	}
+	return best
+}
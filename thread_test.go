@@ -0,0 +1,104 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type threadTweet struct{ id, author, replyTo, replyToAuthor string }
+
+func (tt threadTweet) item() map[string]any {
+	return map[string]any{"__typename": "TimelineTweet", "tweet_results": map[string]any{"result": map[string]any{
+		"__typename": "Tweet",
+		"rest_id":    tt.id,
+		"legacy": map[string]any{
+			"full_text":                 "tweet " + tt.id,
+			"created_at":                fmt.Sprintf("Tue Mar 18 14:%02s:00 +0000 2025", tt.id),
+			"user_id_str":               tt.author,
+			"conversation_id_str":       "1",
+			"in_reply_to_status_id_str": tt.replyTo,
+			"in_reply_to_user_id_str":   tt.replyToAuthor,
+		},
+	}}}
+}
+
+// detailBody renders a TweetDetail response: items as single entries,
+// modules as conversation modules.
+func detailBody(items []threadTweet, modules ...[]threadTweet) string {
+	var entries []any
+	for _, tt := range items {
+		entries = append(entries, map[string]any{"content": map[string]any{"itemContent": tt.item()}})
+	}
+	for _, m := range modules {
+		var moduleItems []any
+		for _, tt := range m {
+			moduleItems = append(moduleItems, map[string]any{"item": map[string]any{"itemContent": tt.item()}})
+		}
+		entries = append(entries, map[string]any{"content": map[string]any{"items": moduleItems}})
+	}
+	b, _ := json.Marshal(map[string]any{"data": map[string]any{"threaded_conversation_with_injections_v2": map[string]any{
+		"instructions": []any{map[string]any{"entries": entries}},
+	}}})
+	return string(b)
+}
+
+// detailTransport answers TweetDetail by focal tweet ID.
+type detailTransport struct {
+	bodies map[string]string
+	focals []string
+}
+
+func (d *detailTransport) Do(_ context.Context, _, rawURL string, _ map[string]string, _ io.Reader) ([]byte, map[string]string, int, error) {
+	u, _ := url.Parse(rawURL)
+	var vars struct {
+		FocalTweetID string `json:"focalTweetId"`
+	}
+	_ = json.Unmarshal([]byte(u.Query().Get("variables")), &vars)
+	d.focals = append(d.focals, vars.FocalTweetID)
+	body, ok := d.bodies[vars.FocalTweetID]
+	if !ok || !strings.Contains(u.Path, "TweetDetail") {
+		return []byte(`{"errors":[{"message":"not found"}]}`), nil, 404, nil
+	}
+	return []byte(body), nil, 200, nil
+}
+
+func TestGetThread(t *testing.T) {
+	t1 := threadTweet{"1", "alice", "", ""}
+	t2 := threadTweet{"2", "alice", "1", "alice"}
+	t3 := threadTweet{"3", "alice", "2", "alice"}
+	t4 := threadTweet{"4", "alice", "3", "alice"}
+	bob := threadTweet{"5", "bob", "2", "alice"}
+	aside := threadTweet{"6", "alice", "5", "bob"}
+
+	tr := &detailTransport{bodies: map[string]string{
+		"2": detailBody([]threadTweet{t1, t2}, []threadTweet{t3}, []threadTweet{bob, aside}),
+		"3": detailBody([]threadTweet{t1, t2, t3}, []threadTweet{t4}),
+		"4": detailBody([]threadTweet{t1, t2, t3, t4}),
+	}}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	thread, err := c.GetThread(context.Background(), "2")
+	require.NoError(t, err)
+	var ids []string
+	for _, tw := range thread {
+		ids = append(ids, tw.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4"}, ids)
+	assert.Equal(t, []string{"2", "3", "4"}, tr.focals)
+	assert.Equal(t, "1", thread[1].InReplyToID)
+	assert.Equal(t, "1", thread[3].ConversationID)
+}
@@ -0,0 +1,79 @@
+package twitter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TombstoneReason says why X shows a notice in place of a tweet.
+type TombstoneReason string
+
+const (
+	TombstoneDeleted       TombstoneReason = "deleted"        // removed by its author
+	TombstoneWithheld      TombstoneReason = "withheld"       // withheld in the viewer's country
+	TombstoneAgeRestricted TombstoneReason = "age_restricted" // adult content, hidden from this viewer
+	TombstoneProtected     TombstoneReason = "protected"      // the author's tweets are protected
+	TombstoneSuspended     TombstoneReason = "suspended"      // the author is suspended
+	TombstoneUnavailable   TombstoneReason = "unavailable"    // any other reason
+)
+
+// ErrTweetUnavailable matches every *TweetUnavailableError.
+var ErrTweetUnavailable = errors.New("tweet unavailable")
+
+// TweetUnavailableError reports a tweet X returned as a tombstone instead of
+// its content. GetTweetByID returns it when the requested tweet is one.
+type TweetUnavailableError struct {
+	TweetID string // empty when the response did not name the tweet
+	Reason  TombstoneReason
+	Text    string // X's notice, e.g. "This Post was deleted by the Post author."
+}
+
+func (e *TweetUnavailableError) Error() string {
+	msg := "tweet unavailable (" + string(e.Reason) + ")"
+	if e.TweetID != "" {
+		msg = fmt.Sprintf("tweet %s unavailable (%s)", e.TweetID, e.Reason)
+	}
+	if e.Text != "" {
+		msg += ": " + e.Text
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrTweetUnavailable) match.
+func (e *TweetUnavailableError) Is(target error) bool { return target == ErrTweetUnavailable }
+
+// newTweetUnavailable builds the error for a tombstone with notice text and,
+// for TweetUnavailable results, X's reason code.
+func newTweetUnavailable(tweetID, text, reason string) *TweetUnavailableError {
+	return &TweetUnavailableError{TweetID: tweetID, Reason: tombstoneReason(text, reason), Text: text}
+}
+
+// tombstoneReason classifies a tombstone by X's reason code (e.g.
+// "Protected", "NsfwLoggedOut") or, failing that, by its notice text.
+func tombstoneReason(text, reason string) TombstoneReason {
+	switch strings.ToLower(reason) {
+	case "protected":
+		return TombstoneProtected
+	case "suspended":
+		return TombstoneSuspended
+	case "nsfwloggedout", "nsfw", "agerestricted":
+		return TombstoneAgeRestricted
+	case "withheld":
+		return TombstoneWithheld
+	}
+	t := strings.ToLower(text)
+	switch {
+	case strings.Contains(t, "deleted"):
+		return TombstoneDeleted
+	case strings.Contains(t, "withheld"):
+		return TombstoneWithheld
+	case strings.Contains(t, "age-restricted"), strings.Contains(t, "adult content"), strings.Contains(t, "age restricted"):
+		return TombstoneAgeRestricted
+	case strings.Contains(t, "suspended"):
+		return TombstoneSuspended
+	case strings.Contains(t, "protected"), strings.Contains(t, "only approved followers"):
+		return TombstoneProtected
+	}
+	return TombstoneUnavailable
+}
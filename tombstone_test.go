@@ -0,0 +1,61 @@
+package twitter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTombstoneReason(t *testing.T) {
+	cases := []struct {
+		text, reason string
+		want         TombstoneReason
+	}{
+		{"This Post was deleted by the Post author. Learn more", "", TombstoneDeleted},
+		{"This Post has been withheld in Germany in response to a legal demand.", "", TombstoneWithheld},
+		{"Age-restricted adult content. This content might not be appropriate for people under 18 years old.", "", TombstoneAgeRestricted},
+		{"", "NsfwLoggedOut", TombstoneAgeRestricted},
+		{"", "Protected", TombstoneProtected},
+		{"This Post is from a suspended account.", "", TombstoneSuspended},
+		{"This Post is unavailable.", "", TombstoneUnavailable},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, tombstoneReason(tc.text, tc.reason), "%q/%q", tc.text, tc.reason)
+	}
+}
+
+func TestParseTweetDetail_VisibilityAndTombstones(t *testing.T) {
+	body := `{"data":{"threaded_conversation_with_injections_v2":{"instructions":[{"entries":[
+		{"entryId":"tweet-1","content":{"itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{
+			"__typename":"TweetWithVisibilityResults",
+			"tweet":{"__typename":"Tweet","rest_id":"1","legacy":{"full_text":"limited","user_id_str":"7"}},
+			"limitedActionResults":{"limited_actions":[{"action":"Reply"}]}}}}}},
+		{"entryId":"tweet-2","content":{"itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{
+			"__typename":"TweetTombstone","tombstone":{"text":{"text":"This Post was deleted by the Post author. Learn more"}}}}}}},
+		{"entryId":"conversationthread-3","content":{"items":[
+			{"entryId":"conversationthread-3-tweet-3","item":{"itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{
+				"__typename":"TweetUnavailable","reason":"NsfwLoggedOut"}}}}},
+			{"entryId":"conversationthread-3-tombstone-4","item":{"itemContent":{"__typename":"TimelineTombstone",
+				"tombstoneInfo":{"richText":{"text":"This Post is from a suspended account."}}}}}
+		]}}
+	]}]}}}`
+
+	tweets, unavailable, err := parseTweetDetail([]byte(body))
+	require.NoError(t, err)
+	require.Len(t, tweets, 1)
+	assert.Equal(t, "1", tweets[0].ID)
+	assert.Equal(t, "limited", tweets[0].Text)
+
+	require.Len(t, unavailable, 3)
+	assert.Equal(t, TweetUnavailableError{TweetID: "2", Reason: TombstoneDeleted, Text: "This Post was deleted by the Post author. Learn more"}, *unavailable[0])
+	assert.Equal(t, TombstoneAgeRestricted, unavailable[1].Reason)
+	assert.Equal(t, "3", unavailable[1].TweetID)
+	assert.Equal(t, TombstoneSuspended, unavailable[2].Reason)
+	assert.Equal(t, "4", unavailable[2].TweetID)
+
+	var err2 error = unavailable[0]
+	assert.True(t, errors.Is(err2, ErrTweetUnavailable))
+	assert.Equal(t, "tweet 2 unavailable (deleted): This Post was deleted by the Post author. Learn more", err2.Error())
+}
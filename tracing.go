@@ -0,0 +1,71 @@
+package twitter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope reported on every span.
+const tracerName = "github.com/anatolykoptev/go-twitter"
+
+// newTracer returns a tracer from tp, or a no-op tracer when tp is nil.
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// startSpan starts a client span. Safe on a Client built without NewClient.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tr := c.tracer
+	if tr == nil {
+		tr = newTracer(nil)
+	}
+	return tr.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records err (if any) on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceAttempt adds an "attempt" event naming the account chosen for it.
+func traceAttempt(span trace.Span, attempt int, acc *Account) {
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent("attempt", trace.WithAttributes(
+		attribute.Int("twitter.attempt", attempt+1),
+		attribute.String("twitter.account", hashAccount(acc.Username)),
+	))
+}
+
+// traceResponse adds a "response" event with the HTTP status and the Twitter
+// error class found in the body.
+func traceResponse(span trace.Span, status int, body []byte) {
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent("response", trace.WithAttributes(
+		attribute.Int("http.status_code", status),
+		attribute.Int("twitter.error_class", int(classifyError(body, nil))),
+	))
+}
+
+// hashAccount returns a short, stable, non-reversible account identifier so
+// usernames don't leak into trace backends.
+func hashAccount(username string) string {
+	sum := sha256.Sum256([]byte(username))
+	return hex.EncodeToString(sum[:6])
+}
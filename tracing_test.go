@@ -0,0 +1,27 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashAccount(t *testing.T) {
+	h := hashAccount("alice")
+	assert.Len(t, h, 12)
+	assert.Equal(t, h, hashAccount("alice"))
+	assert.NotEqual(t, h, hashAccount("bob"))
+	assert.NotContains(t, h, "alice")
+}
+
+func TestStartSpan_NoTracer(t *testing.T) {
+	c := &Client{}
+	ctx, span := c.startSpan(context.Background(), "twitter.test")
+	assert.NotNil(t, ctx)
+	assert.False(t, span.IsRecording())
+	traceAttempt(span, 0, &Account{Username: "alice"})
+	traceResponse(span, 200, []byte(`{}`))
+	endSpan(span, errors.New("boom"))
+}
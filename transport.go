@@ -0,0 +1,73 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+)
+
+// Transport sends one HTTP request on behalf of the client and returns the
+// response body, lower-cased response headers and status code. Set
+// ClientConfig.Transport to serve requests from something other than the
+// network, e.g. the canned responses of the twittertest package.
+type Transport interface {
+	Do(ctx context.Context, method, url string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error)
+}
+
+// send issues one request, through the configured interceptors and the
+// VCR when ClientConfig.VCR is set.
+func (c *Client) send(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	if c.intercepted() {
+		return c.sendIntercepted(ctx, bc, method, urlStr, headers, body)
+	}
+	return c.sendDirect(ctx, bc, method, urlStr, headers, body)
+}
+
+// sendDirect issues one request, through the VCR when ClientConfig.VCR is set.
+func (c *Client) sendDirect(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	if c.vcr != nil {
+		return c.sendRecorded(ctx, bc, method, urlStr, headers, body)
+	}
+	return c.transmit(ctx, bc, method, urlStr, headers, body)
+}
+
+// transmit issues one request on ClientConfig.Transport when set, otherwise
+// on bc. Response bodies are bounded and decoded by decodeBody.
+func (c *Client) transmit(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	var (
+		respBody    []byte
+		respHeaders map[string]string
+		status      int
+		err         error
+	)
+	if c.cfg.Transport != nil {
+		respBody, respHeaders, status, err = c.cfg.Transport.Do(ctx, method, urlStr, headers, body)
+	} else {
+		respBody, respHeaders, status, err = bc.DoWithHeaderOrderCtx(ctx, method, urlStr, headers, body, twitterHeaderOrder)
+	}
+	if err != nil {
+		return respBody, respHeaders, status, err
+	}
+	respBody, err = decodeBody(respBody, respHeaders, c.cfg.MaxResponseBytes)
+	if err != nil {
+		return nil, respHeaders, status, fmt.Errorf("%s %s: %w", method, urlStr, err)
+	}
+	return respBody, respHeaders, status, nil
+}
+
+// offline reports whether requests are answered without the browser
+// client: by a custom Transport or a VCR replay.
+func (c *Client) offline() bool {
+	return c.cfg.Transport != nil || c.vcr.replaying()
+}
+
+// fingerprintJitter sleeps the anti-fingerprint delay before a request.
+// Offline there is no fingerprint to protect, so it is skipped.
+func (c *Client) fingerprintJitter(ctx context.Context) error {
+	if c.offline() {
+		return ctx.Err()
+	}
+	return stealth.DefaultJitter.Sleep(ctx)
+}
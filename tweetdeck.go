@@ -0,0 +1,138 @@
+package twitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// tweetdeckBase is the X Pro (TweetDeck) GraphQL host. Its operations are
+	// rate-limited separately from x.com, so they often keep working while the
+	// main web endpoints are throttled.
+	tweetdeckBase   = "https://pro.x.com/i/api/graphql"
+	tweetdeckOrigin = "https://pro.x.com"
+
+	// tweetdeckPrefix marks X Pro operations in Endpoints. The prefix keeps
+	// their per-account rate-limit buckets separate from the web ones.
+	tweetdeckPrefix = "TweetDeck/"
+)
+
+// tweetdeckFeatures returns the feature flags sent by the X Pro bundle. It
+// ships without the Grok and Jetfuel UI, so those flags are off.
+func tweetdeckFeatures() map[string]any {
+	f := gqlFeatures()
+	for k := range f {
+		if strings.HasPrefix(k, "responsive_web_grok_") {
+			f[k] = false
+		}
+	}
+	f["responsive_web_jetfuel_frame"] = false
+	return f
+}
+
+type readPathKey struct{}
+
+// WithTweetDeck returns a context that routes supported reads (GetUserTweets,
+// SearchTimeline) through the X Pro (TweetDeck) endpoints instead of x.com.
+func WithTweetDeck(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPathKey{}, true)
+}
+
+// usesTweetDeck reports whether ctx selects the X Pro read path.
+func usesTweetDeck(ctx context.Context) bool {
+	v, _ := ctx.Value(readPathKey{}).(bool)
+	return v
+}
+
+// readOperation returns the Endpoints key to use for operation: the X Pro
+// variant when ctx selects it and one exists, otherwise operation itself.
+func readOperation(ctx context.Context, operation string) string {
+	if usesTweetDeck(ctx) {
+		if _, ok := lookupEndpoint(tweetdeckPrefix + operation); ok {
+			return tweetdeckPrefix + operation
+		}
+	}
+	return operation
+}
+
+// isTweetDeckURL reports whether urlStr targets the X Pro GraphQL host.
+func isTweetDeckURL(urlStr string) bool {
+	return strings.HasPrefix(urlStr, tweetdeckOrigin+"/")
+}
+
+// ColumnType selects what an X Pro column shows.
+type ColumnType string
+
+const (
+	ColumnUser   ColumnType = "user"   // Target is a user ID
+	ColumnSearch ColumnType = "search" // Target is a raw search query
+	ColumnList   ColumnType = "list"   // Target is a list ID
+)
+
+// Column describes a single X Pro (TweetDeck) column.
+type Column struct {
+	Type   ColumnType
+	Target string
+	Count  int // default 20
+}
+
+// FetchColumns fetches several X Pro columns, returning one tweet slice per
+// column in input order. Columns that fail are left nil and their errors
+// joined into the returned error.
+func (c *Client) FetchColumns(ctx context.Context, cols []Column) ([][]*Tweet, error) {
+	ctx = WithTweetDeck(ctx)
+	results := make([][]*Tweet, len(cols))
+	var errs []error
+	for i, col := range cols {
+		if err := ctx.Err(); err != nil {
+			return results, errors.Join(append(errs, err)...)
+		}
+		count := col.Count
+		if count <= 0 {
+			count = 20
+		}
+		var tweets []*Tweet
+		var err error
+		switch col.Type {
+		case ColumnUser:
+			tweets, err = c.GetUserTweets(ctx, col.Target, count)
+		case ColumnSearch:
+			tweets, err = c.SearchTimeline(ctx, col.Target, count)
+		case ColumnList:
+			tweets, err = c.getListTimeline(ctx, col.Target, count)
+		default:
+			err = fmt.Errorf("unknown column type %q", col.Type)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("column %d (%s %s): %w", i, col.Type, col.Target, err))
+			continue
+		}
+		results[i] = tweets
+	}
+	return results, errors.Join(errs...)
+}
+
+// getListTimeline fetches the latest tweets of a list through X Pro.
+func (c *Client) getListTimeline(ctx context.Context, listID string, count int) ([]*Tweet, error) {
+	if err := validateListID(listID); err != nil {
+		return nil, err
+	}
+	op := tweetdeckPrefix + "ListLatestTweetsTimeline"
+	variables := map[string]any{
+		"listId": listID,
+		"count":  count,
+	}
+	url, err := EndpointURL(op)
+	if err != nil {
+		return nil, err
+	}
+	url = addGraphQLParams(url, variables, c.features(op))
+
+	body, _, err := c.doGET(ctx, op, url)
+	if err != nil {
+		return nil, fmt.Errorf("ListLatestTweetsTimeline: %w", err)
+	}
+	return c.enrichTweets(parseListTimeline(body))
+}
@@ -0,0 +1,62 @@
+package twitter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReadOperation(t *testing.T) {
+	ctx := context.Background()
+	if got := readOperation(ctx, "UserTweets"); got != "UserTweets" {
+		t.Fatalf("default path: got %q", got)
+	}
+	td := WithTweetDeck(ctx)
+	if got := readOperation(td, "UserTweets"); got != "TweetDeck/UserTweets" {
+		t.Fatalf("tweetdeck path: got %q", got)
+	}
+	// Operations without an X Pro variant stay on the web endpoint.
+	if got := readOperation(td, "Followers"); got != "Followers" {
+		t.Fatalf("no variant: got %q", got)
+	}
+}
+
+func TestTweetDeckEndpoints(t *testing.T) {
+	for name, ep := range Endpoints {
+		if !strings.HasPrefix(name, tweetdeckPrefix) {
+			continue
+		}
+		if !isTweetDeckURL(ep.URL()) {
+			t.Errorf("%s: URL %s not on X Pro host", name, ep.URL())
+		}
+		if !requiresAuth(name) {
+			t.Errorf("%s: should require auth", name)
+		}
+		if ep.Features["responsive_web_jetfuel_frame"] != false {
+			t.Errorf("%s: jetfuel flag should be off", name)
+		}
+	}
+	if isTweetDeckURL(Endpoints["UserTweets"].URL()) {
+		t.Fatal("web UserTweets must stay on x.com")
+	}
+}
+
+func TestParseListTimeline(t *testing.T) {
+	body := `{"data":{"list":{"tweets_timeline":{"timeline":{"instructions":[{
+		"type":"TimelineAddEntries",
+		"entries":[{"entryId":"tweet-7","content":{
+			"entryType":"TimelineTimelineItem","__typename":"TimelineTimelineItem",
+			"itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{
+				"__typename":"Tweet","rest_id":"7",
+				"legacy":{"full_text":"hi","created_at":"Mon Jan 02 15:04:05 +0000 2024","user_id_str":"42"}
+			}}}
+		}}]
+	}]}}}}}`
+	tweets, err := parseListTimeline([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tweets) != 1 || tweets[0].ID != "7" || tweets[0].AuthorID != "42" {
+		t.Fatalf("unexpected tweets: %+v", tweets)
+	}
+}
@@ -0,0 +1,63 @@
+package twitter
+
+// TweetOption configures a tweet posted with CreateTweet or PostWithAccount.
+type TweetOption func(*tweetOptions)
+
+type tweetOptions struct {
+	conversationControl ConversationControl
+	communityID         string
+	subscribersOnly     bool
+}
+
+// ConversationControl limits who can reply to a tweet.
+type ConversationControl string
+
+const (
+	RepliesFollowing ConversationControl = "Community"    // accounts the author follows
+	RepliesVerified  ConversationControl = "Verified"     // verified accounts
+	RepliesMentioned ConversationControl = "ByInvitation" // only accounts mentioned in the tweet
+)
+
+// WithConversationControl limits replies to the tweet, e.g. to keep reply
+// spam off automated posts. Without it everyone can reply.
+func WithConversationControl(mode ConversationControl) TweetOption {
+	return func(o *tweetOptions) { o.conversationControl = mode }
+}
+
+// WithCommunity posts the tweet into the Community communityID, which the
+// account must have joined. Community tweets show in the Community timeline
+// rather than to all followers.
+func WithCommunity(communityID string) TweetOption {
+	return func(o *tweetOptions) { o.communityID = communityID }
+}
+
+// WithSubscribersOnly makes the tweet visible only to the author's paid
+// subscribers (Super Follows). The account must have subscriptions enabled.
+func WithSubscribersOnly() TweetOption {
+	return func(o *tweetOptions) { o.subscribersOnly = true }
+}
+
+// newTweetOptions applies opts and checks the result.
+func newTweetOptions(opts []TweetOption) (tweetOptions, error) {
+	var o tweetOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.communityID != "" && !IsValidUserID(o.communityID) {
+		return o, &ValidationError{Field: "community_id", Value: o.communityID, Reason: "must be a non-zero numeric ID"}
+	}
+	return o, nil
+}
+
+// apply adds the options to CreateTweet variables.
+func (o tweetOptions) apply(variables map[string]any) {
+	if o.conversationControl != "" {
+		variables["conversation_control"] = map[string]any{"mode": string(o.conversationControl)}
+	}
+	if o.communityID != "" {
+		variables["community_id"] = o.communityID
+	}
+	if o.subscribersOnly {
+		variables["exclusive_tweet_control"] = map[string]any{"audience_policy": "Exclusive"}
+	}
+}
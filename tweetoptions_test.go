@@ -0,0 +1,33 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTweetOptions_ConversationControl(t *testing.T) {
+	variables := createTweetVariables("gm")
+	tweetOptions{}.apply(variables)
+	assert.NotContains(t, variables, "conversation_control")
+
+	var o tweetOptions
+	WithConversationControl(RepliesMentioned)(&o)
+	o.apply(variables)
+	assert.Equal(t, map[string]any{"mode": "ByInvitation"}, variables["conversation_control"])
+}
+
+func TestTweetOptions_CommunityAndSubscribers(t *testing.T) {
+	o, err := newTweetOptions([]TweetOption{WithCommunity("1493446837214187523"), WithSubscribersOnly()})
+	require.NoError(t, err)
+	variables := createTweetVariables("gm")
+	o.apply(variables)
+	assert.Equal(t, "1493446837214187523", variables["community_id"])
+	assert.Equal(t, map[string]any{"audience_policy": "Exclusive"}, variables["exclusive_tweet_control"])
+
+	_, err = newTweetOptions([]TweetOption{WithCommunity("golang")})
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "community_id", verr.Field)
+}
@@ -0,0 +1,105 @@
+package twitter
+
+import (
+	"context"
+	"time"
+)
+
+// TweetPageOption sets which tweets GetUserTweetsPaged and
+// SearchTimelinePaged return and where they stop paging.
+type TweetPageOption func(*tweetPageOptions)
+
+type tweetPageOptions struct {
+	sinceID, untilID string
+	since, until     time.Time
+	stop             func(*Tweet) bool
+}
+
+// WithSinceID returns only tweets newer than id and stops paging after the
+// page that reaches it: "everything since the last run".
+func WithSinceID(id string) TweetPageOption {
+	return func(o *tweetPageOptions) { o.sinceID = id }
+}
+
+// WithUntilID returns only tweets older than id.
+func WithUntilID(id string) TweetPageOption {
+	return func(o *tweetPageOptions) { o.untilID = id }
+}
+
+// WithSinceTime returns only tweets posted at or after t and stops paging
+// after the page that reaches older ones.
+func WithSinceTime(t time.Time) TweetPageOption {
+	return func(o *tweetPageOptions) { o.since = t }
+}
+
+// WithUntilTime returns only tweets posted before t.
+func WithUntilTime(t time.Time) TweetPageOption {
+	return func(o *tweetPageOptions) { o.until = t }
+}
+
+// WithStopFunc stops paging after the page holding a tweet for which fn
+// returns true. Such tweets are left out.
+func WithStopFunc(fn func(*Tweet) bool) TweetPageOption {
+	return func(o *tweetPageOptions) { o.stop = fn }
+}
+
+// tweetPageFilter builds the listPage filter for opts; nil without any.
+func tweetPageFilter(opts []TweetPageOption) func(*Tweet) (keep, last bool) {
+	if len(opts) == 0 {
+		return nil
+	}
+	o := &tweetPageOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(t *Tweet) (keep, last bool) {
+		switch {
+		case o.stop != nil && o.stop(t):
+			return false, true
+		case o.sinceID != "" && compareIDs(t.ID, o.sinceID) <= 0:
+			return false, true
+		case !o.since.IsZero() && !t.CreatedAt.IsZero() && t.CreatedAt.Before(o.since):
+			return false, true
+		case o.untilID != "" && compareIDs(t.ID, o.untilID) >= 0:
+			return false, false
+		case !o.until.IsZero() && !t.CreatedAt.Before(o.until):
+			return false, false
+		}
+		return true, false
+	}
+}
+
+// GetUserTweetsPaged pages through the tweets of userID, newest first,
+// starting at cursor ("" = first page) until maxCount tweets are collected,
+// the timeline ends, or a stop option is met.
+func (c *Client) GetUserTweetsPaged(ctx context.Context, userID, cursor string, maxCount int, opts ...TweetPageOption) PagedResult[*Tweet] {
+	if err := validateUserID(userID); err != nil {
+		return PagedResult[*Tweet]{NextCursor: cursor, PartialErr: err}
+	}
+	ctx = c.withTargetAffinity(ctx, "user", userID)
+	r := paginate(ctx, c, listPage[*Tweet]{
+		operation: readOperation(ctx, "UserTweets"),
+		pageSize:  defaultPollCount,
+		variables: func(count int) map[string]any { return userTweetsVariables(userID, count) },
+		parse:     func(body []byte) ([]*Tweet, string, error) { return parseTweetTimelinePage(body, userID) },
+		filter:    tweetPageFilter(opts),
+	}, cursor, maxCount)
+	r.Items, _ = c.enrichTweets(r.Items, nil)
+	return r
+}
+
+// SearchTimelinePaged pages through the Latest results for query, newest
+// first, starting at cursor ("" = first page) until maxCount tweets are
+// collected, the results end, or a stop option is met.
+func (c *Client) SearchTimelinePaged(ctx context.Context, query, cursor string, maxCount int, opts ...TweetPageOption) PagedResult[*Tweet] {
+	r := paginate(ctx, c, listPage[*Tweet]{
+		operation: readOperation(ctx, "SearchTimeline"),
+		pageSize:  defaultPollCount,
+		variables: func(count int) map[string]any { return searchVariables(query, count) },
+		parse:     parseSearchTimelinePage,
+		fetch:     c.searchRequest,
+		filter:    tweetPageFilter(opts),
+	}, cursor, maxCount)
+	r.Items, _ = c.enrichTweets(r.Items, nil)
+	return r
+}
@@ -0,0 +1,87 @@
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userTweetsPage builds a UserTweets page of tweets with the given IDs, one
+// hour apart going back from 12:00, and a bottom cursor.
+func userTweetsPage(next string, ids ...string) string {
+	var entries []string
+	for _, id := range ids {
+		entries = append(entries, fmt.Sprintf(`{"entryId":"tweet-%s","content":{"entryType":"TimelineTimelineItem","itemContent":{"__typename":"TimelineTweet","tweet_results":{"result":{"__typename":"Tweet","rest_id":"%s","legacy":{"full_text":"t%s","created_at":"Tue Mar 18 %s:00:00 +0000 2025"}}}}}}`,
+			id, id, id, tweetHour(id)))
+	}
+	if next != "" {
+		entries = append(entries, `{"entryId":"cursor-bottom-1","content":{"entryType":"TimelineTimelineCursor","cursorType":"Bottom","value":"`+next+`"}}`)
+	}
+	return `{"data":{"user":{"result":{"timeline":{"timeline":{"instructions":[{"type":"TimelineAddEntries","entries":[` + strings.Join(entries, ",") + `]}]}}}}}}`
+}
+
+// tweetHour maps test tweet IDs 10..1 to hours 12..03.
+func tweetHour(id string) string {
+	var n int
+	fmt.Sscan(id, &n)
+	return fmt.Sprintf("%02d", n+2)
+}
+
+func TestGetUserTweetsPaged_StopConditions(t *testing.T) {
+	tr := &pagedTransport{pages: map[string]string{
+		"":   userTweetsPage("c2", "10", "9", "8"),
+		"c2": userTweetsPage("c3", "7", "6", "5"),
+		"c3": userTweetsPage("c4", "4", "3", "2"),
+		"c4": userTweetsPage("", "1"),
+	}}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	ids := func(r PagedResult[*Tweet]) []string {
+		var out []string
+		for _, tw := range r.Items {
+			out = append(out, tw.ID)
+		}
+		return out
+	}
+
+	r := c.GetUserTweetsPaged(ctx, "42", "", 100)
+	require.NoError(t, r.PartialErr)
+	assert.Equal(t, []string{"10", "9", "8", "7", "6", "5", "4", "3", "2", "1"}, ids(r))
+
+	r = c.GetUserTweetsPaged(ctx, "42", "", 100, WithSinceID("6"))
+	assert.Equal(t, []string{"10", "9", "8", "7"}, ids(r))
+	assert.Equal(t, 2, r.Pages, "stops after the page reaching the since ID")
+	assert.True(t, r.Complete())
+
+	r = c.GetUserTweetsPaged(ctx, "42", "", 100, WithUntilID("9"), WithSinceTime(time.Date(2025, 3, 18, 7, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"8", "7", "6", "5"}, ids(r))
+	assert.Equal(t, 3, r.Pages)
+
+	r = c.GetUserTweetsPaged(ctx, "42", "", 100, WithStopFunc(func(tw *Tweet) bool { return tw.Text == "t9" }))
+	assert.Equal(t, []string{"10", "8"}, ids(r), "the rest of the last page is kept")
+	assert.Equal(t, 1, r.Pages)
+
+	r = c.GetUserTweetsPaged(ctx, "42", "", 100, WithUntilTime(time.Date(2025, 3, 18, 5, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"2", "1"}, ids(r))
+}
+
+func TestBottomCursor_ReplaceEntry(t *testing.T) {
+	body := `{"data":{"search_by_raw_query":{"search_timeline":{"timeline":{"instructions":[
+		{"type":"TimelineAddEntries","entries":[]},
+		{"type":"TimelineReplaceEntry","entry_id_to_replace":"cursor-bottom-0","entry":{"entryId":"cursor-bottom-0","content":{"entryType":"TimelineTimelineCursor","value":"DAAD","cursorType":"Bottom"}}}]}}}}}`
+	tweets, next, err := parseSearchTimelinePage([]byte(body))
+	require.NoError(t, err)
+	assert.Empty(t, tweets)
+	assert.Equal(t, "DAAD", next)
+}
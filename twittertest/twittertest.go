@@ -0,0 +1,182 @@
+// Package twittertest serves canned Twitter responses to a *twitter.Client,
+// so code that takes a client can be unit-tested without network access.
+//
+//	tr := twittertest.NewTransport()
+//	tr.ServeFixture("UserByScreenName", "UserByScreenName")
+//	c := twittertest.NewClient(t, tr)
+//	u, err := c.GetUserByScreenName(ctx, "nasa")
+package twittertest
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fixture returns the golden response named name (e.g. "UserTweets"). It
+// panics if there is no such fixture.
+func Fixture(name string) []byte {
+	b, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		panic(fmt.Sprintf("twittertest: no fixture %q", name))
+	}
+	return b
+}
+
+// Response is one canned reply.
+type Response struct {
+	Status  int
+	Body    []byte
+	Headers map[string]string
+	Err     error         // returned instead of a response, e.g. to simulate a proxy failure
+	Delay   time.Duration // how long the reply takes, e.g. to overlap concurrent calls
+}
+
+// Request is a request the client sent.
+type Request struct {
+	Method    string
+	URL       string
+	Operation string
+	Headers   map[string]string
+	Body      []byte
+}
+
+// Transport is a twitter.Transport that answers each operation with its
+// queued responses in order, repeating the last one. Operations are
+// GraphQL operation names (e.g. "UserByScreenName") or, for REST calls, URL
+// paths (e.g. "/1.1/friendships/show.json"). Unknown operations get a 404.
+// Safe for concurrent use.
+type Transport struct {
+	mu        sync.Mutex
+	responses map[string][]Response
+	served    map[string]int
+	requests  []Request
+}
+
+// NewTransport returns a Transport with no responses.
+func NewTransport() *Transport {
+	return &Transport{
+		responses: make(map[string][]Response),
+		served:    make(map[string]int),
+	}
+}
+
+// Serve queues a response for operation.
+func (t *Transport) Serve(operation string, resp Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.responses[operation] = append(t.responses[operation], resp)
+}
+
+// ServeJSON queues a 200 response with body for operation.
+func (t *Transport) ServeJSON(operation string, body []byte) {
+	t.Serve(operation, Response{Status: 200, Body: body})
+}
+
+// ServeFixture queues the golden fixture name as a 200 response for
+// operation.
+func (t *Transport) ServeFixture(operation, name string) {
+	t.ServeJSON(operation, Fixture(name))
+}
+
+// Requests returns the requests sent so far.
+func (t *Transport) Requests() []Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Request(nil), t.requests...)
+}
+
+// Do implements twitter.Transport.
+func (t *Transport) Do(ctx context.Context, method, rawURL string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, 0, err
+	}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	op := Operation(rawURL)
+	hdrs := make(map[string]string, len(headers))
+	for k, v := range headers {
+		hdrs[k] = v
+	}
+
+	t.mu.Lock()
+	t.requests = append(t.requests, Request{Method: method, URL: rawURL, Operation: op, Headers: hdrs, Body: payload})
+	queue := t.responses[op]
+	if len(queue) == 0 {
+		t.mu.Unlock()
+		return []byte(fmt.Sprintf(`{"errors":[{"message":"twittertest: no response for %s"}]}`, op)), nil, 404, nil
+	}
+	resp := queue[min(t.served[op], len(queue)-1)]
+	t.served[op]++
+	t.mu.Unlock()
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-ctx.Done():
+			return nil, nil, 0, ctx.Err()
+		}
+	}
+	if resp.Err != nil {
+		return nil, nil, 0, resp.Err
+	}
+	status := resp.Status
+	if status == 0 {
+		status = 200
+	}
+	return resp.Body, resp.Headers, status, nil
+}
+
+// Operation returns the operation a request URL is matched by: the GraphQL
+// operation name, or the URL path for other calls.
+func Operation(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if strings.Contains(u.Path, "/graphql/") {
+		return path.Base(u.Path)
+	}
+	return u.Path
+}
+
+// NewClient returns a client with one logged-in account whose requests are
+// answered by tr. Sessions go to a temporary directory and guest fallback
+// is off, so nothing leaves the process. configure, if given, adjusts the
+// config before the client is built.
+func NewClient(tb testing.TB, tr *Transport, configure ...func(*twitter.ClientConfig)) *twitter.Client {
+	tb.Helper()
+	cfg := twitter.ClientConfig{
+		Accounts: []*twitter.Account{
+			{Username: "twittertest", AuthToken: "twittertest-auth-token", CT0: "twittertest-ct0"},
+		},
+		SessionDir:           tb.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	}
+	for _, f := range configure {
+		f(&cfg)
+	}
+	c, err := twitter.NewClient(cfg)
+	if err != nil {
+		tb.Fatalf("twittertest: new client: %v", err)
+	}
+	return c
+}
@@ -0,0 +1,188 @@
+package twittertest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	twitter "github.com/anatolykoptev/go-twitter"
+	"github.com/anatolykoptev/go-twitter/twittertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtures_UserByScreenName(t *testing.T) {
+	tr := twittertest.NewTransport()
+	tr.ServeFixture("UserByScreenName", "UserByScreenName")
+	c := twittertest.NewClient(t, tr)
+
+	u, err := c.GetUserByScreenName(context.Background(), "@NASA")
+	require.NoError(t, err)
+	assert.Equal(t, "11348282", u.ID)
+	assert.Equal(t, "NASA", u.Handle)
+	assert.Equal(t, int64(89000000), u.Followers)
+	assert.True(t, u.IsVerified)
+	assert.True(t, u.HasAvatar)
+
+	reqs := tr.Requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "GET", reqs[0].Method)
+	assert.Equal(t, "UserByScreenName", reqs[0].Operation)
+	assert.Equal(t, "twittertest-ct0", reqs[0].Headers["x-csrf-token"])
+}
+
+func TestFixtures_Timelines(t *testing.T) {
+	tr := twittertest.NewTransport()
+	tr.ServeFixture("UserTweets", "UserTweets")
+	tr.ServeFixture("SearchTimeline", "SearchTimeline")
+	tr.ServeFixture("TweetDetail", "TweetDetail")
+	c := twittertest.NewClient(t, tr)
+	ctx := context.Background()
+
+	tweets, err := c.GetUserTweets(ctx, "11348282", 20)
+	require.NoError(t, err)
+	require.Len(t, tweets, 2)
+	assert.Equal(t, "1901234567890123456", tweets[0].ID)
+	assert.Equal(t, "NASA", tweets[0].AuthorHandle)
+	assert.Equal(t, int64(5400000), tweets[0].Views)
+	assert.Equal(t, []string{"SPCE"}, tweets[0].TokenMentions)
+	assert.Len(t, tweets[0].EditIDs, 2)
+
+	found, err := c.SearchTimeline(ctx, "artemis", 20)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "esa", found[1].AuthorHandle)
+
+	tw, err := c.GetTweetByID(ctx, "1901222222222222222")
+	require.NoError(t, err)
+	assert.Equal(t, "21436663", tw.AuthorID)
+}
+
+func TestFixtures_UserLists(t *testing.T) {
+	tr := twittertest.NewTransport()
+	tr.ServeFixture("Followers", "Followers")
+	tr.ServeFixture("UsersByRestIds", "UsersByRestIds")
+	c := twittertest.NewClient(t, tr)
+	ctx := context.Background()
+
+	followers, err := c.GetFollowers(ctx, "11348282", 100)
+	require.NoError(t, err)
+	require.Len(t, followers, 2)
+	assert.False(t, followers[1].HasAvatar, "default avatar")
+	assert.False(t, followers[1].HasBio)
+
+	results := c.GetUsersByIDs(ctx, []string{"11348282", "21436663", "1"}, 1)
+	require.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "esa", results[1].User.Handle)
+	assert.ErrorIs(t, results[2].Err, twitter.ErrUserUnavailable)
+}
+
+func TestTransport_QueueAndErrors(t *testing.T) {
+	tr := twittertest.NewTransport()
+	tr.Serve("UserByScreenName", twittertest.Response{Status: 200, Body: []byte(`{"data":{"user":{"result":{"__typename":"UserUnavailable"}}}}`)})
+	tr.ServeFixture("UserByScreenName", "UserByScreenName")
+	c := twittertest.NewClient(t, tr)
+	ctx := context.Background()
+
+	_, err := c.GetUserByScreenName(ctx, "nasa")
+	assert.Error(t, err, "first queued response")
+	u, err := c.GetUserByScreenName(ctx, "nasa")
+	require.NoError(t, err)
+	assert.Equal(t, "NASA", u.Handle)
+	u, err = c.GetUserByScreenName(ctx, "nasa")
+	require.NoError(t, err, "the last response repeats")
+	assert.Equal(t, "NASA", u.Handle)
+
+	_, _, status, err := tr.Do(ctx, "GET", "https://x.com/i/api/graphql/abc/UserTweets?variables=%7B%7D", map[string]string{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 404, status, "unknown operations get a 404")
+
+	down := errors.New("proxyconnect tcp: connection refused")
+	tr.Serve("/1.1/friendships/show.json", twittertest.Response{Err: down})
+	_, _, _, err = tr.Do(ctx, "GET", "https://api.x.com/1.1/friendships/show.json?source_id=1&target_id=2", map[string]string{}, nil)
+	assert.ErrorIs(t, err, down)
+}
+
+func TestOperation(t *testing.T) {
+	assert.Equal(t, "UserByScreenName", twittertest.Operation("https://x.com/i/api/graphql/xc8f1g7BYqr6VTzTbvNlGw/UserByScreenName?variables=%7B%7D"))
+	assert.Equal(t, "/1.1/account/settings.json", twittertest.Operation("https://api.x.com/1.1/account/settings.json"))
+}
+
+func TestConcurrentIdenticalGETsShareOneRequest(t *testing.T) {
+	tr := twittertest.NewTransport()
+	tr.Serve("UserByScreenName", twittertest.Response{Body: twittertest.Fixture("UserByScreenName"), Delay: 100 * time.Millisecond})
+	c := twittertest.NewClient(t, tr)
+
+	users := make([]*twitter.TwitterUser, 5)
+	var wg sync.WaitGroup
+	for i := range users {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u, err := c.GetUserByScreenName(context.Background(), "NASA")
+			assert.NoError(t, err)
+			users[i] = u
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, tr.Requests(), 1, "the calls share one upstream request")
+	for _, u := range users {
+		require.NotNil(t, u)
+		assert.Equal(t, "11348282", u.ID)
+	}
+}
+
+// usersByRestIDs is a UsersByRestIds response with users given as
+// ID, handle pairs.
+func usersByRestIDs(pairs ...string) []byte {
+	var users []string
+	for i := 0; i < len(pairs); i += 2 {
+		users = append(users, fmt.Sprintf(`{"result":{"__typename":"User","rest_id":%q,"legacy":{"screen_name":%q}}}`, pairs[i], pairs[i+1]))
+	}
+	return []byte(`{"data":{"users":[` + strings.Join(users, ",") + `]}}`)
+}
+
+func TestHydrator_BatchesAndFallsBack(t *testing.T) {
+	tr := twittertest.NewTransport()
+	tr.ServeJSON("UsersByRestIds", usersByRestIDs("1", "alice", "2", "bob"))
+	tr.ServeJSON("UsersByRestIds", usersByRestIDs("3", "carol"))
+	tr.ServeFixture("UsersByRestIds", "UsersByRestIds")
+	tr.ServeFixture("UserByScreenName", "UserByScreenName")
+	c := twittertest.NewClient(t, tr)
+	ctx := context.Background()
+
+	h := c.NewHydrator()
+	h.BatchSize = 2
+	h.Remember("alice", "1")
+	h.Remember("bob", "2")
+	h.Remember("carol", "3")
+	h.Remember("nasa", "999") // stale: not returned, so resolved by handle
+
+	users, err := h.Hydrate(ctx, []string{"@Alice", "bob", "carol", "NASA", "bob"})
+	require.NoError(t, err)
+	require.Len(t, users, 4)
+	assert.Equal(t, "1", users["alice"].ID)
+	assert.Equal(t, "3", users["carol"].ID)
+	assert.Equal(t, "11348282", users["nasa"].ID)
+	operations := func() []string {
+		var ops []string
+		for _, r := range tr.Requests() {
+			ops = append(ops, r.Operation)
+		}
+		return ops
+	}
+	assert.Equal(t, []string{"UsersByRestIds", "UsersByRestIds", "UserByScreenName"}, operations(),
+		"two batches of two IDs, then one handle lookup")
+
+	users, err = h.Hydrate(ctx, []string{"nasa"})
+	require.NoError(t, err)
+	assert.Equal(t, "11348282", users["nasa"].ID)
+	assert.Equal(t, "UsersByRestIds", operations()[3], "the looked-up ID is batched next time")
+	assert.Len(t, operations(), 4)
+}
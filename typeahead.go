@@ -0,0 +1,51 @@
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// Typeahead returns the accounts the search box suggests for prefix, best
+// match first. It is cheap, works with guest tokens, and is the quickest way
+// to resolve a partial or mistyped handle.
+func (c *Client) Typeahead(ctx context.Context, prefix string) ([]*TwitterUser, error) {
+	q := strings.TrimSpace(prefix)
+	if strings.TrimPrefix(q, "@") == "" {
+		return nil, errors.New("Typeahead: empty prefix")
+	}
+	params := url.Values{
+		"q":           {q},
+		"src":         {"search_box"},
+		"result_type": {"users"},
+	}
+	body, _, err := c.doGET(ctx, "Typeahead", typeaheadURL+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("Typeahead: %w", err)
+	}
+	return c.shapeUsers(parseTypeahead(body))
+}
+
+// parseTypeahead parses the users of a search/typeahead response.
+func parseTypeahead(body []byte) ([]*TwitterUser, error) {
+	var raw struct {
+		Users []json.RawMessage `json:"users"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal typeahead: %w", err)
+	}
+	users := make([]*TwitterUser, 0, len(raw.Users))
+	for _, r := range raw.Users {
+		u, err := parseRESTUser(r)
+		if err != nil {
+			slog.Debug("skip typeahead user", slog.Any("error", err))
+			continue
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
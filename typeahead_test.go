@@ -0,0 +1,52 @@
+package twitter
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTypeahead(t *testing.T) {
+	body := `{"num_results":3,"users":[
+		{"id_str":"44196397","screen_name":"elonmusk","name":"Elon Musk","verified":true,"followers_count":200000000},
+		{"screen_name":"noid"},
+		{"id_str":"2","screen_name":"elonmuskfan","name":"Fan","profile_image_url_https":"https://pbs.twimg.com/profile_images/1/a.jpg"}
+	],"topics":[{"topic":"elon"}]}`
+	users, err := parseTypeahead([]byte(body))
+	require.NoError(t, err)
+	require.Len(t, users, 2, "entries without an ID are skipped")
+	assert.Equal(t, "elonmusk", users[0].Handle)
+	assert.Equal(t, int64(200000000), users[0].Followers)
+	assert.True(t, users[0].IsVerified)
+	assert.True(t, users[1].HasAvatar)
+
+	_, err = parseTypeahead([]byte(`<html>`))
+	assert.Error(t, err)
+}
+
+func TestTypeahead_Request(t *testing.T) {
+	tr := &headerTransport{}
+	c, err := NewClient(ClientConfig{
+		Accounts:             []*Account{{Username: "u", AuthToken: "a", CT0: "c"}},
+		SessionDir:           t.TempDir(),
+		Transport:            tr,
+		DisableGuestFallback: true,
+	})
+	require.NoError(t, err)
+
+	users, err := c.Typeahead(context.Background(), " elonmsk ")
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	require.Len(t, tr.urls, 1)
+	u, err := url.Parse(tr.urls[0])
+	require.NoError(t, err)
+	assert.Equal(t, "/1.1/search/typeahead.json", u.Path)
+	assert.Equal(t, "elonmsk", u.Query().Get("q"))
+	assert.Equal(t, "users", u.Query().Get("result_type"))
+
+	_, err = c.Typeahead(context.Background(), " @ ")
+	assert.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package twitter
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // TwitterUser represents a Twitter/X account profile.
 type TwitterUser struct {
@@ -8,14 +11,24 @@ type TwitterUser struct {
 	Handle      string
 	DisplayName string
 	Bio         string
-	Followers   int
-	Following   int
-	TweetCount  int
-	ListedCount int
+	Followers   int64
+	Following   int64
+	TweetCount  int64
+	ListedCount int64
 	CreatedAt   time.Time
 	IsVerified  bool
 	HasAvatar   bool
 	HasBio      bool
+	AvatarURL   string // profile image URL; empty if unknown
+
+	// Extended profile fields, left empty with ClientConfig.MinimalUsers.
+	Location             string
+	URL                  string // profile link, expanded from t.co
+	PinnedTweetID        string
+	ProfessionalCategory string // e.g. "Media & News"; empty for non-professional accounts
+	BirthdateVisibility  string // e.g. "self", "public"; empty if no birthdate is set
+	Protected            bool
+	CanDM                bool
 }
 
 // Tweet represents a single tweet.
@@ -26,12 +39,92 @@ type Tweet struct {
 	AuthorName    string // display name (from core.user_results)
 	Text          string
 	CreatedAt     time.Time
-	Views         int
-	Likes         int
-	Retweets      int
-	Quotes        int
-	ReplyCount    int
+	Views         int64 // 0 when X does not report a view count
+	Likes         int64
+	Retweets      int64
+	Quotes        int64
+	ReplyCount    int64
 	TokenMentions []string // extracted $TICKER patterns, e.g. ["BTC", "ETH"]
+	EditIDs       []string // all revision IDs, oldest first; nil if never edited
+
+	// Reply links; empty for a tweet that starts a conversation.
+	ConversationID  string // ID of the conversation's first tweet
+	InReplyToID     string
+	InReplyToUserID string
+	Media           []Media // attached photos, videos and GIFs, in display order
+
+	Lang        string    // language X detected (BCP 47, e.g. "en"); "und" if undetermined
+	Place       *Place    // place the tweet is tagged with; nil if none
+	Coordinates *GeoPoint // exact location the author shared; nil if none
+
+	// Entities holds values found by ClientConfig.TweetEnrichers, keyed by
+	// extractor name.
+	Entities map[string][]string
+}
+
+// Place is a named location a tweet is tagged with. Its ID works with the
+// SearchQuery Place filter.
+type Place struct {
+	ID          string
+	Name        string // e.g. "Manhattan"
+	FullName    string // e.g. "Manhattan, NY"
+	Type        string // "city", "admin", "country", "neighborhood" or "poi"
+	Country     string
+	CountryCode string // ISO 3166-1 alpha-2, e.g. "US"
+}
+
+// GeoPoint is a point on Earth in degrees.
+type GeoPoint struct {
+	Lat, Lon float64
+}
+
+// Media is a photo, video or animated GIF attached to a tweet. Download it
+// with Client.DownloadMedia.
+type Media struct {
+	Type     string // "photo", "video" or "animated_gif"
+	URL      string // the image; for videos and GIFs, the poster frame
+	Duration time.Duration
+	Variants []MediaVariant
+}
+
+// MediaVariant is one encoding of a video or GIF: an MP4 file, or an HLS
+// playlist (download it with Client.DownloadHLS).
+type MediaVariant struct {
+	URL         string
+	ContentType string // "video/mp4" or "application/x-mpegURL"
+	Bitrate     int    // bits per second; 0 for HLS playlists
+	Width       int    // from the URL; 0 if not encoded there
+	Height      int
+}
+
+// isHLS reports whether v is an HLS playlist.
+func (v MediaVariant) isHLS() bool {
+	return strings.EqualFold(v.ContentType, "application/x-mpegURL")
+}
+
+// BestVariant returns the highest-bitrate MP4 variant. ok is false for
+// photos and for videos offered only as HLS playlists.
+func (m Media) BestVariant() (v MediaVariant, ok bool) {
+	for _, cand := range m.Variants {
+		if cand.ContentType == "video/mp4" && (!ok || cand.Bitrate > v.Bitrate) {
+			v, ok = cand, true
+		}
+	}
+	return v, ok
+}
+
+// Trend is a single entry of the Explore trending timeline, including the
+// context labels X attaches to it.
+type Trend struct {
+	Name      string
+	Query     string   // search query behind the trend link
+	Rank      int      // 1-based position; 0 if not ranked
+	PostCount int64    // parsed from "12.3K posts"; 0 if not shown
+	Genre     string   // e.g. "Sports", "Politics"; empty for plain "Trending"
+	Context   string   // raw domain context, e.g. "Sports · Trending"
+	Summary   string   // Grok-generated context summary, if any
+	IsAI      bool     // Grok-curated trend card
+	Events    []string // associated events and grouped trend names
 }
 
 // Cursor is used for paginated GraphQL requests.
@@ -11,9 +11,9 @@ import (
 func (c *Client) ValidateAccount(ctx context.Context, acc *Account) error {
 	bc := c.clientForAccount(acc)
 	authTok, ct0, ua := acc.Credentials()
-	headers := twitterHeaders(authTok, ct0, ua)
+	headers := acc.apiHeaders(authTok, ct0, ua)
 
-	_, _, status, err := c.doRequest(bc, "GET", accountSettingsURL, headers)
+	_, _, status, err := c.doRequest(acc.authContext(ctx), bc, "GET", accountSettingsURL, headers)
 	if err != nil {
 		return fmt.Errorf("validate account %s: request failed: %w", acc.Username, err)
 	}
@@ -0,0 +1,244 @@
+package twitter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+)
+
+// VCRMode selects whether the client records or replays responses.
+type VCRMode string
+
+const (
+	// VCRRecord sends requests as usual and writes each sanitized
+	// request/response pair to VCRConfig.Dir.
+	VCRRecord VCRMode = "record"
+	// VCRReplay serves responses from VCRConfig.Dir and never touches the
+	// network; a request with no recording fails.
+	VCRReplay VCRMode = "replay"
+)
+
+// VCRConfig enables record/replay of API responses, for regression-testing
+// parsers against real payloads without scraping again.
+type VCRConfig struct {
+	Mode VCRMode
+
+	// Dir holds the recordings, one file per response in a directory per
+	// operation.
+	Dir string
+}
+
+// cassetteEntry is one recorded request/response pair. Credentials are
+// stripped before it is written.
+type cassetteEntry struct {
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	RequestBody string            `json:"request_body,omitempty"`
+	Status      int               `json:"status"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        json.RawMessage   `json:"body,omitempty"` // JSON responses
+	Text        string            `json:"text,omitempty"` // anything else
+}
+
+// vcr records or replays requests. A nil vcr does neither.
+type vcr struct {
+	cfg VCRConfig
+
+	mu   sync.Mutex
+	seqs map[string]int // key → responses recorded or replayed so far
+}
+
+func newVCR(cfg *VCRConfig) *vcr {
+	if cfg == nil || cfg.Mode == "" {
+		return nil
+	}
+	return &vcr{cfg: *cfg, seqs: make(map[string]int)}
+}
+
+func (v *vcr) replaying() bool { return v != nil && v.cfg.Mode == VCRReplay }
+
+// vcrKey identifies a request by method, path and GraphQL variables, so
+// recordings still match after feature flags or query IDs change.
+func vcrKey(method, urlStr string, body []byte) (operation, key string) {
+	operation, vars := "other", urlStr
+	if u, err := url.Parse(urlStr); err == nil {
+		operation, vars = requestVariables(u, body)
+	}
+	sum := sha256.Sum256([]byte(method + " " + operation + " " + vars))
+	return operation, hex.EncodeToString(sum[:8])
+}
+
+// requestVariables returns the operation of a request (the GraphQL operation
+// name, or the path with slashes flattened) and what distinguishes calls to
+// it: the GraphQL variables, or else the query without feature flags, or
+// else the body.
+func requestVariables(u *url.URL, body []byte) (operation, vars string) {
+	operation = strings.Trim(strings.ReplaceAll(u.Path, "/", "_"), "_")
+	if strings.Contains(u.Path, "/graphql/") {
+		operation = path.Base(u.Path)
+	}
+	q := u.Query()
+	if vars = q.Get("variables"); vars != "" {
+		return operation, vars
+	}
+	var payload struct {
+		Variables json.RawMessage `json:"variables"`
+	}
+	if len(body) > 0 && json.Unmarshal(body, &payload) == nil && len(payload.Variables) > 0 {
+		return operation, string(payload.Variables)
+	}
+	q.Del("features")
+	q.Del("fieldToggles")
+	if vars = q.Encode(); vars == "" {
+		vars = string(body)
+	}
+	return operation, vars
+}
+
+// file returns the recording path of the seq-th response for key.
+func (v *vcr) file(operation, key string, seq int) string {
+	return filepath.Join(v.cfg.Dir, operation, fmt.Sprintf("%s-%03d.json", key, seq))
+}
+
+// next returns the next sequence number for key.
+func (v *vcr) next(key string) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	seq := v.seqs[key]
+	v.seqs[key]++
+	return seq
+}
+
+// replay serves the next recorded response for the request, repeating the
+// last one when the recordings run out.
+func (v *vcr) replay(method, urlStr string, body []byte) ([]byte, map[string]string, int, error) {
+	op, key := vcrKey(method, urlStr, body)
+	seq := v.next(key)
+	var data []byte
+	var err error
+	for ; seq >= 0; seq-- {
+		if data, err = os.ReadFile(v.file(op, key, seq)); !os.IsNotExist(err) {
+			break
+		}
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, 0, fmt.Errorf("vcr: no recording for %s %s", method, sanitizeURL(urlStr))
+		}
+		return nil, nil, 0, fmt.Errorf("vcr: %w", err)
+	}
+	var e cassetteEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, nil, 0, fmt.Errorf("vcr: parse recording: %w", err)
+	}
+	if e.Body != nil {
+		return e.Body, e.Headers, e.Status, nil
+	}
+	return []byte(e.Text), e.Headers, e.Status, nil
+}
+
+// record writes one sanitized request/response pair. Failures are logged,
+// never returned: recording must not break the request.
+func (v *vcr) record(method, urlStr string, reqBody, respBody []byte, headers map[string]string, status int) {
+	op, key := vcrKey(method, urlStr, reqBody)
+	e := cassetteEntry{
+		Method:      method,
+		URL:         sanitizeURL(urlStr),
+		RequestBody: string(reqBody),
+		Status:      status,
+		Headers:     sanitizeHeaders(headers),
+	}
+	respBody = sanitizeBody(respBody)
+	if json.Valid(respBody) {
+		e.Body = respBody
+	} else {
+		e.Text = string(respBody)
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err == nil {
+		name := v.file(op, key, v.next(key))
+		if err = os.MkdirAll(filepath.Dir(name), 0o755); err == nil {
+			err = os.WriteFile(name, data, 0o644)
+		}
+	}
+	if err != nil {
+		slog.Warn("vcr: record failed", slog.String("operation", op), slog.Any("error", err))
+	}
+}
+
+// vcrSecretHeaders are response headers never written to a recording.
+var vcrSecretHeaders = map[string]bool{"set-cookie": true, "authorization": true, "x-csrf-token": true, "x-guest-token": true}
+
+func sanitizeHeaders(h map[string]string) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if !vcrSecretHeaders[strings.ToLower(k)] {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// vcrSecretParams are query parameters blanked in recorded URLs.
+var vcrSecretParams = []string{"auth_token", "ct0", "oauth_token", "access_token"}
+
+func sanitizeURL(urlStr string) string {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return urlStr
+	}
+	q := u.Query()
+	for _, p := range vcrSecretParams {
+		if q.Has(p) {
+			q.Set(p, "REDACTED")
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// vcrSecretFieldRe matches token fields in JSON response bodies.
+var vcrSecretFieldRe = regexp.MustCompile(`"(guest_token|auth_token|ct0|access_token|oauth_token|oauth_token_secret)"\s*:\s*"[^"]*"`)
+
+func sanitizeBody(body []byte) []byte {
+	return vcrSecretFieldRe.ReplaceAll(body, []byte(`"$1":"REDACTED"`))
+}
+
+// sendRecorded is send with the VCR on: in replay mode the recording
+// answers instead of the network; in record mode the response is also
+// written out.
+func (c *Client) sendRecorded(ctx context.Context, bc *stealth.BrowserClient, method, urlStr string, headers map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(body); err != nil {
+			return nil, nil, 0, err
+		}
+		body = bytes.NewReader(reqBody)
+	}
+	if c.vcr.replaying() {
+		return c.vcr.replay(method, urlStr, reqBody)
+	}
+	respBody, respHeaders, status, err := c.transmit(ctx, bc, method, urlStr, headers, body)
+	if err == nil {
+		c.vcr.record(method, urlStr, reqBody, respBody, respHeaders, status)
+	}
+	return respBody, respHeaders, status, err
+}
@@ -0,0 +1,84 @@
+package twitter
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedTransport answers every request with the next body, repeating the
+// last one.
+type scriptedTransport struct {
+	bodies []string
+	calls  int
+}
+
+func (s *scriptedTransport) Do(_ context.Context, _, _ string, _ map[string]string, body io.Reader) ([]byte, map[string]string, int, error) {
+	if body != nil {
+		_, _ = io.ReadAll(body)
+	}
+	b := s.bodies[min(s.calls, len(s.bodies)-1)]
+	s.calls++
+	return []byte(b), map[string]string{"x-rate-limit-remaining": "49", "set-cookie": "ct0=secret"}, 200, nil
+}
+
+func TestVCR_RecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	live := &scriptedTransport{bodies: []string{`{"data":{"page":1}}`, `{"data":{"page":2},"guest_token":"1234567890"}`}}
+	rec := &Client{cfg: ClientConfig{Transport: live}, vcr: newVCR(&VCRConfig{Mode: VCRRecord, Dir: dir})}
+	ctx := context.Background()
+	u := `https://x.com/i/api/graphql/QID1/UserTweets?variables={"userId":"12"}&features={"a":true}`
+
+	for range 2 {
+		_, _, _, err := rec.send(ctx, nil, "GET", u, map[string]string{"x-csrf-token": "secret"}, nil)
+		require.NoError(t, err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "UserTweets", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secret")
+		assert.NotContains(t, string(data), "1234567890")
+	}
+
+	replay := &Client{cfg: ClientConfig{}, vcr: newVCR(&VCRConfig{Mode: VCRReplay, Dir: dir})}
+	assert.True(t, replay.offline())
+	// New query ID and features: still the same recording.
+	moved := `https://x.com/i/api/graphql/QID2/UserTweets?variables={"userId":"12"}&features={"b":false}`
+	var pages []string
+	for range 3 {
+		body, hdrs, status, err := replay.send(ctx, nil, "GET", moved, map[string]string{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, status)
+		assert.Equal(t, "49", hdrs["x-rate-limit-remaining"])
+		assert.Empty(t, hdrs["set-cookie"])
+		pages = append(pages, string(body))
+	}
+	assert.JSONEq(t, `{"data":{"page":1}}`, pages[0])
+	assert.JSONEq(t, `{"data":{"page":2},"guest_token":"REDACTED"}`, pages[1])
+	assert.Equal(t, pages[1], pages[2], "the last recording repeats")
+
+	_, _, _, err = replay.send(ctx, nil, "GET", `https://x.com/i/api/graphql/QID1/UserTweets?variables={"userId":"13"}`, map[string]string{}, nil)
+	assert.ErrorContains(t, err, "no recording")
+}
+
+func TestVCRKey_PostBodyVariables(t *testing.T) {
+	op, k1 := vcrKey("POST", "https://x.com/i/api/graphql/A/SearchTimeline", []byte(`{"variables":{"rawQuery":"btc"},"features":{"x":true}}`))
+	_, k2 := vcrKey("POST", "https://x.com/i/api/graphql/B/SearchTimeline", []byte(`{"variables":{"rawQuery":"btc"},"features":{"x":false}}`))
+	_, k3 := vcrKey("POST", "https://x.com/i/api/graphql/B/SearchTimeline", []byte(`{"variables":{"rawQuery":"eth"}}`))
+	assert.Equal(t, "SearchTimeline", op)
+	assert.Equal(t, k1, k2)
+	assert.NotEqual(t, k1, k3)
+
+	op, _ = vcrKey("GET", "https://api.x.com/1.1/friendships/show.json?source_id=1&target_id=2", nil)
+	assert.Equal(t, "1.1_friendships_show.json", op)
+	assert.False(t, strings.ContainsAny(op, `/\`))
+}
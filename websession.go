@@ -0,0 +1,102 @@
+package twitter
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/anatolykoptev/go-twitter/xpff"
+)
+
+// webIdentity is what a logged-in x.com browser sends besides auth_token and
+// ct0: long-lived tracking cookies and the client UUID. It is generated once
+// per account, replaced by the server's values after a login, and kept in
+// the session file so the account looks like the same browser across
+// restarts and re-logins.
+type webIdentity struct {
+	GuestID           string `json:"guest_id,omitempty"`           // "v1%3A<ms>"
+	PersonalizationID string `json:"personalization_id,omitempty"` // `"v1_<base64>"`
+	Twid              string `json:"twid,omitempty"`               // "u%3D<user id>"; only known after a login
+	Lang              string `json:"lang,omitempty"`
+	ClientUUID        string `json:"client_uuid,omitempty"`
+}
+
+// fill generates the values w is missing.
+func (w *webIdentity) fill() {
+	if w.GuestID == "" {
+		w.GuestID = xpff.GenerateGuestID()
+	}
+	if w.PersonalizationID == "" {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		w.PersonalizationID = `"v1_` + base64.StdEncoding.EncodeToString(b) + `"`
+	}
+	if w.Lang == "" {
+		w.Lang = "en"
+	}
+	if w.ClientUUID == "" {
+		w.ClientUUID = newUUID()
+	}
+}
+
+// apply adds w's cookies and client UUID to API request headers.
+func (w webIdentity) apply(h map[string]string) {
+	cookies := []string{h["cookie"]}
+	if w.GuestID != "" {
+		cookies = append(cookies,
+			"guest_id="+w.GuestID, "guest_id_marketing="+w.GuestID, "guest_id_ads="+w.GuestID)
+	}
+	for _, kv := range [][2]string{{"personalization_id", w.PersonalizationID}, {"twid", w.Twid}, {"lang", w.Lang}} {
+		if kv[1] != "" {
+			cookies = append(cookies, kv[0]+"="+kv[1])
+		}
+	}
+	h["cookie"] = strings.Join(cookies, "; ")
+	if w.ClientUUID != "" {
+		h["x-twitter-client-uuid"] = w.ClientUUID
+	}
+}
+
+// adoptCookies takes the identity cookies the server set on bc, e.g. during
+// login, in place of generated ones.
+func (w *webIdentity) adoptCookies(d Domain, bc *stealth.BrowserClient) {
+	for _, c := range []struct {
+		name string
+		dst  *string
+	}{{"guest_id", &w.GuestID}, {"personalization_id", &w.PersonalizationID}, {"twid", &w.Twid}} {
+		for _, o := range d.cookieOrigins() {
+			if v := bc.GetCookieValue(o, c.name); v != "" {
+				*c.dst = v
+				break
+			}
+		}
+	}
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// apiHeaders returns twitterHeaders for the given credentials plus the
+// account's web identity, generating the identity on first use. Accounts in
+// a mobile Mode get their app's headers instead, with the client UUID as
+// device ID.
+func (a *Account) apiHeaders(authToken, ct0, userAgent string) map[string]string {
+	a.mu.Lock()
+	a.web.fill()
+	w := a.web
+	a.mu.Unlock()
+	if app, ok := mobileApps[a.Mode]; ok {
+		return mobileHeaders(app, authToken, ct0, userAgent, w.ClientUUID)
+	}
+	h := twitterHeaders(authToken, ct0, userAgent)
+	w.apply(h)
+	return h
+}
@@ -0,0 +1,35 @@
+package twitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebIdentity_Headers(t *testing.T) {
+	acc := &Account{Username: "alice"}
+	h := acc.apiHeaders("tok", "csrf", "")
+	w := acc.web
+
+	assert.Regexp(t, `^auth_token=tok; ct0=csrf; guest_id=v1%3A\d+; guest_id_marketing=v1%3A\d+; guest_id_ads=v1%3A\d+; personalization_id="v1_[A-Za-z0-9+/=]+"; lang=en$`, h["cookie"])
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, h["x-twitter-client-uuid"])
+
+	// The identity is stable across requests.
+	h2 := acc.apiHeaders("tok", "csrf", "")
+	assert.Equal(t, h["cookie"], h2["cookie"])
+	assert.Equal(t, w, acc.web)
+}
+
+func TestSaveSession_KeepsWebIdentity(t *testing.T) {
+	dir := t.TempDir()
+	acc := &Account{Username: "alice", AuthToken: "tok", CT0: "csrf"}
+	acc.web.fill()
+	acc.web.Twid = "u%3D42"
+	require.NoError(t, saveSession(dir, acc))
+
+	authToken, ct0, web, err := loadSession(dir, "alice", 0)
+	require.NoError(t, err)
+	assert.Empty(t, authToken+ct0, "expired credentials are dropped")
+	assert.Equal(t, acc.web, web, "the web identity outlives them")
+}
@@ -0,0 +1,83 @@
+package twitter
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// AcquireWriteAccount leases a pool account for a write to endpoint, e.g.
+// CreateTweet or FavoriteTweet: one that is active, inside its ActiveHours,
+// matching WithAccountLabels, neither rate-limited on endpoint nor in proxy
+// backoff, with quota left for the endpoint's ActionClass (see
+// ClientConfig.ActionQuotas), and not leased by another caller. The account
+// with the most quota left wins.
+//
+// Pass the account to the write, then call release. The write itself draws
+// on the quota, so a lease released without writing costs nothing. Leases
+// only keep writers apart; reads still rotate through leased accounts.
+// When every candidate is out of quota the error wraps ErrQuotaExceeded,
+// otherwise ErrPoolExhausted.
+func (c *Client) AcquireWriteAccount(ctx context.Context, endpoint string) (*Account, func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	class, capped := actionClasses[endpoint]
+	limit := c.cfg.ActionQuotas[class]
+	capped = capped && limit > 0
+	labels := labelsFrom(ctx)
+	now := time.Now()
+
+	type candidate struct {
+		acc  *Account
+		left int
+	}
+	var candidates []candidate
+	outOfQuota := 0
+	for _, a := range c.eligibleAccounts(endpoint) {
+		if !a.hasLabels(labels) {
+			continue
+		}
+		left := math.MaxInt
+		if capped {
+			if left = a.actionsLeft(class, limit, now); left <= 0 {
+				outOfQuota++
+				continue
+			}
+		}
+		candidates = append(candidates, candidate{a, left})
+	}
+	slices.SortStableFunc(candidates, func(x, y candidate) int { return cmp.Compare(y.left, x.left) })
+	for _, cand := range candidates {
+		if cand.acc.leaseWrite() {
+			var once sync.Once
+			return cand.acc, func() { once.Do(cand.acc.releaseWrite) }, nil
+		}
+	}
+	if outOfQuota > 0 && len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("%s: every account is out of %s quota: %w", endpoint, class, ErrQuotaExceeded)
+	}
+	return nil, nil, fmt.Errorf("%s: no write account available: %w", endpoint, ErrPoolExhausted)
+}
+
+// leaseWrite marks the account as leased for writing; false if it already is.
+func (a *Account) leaseWrite() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.writeLeased {
+		return false
+	}
+	a.writeLeased = true
+	return true
+}
+
+// releaseWrite ends a write lease.
+func (a *Account) releaseWrite() {
+	a.mu.Lock()
+	a.writeLeased = false
+	a.mu.Unlock()
+}
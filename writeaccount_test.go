@@ -0,0 +1,50 @@
+package twitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireWriteAccount(t *testing.T) {
+	busy := &Account{Username: "busy", active: true}
+	fresh := &Account{Username: "fresh", active: true}
+	c := &Client{
+		cfg:  ClientConfig{ActionQuotas: map[ActionClass]int{ActionTweet: 3}},
+		pool: pool.New([]*Account{busy, fresh}, pool.Config{}),
+	}
+	now := time.Now()
+	require.NoError(t, busy.reserveAction(ActionTweet, 3, now))
+	ctx := context.Background()
+
+	acc, release, err := c.AcquireWriteAccount(ctx, "CreateTweet")
+	require.NoError(t, err)
+	assert.Same(t, fresh, acc, "most quota left wins")
+
+	second, release2, err := c.AcquireWriteAccount(ctx, "CreateTweet")
+	require.NoError(t, err)
+	assert.Same(t, busy, second, "leased accounts are skipped")
+
+	_, _, err = c.AcquireWriteAccount(ctx, "CreateTweet")
+	assert.ErrorIs(t, err, ErrPoolExhausted)
+
+	release()
+	release() // idempotent
+	release2()
+	acc, release, err = c.AcquireWriteAccount(ctx, "CreateTweet")
+	require.NoError(t, err)
+	assert.Same(t, fresh, acc)
+	release()
+
+	require.NoError(t, busy.reserveAction(ActionTweet, 3, now))
+	require.NoError(t, busy.reserveAction(ActionTweet, 3, now))
+	for range 3 {
+		require.NoError(t, fresh.reserveAction(ActionTweet, 3, now))
+	}
+	_, _, err = c.AcquireWriteAccount(ctx, "CreateTweet")
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+}
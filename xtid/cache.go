@@ -0,0 +1,102 @@
+package xtid
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedKeys is the on-disk form of a ClientTransaction: everything
+// GenerateID needs, so a restart can skip the x.com fetch.
+type cachedKeys struct {
+	VerificationKey string    `json:"verification_key"` // base64, as in the meta tag
+	RowIndex        int       `json:"row_index"`
+	KeyIndices      []int     `json:"key_indices"`
+	AnimationKey    string    `json:"animation_key"`
+	GuestID         string    `json:"guest_id,omitempty"`
+	FetchedAt       time.Time `json:"fetched_at"`
+}
+
+// WithCacheFile persists the keys to path after every refresh and loads them
+// in NewManager, so a restart within the refresh interval doesn't refetch
+// x.com. Older cached keys are still loaded as the fallback for a failed
+// refresh. Default: no cache.
+func WithCacheFile(path string) Option {
+	return func(m *Manager) { m.cacheFile = path }
+}
+
+// loadCache restores the keys from the cache file; a missing file is not an
+// error.
+func (m *Manager) loadCache() error {
+	data, err := os.ReadFile(m.cacheFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var ck cachedKeys
+	if err := json.Unmarshal(data, &ck); err != nil {
+		return fmt.Errorf("parse %s: %w", m.cacheFile, err)
+	}
+	keyBytes, err := base64.StdEncoding.DecodeString(ck.VerificationKey)
+	if err != nil || len(keyBytes) == 0 || ck.AnimationKey == "" {
+		return fmt.Errorf("%s: incomplete keys", m.cacheFile)
+	}
+
+	m.mu.Lock()
+	m.ct = &ClientTransaction{
+		keyBytes:        keyBytes,
+		animationKey:    ck.AnimationKey,
+		rowIndex:        ck.RowIndex,
+		keyBytesIndices: ck.KeyIndices,
+	}
+	m.guestID = ck.GuestID
+	m.lastRefresh = ck.FetchedAt
+	m.scheduleLocked(nil)
+	m.fromCache = true
+	m.mu.Unlock()
+	return nil
+}
+
+// saveCache writes the current keys to the cache file.
+func (m *Manager) saveCache() error {
+	m.mu.RLock()
+	ct := m.ct
+	ck := cachedKeys{GuestID: m.guestID, FetchedAt: m.lastRefresh}
+	m.mu.RUnlock()
+	if ct == nil {
+		return nil
+	}
+	ck.VerificationKey = base64.StdEncoding.EncodeToString(ct.keyBytes)
+	ck.RowIndex = ct.rowIndex
+	ck.KeyIndices = ct.keyBytesIndices
+	ck.AnimationKey = ct.animationKey
+
+	data, err := json.MarshalIndent(ck, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(m.cacheFile)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	// A unique temp file keeps concurrent refreshes from clobbering each other.
+	f, err := os.CreateTemp(dir, filepath.Base(m.cacheFile)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), m.cacheFile)
+}
@@ -1,39 +1,191 @@
 package xtid
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand"
 	"net/http"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Manager fetches x.com page/JS and caches the ClientTransaction, auto-refreshing every 30 min.
+// defaultUserAgent is sent on page fetches unless WithUserAgent is given.
+const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36"
+
+// Manager fetches x.com page/JS and caches the ClientTransaction, refreshing
+// it about every 30 min (±20% jitter) in the background.
 // Thread-safe. Falls back to old keys on refresh failure.
 type Manager struct {
 	mu              sync.RWMutex
+	refreshMu       sync.Mutex // held while a refresh runs
 	ct              *ClientTransaction
 	guestID         string
 	lastRefresh     time.Time
+	nextRefresh     time.Time // jittered; pushed back after failures
+	failStreak      int       // consecutive failed refreshes
 	refreshInterval time.Duration
 	client          *http.Client
+	fetcher         Fetcher // replaces client when set
+	userAgent       string
+	cacheFile       string // "" = no disk cache
+	fromCache       bool   // ct was loaded from cacheFile
+
+	refreshes        uint64
+	refreshFailures  uint64
+	lastError        string
+	lastErrorAt      time.Time
+	generated        atomic.Uint64
+	generateFailures atomic.Uint64
+}
+
+// Stats is a snapshot of a Manager's key state and counters.
+type Stats struct {
+	LastRefresh      time.Time // when the keys in use were fetched; zero if none
+	FromCache        bool      // the keys in use were loaded from the cache file
+	Refreshes        uint64    // successful key fetches
+	RefreshFailures  uint64    // failed key fetches
+	LastError        string    // error of the last failed fetch
+	LastErrorAt      time.Time
+	Generated        uint64 // transaction IDs generated
+	GenerateFailures uint64 // GenerateID errors, i.e. requests sent without the header
+}
+
+// Fetcher performs a GET of url with headers and returns the body, the
+// lower-cased response headers and the status code. Use it to fetch x.com
+// through the same transport (proxy, TLS fingerprint) as the API traffic the
+// transaction IDs are generated for.
+type Fetcher func(url string, headers map[string]string) ([]byte, map[string]string, int, error)
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithHTTPClient fetches x.com with hc, e.g. one whose transport uses a
+// proxy. Default: a plain client with a 30s timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(m *Manager) { m.client = hc }
+}
+
+// WithFetcher fetches x.com with f instead of an http.Client.
+func WithFetcher(f Fetcher) Option {
+	return func(m *Manager) { m.fetcher = f }
+}
+
+// WithUserAgent sets the User-Agent of page fetches, which should match the
+// one the API requests carry.
+func WithUserAgent(ua string) Option {
+	return func(m *Manager) {
+		if ua != "" {
+			m.userAgent = ua
+		}
+	}
 }
 
 // NewManager creates a new transaction ID manager.
-func NewManager() *Manager {
-	return &Manager{
+func NewManager(opts ...Option) *Manager {
+	m := &Manager{
 		refreshInterval: 30 * time.Minute,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		userAgent: defaultUserAgent,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	if m.cacheFile != "" {
+		if err := m.loadCache(); err != nil {
+			slog.Warn("xtid: cache load failed", slog.String("file", m.cacheFile), slog.Any("error", err))
+		} else if m.ct != nil {
+			slog.Info("xtid: loaded cached keys", slog.Duration("age", time.Since(m.lastRefresh).Round(time.Second)))
+		}
+	}
+	return m
+}
+
+// Stats returns the manager's key state and counters.
+func (m *Manager) Stats() Stats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return Stats{
+		LastRefresh:      m.lastRefresh,
+		FromCache:        m.fromCache,
+		Refreshes:        m.refreshes,
+		RefreshFailures:  m.refreshFailures,
+		LastError:        m.lastError,
+		LastErrorAt:      m.lastErrorAt,
+		Generated:        m.generated.Load(),
+		GenerateFailures: m.generateFailures.Load(),
+	}
+}
+
+// Fresh reports whether the manager holds keys younger than the refresh
+// interval, e.g. loaded from the cache file.
+func (m *Manager) Fresh() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ct != nil && time.Since(m.lastRefresh) <= m.refreshInterval
+}
+
+// refreshJitter spreads refreshes over ±20% of their interval, so managers
+// started together don't hit x.com in lockstep.
+const refreshJitter = 0.2
+
+// refreshRetryMin is the wait after a first failed refresh; it doubles with
+// each further failure, up to the refresh interval.
+const refreshRetryMin = time.Minute
+
+func jittered(d time.Duration) time.Duration {
+	return d + time.Duration((rand.Float64()*2-1)*refreshJitter*float64(d))
+}
+
+// scheduleLocked sets the next refresh after an attempt that returned err.
+func (m *Manager) scheduleLocked(err error) {
+	if err == nil {
+		m.failStreak = 0
+		m.nextRefresh = m.lastRefresh.Add(jittered(m.refreshInterval))
+		return
 	}
+	m.failStreak++
+	wait := min(refreshRetryMin<<min(m.failStreak-1, 6), m.refreshInterval)
+	m.nextRefresh = time.Now().Add(jittered(wait))
 }
 
 // Initialize fetches x.com and the ondemand.s JS file, then builds the ClientTransaction.
-// Must be called at least once before GenerateID.
+// GenerateID calls it on first use; call it up front to fail early.
 func (m *Manager) Initialize() error {
-	homeHTML, guestID, err := m.fetchHome()
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	return m.initializeLocked()
+}
+
+// initializeLocked is Initialize with refreshMu held.
+func (m *Manager) initializeLocked() error {
+	err := m.refresh()
+	m.mu.Lock()
+	if err != nil {
+		m.refreshFailures++
+		m.lastError, m.lastErrorAt = err.Error(), time.Now()
+	} else {
+		m.refreshes++
+	}
+	m.scheduleLocked(err)
+	m.mu.Unlock()
+	if err == nil && m.cacheFile != "" {
+		if saveErr := m.saveCache(); saveErr != nil {
+			slog.Warn("xtid: cache save failed", slog.String("file", m.cacheFile), slog.Any("error", saveErr))
+		}
+	}
+	return err
+}
+
+func (m *Manager) refresh() error {
+	ctx := context.Background()
+	homeHTML, guestID, err := m.fetchHome(ctx)
 	if err != nil {
 		return fmt.Errorf("fetch x.com: %w", err)
 	}
@@ -43,7 +195,7 @@ func (m *Manager) Initialize() error {
 		return fmt.Errorf("ondemand.s URL not found in x.com HTML")
 	}
 
-	ondemandJS, err := m.fetchURL(ondemandURL)
+	ondemandJS, err := m.fetchURL(ctx, ondemandURL)
 	if err != nil {
 		return fmt.Errorf("fetch ondemand.s: %w", err)
 	}
@@ -59,6 +211,7 @@ func (m *Manager) Initialize() error {
 		m.guestID = guestID
 	}
 	m.lastRefresh = time.Now()
+	m.fromCache = false
 	m.mu.Unlock()
 
 	prefix := ct.animationKey
@@ -78,38 +231,76 @@ func (m *Manager) GuestID() string {
 	return m.guestID
 }
 
+// FetchHomeHTML fetches the x.com home page HTML. Exposed so other x.com
+// scrapers (e.g. GraphQL query ID discovery) reuse the same fetch.
+func (m *Manager) FetchHomeHTML(ctx context.Context) (string, error) {
+	html, _, err := m.fetchHome(ctx)
+	return html, err
+}
+
+// FetchURL fetches url with browser-like headers, retrying transient
+// failures until ctx is done.
+func (m *Manager) FetchURL(ctx context.Context, url string) (string, error) {
+	return m.fetchURL(ctx, url)
+}
+
 // fetchHome fetches x.com and extracts the guest_id from set-cookie headers.
-func (m *Manager) fetchHome() (html, guestID string, err error) {
-	req, err := http.NewRequest("GET", "https://x.com", nil)
+func (m *Manager) fetchHome(ctx context.Context) (html, guestID string, err error) {
+	body, headers, status, err := m.get(ctx, "https://x.com")
 	if err != nil {
 		return "", "", err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
-
-	resp, err := m.client.Do(req)
-	if err != nil {
-		return "", "", err
+	if status != http.StatusOK {
+		return "", "", fmt.Errorf("HTTP %d", status)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	if match := guestIDCookieRe.FindStringSubmatch(headers["set-cookie"]); match != nil {
+		guestID = match[1]
 	}
+	return string(body), guestID, nil
+}
 
-	for _, c := range resp.Cookies() {
-		if c.Name == "guest_id" && c.Value != "" {
-			guestID = c.Value
-			break
+// guestIDCookieRe finds the guest_id cookie in (possibly joined) set-cookie
+// headers.
+var guestIDCookieRe = regexp.MustCompile(`(?:^|[\s,;])guest_id=([^;,\s]+)`)
+
+// get fetches url with browser-like headers through the fetcher or the HTTP
+// client. A Fetcher takes no context, so ctx is only checked before calling
+// it.
+func (m *Manager) get(ctx context.Context, url string) ([]byte, map[string]string, int, error) {
+	headers := map[string]string{
+		"user-agent":      m.userAgent,
+		"accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"accept-language": "en-US,en;q=0.9",
+	}
+	if m.fetcher != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, 0, err
 		}
+		return m.fetcher(url, headers)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", "", err
+		return nil, nil, 0, err
 	}
-	return string(body), guestID, nil
+	respHeaders := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		respHeaders[strings.ToLower(k)] = strings.Join(v, "\n")
+	}
+	return body, respHeaders, resp.StatusCode, nil
 }
 
 // fetchMaxAttempts is the number of attempts for transient network failures.
@@ -118,10 +309,10 @@ const fetchMaxAttempts = 3
 // fetchBackoffBase is the initial backoff between retry attempts.
 const fetchBackoffBase = 500 * time.Millisecond
 
-func (m *Manager) fetchURL(url string) (string, error) {
+func (m *Manager) fetchURL(ctx context.Context, url string) (string, error) {
 	var lastErr error
 	for attempt := 1; attempt <= fetchMaxAttempts; attempt++ {
-		body, err := m.fetchOnce(url)
+		body, err := m.fetchOnce(ctx, url)
 		if err == nil {
 			return body, nil
 		}
@@ -133,33 +324,22 @@ func (m *Manager) fetchURL(url string) (string, error) {
 			slog.String("url", url),
 			slog.Int("attempt", attempt),
 			slog.Any("error", err))
-		time.Sleep(fetchBackoffBase * time.Duration(1<<(attempt-1)))
+		select {
+		case <-time.After(fetchBackoffBase * time.Duration(1<<(attempt-1))):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
 	}
 	return "", lastErr
 }
 
-func (m *Manager) fetchOnce(url string) (string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (m *Manager) fetchOnce(ctx context.Context, url string) (string, error) {
+	body, _, status, err := m.get(ctx, url)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
-
-	resp, err := m.client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("HTTP %d for %s", resp.StatusCode, url)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+	if status != 200 {
+		return "", fmt.Errorf("HTTP %d for %s", status, url)
 	}
 	return string(body), nil
 }
@@ -180,29 +360,100 @@ func isPermanentFetchErr(err error) bool {
 }
 
 // GenerateID returns a new x-client-transaction-id for the given HTTP method and URL path.
-// Auto-refreshes keys if they are older than refreshInterval.
+// It never waits on a refresh once keys are loaded: due keys are refreshed in
+// the background while the current ones keep serving. Only the very first
+// call blocks, fetching the keys; after it fails, calls fail fast until the
+// retry backoff has passed.
 func (m *Manager) GenerateID(method, path string) (string, error) {
 	m.mu.RLock()
-	needRefresh := m.ct == nil || time.Since(m.lastRefresh) > m.refreshInterval
+	loaded := m.ct != nil
+	due := !time.Now().Before(m.nextRefresh)
 	m.mu.RUnlock()
 
-	if needRefresh {
-		if err := m.Initialize(); err != nil {
-			m.mu.RLock()
-			hasOld := m.ct != nil
-			m.mu.RUnlock()
-			if !hasOld {
-				return "", fmt.Errorf("xtid init failed: %w", err)
-			}
-			slog.Warn("xtid: refresh failed, using stale keys", slog.Any("error", err))
+	if !loaded {
+		if err := m.coldStart(); err != nil {
+			m.generateFailures.Add(1)
+			return "", err
 		}
+	} else if due {
+		m.refreshAsync()
 	}
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	m.generated.Add(1)
+	return m.ct.GenerateID(method, path), nil
+}
 
-	if m.ct == nil {
-		return "", fmt.Errorf("xtid not initialized")
+// coldStart loads the first keys, or fails fast while a previous attempt's
+// retry backoff runs.
+func (m *Manager) coldStart() error {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	m.mu.RLock()
+	loaded := m.ct != nil
+	due := !time.Now().Before(m.nextRefresh)
+	lastErr := m.lastError
+	m.mu.RUnlock()
+
+	switch {
+	case loaded:
+		return nil
+	case !due:
+		return fmt.Errorf("xtid not initialized, retrying later: %s", lastErr)
+	}
+	if err := m.initializeLocked(); err != nil {
+		return fmt.Errorf("xtid init failed: %w", err)
+	}
+	return nil
+}
+
+// refreshAsync refreshes the keys in a goroutine unless a refresh is already
+// running.
+func (m *Manager) refreshAsync() {
+	if !m.refreshMu.TryLock() {
+		return
+	}
+	go func() {
+		defer m.refreshMu.Unlock()
+		m.mu.RLock()
+		due := !time.Now().Before(m.nextRefresh)
+		m.mu.RUnlock()
+		if !due {
+			return // refreshed meanwhile
+		}
+		if err := m.initializeLocked(); err != nil {
+			slog.Warn("xtid: refresh failed, using stale keys", slog.Any("error", err))
+		}
+	}()
+}
+
+// Run refreshes the keys on schedule until ctx is done, so even the first
+// GenerateID after a quiet period finds them current. Optional: without it,
+// GenerateID starts due refreshes itself.
+func (m *Manager) Run(ctx context.Context) {
+	for {
+		m.mu.RLock()
+		wait := time.Until(m.nextRefresh)
+		m.mu.RUnlock()
+
+		timer := time.NewTimer(max(wait, 0))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		m.refreshMu.Lock()
+		m.mu.RLock()
+		due := !time.Now().Before(m.nextRefresh)
+		m.mu.RUnlock()
+		if due {
+			if err := m.initializeLocked(); err != nil {
+				slog.Warn("xtid: scheduled refresh failed", slog.Any("error", err))
+			}
+		}
+		m.refreshMu.Unlock()
 	}
-	return m.ct.GenerateID(method, path), nil
 }
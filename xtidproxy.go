@@ -0,0 +1,98 @@
+package twitter
+
+import (
+	"sync"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/anatolykoptev/go-twitter/xtid"
+)
+
+// xtidCacheFile holds the shared transaction-ID keys inside SessionDir.
+const xtidCacheFile = ".xtid.json"
+
+// browserFetcher fetches x.com pages for an xtid.Manager through bc, so the
+// bootstrap goes out with the same proxy and TLS fingerprint as the API
+// requests.
+func browserFetcher(bc *stealth.BrowserClient) xtid.Fetcher {
+	return func(url string, headers map[string]string) ([]byte, map[string]string, int, error) {
+		for k, v := range stealth.ClientHintsHeaders(headers["user-agent"]) {
+			headers[k] = v
+		}
+		return bc.DoWithHeaderOrder("GET", url, headers, nil, twitterHeaderOrder)
+	}
+}
+
+// xtidManagers holds the transaction-ID state of each account client when
+// ClientConfig.PerProxyXTID is set.
+type xtidManagers struct {
+	mu sync.Mutex
+	m  map[*stealth.BrowserClient]*xtid.Manager
+}
+
+// xtidFor returns the transaction-ID manager for requests sent on bc with
+// User-Agent ua: bc's own with PerProxyXTID, otherwise the shared one. A new
+// manager bootstraps on its first GenerateID.
+func (c *Client) xtidFor(bc *stealth.BrowserClient, ua string) *xtid.Manager {
+	if !c.cfg.PerProxyXTID || bc == nil || bc == c.client {
+		return c.xtidMgr
+	}
+	c.xtidPerClient.mu.Lock()
+	defer c.xtidPerClient.mu.Unlock()
+	if c.xtidPerClient.m == nil {
+		c.xtidPerClient.m = make(map[*stealth.BrowserClient]*xtid.Manager)
+	}
+	mgr := c.xtidPerClient.m[bc]
+	if mgr == nil {
+		c.pruneXTIDLocked()
+		mgr = xtid.NewManager(xtid.WithFetcher(browserFetcher(bc)), xtid.WithUserAgent(ua))
+		c.xtidPerClient.m[bc] = mgr
+	}
+	return mgr
+}
+
+// pruneXTIDLocked forgets the state of clients no account uses any more
+// (replaced by a proxy swap or session rotation).
+func (c *Client) pruneXTIDLocked() {
+	live := make(map[*stealth.BrowserClient]bool)
+	for _, acc := range c.pool.Items() {
+		acc.mu.Lock()
+		live[acc.client] = true
+		acc.mu.Unlock()
+	}
+	for bc := range c.xtidPerClient.m {
+		if !live[bc] {
+			delete(c.xtidPerClient.m, bc)
+		}
+	}
+}
+
+// xtidStats sums the counters of the shared manager and, with PerProxyXTID,
+// the per-client ones; key state (refresh and error times, FromCache) is that
+// of the most recently refreshed manager.
+func (c *Client) xtidStats() xtid.Stats {
+	mgrs := []*xtid.Manager{c.xtidMgr}
+	c.xtidPerClient.mu.Lock()
+	for _, mgr := range c.xtidPerClient.m {
+		mgrs = append(mgrs, mgr)
+	}
+	c.xtidPerClient.mu.Unlock()
+
+	var total xtid.Stats
+	for _, mgr := range mgrs {
+		if mgr == nil {
+			continue
+		}
+		s := mgr.Stats()
+		total.Refreshes += s.Refreshes
+		total.RefreshFailures += s.RefreshFailures
+		total.Generated += s.Generated
+		total.GenerateFailures += s.GenerateFailures
+		if s.LastRefresh.After(total.LastRefresh) {
+			total.LastRefresh, total.FromCache = s.LastRefresh, s.FromCache
+		}
+		if s.LastErrorAt.After(total.LastErrorAt) {
+			total.LastError, total.LastErrorAt = s.LastError, s.LastErrorAt
+		}
+	}
+	return total
+}
@@ -0,0 +1,92 @@
+package twitter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	stealth "github.com/anatolykoptev/go-stealth"
+	"github.com/anatolykoptev/go-stealth/pool"
+	"github.com/anatolykoptev/go-twitter/xtid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXTIDFor(t *testing.T) {
+	shared, bcA, bcB := &stealth.BrowserClient{}, &stealth.BrowserClient{}, &stealth.BrowserClient{}
+	a := &Account{Username: "a", client: bcA}
+	c := &Client{client: shared, xtidMgr: xtid.NewManager(), pool: pool.New([]*Account{a}, pool.Config{})}
+
+	assert.Same(t, c.xtidMgr, c.xtidFor(bcA, ""), "shared state unless PerProxyXTID")
+
+	c.cfg.PerProxyXTID = true
+	assert.Same(t, c.xtidMgr, c.xtidFor(shared, ""))
+	mgrA := c.xtidFor(bcA, "ua")
+	assert.NotSame(t, c.xtidMgr, mgrA)
+	assert.Same(t, mgrA, c.xtidFor(bcA, "ua"))
+
+	// The account moves to bcB (proxy swap): its old state is dropped.
+	a.client = bcB
+	mgrB := c.xtidFor(bcB, "ua")
+	assert.NotSame(t, mgrA, mgrB)
+	assert.Len(t, c.xtidPerClient.m, 1)
+}
+
+// writeXTIDCache writes a cache file with keys fetched age ago.
+func writeXTIDCache(t *testing.T, age time.Duration) string {
+	path := filepath.Join(t.TempDir(), xtidCacheFile)
+	cache := fmt.Sprintf(`{"verification_key":"AQIDBAUGBwgJCgsMDQ4PEA==","row_index":2,"key_indices":[5,9],"animation_key":"a1b2c3","fetched_at":%q}`,
+		time.Now().Add(-age).Format(time.RFC3339))
+	require.NoError(t, os.WriteFile(path, []byte(cache), 0600))
+	return path
+}
+
+func TestXTIDCachedKeys(t *testing.T) {
+	path := writeXTIDCache(t, time.Minute)
+
+	mgr := xtid.NewManager(xtid.WithCacheFile(path))
+	require.True(t, mgr.Fresh(), "cached keys are used without fetching x.com")
+	id, err := mgr.GenerateID("GET", "/i/api/graphql/x/UserTweets")
+	require.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	c := &Client{xtidMgr: mgr, pool: pool.New([]*Account{}, pool.Config{})}
+	st := c.Stats().XTID
+	assert.True(t, st.FromCache)
+	assert.Equal(t, uint64(1), st.Generated)
+	assert.Zero(t, st.GenerateFailures)
+
+	var b strings.Builder
+	c.WriteMetrics(&b)
+	assert.Contains(t, b.String(), "twitter_xtid_generated_total 1")
+}
+
+func TestXTIDStaleKeysRefreshInBackground(t *testing.T) {
+	path := writeXTIDCache(t, 2*time.Hour)
+	release := make(chan struct{})
+	fetches := make(chan string, 4)
+	mgr := xtid.NewManager(xtid.WithCacheFile(path), xtid.WithFetcher(func(url string, _ map[string]string) ([]byte, map[string]string, int, error) {
+		fetches <- url
+		<-release
+		return nil, nil, 403, nil
+	}))
+	require.False(t, mgr.Fresh())
+
+	// Both calls are served from the stale keys while one refresh hangs.
+	for range 2 {
+		id, err := mgr.GenerateID("GET", "/i/api/graphql/x/UserTweets")
+		require.NoError(t, err)
+		assert.NotEmpty(t, id)
+	}
+	assert.Equal(t, "https://x.com", <-fetches)
+	close(release)
+
+	require.Eventually(t, func() bool { return mgr.Stats().RefreshFailures == 1 }, time.Second, 5*time.Millisecond)
+	assert.Len(t, fetches, 0, "one refresh at a time")
+	assert.False(t, mgr.Fresh())
+	_, err := mgr.GenerateID("GET", "/")
+	assert.NoError(t, err, "a failed refresh keeps the stale keys")
+}